@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"auth-service/config"
+	"auth-service/database"
+	"auth-service/grpcserver"
+	"auth-service/handlers"
+	"auth-service/health"
+	"auth-service/logging"
+	"auth-service/mailer"
+	"auth-service/middleware"
+	"auth-service/routes"
+	"auth-service/services"
+	"auth-service/store"
+	"auth-service/tracing"
+
+	"google.golang.org/grpc"
+	"gopkg.in/yaml.v3"
+)
+
+func main() {
+	configPath := flag.String("config", os.Getenv("CONFIG_FILE"), "arquivo de configuração YAML ou TOML (opcional)")
+	printConfig := flag.Bool("print-config", false, "exibe a configuração efetiva, com segredos mascarados, e sai")
+	flag.Parse()
+
+	// Carregar configurações
+	cfg, err := config.LoadFrom(*configPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if *printConfig {
+		out, err := yaml.Marshal(cfg.Redacted())
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Stdout.Write(out)
+		return
+	}
+
+	logging.Setup(cfg)
+
+	// Configurar exportação de traces
+	shutdownTracing, err := tracing.Setup(cfg)
+	if err != nil {
+		fatal("Erro ao configurar tracing", err)
+	}
+
+	// Conectar ao banco de dados
+	db, err := database.NewDatabase(cfg)
+	if err != nil {
+		fatal("Erro ao conectar ao banco de dados", err)
+	}
+	defer db.Close()
+
+	// Executar migrações
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		fatal("Erro ao carregar migrações", err)
+	}
+	if err := migrator.Up(); err != nil {
+		fatal("Erro ao executar migrações", err)
+	}
+
+	// Verificações de prontidão expostas em /readyz
+	checker := health.NewChecker()
+	checker.Register("database", db.DB.PingContext)
+	checker.Register("migrations", migrator.CheckPending)
+
+	// Carregar chave de assinatura dos ID tokens
+	signingKey, err := services.LoadSigningKey(cfg.OIDC.SigningKeyPath)
+	if err != nil {
+		fatal("Erro ao carregar chave de assinatura", err)
+	}
+
+	// Inicializar stores e serviços
+	stores := store.NewSQLStores(db)
+	authService := services.NewAuthService(stores, cfg, signingKey)
+	federationService := services.NewFederationService(authService, cfg, nil)
+
+	// Mailer usado pelo login por link e pela redefinição de senha
+	emailSender, err := mailer.New(cfg)
+	if err != nil {
+		fatal("Erro ao configurar envio de emails", err)
+	}
+	if cfg.IsProduction() && cfg.Mailer.Driver == "log" {
+		slog.Warn("Driver de email log em produção: os links de acesso só aparecerão no log")
+	}
+	magicLinkService := services.NewMagicLinkService(authService, cfg, emailSender, stores.MagicLinks)
+	passwordResetService := services.NewPasswordResetService(authService, cfg, emailSender)
+	loginAlertService := services.NewLoginAlertService(authService, cfg, emailSender, passwordResetService)
+	authService.SetDeviceNotifier(loginAlertService)
+
+	// Iniciar limpeza periódica de tokens
+	janitor := services.NewJanitor(cfg, stores)
+	if cfg.Cleanup.Enabled {
+		janitor.Start()
+	}
+
+	// Entregar os eventos do outbox aos webhooks
+	dispatcher := services.NewWebhookDispatcher(cfg, stores.Outbox, stores.Webhooks)
+	if cfg.Webhooks.Enabled {
+		dispatcher.Start()
+	}
+
+	// Configurar rotas
+	router := routes.SetupRoutes(
+		handlers.NewAuthHandler(authService, cfg),
+		handlers.NewFederationHandler(federationService),
+		handlers.NewMagicLinkHandler(magicLinkService),
+		handlers.NewAuditHandler(services.NewAuditService(stores.Events)),
+		handlers.NewWebhookHandler(services.NewWebhookService(authService, cfg, stores.Outbox, stores.Webhooks)),
+		handlers.NewPasswordResetHandler(passwordResetService),
+		handlers.NewUserImportHandler(services.NewUserImportService(authService, passwordResetService)),
+		handlers.NewLoginAlertHandler(loginAlertService),
+		middleware.NewAuthMiddleware(authService),
+		middleware.NewRateLimiter(cfg),
+		checker,
+	)
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		fatal("Erro ao configurar proxies confiáveis", err)
+	}
+
+	// Configurar servidor HTTP
+	srv := &http.Server{
+		Addr:    ":" + cfg.Server.Port,
+		Handler: router,
+	}
+
+	// Canal para receber sinais de interrupção
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+
+	// Iniciar servidor em uma goroutine
+	go func() {
+		slog.Info("Auth Service iniciado", "port", cfg.Server.Port, "env", cfg.Server.Env)
+
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			fatal("Erro ao iniciar servidor", err)
+		}
+	}()
+
+	// API gRPC interna, em porta separada
+	var grpcServer *grpc.Server
+	if cfg.GRPC.Enabled {
+		listener, err := net.Listen("tcp", ":"+cfg.GRPC.Port)
+		if err != nil {
+			fatal("Erro ao abrir porta gRPC", err)
+		}
+		if cfg.GRPC.AuthToken == "" {
+			slog.Warn("GRPC_AUTH_TOKEN não configurado; a API gRPC aceita qualquer chamador que alcance a porta")
+		}
+
+		grpcServer = grpcserver.NewServer(cfg, authService)
+		go func() {
+			slog.Info("API gRPC iniciada", "port", cfg.GRPC.Port)
+
+			if err := grpcServer.Serve(listener); err != nil {
+				fatal("Erro ao iniciar servidor gRPC", err)
+			}
+		}()
+	}
+
+	// Aguardar sinal de interrupção
+	<-quit
+	slog.Info("Desligando Auth Service...")
+
+	// Responder 503 em /readyz e dar tempo aos balanceadores de retirar a instância
+	checker.Drain()
+	time.Sleep(time.Duration(cfg.Server.ShutdownDelaySeconds) * time.Second)
+
+	// Contexto com timeout para shutdown graceful
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// Tentar desligar o servidor gracefulmente
+	if err := srv.Shutdown(ctx); err != nil {
+		slog.Error("Erro ao desligar servidor", "error", err)
+	}
+	if grpcServer != nil {
+		grpcServer.GracefulStop()
+	}
+
+	// Parar a limpeza e as entregas depois que as requisições em andamento terminarem
+	janitor.Stop()
+	dispatcher.Stop()
+
+	// Enviar os spans pendentes antes de sair
+	if err := shutdownTracing(ctx); err != nil {
+		slog.Error("Erro ao finalizar tracing", "error", err)
+	}
+
+	slog.Info("Auth Service desligado com sucesso")
+}
+
+// fatal registra o erro e encerra o processo
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
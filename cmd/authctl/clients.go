@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"auth-service/models"
+)
+
+// clientWithSecret expõe o secret, que o modelo omite do JSON, apenas na criação e na rotação
+type clientWithSecret struct {
+	models.Client
+	Secret string `json:"secret"`
+}
+
+func runClients(command string, args []string) error {
+	env, err := openEnvironment()
+	if err != nil {
+		return err
+	}
+	defer env.Close()
+
+	ctx := context.Background()
+
+	switch command {
+	case "create":
+		if len(args) < 1 || len(args) > 3 {
+			return fmt.Errorf("informe o nome do client")
+		}
+		var description string
+		if len(args) > 1 {
+			description = args[1]
+		}
+		tenantID, err := env.tenantID(ctx, args, 2)
+		if err != nil {
+			return err
+		}
+
+		client, err := env.authService.CreateClient(ctx, tenantID, args[0], description)
+		if err != nil {
+			return err
+		}
+		return renderClientSecret(client, "Client criado")
+
+	case "list":
+		if len(args) > 1 {
+			return fmt.Errorf("argumentos demais")
+		}
+		tenantID, err := env.tenantID(ctx, args, 0)
+		if err != nil {
+			return err
+		}
+
+		clients, err := env.authService.ListClients(ctx, tenantID)
+		if err != nil {
+			return err
+		}
+		rows := make([][]string, 0, len(clients))
+		for _, client := range clients {
+			rows = append(rows, []string{client.ID.String(), client.Name, formatBool(client.Active),
+				formatBool(client.MagicLinkEnabled), formatTime(client.CreatedAt)})
+		}
+		return render(clients, []string{"ID", "NOME", "ATIVO", "MAGIC LINK", "CRIADO EM"}, rows)
+
+	case "disable":
+		if len(args) < 1 || len(args) > 2 {
+			return fmt.Errorf("informe o ID do client")
+		}
+		tenantID, err := env.tenantID(ctx, args, 1)
+		if err != nil {
+			return err
+		}
+
+		active := false
+		client, err := env.authService.UpdateClient(ctx, tenantID, args[0], &models.UpdateClientRequest{Active: &active})
+		if err != nil {
+			return err
+		}
+		return renderMessage(client, "Client %s desativado", client.ID)
+
+	case "rotate-secret":
+		if len(args) < 1 || len(args) > 2 {
+			return fmt.Errorf("informe o ID do client")
+		}
+		tenantID, err := env.tenantID(ctx, args, 1)
+		if err != nil {
+			return err
+		}
+
+		client, err := env.authService.RotateClientSecret(ctx, tenantID, args[0])
+		if err != nil {
+			return err
+		}
+		return renderClientSecret(client, "Secret do client rotacionado")
+
+	default:
+		return fmt.Errorf("comando de clients desconhecido: %s", command)
+	}
+}
+
+// renderClientSecret exibe o client com o secret, que não pode ser consultado depois
+func renderClientSecret(client *models.Client, message string) error {
+	if outputFormat == outputJSON {
+		return render(clientWithSecret{Client: *client, Secret: client.Secret}, nil, nil)
+	}
+
+	fmt.Println(message)
+	fmt.Printf("ID:     %s\n", client.ID)
+	fmt.Printf("Nome:   %s\n", client.Name)
+	fmt.Printf("Secret: %s\n", client.Secret)
+	fmt.Println("Guarde o secret agora: ele não será exibido novamente.")
+	return nil
+}
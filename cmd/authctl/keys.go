@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+
+	"auth-service/config"
+	"auth-service/services"
+)
+
+func runKeys(command string, args []string) error {
+	if command != "rotate" {
+		return fmt.Errorf("comando de chaves desconhecido: %s", command)
+	}
+	if len(args) > 0 {
+		return fmt.Errorf("argumentos demais")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	key, err := services.RotateSigningKey(cfg.OIDC.SigningKeyPath)
+	if err != nil {
+		return err
+	}
+
+	result := map[string]string{"kid": key.ID, "path": cfg.OIDC.SigningKeyPath}
+	if key.Previous != nil {
+		result["previous_kid"] = key.Previous.ID
+	}
+	if key.Next == nil {
+		return renderMessage(result, "Chave %s gravada em %s; reinicie o serviço para usá-la", key.ID, cfg.OIDC.SigningKeyPath)
+	}
+	result["next_kid"] = key.Next.ID
+	return renderMessage(result, "Chave %s assina os tokens e a próxima, %s, foi publicada no JWKS; reinicie o serviço para aplicar", key.ID, key.Next.ID)
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"auth-service/config"
+	"auth-service/database"
+	"auth-service/services"
+	"auth-service/store"
+)
+
+const usage = `Uso: authctl [-o table|json] <comando> [argumentos]
+
+Comandos:
+  migrate up                    Aplica todas as migrações pendentes
+  migrate to <versão>           Migra (para frente ou para trás) até a versão informada
+  migrate down [passos]         Reverte as últimas migrações (padrão: 1)
+  migrate status                Lista as migrações e se já foram aplicadas
+  migrate force-unlock          Remove a trava deixada por uma migração interrompida
+  roles grant <email> <papel> [organização]
+                                Concede um papel ao usuário (ex: admin) da
+                                organização informada pelo slug (padrão: default)
+  roles revoke <email> <papel> [organização]
+                                Remove um papel do usuário
+  admin create <email> <nome> [organização]
+                                Cria um administrador. A senha vem de
+                                AUTHCTL_ADMIN_PASSWORD (mínimo de 8 caracteres) ou é
+                                gerada e exibida uma vez
+  clients create <nome> [descrição] [organização]
+                                Cria um client e exibe o secret uma única vez
+  clients list [organização]    Lista os clients da organização
+  clients disable <id> [organização]
+                                Desativa o client
+  clients rotate-secret <id> [organização]
+                                Gera um novo secret; o anterior deixa de valer
+  keys rotate                   Publica uma nova chave de assinatura OIDC no JWKS; se
+                                já havia uma publicada, ela passa a assinar e a atual
+                                fica no JWKS até a próxima rotação. Reinicie o serviço
+                                após cada rotação e aguarde os clients atualizarem o
+                                JWKS antes da seguinte
+  sessions revoke <email> [organização]
+                                Revoga todos os refresh tokens do usuário
+  tokens purge                  Remove os registros vencidos, como o janitor do serviço
+  users import <arquivo> [organização] [--dry-run]
+                                Cria ou atualiza usuários a partir de um CSV ou JSON;
+                                password_hash só vale para contas novas; sem ele, o
+                                usuário recebe um link por email para definir a senha.
+                                --dry-run apenas valida
+  users export <csv|json> [organização]
+                                Exporta os usuários, sem hashes, na saída padrão
+
+Opções:
+  -o, --output table|json       Formato da saída (padrão: table)
+`
+
+func main() {
+	args, err := parseOutputFlag(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Erro: %v\n", err)
+		os.Exit(2)
+	}
+	if len(args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	switch args[0] {
+	case "migrate":
+		err = runMigrate(args[1], args[2:])
+	case "roles":
+		err = runRoles(args[1], args[2:])
+	case "admin":
+		err = runAdmin(args[1], args[2:])
+	case "clients":
+		err = runClients(args[1], args[2:])
+	case "keys":
+		err = runKeys(args[1], args[2:])
+	case "sessions":
+		err = runSessions(args[1], args[2:])
+	case "users":
+		err = runUsers(args[1], args[2:])
+	case "tokens":
+		err = runTokens(args[1], args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Erro: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// environment reúne a configuração, o banco e os serviços usados pelos comandos
+type environment struct {
+	cfg         *config.Config
+	db          *database.Database
+	stores      *store.Stores
+	authService *services.AuthService
+}
+
+func openEnvironment() (*environment, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := database.NewDatabase(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	signingKey, err := services.LoadSigningKey(cfg.OIDC.SigningKeyPath)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	stores := store.NewSQLStores(db)
+	return &environment{
+		cfg:         cfg,
+		db:          db,
+		stores:      stores,
+		authService: services.NewAuthService(stores, cfg, signingKey),
+	}, nil
+}
+
+func (e *environment) Close() {
+	e.db.Close()
+}
+
+// tenantID resolve o slug da organização, informado como argumento opcional após os
+// obrigatórios (padrão: default)
+func (e *environment) tenantID(ctx context.Context, args []string, position int) (string, error) {
+	slug := "default"
+	if len(args) > position {
+		slug = args[position]
+	}
+
+	organization, err := e.authService.GetOrganizationBySlug(ctx, slug)
+	if err != nil {
+		return "", err
+	}
+	return organization.ID.String(), nil
+}
+
+func runMigrate(command string, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	db, err := database.NewDatabase(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	switch command {
+	case "up":
+		return migrator.Up()
+
+	case "to":
+		if len(args) != 1 {
+			return fmt.Errorf("informe a versão de destino")
+		}
+		version, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("versão inválida: %s", args[0])
+		}
+		return migrator.MigrateTo(version)
+
+	case "down":
+		steps := 1
+		if len(args) > 0 {
+			if steps, err = strconv.Atoi(args[0]); err != nil {
+				return fmt.Errorf("número de passos inválido: %s", args[0])
+			}
+		}
+		return migrator.Rollback(steps)
+
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		rows := make([][]string, 0, len(statuses))
+		for _, status := range statuses {
+			appliedAt := "pendente"
+			if status.AppliedAt != nil {
+				appliedAt = formatTime(*status.AppliedAt)
+			}
+			rows = append(rows, []string{fmt.Sprintf("%04d", status.Version), status.Name, appliedAt})
+		}
+		return render(statuses, []string{"VERSÃO", "NOME", "APLICADA EM"}, rows)
+
+	case "force-unlock":
+		return migrator.ForceUnlock()
+
+	default:
+		return fmt.Errorf("comando de migração desconhecido: %s", command)
+	}
+}
+
+func runRoles(command string, args []string) error {
+	if len(args) < 2 || len(args) > 3 {
+		return fmt.Errorf("informe o email e o papel")
+	}
+	email, role := args[0], args[1]
+
+	env, err := openEnvironment()
+	if err != nil {
+		return err
+	}
+	defer env.Close()
+
+	ctx := context.Background()
+	tenantID, err := env.tenantID(ctx, args, 2)
+	if err != nil {
+		return err
+	}
+
+	result := map[string]string{"email": email, "role": role}
+	switch command {
+	case "grant":
+		if err := env.authService.GrantRole(ctx, tenantID, email, role); err != nil {
+			return err
+		}
+		return renderMessage(result, "Papel %s concedido a %s", role, email)
+
+	case "revoke":
+		if err := env.authService.RevokeRole(ctx, tenantID, email, role); err != nil {
+			return err
+		}
+		return renderMessage(result, "Papel %s removido de %s", role, email)
+
+	default:
+		return fmt.Errorf("comando de papéis desconhecido: %s", command)
+	}
+}
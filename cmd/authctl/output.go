@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Formatos aceitos em -o/--output
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+// outputFormat é o formato escolhido na linha de comando
+var outputFormat = outputTable
+
+// parseOutputFlag remove -o/--output dos argumentos, em qualquer posição, e define o formato
+func parseOutputFlag(args []string) ([]string, error) {
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		var value string
+		switch {
+		case arg == "-o" || arg == "--output":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("informe o formato de saída (%s ou %s)", outputTable, outputJSON)
+			}
+			i++
+			value = args[i]
+		case strings.HasPrefix(arg, "-o="):
+			value = strings.TrimPrefix(arg, "-o=")
+		case strings.HasPrefix(arg, "--output="):
+			value = strings.TrimPrefix(arg, "--output=")
+		default:
+			rest = append(rest, arg)
+			continue
+		}
+
+		if value != outputTable && value != outputJSON {
+			return nil, fmt.Errorf("formato de saída desconhecido: %s", value)
+		}
+		outputFormat = value
+	}
+	return rest, nil
+}
+
+// render escreve value como JSON ou, no formato tabela, as linhas sob os cabeçalhos
+func render(value any, headers []string, rows [][]string) error {
+	if outputFormat == outputJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(value)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(headers, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
+
+// renderMessage escreve uma mensagem para humanos na tabela e value no JSON
+func renderMessage(value any, format string, args ...any) error {
+	if outputFormat == outputJSON {
+		return render(value, nil, nil)
+	}
+	fmt.Printf(format+"\n", args...)
+	return nil
+}
+
+func formatTime(t time.Time) string {
+	return t.Format("2006-01-02 15:04:05")
+}
+
+func formatBool(value bool) string {
+	if value {
+		return "sim"
+	}
+	return "não"
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"auth-service/services"
+)
+
+func runTokens(command string, args []string) error {
+	if command != "purge" {
+		return fmt.Errorf("comando de tokens desconhecido: %s", command)
+	}
+	if len(args) > 0 {
+		return fmt.Errorf("argumentos demais")
+	}
+
+	env, err := openEnvironment()
+	if err != nil {
+		return err
+	}
+	defer env.Close()
+
+	janitor := services.NewJanitor(env.cfg, env.stores)
+	janitor.RunOnce(context.Background())
+	purged := janitor.Stats().Purged
+
+	names := make([]string, 0, len(purged))
+	for name := range purged {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	rows := make([][]string, 0, len(names))
+	for _, name := range names {
+		rows = append(rows, []string{name, strconv.FormatInt(purged[name], 10)})
+	}
+	return render(purged, []string{"TIPO", "REMOVIDOS"}, rows)
+}
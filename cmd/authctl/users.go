@@ -0,0 +1,214 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"auth-service/mailer"
+	"auth-service/models"
+	"auth-service/services"
+)
+
+// adminPasswordEnv permite informar a senha do administrador sem passá-la como argumento
+const adminPasswordEnv = "AUTHCTL_ADMIN_PASSWORD"
+
+func runAdmin(command string, args []string) error {
+	if command != "create" {
+		return fmt.Errorf("comando de administradores desconhecido: %s", command)
+	}
+	if len(args) < 2 || len(args) > 3 {
+		return fmt.Errorf("informe o email e o nome")
+	}
+	email, name := args[0], args[1]
+
+	password := os.Getenv(adminPasswordEnv)
+	generated := password == ""
+	if generated {
+		passwordBytes := make([]byte, 18)
+		if _, err := rand.Read(passwordBytes); err != nil {
+			return fmt.Errorf("erro ao gerar senha: %w", err)
+		}
+		password = base64.RawURLEncoding.EncodeToString(passwordBytes)
+	}
+
+	env, err := openEnvironment()
+	if err != nil {
+		return err
+	}
+	defer env.Close()
+
+	ctx := context.Background()
+	tenantID, err := env.tenantID(ctx, args, 2)
+	if err != nil {
+		return err
+	}
+
+	user, err := env.authService.CreateAdmin(ctx, tenantID, email, name, password)
+	if err != nil {
+		return err
+	}
+
+	if outputFormat == outputJSON {
+		result := struct {
+			*models.UserResponse
+			Password string `json:"password,omitempty"`
+		}{UserResponse: user}
+		if generated {
+			result.Password = password
+		}
+		return render(result, nil, nil)
+	}
+
+	fmt.Printf("Administrador %s criado (%s)\n", user.Email, user.ID)
+	if generated {
+		fmt.Printf("Senha: %s\n", password)
+		fmt.Println("Guarde a senha agora: ela não será exibida novamente.")
+	}
+	return nil
+}
+
+func runSessions(command string, args []string) error {
+	if command != "revoke" {
+		return fmt.Errorf("comando de sessões desconhecido: %s", command)
+	}
+	if len(args) < 1 || len(args) > 2 {
+		return fmt.Errorf("informe o email do usuário")
+	}
+	email := strings.TrimSpace(args[0])
+
+	env, err := openEnvironment()
+	if err != nil {
+		return err
+	}
+	defer env.Close()
+
+	ctx := context.Background()
+	tenantID, err := env.tenantID(ctx, args, 1)
+	if err != nil {
+		return err
+	}
+
+	revoked, err := env.authService.RevokeUserSessions(ctx, tenantID, email)
+	if err != nil {
+		return err
+	}
+	return renderMessage(map[string]any{"email": email, "revoked": revoked},
+		"%d sessões de %s revogadas", revoked, email)
+}
+
+func runUsers(command string, args []string) error {
+	switch command {
+	case "import":
+		return runUsersImport(args)
+	case "export":
+		return runUsersExport(args)
+	default:
+		return fmt.Errorf("comando de usuários desconhecido: %s", command)
+	}
+}
+
+func runUsersImport(args []string) error {
+	dryRun := false
+	var positional []string
+	for _, arg := range args {
+		if arg == "--dry-run" {
+			dryRun = true
+			continue
+		}
+		positional = append(positional, arg)
+	}
+	if len(positional) < 1 || len(positional) > 2 {
+		return fmt.Errorf("informe o arquivo CSV ou JSON")
+	}
+	path := positional[0]
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	records, err := services.ParseUserFile(file, services.UserFileFormat(path))
+	if err != nil {
+		return err
+	}
+
+	env, err := openEnvironment()
+	if err != nil {
+		return err
+	}
+	defer env.Close()
+
+	emailSender, err := mailer.New(env.cfg)
+	if err != nil {
+		return err
+	}
+	importService := services.NewUserImportService(env.authService, services.NewPasswordResetService(env.authService, env.cfg, emailSender))
+
+	ctx := context.Background()
+	tenantID, err := env.tenantID(ctx, positional, 1)
+	if err != nil {
+		return err
+	}
+
+	result, err := importService.ImportUsers(ctx, tenantID, "", records, dryRun)
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, 0, len(result.Rows))
+	for _, row := range result.Rows {
+		rows = append(rows, []string{strconv.Itoa(row.Row), row.Email, row.Action, row.Error})
+	}
+	if err := render(result, []string{"LINHA", "EMAIL", "AÇÃO", "ERRO"}, rows); err != nil {
+		return err
+	}
+
+	if outputFormat == outputTable {
+		prefix := ""
+		if dryRun {
+			prefix = "Simulação: "
+		}
+		fmt.Printf("\n%s%d criados, %d atualizados, %d sem alteração, %d com erro\n",
+			prefix, result.Created, result.Updated, result.Unchanged, result.Failed)
+	}
+	if result.Failed > 0 {
+		return fmt.Errorf("%d linhas com erro", result.Failed)
+	}
+	return nil
+}
+
+// runUsersExport grava o arquivo na saída padrão, no formato informado; -o não se aplica
+func runUsersExport(args []string) error {
+	if len(args) < 1 || len(args) > 2 {
+		return fmt.Errorf("informe o formato (csv ou json)")
+	}
+	format := args[0]
+	if format != models.UserFileCSV && format != models.UserFileJSON {
+		return fmt.Errorf("formato desconhecido: %s", format)
+	}
+
+	env, err := openEnvironment()
+	if err != nil {
+		return err
+	}
+	defer env.Close()
+
+	ctx := context.Background()
+	tenantID, err := env.tenantID(ctx, args, 1)
+	if err != nil {
+		return err
+	}
+
+	importService := services.NewUserImportService(env.authService, nil)
+	records, err := importService.ExportUsers(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	return services.WriteUserFile(os.Stdout, format, records)
+}
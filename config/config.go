@@ -1,69 +1,583 @@
 package config
 
 import (
-	"log"
+	"bytes"
+	"fmt"
+	"log/slog"
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
 )
 
+// defaultJWTSecret é o segredo de exemplo; só é aceito fora de produção
+const defaultJWTSecret = "your-super-secret-jwt-key-change-in-production"
+
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	JWT      JWTConfig
+	Server        ServerConfig        `yaml:"server" toml:"server"`
+	Database      DatabaseConfig      `yaml:"database" toml:"database"`
+	JWT           JWTConfig           `yaml:"jwt" toml:"jwt"`
+	OIDC          OIDCConfig          `yaml:"oidc" toml:"oidc"`
+	Federation    FederationConfig    `yaml:"federation" toml:"federation"`
+	Cleanup       CleanupConfig       `yaml:"cleanup" toml:"cleanup"`
+	Tracing       TracingConfig       `yaml:"tracing" toml:"tracing"`
+	Logging       LoggingConfig       `yaml:"logging" toml:"logging"`
+	RateLimit     RateLimitConfig     `yaml:"rate_limit" toml:"rate_limit"`
+	GRPC          GRPCConfig          `yaml:"grpc" toml:"grpc"`
+	Mailer        MailerConfig        `yaml:"mailer" toml:"mailer"`
+	MagicLink     MagicLinkConfig     `yaml:"magic_link" toml:"magic_link"`
+	DeviceAuth    DeviceAuthConfig    `yaml:"device_auth" toml:"device_auth"`
+	Registration  RegistrationConfig  `yaml:"registration" toml:"registration"`
+	Session       SessionConfig       `yaml:"session" toml:"session"`
+	Webhooks      WebhooksConfig      `yaml:"webhooks" toml:"webhooks"`
+	PasswordReset PasswordResetConfig `yaml:"password_reset" toml:"password_reset"`
+	LoginAlerts   LoginAlertsConfig   `yaml:"login_alerts" toml:"login_alerts"`
+	Passwords     PasswordsConfig     `yaml:"passwords" toml:"passwords"`
+
+	// envErrors são as variáveis de ambiente com valores que não puderam ser interpretados;
+	// Validate as informa junto com os demais problemas
+	envErrors []error
 }
 
 type ServerConfig struct {
-	Port string
-	Env  string
+	Port                 string `yaml:"port" toml:"port"`
+	Env                  string `yaml:"env" toml:"env"`
+	ShutdownDelaySeconds int    `yaml:"shutdown_delay_seconds" toml:"shutdown_delay_seconds"` // tempo em /readyz 503 antes de parar de aceitar conexões
+
+	// TrustedProxies são os proxies reversos (IPs/CIDRs) cujo X-Forwarded-For é aceito. Vazio:
+	// o IP do cliente é sempre o da conexão, e o header não pode ser forjado.
+	TrustedProxies []string `yaml:"trusted_proxies" toml:"trusted_proxies"`
 }
 
 type DatabaseConfig struct {
-	Type     string // "sqlite" ou "mysql"
-	Path     string // Para SQLite
-	Host     string // Para MySQL
-	Port     string // Para MySQL
-	User     string // Para MySQL
-	Password string // Para MySQL
-	Name     string // Para MySQL
-	SSLMode  string // Para MySQL
+	Type     string `yaml:"type" toml:"type"`         // "sqlite" ou "mysql"
+	Path     string `yaml:"path" toml:"path"`         // Para SQLite
+	Host     string `yaml:"host" toml:"host"`         // Para MySQL
+	Port     string `yaml:"port" toml:"port"`         // Para MySQL
+	User     string `yaml:"user" toml:"user"`         // Para MySQL
+	Password string `yaml:"password" toml:"password"` // Para MySQL
+	Name     string `yaml:"name" toml:"name"`         // Para MySQL
+	SSLMode  string `yaml:"ssl_mode" toml:"ssl_mode"` // Para MySQL
 }
 
 type JWTConfig struct {
-	Secret                 string
-	ExpirationHours        int
-	RefreshExpirationHours int
+	Secret                 string `yaml:"secret" toml:"secret"`
+	ExpirationHours        int    `yaml:"expiration_hours" toml:"expiration_hours"`
+	RefreshExpirationHours int    `yaml:"refresh_expiration_hours" toml:"refresh_expiration_hours"`
+}
+
+type OIDCConfig struct {
+	Issuer         string `yaml:"issuer" toml:"issuer"`                     // URL pública do serviço, usada como "iss" nos tokens
+	SigningKeyPath string `yaml:"signing_key_path" toml:"signing_key_path"` // Chave RSA (PEM) usada para assinar ID tokens
+	AuthorizeURL   string `yaml:"authorize_url" toml:"authorize_url"`       // página do front-end onde o usuário logado autoriza o client
+}
+
+type FederationConfig struct {
+	Providers []FederatedProviderConfig `yaml:"providers" toml:"providers"`
+}
+
+// FederatedProviderConfig descreve um provedor OIDC externo (Google, Microsoft, ...)
+type FederatedProviderConfig struct {
+	Name         string   `yaml:"name" toml:"name"`
+	Issuer       string   `yaml:"issuer" toml:"issuer"`
+	ClientID     string   `yaml:"client_id" toml:"client_id"`
+	ClientSecret string   `yaml:"client_secret" toml:"client_secret"`
+	RedirectURL  string   `yaml:"redirect_url" toml:"redirect_url"`
+	Scopes       []string `yaml:"scopes" toml:"scopes"`
 }
 
-func Load() *Config {
+// CleanupConfig controla a remoção periódica de tokens expirados ou revogados
+type CleanupConfig struct {
+	Enabled         bool `yaml:"enabled" toml:"enabled"`
+	IntervalMinutes int  `yaml:"interval_minutes" toml:"interval_minutes"`
+	RetentionHours  int  `yaml:"retention_hours" toml:"retention_hours"` // tempo que tokens expirados/revogados permanecem antes da remoção
+	BatchSize       int  `yaml:"batch_size" toml:"batch_size"`
+}
+
+// TracingConfig controla a exportação de spans OpenTelemetry
+type TracingConfig struct {
+	Exporter     string  `yaml:"exporter" toml:"exporter"`           // "none", "stdout", "file" ou "otlp"
+	FilePath     string  `yaml:"file" toml:"file"`                   // Para o exporter "file"
+	OTLPEndpoint string  `yaml:"otlp_endpoint" toml:"otlp_endpoint"` // Para o exporter "otlp" (host:porta do coletor, via HTTP)
+	OTLPInsecure bool    `yaml:"otlp_insecure" toml:"otlp_insecure"`
+	ServiceName  string  `yaml:"service_name" toml:"service_name"`
+	SampleRatio  float64 `yaml:"sample_ratio" toml:"sample_ratio"` // fração dos traces iniciados aqui que são amostrados (0 a 1)
+}
+
+// LoggingConfig controla o logger estruturado
+type LoggingConfig struct {
+	Level  string `yaml:"level" toml:"level"`   // "debug", "info", "warn" ou "error"
+	Format string `yaml:"format" toml:"format"` // "text" ou "json"
+}
+
+// RateLimitConfig controla o rate limiting por rota (token bucket)
+type RateLimitConfig struct {
+	Enabled         bool     `yaml:"enabled" toml:"enabled"`
+	TrustedNetworks []string `yaml:"trusted_networks" toml:"trusted_networks"` // IPs/CIDRs internos isentos do limite de /validate e /introspect
+
+	Login          RateLimitPolicy `yaml:"login" toml:"login"`
+	LoginIP        RateLimitPolicy `yaml:"login_ip" toml:"login_ip"` // teto por IP de /login, somado ao limite por email
+	Register       RateLimitPolicy `yaml:"register" toml:"register"`
+	Refresh        RateLimitPolicy `yaml:"refresh" toml:"refresh"`
+	Validate       RateLimitPolicy `yaml:"validate" toml:"validate"`
+	MagicLink      RateLimitPolicy `yaml:"magic_link" toml:"magic_link"`
+	LinkToken      RateLimitPolicy `yaml:"link_token" toml:"link_token"` // consumo dos tokens enviados por email (magic link, denúncia, redefinição)
+	DeviceAuth     RateLimitPolicy `yaml:"device_auth" toml:"device_auth"`
+	PasswordReset  RateLimitPolicy `yaml:"password_reset" toml:"password_reset"`
+	PasswordChange RateLimitPolicy `yaml:"password_change" toml:"password_change"`
+	DeviceToken    RateLimitPolicy `yaml:"device_token" toml:"device_token"`
+}
+
+// RateLimitPolicy define um token bucket: Requests fichas repostas a cada PeriodSeconds,
+// com capacidade Burst, separado por IP, email ou usuário autenticado. O email só serve de
+// chave em rotas que recebem o email; nas demais, trocá-lo daria um bucket novo a cada
+// tentativa. O client_id também não serve: ele vem da requisição sem verificação.
+type RateLimitPolicy struct {
+	Requests      int    `yaml:"requests" toml:"requests"`
+	PeriodSeconds int    `yaml:"period_seconds" toml:"period_seconds"`
+	Burst         int    `yaml:"burst" toml:"burst"`
+	Key           string `yaml:"key" toml:"key"` // "ip", "email" ou "user"
+}
+
+// Policies retorna as políticas indexadas pelo nome usado nas rotas
+func (c RateLimitConfig) Policies() map[string]RateLimitPolicy {
+	return map[string]RateLimitPolicy{
+		"login":           c.Login,
+		"login_ip":        c.LoginIP,
+		"register":        c.Register,
+		"refresh":         c.Refresh,
+		"validate":        c.Validate,
+		"magic_link":      c.MagicLink,
+		"link_token":      c.LinkToken,
+		"device_auth":     c.DeviceAuth,
+		"password_reset":  c.PasswordReset,
+		"password_change": c.PasswordChange,
+		"device_token":    c.DeviceToken,
+	}
+}
+
+// GRPCConfig controla a API gRPC interna, servida em uma porta separada da HTTP
+type GRPCConfig struct {
+	Enabled   bool   `yaml:"enabled" toml:"enabled"`
+	Port      string `yaml:"port" toml:"port"`
+	AuthToken string `yaml:"auth_token" toml:"auth_token"` // se definido, exigido como "authorization: Bearer <token>" nas chamadas
+}
+
+// MailerConfig controla o envio de emails. O driver "log" apenas registra as mensagens
+// no log, para desenvolvimento; "smtp" envia pelo servidor configurado.
+type MailerConfig struct {
+	Driver       string `yaml:"driver" toml:"driver"` // "log" ou "smtp"
+	From         string `yaml:"from" toml:"from"`
+	SMTPHost     string `yaml:"smtp_host" toml:"smtp_host"`
+	SMTPPort     string `yaml:"smtp_port" toml:"smtp_port"`
+	SMTPUsername string `yaml:"smtp_username" toml:"smtp_username"`
+	SMTPPassword string `yaml:"smtp_password" toml:"smtp_password"`
+}
+
+// MagicLinkConfig controla o login sem senha por link enviado por email
+type MagicLinkConfig struct {
+	TTLMinutes int    `yaml:"ttl_minutes" toml:"ttl_minutes"`
+	LinkURL    string `yaml:"link_url" toml:"link_url"` // página do front-end que recebe ?token= e chama o endpoint de consumo
+}
+
+// DeviceAuthConfig controla a autorização de dispositivos (RFC 8628), usada por clientes
+// de terminal que não recebem a senha do usuário
+type DeviceAuthConfig struct {
+	TTLMinutes      int    `yaml:"ttl_minutes" toml:"ttl_minutes"`
+	IntervalSeconds int    `yaml:"interval_seconds" toml:"interval_seconds"` // intervalo mínimo entre consultas ao /oauth/token
+	VerificationURL string `yaml:"verification_url" toml:"verification_url"` // página do front-end onde o usuário digita o código
+}
+
+// Modos de cadastro aceitos em RegistrationConfig.Mode
+const (
+	RegistrationOpen            = "open"
+	RegistrationDomainAllowlist = "domain_allowlist"
+	RegistrationInviteOnly      = "invite_only"
+)
+
+// RegistrationConfig controla quem pode criar contas. Um convite válido é aceito em
+// qualquer modo e dispensa a verificação de domínio.
+type RegistrationConfig struct {
+	Mode           string   `yaml:"mode" toml:"mode"`                       // "open", "domain_allowlist" ou "invite_only"
+	AllowedDomains []string `yaml:"allowed_domains" toml:"allowed_domains"` // para domain_allowlist, ex: ["universidade.edu.br"]
+}
+
+// SessionConfig controla o cookie do refresh token no modo de sessão do navegador, em que
+// o refresh token fica em um cookie HttpOnly em vez de ir no corpo da resposta
+type SessionConfig struct {
+	CookiePath   string `yaml:"cookie_path" toml:"cookie_path"`     // o cookie só é enviado para a rota de refresh
+	CookieDomain string `yaml:"cookie_domain" toml:"cookie_domain"` // vazio: apenas o host do auth-service
+	SameSite     string `yaml:"same_site" toml:"same_site"`         // "strict", "lax" ou "none"
+	Secure       bool   `yaml:"secure" toml:"secure"`
+}
+
+// WebhooksConfig controla o dispatcher que entrega os eventos do outbox aos webhooks
+type WebhooksConfig struct {
+	Enabled             bool `yaml:"enabled" toml:"enabled"` // sem o dispatcher os eventos continuam sendo gravados no outbox
+	PollIntervalSeconds int  `yaml:"poll_interval_seconds" toml:"poll_interval_seconds"`
+	BatchSize           int  `yaml:"batch_size" toml:"batch_size"`
+	TimeoutSeconds      int  `yaml:"timeout_seconds" toml:"timeout_seconds"` // tempo máximo de cada chamada ao webhook
+	MaxAttempts         int  `yaml:"max_attempts" toml:"max_attempts"`       // depois disso a entrega fica como failed
+	BackoffBaseSeconds  int  `yaml:"backoff_base_seconds" toml:"backoff_base_seconds"`
+	BackoffMaxSeconds   int  `yaml:"backoff_max_seconds" toml:"backoff_max_seconds"`
+	// Permite webhooks em loopback e redes privadas; apenas para desenvolvimento
+	AllowPrivateNetworks bool `yaml:"allow_private_networks" toml:"allow_private_networks"`
+}
+
+// PasswordResetConfig controla os links para redefinir a senha, enviados a pedido do
+// usuário ou aos usuários importados sem senha
+type PasswordResetConfig struct {
+	TTLMinutes     int    `yaml:"ttl_minutes" toml:"ttl_minutes"`
+	InviteTTLHours int    `yaml:"invite_ttl_hours" toml:"invite_ttl_hours"` // validade do link enviado aos usuários importados
+	LinkURL        string `yaml:"link_url" toml:"link_url"`                 // página do front-end que recebe ?token= e chama o endpoint de redefinição
+}
+
+// LoginAlertsConfig controla o aviso por email quando a conta é acessada de um dispositivo
+// novo, com o link "não fui eu" que encerra as sessões e exige uma nova senha
+type LoginAlertsConfig struct {
+	Enabled        bool   `yaml:"enabled" toml:"enabled"`
+	ReportTTLHours int    `yaml:"report_ttl_hours" toml:"report_ttl_hours"` // validade do link "não fui eu"
+	ReportURL      string `yaml:"report_url" toml:"report_url"`             // página do front-end que recebe ?token= e chama o endpoint de denúncia
+}
+
+// PasswordsConfig controla a troca de senhas. A senha atual nunca pode ser repetida; além
+// dela, as últimas HistorySize senhas substituídas ficam guardadas e também são recusadas.
+type PasswordsConfig struct {
+	HistorySize int `yaml:"history_size" toml:"history_size"` // 0 desativa o histórico
+}
+
+// Load carrega a configuração do arquivo indicado em CONFIG_FILE, se houver
+func Load() (*Config, error) {
+	return LoadFrom(os.Getenv("CONFIG_FILE"))
+}
+
+// LoadFrom monta a configuração em camadas: valores padrão, depois o arquivo
+// YAML/TOML em path (opcional) e, por cima, as variáveis de ambiente.
+// A configuração resultante é validada antes de ser retornada.
+func LoadFrom(path string) (*Config, error) {
 	if err := godotenv.Load(); err != nil {
-		log.Println("Arquivo .env não encontrado, usando variáveis de ambiente do sistema")
+		slog.Info("Arquivo .env não encontrado, usando variáveis de ambiente do sistema")
+	}
+
+	cfg := defaults()
+
+	if path != "" {
+		if err := loadFile(cfg, path); err != nil {
+			return nil, err
+		}
+	}
+
+	applyEnv(cfg)
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
 	}
 
+	return cfg, nil
+}
+
+func defaults() *Config {
 	return &Config{
 		Server: ServerConfig{
-			Port: getEnv("PORT", "8080"),
-			Env:  getEnv("ENV", "development"),
+			Port: "8080",
+			Env:  "development",
 		},
 		Database: DatabaseConfig{
-			Type:     getEnv("DB_TYPE", "sqlite"),
-			Path:     getEnv("DB_PATH", "auth_service.db"),
-			Host:     getEnv("DB_HOST", "localhost"),
-			Port:     getEnv("DB_PORT", "3306"),
-			User:     getEnv("DB_USER", "root"),
-			Password: getEnv("DB_PASSWORD", ""),
-			Name:     getEnv("DB_NAME", "auth_service"),
-			SSLMode:  getEnv("DB_SSL_MODE", "disable"),
+			Type:    "sqlite",
+			Path:    "auth_service.db",
+			Host:    "localhost",
+			Port:    "3306",
+			User:    "root",
+			Name:    "auth_service",
+			SSLMode: "disable",
 		},
 		JWT: JWTConfig{
-			Secret:                 getEnv("JWT_SECRET", "your-super-secret-jwt-key-change-in-production"),
-			ExpirationHours:        getEnvAsInt("JWT_EXPIRATION_HOURS", 24),
-			RefreshExpirationHours: getEnvAsInt("JWT_REFRESH_EXPIRATION_HOURS", 168),
+			Secret:                 defaultJWTSecret,
+			ExpirationHours:        24,
+			RefreshExpirationHours: 168,
+		},
+		OIDC: OIDCConfig{
+			AuthorizeURL: "http://localhost:4200/authorize",
+		},
+		Cleanup: CleanupConfig{
+			Enabled:         true,
+			IntervalMinutes: 60,
+			RetentionHours:  24,
+			BatchSize:       500,
+		},
+		Tracing: TracingConfig{
+			Exporter:     "none",
+			FilePath:     "traces.json",
+			OTLPEndpoint: "localhost:4318",
+			OTLPInsecure: true,
+			ServiceName:  "auth-service",
+			SampleRatio:  1,
+		},
+		Logging: LoggingConfig{
+			Level:  "info",
+			Format: "text",
+		},
+		RateLimit: RateLimitConfig{
+			// Loopback e redes privadas: o study manager e outros serviços internos
+			TrustedNetworks: []string{"127.0.0.0/8", "::1/128", "10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "fc00::/7"},
+			Enabled:         true,
+			Login:           RateLimitPolicy{Requests: 5, PeriodSeconds: 60, Burst: 10, Key: "email"},
+			LoginIP:         RateLimitPolicy{Requests: 30, PeriodSeconds: 60, Burst: 30, Key: "ip"},
+			Register:        RateLimitPolicy{Requests: 10, PeriodSeconds: 3600, Burst: 5, Key: "ip"},
+			Refresh:         RateLimitPolicy{Requests: 30, PeriodSeconds: 60, Burst: 30, Key: "ip"},
+			Validate:        RateLimitPolicy{Requests: 600, PeriodSeconds: 60, Burst: 100, Key: "ip"},
+			MagicLink:       RateLimitPolicy{Requests: 5, PeriodSeconds: 3600, Burst: 3, Key: "email"},
+			LinkToken:       RateLimitPolicy{Requests: 10, PeriodSeconds: 60, Burst: 10, Key: "ip"},
+			DeviceAuth:      RateLimitPolicy{Requests: 10, PeriodSeconds: 60, Burst: 10, Key: "ip"},
+			PasswordReset:   RateLimitPolicy{Requests: 5, PeriodSeconds: 3600, Burst: 3, Key: "email"},
+			PasswordChange:  RateLimitPolicy{Requests: 5, PeriodSeconds: 60, Burst: 5, Key: "user"},
+			DeviceToken:     RateLimitPolicy{Requests: 30, PeriodSeconds: 60, Burst: 30, Key: "ip"},
+		},
+		GRPC: GRPCConfig{
+			Enabled: true,
+			Port:    "9090",
+		},
+		Mailer: MailerConfig{
+			Driver:   "log",
+			From:     "no-reply@localhost",
+			SMTPPort: "587",
+		},
+		MagicLink: MagicLinkConfig{
+			TTLMinutes: 15,
+			LinkURL:    "http://localhost:4200/login/magic-link",
+		},
+		DeviceAuth: DeviceAuthConfig{
+			TTLMinutes:      10,
+			IntervalSeconds: 5,
+			VerificationURL: "http://localhost:4200/device",
+		},
+		Registration: RegistrationConfig{
+			Mode: RegistrationOpen,
+		},
+		Session: SessionConfig{
+			CookiePath: "/api/v1/refresh",
+			SameSite:   "strict",
+			Secure:     true,
+		},
+		Webhooks: WebhooksConfig{
+			Enabled:             true,
+			PollIntervalSeconds: 5,
+			BatchSize:           50,
+			TimeoutSeconds:      10,
+			MaxAttempts:         8,
+			BackoffBaseSeconds:  30,
+			BackoffMaxSeconds:   3600,
+		},
+		PasswordReset: PasswordResetConfig{
+			TTLMinutes:     30,
+			InviteTTLHours: 72,
+			LinkURL:        "http://localhost:4200/reset-password",
+		},
+		LoginAlerts: LoginAlertsConfig{
+			Enabled:        true,
+			ReportTTLHours: 168,
+			ReportURL:      "http://localhost:4200/login/report",
+		},
+		Passwords: PasswordsConfig{
+			HistorySize: 5,
 		},
 	}
 }
 
+// loadFile aplica sobre cfg os valores do arquivo; o formato é escolhido pela extensão.
+// Chaves desconhecidas são rejeitadas para que erros de digitação não passem despercebidos.
+func loadFile(cfg *Config, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("erro ao ler arquivo de configuração: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(cfg)
+	case ".toml":
+		decoder := toml.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(cfg)
+	default:
+		return fmt.Errorf("formato de arquivo de configuração não suportado: %s (use .yaml, .yml ou .toml)", path)
+	}
+	if err != nil {
+		return fmt.Errorf("erro ao interpretar %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// applyEnv sobrepõe as variáveis de ambiente definidas aos valores já carregados. Valores
+// que não puderam ser interpretados mantêm o valor anterior e ficam em cfg.envErrors.
+func applyEnv(cfg *Config) {
+	env := &envReader{}
+	defer func() { cfg.envErrors = env.errs }()
+
+	cfg.Server.Port = getEnv("PORT", cfg.Server.Port)
+	cfg.Server.Env = getEnv("ENV", cfg.Server.Env)
+	cfg.Server.ShutdownDelaySeconds = env.getInt("SHUTDOWN_DELAY_SECONDS", cfg.Server.ShutdownDelaySeconds)
+	if proxies := getEnv("TRUSTED_PROXIES", ""); proxies != "" {
+		cfg.Server.TrustedProxies = strings.Split(strings.ReplaceAll(proxies, " ", ""), ",")
+	}
+
+	cfg.Database.Type = getEnv("DB_TYPE", cfg.Database.Type)
+	cfg.Database.Path = getEnv("DB_PATH", cfg.Database.Path)
+	cfg.Database.Host = getEnv("DB_HOST", cfg.Database.Host)
+	cfg.Database.Port = getEnv("DB_PORT", cfg.Database.Port)
+	cfg.Database.User = getEnv("DB_USER", cfg.Database.User)
+	cfg.Database.Password = getEnv("DB_PASSWORD", cfg.Database.Password)
+	cfg.Database.Name = getEnv("DB_NAME", cfg.Database.Name)
+	cfg.Database.SSLMode = getEnv("DB_SSL_MODE", cfg.Database.SSLMode)
+
+	cfg.JWT.Secret = getEnv("JWT_SECRET", cfg.JWT.Secret)
+	cfg.JWT.ExpirationHours = env.getInt("JWT_EXPIRATION_HOURS", cfg.JWT.ExpirationHours)
+	cfg.JWT.RefreshExpirationHours = env.getInt("JWT_REFRESH_EXPIRATION_HOURS", cfg.JWT.RefreshExpirationHours)
+
+	cfg.OIDC.Issuer = getEnv("OIDC_ISSUER", cfg.OIDC.Issuer)
+	if cfg.OIDC.Issuer == "" {
+		cfg.OIDC.Issuer = "http://localhost:" + cfg.Server.Port
+	}
+	cfg.OIDC.Issuer = strings.TrimSuffix(cfg.OIDC.Issuer, "/")
+	cfg.OIDC.SigningKeyPath = getEnv("OIDC_SIGNING_KEY_PATH", cfg.OIDC.SigningKeyPath)
+	cfg.OIDC.AuthorizeURL = getEnv("OIDC_AUTHORIZE_URL", cfg.OIDC.AuthorizeURL)
+
+	if os.Getenv("FEDERATION_PROVIDERS") != "" {
+		cfg.Federation.Providers = loadFederatedProviders()
+	}
+	for i := range cfg.Federation.Providers {
+		provider := &cfg.Federation.Providers[i]
+		provider.Name = strings.ToLower(provider.Name)
+		provider.Issuer = strings.TrimSuffix(provider.Issuer, "/")
+		if provider.RedirectURL == "" {
+			provider.RedirectURL = cfg.OIDC.Issuer + "/api/v1/federation/" + provider.Name + "/callback"
+		}
+		if len(provider.Scopes) == 0 {
+			provider.Scopes = []string{"openid", "email", "profile"}
+		}
+	}
+
+	cfg.Cleanup.Enabled = env.getBool("CLEANUP_ENABLED", cfg.Cleanup.Enabled)
+	cfg.Cleanup.IntervalMinutes = env.getInt("CLEANUP_INTERVAL_MINUTES", cfg.Cleanup.IntervalMinutes)
+	cfg.Cleanup.RetentionHours = env.getInt("CLEANUP_RETENTION_HOURS", cfg.Cleanup.RetentionHours)
+	cfg.Cleanup.BatchSize = env.getInt("CLEANUP_BATCH_SIZE", cfg.Cleanup.BatchSize)
+
+	cfg.Tracing.Exporter = getEnv("TRACING_EXPORTER", cfg.Tracing.Exporter)
+	cfg.Tracing.FilePath = getEnv("TRACING_FILE", cfg.Tracing.FilePath)
+	cfg.Tracing.OTLPEndpoint = getEnv("TRACING_OTLP_ENDPOINT", cfg.Tracing.OTLPEndpoint)
+	cfg.Tracing.OTLPInsecure = env.getBool("TRACING_OTLP_INSECURE", cfg.Tracing.OTLPInsecure)
+	cfg.Tracing.ServiceName = getEnv("TRACING_SERVICE_NAME", cfg.Tracing.ServiceName)
+	cfg.Tracing.SampleRatio = env.getFloat("TRACING_SAMPLE_RATIO", cfg.Tracing.SampleRatio)
+
+	cfg.Logging.Level = getEnv("LOG_LEVEL", cfg.Logging.Level)
+	cfg.Logging.Format = getEnv("LOG_FORMAT", cfg.Logging.Format)
+
+	cfg.RateLimit.Enabled = env.getBool("RATE_LIMIT_ENABLED", cfg.RateLimit.Enabled)
+	if networks := getEnv("RATE_LIMIT_TRUSTED_NETWORKS", ""); networks != "" {
+		cfg.RateLimit.TrustedNetworks = strings.Split(networks, ",")
+	}
+	applyRateLimitEnv(env, "LOGIN", &cfg.RateLimit.Login)
+	applyRateLimitEnv(env, "LOGIN_IP", &cfg.RateLimit.LoginIP)
+	applyRateLimitEnv(env, "REGISTER", &cfg.RateLimit.Register)
+	applyRateLimitEnv(env, "REFRESH", &cfg.RateLimit.Refresh)
+	applyRateLimitEnv(env, "VALIDATE", &cfg.RateLimit.Validate)
+	applyRateLimitEnv(env, "MAGIC_LINK", &cfg.RateLimit.MagicLink)
+	applyRateLimitEnv(env, "LINK_TOKEN", &cfg.RateLimit.LinkToken)
+	applyRateLimitEnv(env, "DEVICE_AUTH", &cfg.RateLimit.DeviceAuth)
+	applyRateLimitEnv(env, "PASSWORD_RESET", &cfg.RateLimit.PasswordReset)
+	applyRateLimitEnv(env, "PASSWORD_CHANGE", &cfg.RateLimit.PasswordChange)
+	applyRateLimitEnv(env, "DEVICE_TOKEN", &cfg.RateLimit.DeviceToken)
+
+	cfg.GRPC.Enabled = env.getBool("GRPC_ENABLED", cfg.GRPC.Enabled)
+	cfg.GRPC.Port = getEnv("GRPC_PORT", cfg.GRPC.Port)
+	cfg.GRPC.AuthToken = getEnv("GRPC_AUTH_TOKEN", cfg.GRPC.AuthToken)
+
+	cfg.Mailer.Driver = getEnv("MAILER_DRIVER", cfg.Mailer.Driver)
+	cfg.Mailer.From = getEnv("MAILER_FROM", cfg.Mailer.From)
+	cfg.Mailer.SMTPHost = getEnv("SMTP_HOST", cfg.Mailer.SMTPHost)
+	cfg.Mailer.SMTPPort = getEnv("SMTP_PORT", cfg.Mailer.SMTPPort)
+	cfg.Mailer.SMTPUsername = getEnv("SMTP_USERNAME", cfg.Mailer.SMTPUsername)
+	cfg.Mailer.SMTPPassword = getEnv("SMTP_PASSWORD", cfg.Mailer.SMTPPassword)
+
+	cfg.MagicLink.TTLMinutes = env.getInt("MAGIC_LINK_TTL_MINUTES", cfg.MagicLink.TTLMinutes)
+	cfg.MagicLink.LinkURL = getEnv("MAGIC_LINK_URL", cfg.MagicLink.LinkURL)
+
+	cfg.DeviceAuth.TTLMinutes = env.getInt("DEVICE_AUTH_TTL_MINUTES", cfg.DeviceAuth.TTLMinutes)
+	cfg.DeviceAuth.IntervalSeconds = env.getInt("DEVICE_AUTH_INTERVAL_SECONDS", cfg.DeviceAuth.IntervalSeconds)
+	cfg.DeviceAuth.VerificationURL = getEnv("DEVICE_AUTH_VERIFICATION_URL", cfg.DeviceAuth.VerificationURL)
+
+	cfg.Registration.Mode = getEnv("REGISTRATION_MODE", cfg.Registration.Mode)
+	if domains := getEnv("REGISTRATION_ALLOWED_DOMAINS", ""); domains != "" {
+		cfg.Registration.AllowedDomains = strings.Split(domains, ",")
+	}
+
+	cfg.Session.CookiePath = getEnv("SESSION_COOKIE_PATH", cfg.Session.CookiePath)
+	cfg.Session.CookieDomain = getEnv("SESSION_COOKIE_DOMAIN", cfg.Session.CookieDomain)
+	cfg.Session.SameSite = getEnv("SESSION_COOKIE_SAMESITE", cfg.Session.SameSite)
+	cfg.Session.Secure = env.getBool("SESSION_COOKIE_SECURE", cfg.Session.Secure)
+
+	cfg.Webhooks.Enabled = env.getBool("WEBHOOKS_ENABLED", cfg.Webhooks.Enabled)
+	cfg.Webhooks.PollIntervalSeconds = env.getInt("WEBHOOKS_POLL_INTERVAL_SECONDS", cfg.Webhooks.PollIntervalSeconds)
+	cfg.Webhooks.BatchSize = env.getInt("WEBHOOKS_BATCH_SIZE", cfg.Webhooks.BatchSize)
+	cfg.Webhooks.TimeoutSeconds = env.getInt("WEBHOOKS_TIMEOUT_SECONDS", cfg.Webhooks.TimeoutSeconds)
+	cfg.Webhooks.MaxAttempts = env.getInt("WEBHOOKS_MAX_ATTEMPTS", cfg.Webhooks.MaxAttempts)
+	cfg.Webhooks.BackoffBaseSeconds = env.getInt("WEBHOOKS_BACKOFF_BASE_SECONDS", cfg.Webhooks.BackoffBaseSeconds)
+	cfg.Webhooks.BackoffMaxSeconds = env.getInt("WEBHOOKS_BACKOFF_MAX_SECONDS", cfg.Webhooks.BackoffMaxSeconds)
+	cfg.Webhooks.AllowPrivateNetworks = env.getBool("WEBHOOKS_ALLOW_PRIVATE_NETWORKS", cfg.Webhooks.AllowPrivateNetworks)
+
+	cfg.PasswordReset.TTLMinutes = env.getInt("PASSWORD_RESET_TTL_MINUTES", cfg.PasswordReset.TTLMinutes)
+	cfg.PasswordReset.InviteTTLHours = env.getInt("PASSWORD_RESET_INVITE_TTL_HOURS", cfg.PasswordReset.InviteTTLHours)
+	cfg.PasswordReset.LinkURL = getEnv("PASSWORD_RESET_URL", cfg.PasswordReset.LinkURL)
+
+	cfg.LoginAlerts.Enabled = env.getBool("LOGIN_ALERTS_ENABLED", cfg.LoginAlerts.Enabled)
+	cfg.LoginAlerts.ReportTTLHours = env.getInt("LOGIN_ALERTS_REPORT_TTL_HOURS", cfg.LoginAlerts.ReportTTLHours)
+	cfg.LoginAlerts.ReportURL = getEnv("LOGIN_ALERTS_REPORT_URL", cfg.LoginAlerts.ReportURL)
+
+	cfg.Passwords.HistorySize = env.getInt("PASSWORD_HISTORY_SIZE", cfg.Passwords.HistorySize)
+}
+
+// applyRateLimitEnv lê RATE_LIMIT_<ROTA>_REQUESTS, _PERIOD_SECONDS, _BURST e _KEY
+func applyRateLimitEnv(env *envReader, name string, policy *RateLimitPolicy) {
+	prefix := "RATE_LIMIT_" + name + "_"
+	policy.Requests = env.getInt(prefix+"REQUESTS", policy.Requests)
+	policy.PeriodSeconds = env.getInt(prefix+"PERIOD_SECONDS", policy.PeriodSeconds)
+	policy.Burst = env.getInt(prefix+"BURST", policy.Burst)
+	policy.Key = getEnv(prefix+"KEY", policy.Key)
+}
+
+// loadFederatedProviders lê FEDERATION_PROVIDERS (ex: "google,microsoft") e,
+// para cada nome, as variáveis FEDERATION_<NOME>_ISSUER, _CLIENT_ID, _CLIENT_SECRET,
+// _REDIRECT_URL e _SCOPES
+func loadFederatedProviders() []FederatedProviderConfig {
+	var providers []FederatedProviderConfig
+
+	for _, name := range strings.Split(getEnv("FEDERATION_PROVIDERS", ""), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		prefix := "FEDERATION_" + strings.ToUpper(name) + "_"
+		providers = append(providers, FederatedProviderConfig{
+			Name:         name,
+			Issuer:       getEnv(prefix+"ISSUER", ""),
+			ClientID:     getEnv(prefix+"CLIENT_ID", ""),
+			ClientSecret: getEnv(prefix+"CLIENT_SECRET", ""),
+			RedirectURL:  getEnv(prefix+"REDIRECT_URL", ""),
+			Scopes:       strings.Fields(getEnv(prefix+"SCOPES", "")),
+		})
+	}
+
+	return providers
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -71,11 +585,47 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
-func getEnvAsInt(key string, defaultValue int) int {
-	if value := os.Getenv(key); value != "" {
-		if intValue, err := strconv.Atoi(value); err == nil {
-			return intValue
-		}
+// envReader lê as variáveis de ambiente numéricas e booleanas, acumulando as que não
+// puderam ser interpretadas em vez de ignorá-las
+type envReader struct {
+	errs []error
+}
+
+func (r *envReader) getInt(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
 	}
-	return defaultValue
+	intValue, err := strconv.Atoi(value)
+	if err != nil {
+		r.errs = append(r.errs, fmt.Errorf("%s deve ser um número inteiro: %q", key, value))
+		return defaultValue
+	}
+	return intValue
+}
+
+func (r *envReader) getBool(key string, defaultValue bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	boolValue, err := strconv.ParseBool(value)
+	if err != nil {
+		r.errs = append(r.errs, fmt.Errorf("%s deve ser true ou false: %q", key, value))
+		return defaultValue
+	}
+	return boolValue
+}
+
+func (r *envReader) getFloat(key string, defaultValue float64) float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	floatValue, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		r.errs = append(r.errs, fmt.Errorf("%s deve ser um número: %q", key, value))
+		return defaultValue
+	}
+	return floatValue
 }
@@ -0,0 +1,39 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadReportsUnparsableEnv(t *testing.T) {
+	t.Setenv("WEBHOOKS_BATCH_SIZE", "cinquenta")
+	t.Setenv("WEBHOOKS_ENABLED", "sim")
+	t.Setenv("RATE_LIMIT_LOGIN_BURST", "10x")
+	t.Setenv("RATE_LIMIT_LOGIN_REQUESTS", "")
+
+	_, err := LoadFrom("")
+	if err == nil {
+		t.Fatal("configuração com variáveis inválidas foi aceita")
+	}
+	for _, key := range []string{"WEBHOOKS_BATCH_SIZE", "WEBHOOKS_ENABLED", "RATE_LIMIT_LOGIN_BURST"} {
+		if !strings.Contains(err.Error(), key) {
+			t.Errorf("erro não menciona %s: %v", key, err)
+		}
+	}
+	if strings.Contains(err.Error(), "RATE_LIMIT_LOGIN_REQUESTS") {
+		t.Errorf("variável vazia deve manter o valor anterior: %v", err)
+	}
+}
+
+func TestLoadAcceptsValidEnv(t *testing.T) {
+	t.Setenv("WEBHOOKS_BATCH_SIZE", "25")
+	t.Setenv("WEBHOOKS_ENABLED", "false")
+
+	cfg, err := LoadFrom("")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if cfg.Webhooks.BatchSize != 25 || cfg.Webhooks.Enabled {
+		t.Errorf("webhooks = %+v", cfg.Webhooks)
+	}
+}
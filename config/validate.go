@@ -0,0 +1,231 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// minJWTSecretLength é o tamanho mínimo do segredo HMAC (256 bits)
+const minJWTSecretLength = 32
+
+// redacted substitui segredos na saída de --print-config
+const redacted = "[REDACTED]"
+
+// IsProduction indica se o serviço está rodando em produção
+func (c *Config) IsProduction() bool {
+	return strings.EqualFold(c.Server.Env, "production")
+}
+
+// Validate verifica a configuração inteira e retorna todos os problemas encontrados de uma
+// vez, começando pelas variáveis de ambiente com valores inválidos
+func (c *Config) Validate() error {
+	errs := slices.Clone(c.envErrors)
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(validPort(c.Server.Port), "server.port inválida: %q", c.Server.Port)
+	check(c.Server.ShutdownDelaySeconds >= 0, "server.shutdown_delay_seconds não pode ser negativo")
+	for _, proxy := range c.Server.TrustedProxies {
+		check(validNetwork(proxy), "server.trusted_proxies: IP ou CIDR inválido: %q", proxy)
+	}
+
+	switch c.Database.Type {
+	case "sqlite":
+		check(c.Database.Path != "", "database.path é obrigatório para SQLite")
+	case "mysql":
+		check(c.Database.Host != "", "database.host é obrigatório para MySQL")
+		check(validPort(c.Database.Port), "database.port inválida: %q", c.Database.Port)
+		check(c.Database.Name != "", "database.name é obrigatório para MySQL")
+	default:
+		check(false, "database.type desconhecido: %q (use sqlite ou mysql)", c.Database.Type)
+	}
+
+	check(len(c.JWT.Secret) >= minJWTSecretLength, "jwt.secret deve ter pelo menos %d caracteres", minJWTSecretLength)
+	check(c.JWT.ExpirationHours > 0, "jwt.expiration_hours deve ser positivo")
+	check(c.JWT.RefreshExpirationHours > 0, "jwt.refresh_expiration_hours deve ser positivo")
+
+	check(validURL(c.OIDC.Issuer), "oidc.issuer deve ser uma URL http(s) absoluta: %q", c.OIDC.Issuer)
+	check(validURL(c.OIDC.AuthorizeURL), "oidc.authorize_url deve ser uma URL http(s) absoluta: %q", c.OIDC.AuthorizeURL)
+
+	seen := make(map[string]bool)
+	for _, provider := range c.Federation.Providers {
+		check(provider.Name != "", "federation.providers: provedor sem nome")
+		check(!seen[provider.Name], "federation.providers: provedor %q duplicado", provider.Name)
+		check(validURL(provider.Issuer), "federation.providers[%s].issuer deve ser uma URL http(s) absoluta", provider.Name)
+		check(provider.ClientID != "", "federation.providers[%s].client_id é obrigatório", provider.Name)
+		seen[provider.Name] = true
+	}
+
+	if c.Cleanup.Enabled {
+		check(c.Cleanup.IntervalMinutes > 0, "cleanup.interval_minutes deve ser positivo")
+		check(c.Cleanup.BatchSize > 0, "cleanup.batch_size deve ser positivo")
+	}
+	check(c.Cleanup.RetentionHours >= 0, "cleanup.retention_hours não pode ser negativo")
+
+	switch c.Tracing.Exporter {
+	case "none", "stdout", "otlp":
+	case "file":
+		check(c.Tracing.FilePath != "", "tracing.file é obrigatório para o exporter file")
+	default:
+		check(false, "tracing.exporter desconhecido: %q (use none, stdout, file ou otlp)", c.Tracing.Exporter)
+	}
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "tracing.sample_ratio deve estar entre 0 e 1")
+
+	switch strings.ToLower(c.Logging.Level) {
+	case "debug", "info", "warn", "error":
+	default:
+		check(false, "logging.level desconhecido: %q (use debug, info, warn ou error)", c.Logging.Level)
+	}
+	switch strings.ToLower(c.Logging.Format) {
+	case "text", "json":
+	default:
+		check(false, "logging.format desconhecido: %q (use text ou json)", c.Logging.Format)
+	}
+
+	policies := c.RateLimit.Policies()
+	names := make([]string, 0, len(policies))
+	for name := range policies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		policy := policies[name]
+		check(policy.Requests > 0, "rate_limit.%s.requests deve ser positivo", name)
+		check(policy.PeriodSeconds > 0, "rate_limit.%s.period_seconds deve ser positivo", name)
+		check(policy.Burst > 0, "rate_limit.%s.burst deve ser positivo", name)
+		switch policy.Key {
+		case "ip", "email", "user":
+		default:
+			check(false, "rate_limit.%s.key desconhecida: %q (use ip, email ou user)", name, policy.Key)
+		}
+	}
+	for _, network := range c.RateLimit.TrustedNetworks {
+		check(validNetwork(network), "rate_limit.trusted_networks: IP ou CIDR inválido: %q", network)
+	}
+
+	if c.GRPC.Enabled {
+		check(validPort(c.GRPC.Port), "grpc.port inválida: %q", c.GRPC.Port)
+		check(c.GRPC.Port != c.Server.Port, "grpc.port deve ser diferente de server.port")
+	}
+
+	switch c.Mailer.Driver {
+	case "log":
+	case "smtp":
+		check(c.Mailer.SMTPHost != "", "mailer.smtp_host é obrigatório para o driver smtp")
+		check(validPort(c.Mailer.SMTPPort), "mailer.smtp_port inválida: %q", c.Mailer.SMTPPort)
+	default:
+		check(false, "mailer.driver desconhecido: %q (use log ou smtp)", c.Mailer.Driver)
+	}
+	check(strings.Contains(c.Mailer.From, "@"), "mailer.from deve ser um endereço de email: %q", c.Mailer.From)
+
+	check(c.MagicLink.TTLMinutes > 0, "magic_link.ttl_minutes deve ser positivo")
+	check(validURL(c.MagicLink.LinkURL), "magic_link.link_url deve ser uma URL http(s) absoluta: %q", c.MagicLink.LinkURL)
+
+	check(c.DeviceAuth.TTLMinutes > 0, "device_auth.ttl_minutes deve ser positivo")
+	check(c.DeviceAuth.IntervalSeconds > 0, "device_auth.interval_seconds deve ser positivo")
+	check(validURL(c.DeviceAuth.VerificationURL), "device_auth.verification_url deve ser uma URL http(s) absoluta: %q", c.DeviceAuth.VerificationURL)
+
+	switch c.Registration.Mode {
+	case RegistrationOpen, RegistrationInviteOnly:
+	case RegistrationDomainAllowlist:
+		check(len(c.Registration.AllowedDomains) > 0, "registration.allowed_domains é obrigatório no modo domain_allowlist")
+	default:
+		check(false, "registration.mode desconhecido: %q (use open, domain_allowlist ou invite_only)", c.Registration.Mode)
+	}
+	for _, domain := range c.Registration.AllowedDomains {
+		domain = strings.TrimSpace(domain)
+		check(domain != "" && !strings.Contains(domain, "@"), "registration.allowed_domains: domínio inválido: %q", domain)
+	}
+
+	check(strings.HasPrefix(c.Session.CookiePath, "/"), "session.cookie_path deve começar com /: %q", c.Session.CookiePath)
+	switch strings.ToLower(c.Session.SameSite) {
+	case "strict", "lax":
+	case "none":
+		// Navegadores descartam cookies SameSite=None sem Secure
+		check(c.Session.Secure, "session.same_site none exige session.secure")
+	default:
+		check(false, "session.same_site desconhecido: %q (use strict, lax ou none)", c.Session.SameSite)
+	}
+
+	check(c.Webhooks.PollIntervalSeconds > 0, "webhooks.poll_interval_seconds deve ser positivo")
+	check(c.Webhooks.BatchSize > 0, "webhooks.batch_size deve ser positivo")
+	check(c.Webhooks.TimeoutSeconds > 0, "webhooks.timeout_seconds deve ser positivo")
+	check(c.Webhooks.MaxAttempts > 0, "webhooks.max_attempts deve ser positivo")
+	check(c.Webhooks.BackoffBaseSeconds > 0, "webhooks.backoff_base_seconds deve ser positivo")
+	check(c.Webhooks.BackoffMaxSeconds >= c.Webhooks.BackoffBaseSeconds, "webhooks.backoff_max_seconds não pode ser menor que backoff_base_seconds")
+
+	check(c.PasswordReset.TTLMinutes > 0, "password_reset.ttl_minutes deve ser positivo")
+	check(c.PasswordReset.InviteTTLHours > 0, "password_reset.invite_ttl_hours deve ser positivo")
+	check(validURL(c.PasswordReset.LinkURL), "password_reset.link_url deve ser uma URL http(s) absoluta: %q", c.PasswordReset.LinkURL)
+
+	check(c.LoginAlerts.ReportTTLHours > 0, "login_alerts.report_ttl_hours deve ser positivo")
+	check(validURL(c.LoginAlerts.ReportURL), "login_alerts.report_url deve ser uma URL http(s) absoluta: %q", c.LoginAlerts.ReportURL)
+
+	check(c.Passwords.HistorySize >= 0 && c.Passwords.HistorySize <= 24, "passwords.history_size deve estar entre 0 e 24")
+
+	// Em produção, recusar configurações que só servem para desenvolvimento
+	if c.IsProduction() {
+		check(c.JWT.Secret != defaultJWTSecret, "jwt.secret está com o valor de exemplo; defina JWT_SECRET em produção")
+		check(c.OIDC.SigningKeyPath != "", "oidc.signing_key_path é obrigatório em produção (chaves efêmeras invalidam os ID tokens a cada reinício)")
+		check(!c.GRPC.Enabled || c.GRPC.AuthToken != "", "grpc.auth_token é obrigatório em produção com a API gRPC habilitada; defina GRPC_AUTH_TOKEN ou desabilite com GRPC_ENABLED=false")
+		check(c.Session.Secure, "session.secure é obrigatório em produção")
+		check(!c.Webhooks.AllowPrivateNetworks, "webhooks.allow_private_networks não é permitido em produção")
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("configuração inválida:\n%w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// Redacted retorna uma cópia da configuração com os segredos mascarados
+func (c *Config) Redacted() *Config {
+	out := *c
+
+	out.Database.Password = redact(c.Database.Password)
+	out.JWT.Secret = redact(c.JWT.Secret)
+	out.GRPC.AuthToken = redact(c.GRPC.AuthToken)
+	out.Mailer.SMTPPassword = redact(c.Mailer.SMTPPassword)
+
+	out.Federation.Providers = make([]FederatedProviderConfig, len(c.Federation.Providers))
+	for i, provider := range c.Federation.Providers {
+		provider.ClientSecret = redact(provider.ClientSecret)
+		out.Federation.Providers[i] = provider
+	}
+
+	return &out
+}
+
+func redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return redacted
+}
+
+func validPort(port string) bool {
+	n, err := strconv.Atoi(port)
+	return err == nil && n > 0 && n <= 65535
+}
+
+func validNetwork(network string) bool {
+	network = strings.TrimSpace(network)
+	if _, _, err := net.ParseCIDR(network); err == nil {
+		return true
+	}
+	return net.ParseIP(network) != nil
+}
+
+func validURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -3,7 +3,7 @@ package database
 import (
 	"database/sql"
 	"fmt"
-	"log"
+	"log/slog"
 	"strings"
 
 	"auth-service/config"
@@ -54,7 +54,7 @@ func NewDatabase(cfg *config.Config) (*Database, error) {
 		return nil, fmt.Errorf("erro ao conectar com banco: %w", err)
 	}
 
-	log.Printf("Conexão com banco de dados %s estabelecida com sucesso", strings.ToUpper(dbType))
+	slog.Info("Conexão com banco de dados estabelecida", "driver", strings.ToUpper(dbType))
 
 	return &Database{DB: db, DBType: dbType}, nil
 }
@@ -74,149 +74,3 @@ func (d *Database) Placeholder(index int) string {
 		return "?"
 	}
 }
-
-// GetDataType retorna o tipo de dados específico para cada banco
-func (d *Database) GetDataType(genericType string) string {
-	switch d.DBType {
-	case "mysql":
-		switch genericType {
-		case "TEXT_ID":
-			return "VARCHAR(36)"
-		case "TEXT":
-			return "TEXT"
-		case "INTEGER":
-			return "INT"
-		case "DATETIME":
-			return "DATETIME"
-		case "BOOLEAN":
-			return "BOOLEAN"
-		default:
-			return genericType
-		}
-	case "sqlite":
-		switch genericType {
-		case "TEXT_ID":
-			return "TEXT"
-		case "TEXT":
-			return "TEXT"
-		case "INTEGER":
-			return "INTEGER"
-		case "DATETIME":
-			return "DATETIME"
-		case "BOOLEAN":
-			return "INTEGER"
-		default:
-			return genericType
-		}
-	default:
-		return genericType
-	}
-}
-
-func (d *Database) InitTables() error {
-	var queries []string
-
-	// Queries específicas para cada tipo de banco
-	if d.DBType == "mysql" {
-		queries = []string{
-			fmt.Sprintf(`CREATE TABLE IF NOT EXISTS users (
-				id %s PRIMARY KEY,
-				email %s UNIQUE NOT NULL,
-				password %s NOT NULL,
-				name %s NOT NULL,
-				active %s DEFAULT 1,
-				created_at %s DEFAULT CURRENT_TIMESTAMP,
-				updated_at %s DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
-			)`, d.GetDataType("TEXT_ID"), d.GetDataType("TEXT"), d.GetDataType("TEXT"),
-				d.GetDataType("TEXT"), d.GetDataType("BOOLEAN"), d.GetDataType("DATETIME"),
-				d.GetDataType("DATETIME")),
-
-			fmt.Sprintf(`CREATE TABLE IF NOT EXISTS clients (
-				id %s PRIMARY KEY,
-				name %s NOT NULL,
-				description %s,
-				secret %s NOT NULL,
-				active %s DEFAULT 1,
-				created_at %s DEFAULT CURRENT_TIMESTAMP,
-				updated_at %s DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
-			)`, d.GetDataType("TEXT_ID"), d.GetDataType("TEXT"), d.GetDataType("TEXT"),
-				d.GetDataType("TEXT"), d.GetDataType("BOOLEAN"), d.GetDataType("DATETIME"),
-				d.GetDataType("DATETIME")),
-
-			fmt.Sprintf(`CREATE TABLE IF NOT EXISTS refresh_tokens (
-				id %s PRIMARY KEY,
-				user_id %s NOT NULL,
-				client_id %s NOT NULL,
-				token %s UNIQUE NOT NULL,
-				expires_at %s NOT NULL,
-				revoked %s DEFAULT 0,
-				created_at %s DEFAULT CURRENT_TIMESTAMP,
-				updated_at %s DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
-				FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
-				FOREIGN KEY (client_id) REFERENCES clients(id) ON DELETE CASCADE
-			)`, d.GetDataType("TEXT_ID"), d.GetDataType("TEXT_ID"), d.GetDataType("TEXT_ID"),
-				d.GetDataType("TEXT"), d.GetDataType("DATETIME"), d.GetDataType("BOOLEAN"),
-				d.GetDataType("DATETIME"), d.GetDataType("DATETIME")),
-		}
-	} else {
-		// SQLite
-		queries = []string{
-			fmt.Sprintf(`CREATE TABLE IF NOT EXISTS users (
-				id %s PRIMARY KEY,
-				email %s UNIQUE NOT NULL,
-				password %s NOT NULL,
-				name %s NOT NULL,
-				active %s DEFAULT 1,
-				created_at %s DEFAULT CURRENT_TIMESTAMP,
-				updated_at %s DEFAULT CURRENT_TIMESTAMP
-			)`, d.GetDataType("TEXT_ID"), d.GetDataType("TEXT"), d.GetDataType("TEXT"),
-				d.GetDataType("TEXT"), d.GetDataType("BOOLEAN"), d.GetDataType("DATETIME"),
-				d.GetDataType("DATETIME")),
-
-			fmt.Sprintf(`CREATE TABLE IF NOT EXISTS clients (
-				id %s PRIMARY KEY,
-				name %s NOT NULL,
-				description %s,
-				secret %s NOT NULL,
-				active %s DEFAULT 1,
-				created_at %s DEFAULT CURRENT_TIMESTAMP,
-				updated_at %s DEFAULT CURRENT_TIMESTAMP
-			)`, d.GetDataType("TEXT_ID"), d.GetDataType("TEXT"), d.GetDataType("TEXT"),
-				d.GetDataType("TEXT"), d.GetDataType("BOOLEAN"), d.GetDataType("DATETIME"),
-				d.GetDataType("DATETIME")),
-
-			fmt.Sprintf(`CREATE TABLE IF NOT EXISTS refresh_tokens (
-				id %s PRIMARY KEY,
-				user_id %s NOT NULL,
-				client_id %s NOT NULL,
-				token %s UNIQUE NOT NULL,
-				expires_at %s NOT NULL,
-				revoked %s DEFAULT 0,
-				created_at %s DEFAULT CURRENT_TIMESTAMP,
-				updated_at %s DEFAULT CURRENT_TIMESTAMP,
-				FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
-				FOREIGN KEY (client_id) REFERENCES clients(id) ON DELETE CASCADE
-			)`, d.GetDataType("TEXT_ID"), d.GetDataType("TEXT_ID"), d.GetDataType("TEXT_ID"),
-				d.GetDataType("TEXT"), d.GetDataType("DATETIME"), d.GetDataType("BOOLEAN"),
-				d.GetDataType("DATETIME"), d.GetDataType("DATETIME")),
-		}
-	}
-
-	// Índices comuns
-	indexQueries := []string{
-		`CREATE INDEX IF NOT EXISTS idx_users_email ON users(email)`,
-		`CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_id ON refresh_tokens(user_id)`,
-		`CREATE INDEX IF NOT EXISTS idx_refresh_tokens_token ON refresh_tokens(token)`,
-	}
-
-	queries = append(queries, indexQueries...)
-
-	for _, query := range queries {
-		if _, err := d.DB.Exec(query); err != nil {
-			return fmt.Errorf("erro ao executar query: %w", err)
-		}
-	}
-
-	log.Printf("Tabelas %s inicializadas com sucesso", strings.ToUpper(d.DBType))
-	return nil
-}
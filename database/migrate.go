@@ -0,0 +1,397 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed migrations
+var migrationFiles embed.FS
+
+// migrationFilePattern reconhece arquivos como 0001_initial_schema.up.sql
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration é uma alteração de esquema numerada, com scripts de ida e volta
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus descreve se uma migração já foi aplicada ao banco
+type MigrationStatus struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+// Migrator aplica e reverte as migrações do dialeto do banco conectado
+type Migrator struct {
+	db         *Database
+	migrations []Migration
+}
+
+func NewMigrator(db *Database) (*Migrator, error) {
+	migrations, err := loadMigrations(db.DBType)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Migrate aplica todas as migrações pendentes
+func (d *Database) Migrate() error {
+	migrator, err := NewMigrator(d)
+	if err != nil {
+		return err
+	}
+	return migrator.Up()
+}
+
+func loadMigrations(dialect string) ([]Migration, error) {
+	dir := path.Join("migrations", dialect)
+	entries, err := fs.ReadDir(migrationFiles, dir)
+	if err != nil {
+		return nil, fmt.Errorf("migrações não encontradas para o banco %s: %w", dialect, err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+
+		version, _ := strconv.Atoi(match[1])
+		content, err := migrationFiles.ReadFile(path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler migração %s: %w", entry.Name(), err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migração %04d_%s sem script up ou down", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// LatestVersion retorna a maior versão disponível
+func (m *Migrator) LatestVersion() int {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Up aplica todas as migrações pendentes
+func (m *Migrator) Up() error {
+	return m.MigrateTo(m.LatestVersion())
+}
+
+// MigrateTo aplica ou reverte migrações até que o banco esteja na versão informada
+func (m *Migrator) MigrateTo(target int) error {
+	if target != 0 && m.find(target) == nil {
+		return fmt.Errorf("versão de migração desconhecida: %d", target)
+	}
+
+	return m.withLock(func() error {
+		current, err := m.currentVersion()
+		if err != nil {
+			return err
+		}
+
+		if target >= current {
+			for _, migration := range m.migrations {
+				if migration.Version > current && migration.Version <= target {
+					if err := m.apply(migration, true); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		}
+
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			migration := m.migrations[i]
+			if migration.Version <= current && migration.Version > target {
+				if err := m.apply(migration, false); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// Rollback reverte as últimas migrações aplicadas
+func (m *Migrator) Rollback(steps int) error {
+	if steps <= 0 {
+		return fmt.Errorf("número de passos deve ser positivo")
+	}
+
+	current, err := m.CurrentVersion()
+	if err != nil {
+		return err
+	}
+
+	target := 0
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		if m.migrations[i].Version > current {
+			continue
+		}
+		if steps == 0 {
+			target = m.migrations[i].Version
+			break
+		}
+		steps--
+	}
+
+	return m.MigrateTo(target)
+}
+
+// CurrentVersion retorna a maior versão aplicada (0 para banco vazio)
+func (m *Migrator) CurrentVersion() (int, error) {
+	if err := m.ensureTables(); err != nil {
+		return 0, err
+	}
+	return m.currentVersion()
+}
+
+// Status lista todas as migrações conhecidas e se já foram aplicadas
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	if err := m.ensureTables(); err != nil {
+		return nil, err
+	}
+
+	rows, err := m.db.DB.Query("SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("erro ao consultar migrações aplicadas: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, fmt.Errorf("erro ao ler migrações aplicadas: %w", err)
+		}
+		applied[version] = appliedAt
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao ler migrações aplicadas: %w", err)
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if appliedAt, ok := applied[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// Pending retorna as migrações ainda não aplicadas
+func (m *Migrator) Pending() ([]Migration, error) {
+	statuses, err := m.Status()
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for i, status := range statuses {
+		if !status.Applied {
+			pending = append(pending, m.migrations[i])
+		}
+	}
+	return pending, nil
+}
+
+// CheckPending retorna erro se houver migrações não aplicadas; usado na verificação de prontidão
+func (m *Migrator) CheckPending(ctx context.Context) error {
+	pending, err := m.Pending()
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%d migração(ões) pendente(s), a partir da versão %d", len(pending), pending[0].Version)
+	}
+	return nil
+}
+
+// ForceUnlock remove a trava de migração deixada por uma execução interrompida
+func (m *Migrator) ForceUnlock() error {
+	if err := m.ensureTables(); err != nil {
+		return err
+	}
+	if _, err := m.db.DB.Exec("DELETE FROM schema_migrations_lock WHERE id = 1"); err != nil {
+		return fmt.Errorf("erro ao remover trava de migração: %w", err)
+	}
+	return nil
+}
+
+func (m *Migrator) ensureTables() error {
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			applied_at DATETIME NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS schema_migrations_lock (
+			id INTEGER PRIMARY KEY,
+			owner VARCHAR(255) NOT NULL,
+			locked_at DATETIME NOT NULL
+		)`,
+	}
+
+	for _, query := range queries {
+		if _, err := m.db.DB.Exec(query); err != nil {
+			return fmt.Errorf("erro ao criar tabelas de controle de migração: %w", err)
+		}
+	}
+	return nil
+}
+
+// withLock impede que duas instâncias migrem o mesmo banco ao mesmo tempo.
+// A trava é uma linha única em schema_migrations_lock, o que funciona igual em MySQL e SQLite.
+func (m *Migrator) withLock(fn func() error) error {
+	if err := m.ensureTables(); err != nil {
+		return err
+	}
+
+	hostname, _ := os.Hostname()
+	owner := fmt.Sprintf("%s:%d", hostname, os.Getpid())
+
+	_, err := m.db.DB.Exec("INSERT INTO schema_migrations_lock (id, owner, locked_at) VALUES (1, ?, ?)", owner, time.Now())
+	if err != nil {
+		var lockOwner string
+		var lockedAt time.Time
+		if scanErr := m.db.DB.QueryRow("SELECT owner, locked_at FROM schema_migrations_lock WHERE id = 1").Scan(&lockOwner, &lockedAt); scanErr == nil {
+			return fmt.Errorf("migrações já em execução por %s desde %s", lockOwner, lockedAt.Format(time.RFC3339))
+		}
+		return fmt.Errorf("erro ao obter trava de migração: %w", err)
+	}
+
+	defer func() {
+		if _, err := m.db.DB.Exec("DELETE FROM schema_migrations_lock WHERE id = 1 AND owner = ?", owner); err != nil {
+			slog.Error("Erro ao liberar trava de migração", "error", err)
+		}
+	}()
+
+	return fn()
+}
+
+func (m *Migrator) currentVersion() (int, error) {
+	var version sql.NullInt64
+	if err := m.db.DB.QueryRow("SELECT MAX(version) FROM schema_migrations").Scan(&version); err != nil {
+		return 0, fmt.Errorf("erro ao consultar versão do esquema: %w", err)
+	}
+	return int(version.Int64), nil
+}
+
+func (m *Migrator) apply(migration Migration, up bool) error {
+	script, direction := migration.Up, "up"
+	if !up {
+		script, direction = migration.Down, "down"
+	}
+
+	tx, err := m.db.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, statement := range splitStatements(script) {
+		if _, err := tx.Exec(statement); err != nil {
+			return fmt.Errorf("erro na migração %04d_%s (%s): %w", migration.Version, migration.Name, direction, err)
+		}
+	}
+
+	if up {
+		_, err = tx.Exec("INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)",
+			migration.Version, migration.Name, time.Now())
+	} else {
+		_, err = tx.Exec("DELETE FROM schema_migrations WHERE version = ?", migration.Version)
+	}
+	if err != nil {
+		return fmt.Errorf("erro ao registrar migração %04d_%s: %w", migration.Version, migration.Name, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("erro ao confirmar migração %04d_%s: %w", migration.Version, migration.Name, err)
+	}
+
+	if up {
+		slog.Info("Migração aplicada", "version", migration.Version, "name", migration.Name)
+	} else {
+		slog.Info("Migração revertida", "version", migration.Version, "name", migration.Name)
+	}
+	return nil
+}
+
+func (m *Migrator) find(version int) *Migration {
+	for i := range m.migrations {
+		if m.migrations[i].Version == version {
+			return &m.migrations[i]
+		}
+	}
+	return nil
+}
+
+// splitStatements separa um script em comandos individuais, já que o driver
+// MySQL não executa múltiplos comandos em uma única chamada
+func splitStatements(script string) []string {
+	var lines []string
+	for _, line := range strings.Split(script, "\n") {
+		if !strings.HasPrefix(strings.TrimSpace(line), "--") {
+			lines = append(lines, line)
+		}
+	}
+
+	var statements []string
+	for _, statement := range strings.Split(strings.Join(lines, "\n"), ";") {
+		if statement = strings.TrimSpace(statement); statement != "" {
+			statements = append(statements, statement)
+		}
+	}
+	return statements
+}
@@ -0,0 +1,183 @@
+package database
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"auth-service/config"
+)
+
+// openTestDatabase abre um banco vazio do dialeto. O MySQL só é testado quando
+// TEST_MYSQL_HOST aponta para um servidor com um banco descartável.
+func openTestDatabase(t *testing.T, dialect string) *Database {
+	t.Helper()
+
+	cfg := &config.Config{Database: config.DatabaseConfig{Type: dialect}}
+	switch dialect {
+	case "sqlite":
+		cfg.Database.Path = filepath.Join(t.TempDir(), "auth.db")
+	case "mysql":
+		cfg.Database.Host = os.Getenv("TEST_MYSQL_HOST")
+		if cfg.Database.Host == "" {
+			t.Skip("TEST_MYSQL_HOST não definido")
+		}
+		cfg.Database.Port = os.Getenv("TEST_MYSQL_PORT")
+		cfg.Database.User = os.Getenv("TEST_MYSQL_USER")
+		cfg.Database.Password = os.Getenv("TEST_MYSQL_PASSWORD")
+		cfg.Database.Name = os.Getenv("TEST_MYSQL_DATABASE")
+	}
+
+	db, err := NewDatabase(cfg)
+	if err != nil {
+		t.Fatalf("erro ao abrir banco %s: %v", dialect, err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// schemaSnapshot lista colunas e índices das tabelas do serviço, sem as de controle de migração
+func schemaSnapshot(t *testing.T, db *Database) []string {
+	t.Helper()
+
+	query := `
+		SELECT 'column ' || m.name || '.' || p.name FROM sqlite_master m, pragma_table_info(m.name) p
+		WHERE m.type = 'table' AND m.name NOT LIKE 'schema_migrations%' AND m.name NOT LIKE 'sqlite_%'
+		UNION ALL
+		SELECT 'index ' || name FROM sqlite_master
+		WHERE type = 'index' AND tbl_name NOT LIKE 'schema_migrations%' AND name NOT LIKE 'sqlite_%'`
+	if db.DBType == "mysql" {
+		query = `
+			SELECT CONCAT('column ', table_name, '.', column_name) FROM information_schema.columns
+			WHERE table_schema = DATABASE() AND table_name NOT LIKE 'schema_migrations%'
+			UNION ALL
+			SELECT DISTINCT CONCAT('index ', table_name, '.', index_name) FROM information_schema.statistics
+			WHERE table_schema = DATABASE() AND table_name NOT LIKE 'schema_migrations%'`
+	}
+
+	rows, err := db.DB.Query(query)
+	if err != nil {
+		t.Fatalf("erro ao ler esquema: %v", err)
+	}
+	defer rows.Close()
+
+	var schema []string
+	for rows.Next() {
+		var item string
+		if err := rows.Scan(&item); err != nil {
+			t.Fatalf("erro ao ler esquema: %v", err)
+		}
+		schema = append(schema, item)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("erro ao ler esquema: %v", err)
+	}
+	slices.Sort(schema)
+	return schema
+}
+
+func TestMigrationsMatchAcrossDialects(t *testing.T) {
+	mysql, err := loadMigrations("mysql")
+	if err != nil {
+		t.Fatalf("mysql: %v", err)
+	}
+	sqlite, err := loadMigrations("sqlite")
+	if err != nil {
+		t.Fatalf("sqlite: %v", err)
+	}
+
+	if len(mysql) != len(sqlite) {
+		t.Fatalf("%d migrações no mysql e %d no sqlite", len(mysql), len(sqlite))
+	}
+	for i := range mysql {
+		if mysql[i].Version != i+1 {
+			t.Errorf("versão %d fora de sequência na posição %d", mysql[i].Version, i)
+		}
+		if mysql[i].Version != sqlite[i].Version || mysql[i].Name != sqlite[i].Name {
+			t.Errorf("mysql tem %04d_%s e sqlite tem %04d_%s", mysql[i].Version, mysql[i].Name, sqlite[i].Version, sqlite[i].Name)
+		}
+	}
+}
+
+// TestMigrationsUpAndDown aplica cada migração, reverte e aplica de novo, conferindo que
+// o down devolve exatamente o esquema anterior ao up
+func TestMigrationsUpAndDown(t *testing.T) {
+	for _, dialect := range []string{"sqlite", "mysql"} {
+		t.Run(dialect, func(t *testing.T) {
+			db := openTestDatabase(t, dialect)
+			m, err := NewMigrator(db)
+			if err != nil {
+				t.Fatalf("migrator: %v", err)
+			}
+			t.Cleanup(func() { m.MigrateTo(0) })
+
+			for _, migration := range m.migrations {
+				before := schemaSnapshot(t, db)
+				if err := m.MigrateTo(migration.Version); err != nil {
+					t.Fatalf("up %04d: %v", migration.Version, err)
+				}
+				after := schemaSnapshot(t, db)
+
+				if err := m.Rollback(1); err != nil {
+					t.Fatalf("down %04d: %v", migration.Version, err)
+				}
+				if got := schemaSnapshot(t, db); !slices.Equal(got, before) {
+					t.Errorf("down %04d não restaurou o esquema:\n%v\nesperado\n%v", migration.Version, got, before)
+				}
+
+				if err := m.MigrateTo(migration.Version); err != nil {
+					t.Fatalf("up %04d depois do down: %v", migration.Version, err)
+				}
+				if got := schemaSnapshot(t, db); !slices.Equal(got, after) {
+					t.Errorf("up %04d repetido gerou outro esquema:\n%v\nesperado\n%v", migration.Version, got, after)
+				}
+			}
+
+			if err := m.CheckPending(context.Background()); err != nil {
+				t.Errorf("pendentes depois do up: %v", err)
+			}
+			if schema := schemaSnapshot(t, db); !slices.Contains(schema, "column device_codes.auth_time") {
+				t.Errorf("esquema final incompleto: %v", schema)
+			}
+			if err := m.MigrateTo(0); err != nil {
+				t.Fatalf("down até 0: %v", err)
+			}
+			if schema := schemaSnapshot(t, db); len(schema) != 0 {
+				t.Errorf("esquema depois de reverter tudo: %v", schema)
+			}
+		})
+	}
+}
+
+func TestMigrateRespectsLock(t *testing.T) {
+	db := openTestDatabase(t, "sqlite")
+	m, err := NewMigrator(db)
+	if err != nil {
+		t.Fatalf("migrator: %v", err)
+	}
+	if err := m.ensureTables(); err != nil {
+		t.Fatalf("tabelas de controle: %v", err)
+	}
+	if _, err := db.DB.Exec("INSERT INTO schema_migrations_lock (id, owner, locked_at) VALUES (1, 'outra-instância', CURRENT_TIMESTAMP)"); err != nil {
+		t.Fatalf("trava: %v", err)
+	}
+
+	if err := m.Up(); err == nil {
+		t.Fatal("migração executada apesar da trava")
+	}
+	if version, _ := m.CurrentVersion(); version != 0 {
+		t.Fatalf("versão %d com a trava ativa", version)
+	}
+
+	if err := m.ForceUnlock(); err != nil {
+		t.Fatalf("force unlock: %v", err)
+	}
+	if err := m.Up(); err != nil {
+		t.Fatalf("up depois do unlock: %v", err)
+	}
+	if version, _ := m.CurrentVersion(); version != m.LatestVersion() {
+		t.Errorf("versão %d, esperado %d", version, m.LatestVersion())
+	}
+}
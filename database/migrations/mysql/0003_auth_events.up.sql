@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS auth_events (
+    id VARCHAR(36) PRIMARY KEY,
+    event_type VARCHAR(64) NOT NULL,
+    user_id VARCHAR(36),
+    client_id VARCHAR(36),
+    ip VARCHAR(64),
+    user_agent TEXT,
+    outcome VARCHAR(16) NOT NULL,
+    reason VARCHAR(64),
+    created_at DATETIME NOT NULL
+);
+
+CREATE INDEX idx_auth_events_user_id ON auth_events(user_id, created_at);
+CREATE INDEX idx_auth_events_type ON auth_events(event_type, created_at);
+CREATE INDEX idx_auth_events_created_at ON auth_events(created_at);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS used_magic_links;
+ALTER TABLE clients DROP COLUMN magic_link_enabled;
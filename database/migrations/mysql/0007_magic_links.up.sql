@@ -0,0 +1,10 @@
+ALTER TABLE clients ADD COLUMN magic_link_enabled BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Links de acesso já consumidos, para recusar reuso até expirarem
+CREATE TABLE IF NOT EXISTS used_magic_links (
+    jti VARCHAR(36) PRIMARY KEY,
+    expires_at DATETIME NOT NULL,
+    used_at DATETIME NOT NULL
+);
+
+CREATE INDEX idx_used_magic_links_expires_at ON used_magic_links(expires_at);
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS invitations (
+    id VARCHAR(36) PRIMARY KEY,
+    code_hash CHAR(64) UNIQUE NOT NULL,
+    prefix VARCHAR(16) NOT NULL,
+    roles VARCHAR(255) NOT NULL DEFAULT '',
+    max_uses INT NOT NULL,
+    uses INT NOT NULL DEFAULT 0,
+    expires_at DATETIME NOT NULL,
+    revoked_at DATETIME NULL,
+    created_by VARCHAR(36) NULL,
+    created_at DATETIME NOT NULL,
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_invitations_expires_at ON invitations(expires_at);
@@ -0,0 +1,17 @@
+ALTER TABLE invitations DROP FOREIGN KEY fk_invitations_tenant;
+ALTER TABLE invitations DROP COLUMN tenant_id;
+
+ALTER TABLE clients DROP FOREIGN KEY fk_clients_tenant;
+ALTER TABLE clients DROP COLUMN tenant_id;
+
+-- Falha se a mesma identidade externa estiver vinculada em mais de uma organização
+ALTER TABLE user_identities DROP INDEX uq_user_identities_provider_subject_user;
+ALTER TABLE user_identities ADD CONSTRAINT provider UNIQUE (provider, subject);
+
+-- Falha se o mesmo email existir em mais de uma organização
+ALTER TABLE users DROP FOREIGN KEY fk_users_tenant;
+ALTER TABLE users DROP INDEX uq_users_tenant_email;
+ALTER TABLE users DROP COLUMN tenant_id;
+ALTER TABLE users ADD CONSTRAINT email UNIQUE (email);
+
+DROP TABLE IF EXISTS organizations;
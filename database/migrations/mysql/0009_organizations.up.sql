@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS organizations (
+    id VARCHAR(36) PRIMARY KEY,
+    slug VARCHAR(63) UNIQUE NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+-- Organização padrão, dona de todos os registros existentes
+INSERT INTO organizations (id, slug, name, created_at, updated_at)
+VALUES ('00000000-0000-0000-0000-000000000001', 'default', 'Organização padrão', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);
+
+-- Email único por organização em vez de global
+ALTER TABLE users ADD COLUMN tenant_id VARCHAR(36) NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' AFTER id;
+ALTER TABLE users DROP INDEX email;
+ALTER TABLE users ADD CONSTRAINT uq_users_tenant_email UNIQUE (tenant_id, email);
+ALTER TABLE users ADD CONSTRAINT fk_users_tenant FOREIGN KEY (tenant_id) REFERENCES organizations(id);
+
+-- A mesma identidade externa pode ser vinculada a uma conta em cada organização
+ALTER TABLE user_identities DROP INDEX provider;
+ALTER TABLE user_identities ADD CONSTRAINT uq_user_identities_provider_subject_user UNIQUE (provider, subject, user_id);
+
+ALTER TABLE clients ADD COLUMN tenant_id VARCHAR(36) NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' AFTER id;
+ALTER TABLE clients ADD CONSTRAINT fk_clients_tenant FOREIGN KEY (tenant_id) REFERENCES organizations(id);
+
+ALTER TABLE invitations ADD COLUMN tenant_id VARCHAR(36) NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' AFTER id;
+ALTER TABLE invitations ADD CONSTRAINT fk_invitations_tenant FOREIGN KEY (tenant_id) REFERENCES organizations(id);
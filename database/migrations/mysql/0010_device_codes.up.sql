@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS device_codes (
+    id VARCHAR(36) PRIMARY KEY,
+    device_code_hash CHAR(64) UNIQUE NOT NULL,
+    user_code VARCHAR(16) UNIQUE NOT NULL,
+    client_id VARCHAR(36) NOT NULL,
+    scope VARCHAR(255) NOT NULL DEFAULT '',
+    status VARCHAR(16) NOT NULL,
+    user_id VARCHAR(36) NULL,
+    interval_seconds INT NOT NULL,
+    last_polled_at DATETIME NULL,
+    expires_at DATETIME NOT NULL,
+    created_at DATETIME NOT NULL,
+    FOREIGN KEY (client_id) REFERENCES clients(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_device_codes_expires_at ON device_codes(expires_at);
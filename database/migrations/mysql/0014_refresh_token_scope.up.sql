@@ -0,0 +1,2 @@
+-- Escopos concedidos no login, mantidos na renovação da sessão
+ALTER TABLE refresh_tokens ADD COLUMN scope VARCHAR(255) NOT NULL DEFAULT '';
@@ -0,0 +1,11 @@
+-- Endereços de retorno aceitos no fluxo authorization code, separados por espaço
+ALTER TABLE clients ADD COLUMN redirect_uris VARCHAR(2048) NOT NULL DEFAULT '';
+
+-- Códigos de autorização já trocados por tokens, para recusar reuso até expirarem
+CREATE TABLE IF NOT EXISTS used_authorization_codes (
+    jti VARCHAR(36) PRIMARY KEY,
+    expires_at DATETIME NOT NULL,
+    used_at DATETIME NOT NULL
+);
+
+CREATE INDEX idx_used_authorization_codes_expires_at ON used_authorization_codes(expires_at);
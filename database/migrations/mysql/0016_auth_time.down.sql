@@ -0,0 +1,2 @@
+ALTER TABLE refresh_tokens DROP COLUMN auth_time;
+ALTER TABLE device_codes DROP COLUMN auth_time;
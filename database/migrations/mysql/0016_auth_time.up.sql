@@ -0,0 +1,5 @@
+-- Momento do login da sessão que aprovou o dispositivo, usado no auth_time do ID token
+ALTER TABLE device_codes ADD COLUMN auth_time DATETIME NULL;
+
+-- Momento do login da sessão, mantido a cada renovação
+ALTER TABLE refresh_tokens ADD COLUMN auth_time DATETIME NULL;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS auth_events (
+    id TEXT PRIMARY KEY,
+    event_type TEXT NOT NULL,
+    user_id TEXT,
+    client_id TEXT,
+    ip TEXT,
+    user_agent TEXT,
+    outcome TEXT NOT NULL,
+    reason TEXT,
+    created_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_auth_events_user_id ON auth_events(user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_auth_events_type ON auth_events(event_type, created_at);
+CREATE INDEX IF NOT EXISTS idx_auth_events_created_at ON auth_events(created_at);
@@ -0,0 +1 @@
+ALTER TABLE auth_events ADD COLUMN request_id TEXT;
@@ -0,0 +1,45 @@
+DROP INDEX IF EXISTS idx_invitations_tenant_id;
+ALTER TABLE invitations DROP COLUMN tenant_id;
+
+DROP INDEX IF EXISTS idx_clients_tenant_id;
+ALTER TABLE clients DROP COLUMN tenant_id;
+
+-- Falha se a mesma identidade externa estiver vinculada em mais de uma organização
+CREATE TABLE user_identities_old (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    provider TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    email TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (provider, subject),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+INSERT INTO user_identities_old (id, user_id, provider, subject, email, created_at, updated_at)
+SELECT id, user_id, provider, subject, email, created_at, updated_at FROM user_identities;
+
+DROP TABLE user_identities;
+ALTER TABLE user_identities_old RENAME TO user_identities;
+CREATE INDEX IF NOT EXISTS idx_user_identities_user_id ON user_identities(user_id);
+
+-- Falha se o mesmo email existir em mais de uma organização
+CREATE TABLE users_old (
+    id TEXT PRIMARY KEY,
+    email TEXT UNIQUE NOT NULL,
+    password TEXT NOT NULL,
+    name TEXT NOT NULL,
+    active INTEGER DEFAULT 1,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO users_old (id, email, password, name, active, created_at, updated_at)
+SELECT id, email, password, name, active, created_at, updated_at FROM users;
+
+DROP TABLE users;
+ALTER TABLE users_old RENAME TO users;
+CREATE INDEX IF NOT EXISTS idx_users_email ON users(email);
+
+DROP TABLE IF EXISTS organizations;
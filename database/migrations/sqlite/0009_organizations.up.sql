@@ -0,0 +1,60 @@
+CREATE TABLE IF NOT EXISTS organizations (
+    id TEXT PRIMARY KEY,
+    slug TEXT UNIQUE NOT NULL,
+    name TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+-- Organização padrão, dona de todos os registros existentes
+INSERT INTO organizations (id, slug, name, created_at, updated_at)
+VALUES ('00000000-0000-0000-0000-000000000001', 'default', 'Organização padrão', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);
+
+-- O SQLite não remove a restrição UNIQUE de uma coluna; a tabela de usuários é recriada
+-- com o email único por organização. As chaves estrangeiras de outras tabelas seguem
+-- apontando para "users" pelo nome.
+CREATE TABLE users_new (
+    id TEXT PRIMARY KEY,
+    tenant_id TEXT NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001',
+    email TEXT NOT NULL,
+    password TEXT NOT NULL,
+    name TEXT NOT NULL,
+    active INTEGER DEFAULT 1,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (tenant_id, email),
+    FOREIGN KEY (tenant_id) REFERENCES organizations(id)
+);
+
+INSERT INTO users_new (id, tenant_id, email, password, name, active, created_at, updated_at)
+SELECT id, '00000000-0000-0000-0000-000000000001', email, password, name, active, created_at, updated_at FROM users;
+
+DROP TABLE users;
+ALTER TABLE users_new RENAME TO users;
+CREATE INDEX IF NOT EXISTS idx_users_email ON users(email);
+
+-- A mesma identidade externa pode ser vinculada a uma conta em cada organização
+CREATE TABLE user_identities_new (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    provider TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    email TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (provider, subject, user_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+INSERT INTO user_identities_new (id, user_id, provider, subject, email, created_at, updated_at)
+SELECT id, user_id, provider, subject, email, created_at, updated_at FROM user_identities;
+
+DROP TABLE user_identities;
+ALTER TABLE user_identities_new RENAME TO user_identities;
+CREATE INDEX IF NOT EXISTS idx_user_identities_user_id ON user_identities(user_id);
+
+ALTER TABLE clients ADD COLUMN tenant_id TEXT NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001';
+CREATE INDEX IF NOT EXISTS idx_clients_tenant_id ON clients(tenant_id);
+
+ALTER TABLE invitations ADD COLUMN tenant_id TEXT NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001';
+CREATE INDEX IF NOT EXISTS idx_invitations_tenant_id ON invitations(tenant_id);
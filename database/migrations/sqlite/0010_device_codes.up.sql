@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS device_codes (
+    id TEXT PRIMARY KEY,
+    device_code_hash TEXT UNIQUE NOT NULL,
+    user_code TEXT UNIQUE NOT NULL,
+    client_id TEXT NOT NULL,
+    scope TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL,
+    user_id TEXT,
+    interval_seconds INTEGER NOT NULL,
+    last_polled_at DATETIME,
+    expires_at DATETIME NOT NULL,
+    created_at DATETIME NOT NULL,
+    FOREIGN KEY (client_id) REFERENCES clients(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_device_codes_expires_at ON device_codes(expires_at);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS used_authorization_codes;
+ALTER TABLE clients DROP COLUMN redirect_uris;
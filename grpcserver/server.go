@@ -0,0 +1,190 @@
+package grpcserver
+
+import (
+	"context"
+	"crypto/subtle"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"auth-service/config"
+	"auth-service/logging"
+	"auth-service/models"
+	"auth-service/problem"
+	authv1 "auth-service/proto/auth/v1"
+	"auth-service/services"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// errorDomain identifica a origem dos códigos de erro enviados em ErrorInfo
+const errorDomain = "auth-service"
+
+// codeByStatus traduz o status HTTP de um Problem para o código gRPC equivalente,
+// mantendo problem.statusByCode como a única tabela de códigos de erro
+var codeByStatus = map[int]codes.Code{
+	http.StatusBadRequest:      codes.InvalidArgument,
+	http.StatusUnauthorized:    codes.Unauthenticated,
+	http.StatusForbidden:       codes.PermissionDenied,
+	http.StatusNotFound:        codes.NotFound,
+	http.StatusConflict:        codes.AlreadyExists,
+	http.StatusTooManyRequests: codes.ResourceExhausted,
+}
+
+// Server implementa a API gRPC interna sobre o AuthService
+type Server struct {
+	authv1.UnimplementedAuthServiceServer
+	authService *services.AuthService
+}
+
+// NewServer cria o servidor gRPC com tracing, ID de requisição, autenticação opcional por
+// token compartilhado e conversão dos erros de serviço em status gRPC
+func NewServer(cfg *config.Config, authService *services.AuthService) *grpc.Server {
+	server := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(unaryInterceptor(cfg.GRPC.AuthToken)),
+	)
+	authv1.RegisterAuthServiceServer(server, &Server{authService: authService})
+	return server
+}
+
+func (s *Server) ValidateToken(ctx context.Context, req *authv1.ValidateTokenRequest) (*authv1.ValidateTokenResponse, error) {
+	if req.GetToken() == "" {
+		return nil, services.ErrMissingAuthToken
+	}
+	if req.GetClientId() == "" {
+		return nil, services.ErrMissingClientID
+	}
+
+	user, err := s.authService.ValidateToken(ctx, req.GetToken(), req.GetClientId())
+	if err != nil {
+		return nil, err
+	}
+
+	return &authv1.ValidateTokenResponse{User: toProtoUser(user), Scope: user.Scope}, nil
+}
+
+func (s *Server) Introspect(ctx context.Context, req *authv1.IntrospectRequest) (*authv1.IntrospectResponse, error) {
+	if req.GetClientId() == "" {
+		return nil, services.ErrMissingClientID
+	}
+
+	result, err := s.authService.Introspect(ctx, req.GetToken(), req.GetClientId())
+	if err != nil {
+		return nil, err
+	}
+
+	return &authv1.IntrospectResponse{
+		Active:    result.Active,
+		Sub:       result.Sub,
+		ClientId:  result.ClientID,
+		TenantId:  result.TenantID,
+		Email:     result.Email,
+		Scope:     result.Scope,
+		TokenType: result.TokenType,
+		Exp:       result.Exp,
+		Iat:       result.Iat,
+	}, nil
+}
+
+func (s *Server) GetUser(ctx context.Context, req *authv1.GetUserRequest) (*authv1.GetUserResponse, error) {
+	user, err := s.authService.GetUser(ctx, req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	return &authv1.GetUserResponse{User: toProtoUser(user)}, nil
+}
+
+func toProtoUser(user *models.UserResponse) *authv1.User {
+	return &authv1.User{
+		Id:        user.ID.String(),
+		TenantId:  user.TenantID.String(),
+		Email:     user.Email,
+		Name:      user.Name,
+		Active:    user.Active,
+		Roles:     user.Roles,
+		CreatedAt: timestamppb.New(user.CreatedAt),
+	}
+}
+
+// unaryInterceptor faz para o gRPC o que RequestID, AccessLog e problem fazem para o HTTP
+func unaryInterceptor(authToken string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		md, _ := metadata.FromIncomingContext(ctx)
+
+		requestID := firstValue(md, "x-request-id")
+		if !logging.ValidRequestID(requestID) {
+			requestID = uuid.NewString()
+		}
+		ctx = logging.WithRequestID(ctx, requestID)
+		_ = grpc.SetHeader(ctx, metadata.Pairs("x-request-id", requestID))
+
+		var resp any
+		var err error
+		if authToken != "" && !validAuthToken(firstValue(md, "authorization"), authToken) {
+			err = status.Error(codes.Unauthenticated, "token de acesso à API interna inválido")
+		} else {
+			resp, err = handler(ctx, req)
+			err = toStatus(ctx, err)
+		}
+
+		code := status.Code(err)
+		level := slog.LevelInfo
+		switch code {
+		case codes.OK:
+		case codes.Internal, codes.Unknown:
+			level = slog.LevelError
+		default:
+			level = slog.LevelWarn
+		}
+		slog.Log(ctx, level, "Chamada gRPC processada",
+			"method", info.FullMethod,
+			"code", code.String(),
+			"duration_ms", time.Since(start).Milliseconds(),
+		)
+
+		return resp, err
+	}
+}
+
+// toStatus converte erros de serviço em status gRPC com o código estável em ErrorInfo.Reason
+func toStatus(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	p := problem.From(err)
+	code, ok := codeByStatus[p.Status]
+	if !ok {
+		slog.ErrorContext(ctx, "Erro ao processar chamada gRPC", "error", err)
+		code = codes.Internal
+	}
+
+	st := status.New(code, p.Detail)
+	if withDetails, detailErr := st.WithDetails(&errdetails.ErrorInfo{Reason: p.Code, Domain: errorDomain}); detailErr == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
+
+func validAuthToken(header, expected string) bool {
+	token, ok := strings.CutPrefix(header, "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(token), []byte(expected)) == 1
+}
+
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
@@ -0,0 +1,142 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"auth-service/config"
+	"auth-service/models"
+	authv1 "auth-service/proto/auth/v1"
+	"auth-service/services"
+	"auth-service/store"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const testAuthToken = "token-interno"
+
+// reasonOf retorna o código de erro enviado em ErrorInfo
+func reasonOf(err error) string {
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return info.Reason
+		}
+	}
+	return ""
+}
+
+func TestToStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		code   codes.Code
+		reason string
+	}{
+		{"sem erro", nil, codes.OK, ""},
+		{"token inválido", services.ErrInvalidToken, codes.Unauthenticated, "INVALID_TOKEN"},
+		{"embrulhado", fmt.Errorf("validação: %w", services.ErrClientUnauthorized), codes.Unauthenticated, "CLIENT_UNAUTHORIZED"},
+		{"requisição inválida", services.ErrMissingClientID, codes.InvalidArgument, "MISSING_CLIENT_ID"},
+		{"escopo insuficiente", services.ErrInsufficientScope, codes.PermissionDenied, "INSUFFICIENT_SCOPE"},
+		{"não encontrado", services.ErrUserNotFound, codes.NotFound, "USER_NOT_FOUND"},
+		{"conflito", services.ErrEmailInUse, codes.AlreadyExists, "EMAIL_IN_USE"},
+		{"limite excedido", services.ErrRateLimited, codes.ResourceExhausted, "RATE_LIMITED"},
+		{"erro interno", errors.New("conexão recusada"), codes.Internal, "INTERNAL_ERROR"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := toStatus(context.Background(), tt.err)
+			if got := status.Code(err); got != tt.code {
+				t.Errorf("código %s, esperado %s", got, tt.code)
+			}
+			if got := reasonOf(err); got != tt.reason {
+				t.Errorf("reason %q, esperado %q", got, tt.reason)
+			}
+		})
+	}
+}
+
+// newTestClient sobe o servidor em memória e retorna um client conectado a ele
+func newTestClient(t *testing.T, authService *services.AuthService) authv1.AuthServiceClient {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	server := NewServer(&config.Config{GRPC: config.GRPCConfig{AuthToken: testAuthToken}}, authService)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("erro ao conectar: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return authv1.NewAuthServiceClient(conn)
+}
+
+func TestValidateToken(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{
+		JWT:          config.JWTConfig{Secret: "abcdefghijklmnopqrstuvwxyz0123456789ABCD", ExpirationHours: 1, RefreshExpirationHours: 24},
+		Registration: config.RegistrationConfig{Mode: config.RegistrationOpen},
+	}
+	authService := services.NewAuthService(store.NewMemoryStores(), cfg, nil)
+	user, err := authService.Register(ctx, &models.RegisterRequest{Email: "ana@example.com", Password: "senha-segura-1", Name: "Ana"})
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	client, err := authService.CreateClient(ctx, models.DefaultTenantID, "app", "")
+	if err != nil {
+		t.Fatalf("client: %v", err)
+	}
+	tokens, err := authService.Login(ctx, &models.LoginRequest{Email: "ana@example.com", Password: "senha-segura-1", ClientID: client.ID.String()})
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+
+	api := newTestClient(t, authService)
+	authorized := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+testAuthToken, "x-request-id", "req-grpc")
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		req    *authv1.ValidateTokenRequest
+		code   codes.Code
+		reason string
+	}{
+		{"token válido", authorized, &authv1.ValidateTokenRequest{Token: tokens.AccessToken, ClientId: client.ID.String()}, codes.OK, ""},
+		{"sem token da API interna", ctx, &authv1.ValidateTokenRequest{Token: tokens.AccessToken, ClientId: client.ID.String()}, codes.Unauthenticated, ""},
+		{"token de acesso inválido", authorized, &authv1.ValidateTokenRequest{Token: "invalido", ClientId: client.ID.String()}, codes.Unauthenticated, "INVALID_TOKEN"},
+		{"sem client", authorized, &authv1.ValidateTokenRequest{Token: tokens.AccessToken}, codes.InvalidArgument, "MISSING_CLIENT_ID"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var header metadata.MD
+			resp, err := api.ValidateToken(tt.ctx, tt.req, grpc.Header(&header))
+			if got := status.Code(err); got != tt.code {
+				t.Fatalf("código %s (%v), esperado %s", got, err, tt.code)
+			}
+			if got := reasonOf(err); got != tt.reason {
+				t.Errorf("reason %q, esperado %q", got, tt.reason)
+			}
+			if err == nil {
+				if resp.GetUser().GetId() != user.ID.String() {
+					t.Errorf("usuário %s, esperado %s", resp.GetUser().GetId(), user.ID)
+				}
+				if got := header.Get("x-request-id"); len(got) != 1 || got[0] != "req-grpc" {
+					t.Errorf("x-request-id = %v, esperado req-grpc", got)
+				}
+			}
+		})
+	}
+}
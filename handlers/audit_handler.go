@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"net/http"
+
+	"auth-service/models"
+	"auth-service/problem"
+	"auth-service/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditHandler struct {
+	auditService *services.AuditService
+}
+
+func NewAuditHandler(auditService *services.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// ListEvents godoc
+// @Summary Consultar eventos de autenticação
+// @Description Lista a trilha de auditoria da organização com filtros e paginação (somente administradores)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param user_id query string false "Usuário"
+// @Param client_id query string false "Client"
+// @Param event_type query string false "Tipo do evento (ex: login)"
+// @Param outcome query string false "success ou failure"
+// @Param ip query string false "IP de origem"
+// @Param request_id query string false "ID da requisição (X-Request-ID)"
+// @Param from query string false "Início do período (RFC 3339)"
+// @Param to query string false "Fim do período (RFC 3339)"
+// @Param page query int false "Página (padrão: 1)"
+// @Param page_size query int false "Itens por página (padrão: 20, máximo: 100)"
+// @Success 200 {object} models.AuthEventPage
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Router /admin/auth-events [get]
+func (h *AuditHandler) ListEvents(c *gin.Context) {
+	var query models.AuthEventQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		problem.InvalidRequest(c, err)
+		return
+	}
+
+	page, err := h.auditService.ListEvents(c.Request.Context(), c.GetString("tenant_id"), &query)
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// RecentActivity godoc
+// @Summary Atividade de segurança recente
+// @Description Lista os eventos de autenticação mais recentes do usuário autenticado
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string][]models.AuthEvent
+// @Failure 401 {object} problem.Problem
+// @Router /auth/activity [get]
+func (h *AuditHandler) RecentActivity(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		problem.Write(c, services.ErrUnauthenticated)
+		return
+	}
+
+	events, err := h.auditService.RecentActivity(c.Request.Context(), userID)
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"events": events})
+}
@@ -3,7 +3,9 @@ package handlers
 import (
 	"net/http"
 
+	"auth-service/config"
 	"auth-service/models"
+	"auth-service/problem"
 	"auth-service/services"
 
 	"github.com/gin-gonic/gin"
@@ -11,39 +13,41 @@ import (
 
 type AuthHandler struct {
 	authService *services.AuthService
+	session     config.SessionConfig
+	// refreshCookieMaxAge acompanha a validade do refresh token, em segundos
+	refreshCookieMaxAge int
 }
 
-func NewAuthHandler(authService *services.AuthService) *AuthHandler {
+func NewAuthHandler(authService *services.AuthService, cfg *config.Config) *AuthHandler {
 	return &AuthHandler{
-		authService: authService,
+		authService:         authService,
+		session:             cfg.Session,
+		refreshCookieMaxAge: cfg.JWT.RefreshExpirationHours * 3600,
 	}
 }
 
 // Register godoc
 // @Summary Registrar novo usuário
-// @Description Cria uma nova conta de usuário
+// @Description Cria uma nova conta de usuário. Conforme o modo de cadastro, exige um convite ou um email de domínio permitido.
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param user body models.RegisterRequest true "Dados do usuário"
 // @Success 201 {object} models.UserResponse
-// @Failure 400 {object} map[string]interface{}
-// @Failure 409 {object} map[string]interface{}
+// @Failure 400 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
 // @Router /auth/register [post]
 func (h *AuthHandler) Register(c *gin.Context) {
 	var req models.RegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "dados inválidos: " + err.Error()})
+		problem.InvalidRequest(c, err)
 		return
 	}
 
-	user, err := h.authService.Register(&req)
+	user, err := h.authService.Register(c.Request.Context(), &req)
 	if err != nil {
-		if err.Error() == "email já está em uso" {
-			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		problem.Write(c, err)
 		return
 	}
 
@@ -52,29 +56,39 @@ func (h *AuthHandler) Register(c *gin.Context) {
 
 // Login godoc
 // @Summary Fazer login
-// @Description Autentica um usuário e retorna tokens
+// @Description Autentica um usuário e retorna tokens. Com o header X-Session-Mode: cookie, o refresh token vai em um cookie HttpOnly e o corpo traz o access token e o token CSRF.
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param credentials body models.LoginRequest true "Credenciais de login"
+// @Param X-Session-Mode header string false "cookie para a sessão do navegador"
 // @Success 200 {object} models.TokenResponse
-// @Failure 400 {object} map[string]interface{}
-// @Failure 401 {object} map[string]interface{}
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
 // @Router /auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req models.LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "dados inválidos: " + err.Error()})
+		problem.InvalidRequest(c, err)
 		return
 	}
 
-	tokens, err := h.authService.Login(&req)
+	// A sessão do navegador é renovada pelo client, então ele precisa ser informado
+	session := cookieSession(c)
+	if session && req.ClientID == "" {
+		problem.Write(c, services.ErrMissingClientID)
+		return
+	}
+	req.WithRefreshToken = session
+
+	tokens, err := h.authService.Login(c.Request.Context(), &req)
 	if err != nil {
-		if err.Error() == "credenciais inválidas" || err.Error() == "usuário inativo" || err.Error() == "cliente não encontrado" || err.Error() == "cliente inativo" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		problem.Write(c, err)
+		return
+	}
+
+	if session {
+		h.writeSession(c, tokens)
 		return
 	}
 
@@ -83,29 +97,32 @@ func (h *AuthHandler) Login(c *gin.Context) {
 
 // RefreshToken godoc
 // @Summary Renovar token
-// @Description Renova o access token usando um refresh token
+// @Description Renova o access token usando um refresh token. Com o header X-Session-Mode: cookie, usa o refresh token do cookie e exige o header X-XSRF-TOKEN igual ao cookie XSRF-TOKEN.
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param refresh body models.RefreshTokenRequest true "Refresh token"
+// @Param X-Session-Mode header string false "cookie para a sessão do navegador"
 // @Success 200 {object} models.TokenResponse
-// @Failure 400 {object} map[string]interface{}
-// @Failure 401 {object} map[string]interface{}
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
 // @Router /auth/refresh [post]
 func (h *AuthHandler) RefreshToken(c *gin.Context) {
+	if cookieSession(c) {
+		h.refreshWithCookie(c)
+		return
+	}
+
 	var req models.RefreshTokenRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "dados inválidos: " + err.Error()})
+		problem.InvalidRequest(c, err)
 		return
 	}
 
-	tokens, err := h.authService.RefreshToken(&req)
+	tokens, err := h.authService.RefreshToken(c.Request.Context(), &req)
 	if err != nil {
-		if err.Error() == "refresh token inválido" || err.Error() == "refresh token revogado" || err.Error() == "refresh token expirado" || err.Error() == "usuário inativo" || err.Error() == "cliente não encontrado" || err.Error() == "cliente inativo" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		problem.Write(c, err)
 		return
 	}
 
@@ -120,39 +137,90 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 // @Produce json
 // @Param validation body models.ValidateTokenRequest true "Token para validação"
 // @Success 200 {object} models.UserResponse
-// @Failure 400 {object} map[string]interface{}
-// @Failure 401 {object} map[string]interface{}
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
 // @Router /auth/validate [post]
 func (h *AuthHandler) ValidateToken(c *gin.Context) {
 	var req models.ValidateTokenRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "dados inválidos: " + err.Error()})
+		problem.InvalidRequest(c, err)
 		return
 	}
 
-	user, err := h.authService.ValidateToken(req.Token, req.ClientID)
+	user, err := h.authService.ValidateToken(c.Request.Context(), req.Token, req.ClientID)
 	if err != nil {
-		if err.Error() == "token inválido" || err.Error() == "cliente não encontrado" || err.Error() == "cliente inativo" || err.Error() == "usuário inativo" || err.Error() == "tipo de token inválido" || err.Error() == "cliente não autorizado" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		problem.Write(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, user)
 }
 
+// ChangePassword godoc
+// @Summary Trocar senha
+// @Description Troca a senha do usuário autenticado, que confirma a senha atual. A nova senha não pode repetir a atual nem as guardadas no histórico. As sessões abertas são encerradas.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.ChangePasswordRequest true "Senha atual e nova senha"
+// @Success 204
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Router /auth/password [put]
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	var req models.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.InvalidRequest(c, err)
+		return
+	}
+
+	if err := h.authService.ChangePassword(c.Request.Context(), c.GetString("user_id"), &req); err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Introspect godoc
+// @Summary Introspecção de token
+// @Description Informa se o access token está ativo e suas claims (RFC 7662)
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param introspection body models.IntrospectRequest true "Token e client"
+// @Success 200 {object} models.IntrospectionResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Router /introspect [post]
+func (h *AuthHandler) Introspect(c *gin.Context) {
+	var req models.IntrospectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.InvalidRequest(c, err)
+		return
+	}
+
+	response, err := h.authService.Introspect(c.Request.Context(), req.Token, req.ClientID)
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
 // CreateClient godoc
 // @Summary Criar cliente
-// @Description Cria um novo cliente para autenticação
+// @Description Cria um novo cliente para autenticação. Pela rota administrativa, o cliente pertence à organização do administrador; pela rota pública, à organização padrão.
 // @Tags clients
 // @Accept json
 // @Produce json
 // @Param client body map[string]string true "Dados do cliente"
 // @Success 201 {object} models.Client
-// @Failure 400 {object} map[string]interface{}
+// @Failure 400 {object} problem.Problem
 // @Router /clients [post]
+// @Router /admin/clients [post]
 func (h *AuthHandler) CreateClient(c *gin.Context) {
 	var req struct {
 		Name        string `json:"name" binding:"required"`
@@ -160,19 +228,53 @@ func (h *AuthHandler) CreateClient(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "dados inválidos: " + err.Error()})
+		problem.InvalidRequest(c, err)
 		return
 	}
 
-	client, err := h.authService.CreateClient(req.Name, req.Description)
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		tenantID = models.DefaultTenantID
+	}
+
+	client, err := h.authService.CreateClient(c.Request.Context(), tenantID, req.Name, req.Description)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		problem.Write(c, err)
 		return
 	}
 
 	c.JSON(http.StatusCreated, client)
 }
 
+// UpdateClient godoc
+// @Summary Atualizar cliente
+// @Description Ativa ou desativa o cliente e o login por link enviado por email
+// @Tags clients
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do cliente"
+// @Param client body models.UpdateClientRequest true "Opções do cliente"
+// @Success 200 {object} models.Client
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Router /admin/clients/{id} [patch]
+func (h *AuthHandler) UpdateClient(c *gin.Context) {
+	var req models.UpdateClientRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.InvalidRequest(c, err)
+		return
+	}
+
+	client, err := h.authService.UpdateClient(c.Request.Context(), c.GetString("tenant_id"), c.Param("id"), &req)
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, client)
+}
+
 // GetProfile godoc
 // @Summary Obter perfil do usuário
 // @Description Retorna informações do usuário autenticado
@@ -181,12 +283,12 @@ func (h *AuthHandler) CreateClient(c *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Success 200 {object} models.UserResponse
-// @Failure 401 {object} map[string]interface{}
+// @Failure 401 {object} problem.Problem
 // @Router /auth/profile [get]
 func (h *AuthHandler) GetProfile(c *gin.Context) {
 	user, exists := c.Get("user")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "usuário não autenticado"})
+		problem.Write(c, services.ErrUnauthenticated)
 		return
 	}
 
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"net/http"
+
+	"auth-service/models"
+	"auth-service/problem"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StartAuthorization godoc
+// @Summary Iniciar fluxo authorization code
+// @Description Valida o client, o redirect_uri cadastrado e o PKCE (S256) e redireciona o navegador para a página de autorização do front-end, com os mesmos parâmetros. Pedidos inválidos não voltam ao redirect_uri.
+// @Tags oauth
+// @Param response_type query string true "code"
+// @Param client_id query string true "Client"
+// @Param redirect_uri query string true "Endereço de retorno cadastrado no client"
+// @Param scope query string false "Escopos separados por espaço, ex: openid email profile"
+// @Param state query string false "Devolvido ao client junto com o código"
+// @Param nonce query string false "Repetido no ID token"
+// @Param code_challenge query string true "BASE64URL(SHA256(code_verifier))"
+// @Param code_challenge_method query string true "S256"
+// @Success 302
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Router /oauth/authorize [get]
+func (h *AuthHandler) StartAuthorization(c *gin.Context) {
+	var req models.AuthorizationRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		problem.InvalidRequest(c, err)
+		return
+	}
+
+	if _, err := h.authService.CheckAuthorizationRequest(c.Request.Context(), &req); err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.Redirect(http.StatusFound, h.authService.AuthorizationPageURL(c.Request.URL.Query()))
+}
+
+// Authorize godoc
+// @Summary Autorizar client
+// @Description Chamado pela página de autorização com o usuário logado: emite o código e retorna o endereço do client para onde o navegador deve seguir
+// @Tags oauth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.AuthorizationRequest true "Parâmetros recebidos em GET /oauth/authorize"
+// @Success 200 {object} models.AuthorizationResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Router /oauth/authorize [post]
+func (h *AuthHandler) Authorize(c *gin.Context) {
+	var req models.AuthorizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.InvalidRequest(c, err)
+		return
+	}
+
+	response, err := h.authService.Authorize(c.Request.Context(), c.GetString("user_id"), &req)
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"net/http"
+
+	"auth-service/models"
+	"auth-service/problem"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeviceAuthorization godoc
+// @Summary Iniciar autorização de dispositivo
+// @Description Gera o device_code e o user_code para clientes sem navegador, como CLIs (RFC 8628)
+// @Tags oauth
+// @Accept json,x-www-form-urlencoded
+// @Produce json
+// @Param request body models.DeviceAuthorizationRequest true "Client e escopos"
+// @Success 200 {object} models.DeviceAuthorizationResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 429 {object} problem.Problem
+// @Router /oauth/device_authorization [post]
+func (h *AuthHandler) DeviceAuthorization(c *gin.Context) {
+	var req models.DeviceAuthorizationRequest
+	if err := c.ShouldBind(&req); err != nil {
+		problem.InvalidRequest(c, err)
+		return
+	}
+
+	response, err := h.authService.StartDeviceAuthorization(c.Request.Context(), &req)
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, response)
+}
+
+// Token godoc
+// @Summary Trocar código por tokens
+// @Description Com grant_type = authorization_code, troca o código do /oauth/authorize, com o code_verifier do PKCE. Com o grant_type do device_code, é consultado periodicamente pelo dispositivo: enquanto o usuário não decide, responde 400 com error = authorization_pending; slow_down, access_denied e expired_token seguem a RFC 8628.
+// @Tags oauth
+// @Accept json,x-www-form-urlencoded
+// @Produce json
+// @Param request body models.TokenRequest true "grant_type, client e o código correspondente"
+// @Success 200 {object} models.TokenResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Router /oauth/token [post]
+func (h *AuthHandler) Token(c *gin.Context) {
+	var req models.TokenRequest
+	if err := c.ShouldBind(&req); err != nil {
+		problem.InvalidRequest(c, err)
+		return
+	}
+
+	var response *models.TokenResponse
+	var err error
+	if req.GrantType == models.GrantTypeAuthorizationCode {
+		response, err = h.authService.ExchangeAuthorizationCode(c.Request.Context(), &req)
+	} else {
+		response, err = h.authService.ExchangeDeviceCode(c.Request.Context(), &req)
+	}
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, response)
+}
+
+// GetDeviceVerification godoc
+// @Summary Consultar pedido de dispositivo
+// @Description Mostra o client e os escopos do pedido identificado pelo código, para a página de aprovação
+// @Tags oauth
+// @Produce json
+// @Security BearerAuth
+// @Param user_code query string true "Código exibido no dispositivo"
+// @Success 200 {object} models.DeviceVerificationInfo
+// @Failure 401 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Router /oauth/device [get]
+func (h *AuthHandler) GetDeviceVerification(c *gin.Context) {
+	info, err := h.authService.GetDeviceVerification(c.Request.Context(), c.Query("user_code"))
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, info)
+}
+
+// VerifyDevice godoc
+// @Summary Aprovar ou recusar dispositivo
+// @Description Registra a decisão do usuário autenticado sobre o pedido identificado pelo código
+// @Tags oauth
+// @Accept json
+// @Security BearerAuth
+// @Param request body models.DeviceVerificationRequest true "Código e decisão (approve ou deny)"
+// @Success 204
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Router /oauth/device [post]
+func (h *AuthHandler) VerifyDevice(c *gin.Context) {
+	var req models.DeviceVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.InvalidRequest(c, err)
+		return
+	}
+
+	user := c.MustGet("user").(*models.UserResponse)
+	if err := h.authService.VerifyDevice(c.Request.Context(), user.ID.String(), user.AuthTime, &req); err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
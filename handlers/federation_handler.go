@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"auth-service/models"
+	"auth-service/problem"
+	"auth-service/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// federationStateCookie amarra o state ao navegador que iniciou o fluxo
+const federationStateCookie = "federation_state"
+
+type FederationHandler struct {
+	federationService *services.FederationService
+}
+
+func NewFederationHandler(federationService *services.FederationService) *FederationHandler {
+	return &FederationHandler{
+		federationService: federationService,
+	}
+}
+
+// Authorize godoc
+// @Summary Iniciar login federado
+// @Description Redireciona o navegador para o provedor OIDC externo
+// @Tags federation
+// @Param provider path string true "Nome do provedor (ex: google)"
+// @Param client_id query string false "Client que receberá os tokens"
+// @Success 302
+// @Failure 404 {object} problem.Problem
+// @Router /federation/{provider}/authorize [get]
+func (h *FederationHandler) Authorize(c *gin.Context) {
+	authURL, state, err := h.federationService.AuthorizationURL(c.Request.Context(), c.Param("provider"), c.Query("client_id"))
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(federationStateCookie, state, int(services.FederationStateTTL.Seconds()), "/api/v1/federation", "", c.Request.TLS != nil, true)
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// Callback godoc
+// @Summary Concluir login federado
+// @Description Recebe o código do provedor externo, vincula a conta e retorna tokens
+// @Tags federation
+// @Produce json
+// @Param provider path string true "Nome do provedor (ex: google)"
+// @Param code query string true "Código de autorização"
+// @Param state query string true "State emitido em /authorize"
+// @Success 200 {object} models.TokenResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Router /federation/{provider}/callback [get]
+func (h *FederationHandler) Callback(c *gin.Context) {
+	if providerErr := c.Query("error"); providerErr != "" {
+		problem.Write(c, services.ErrProviderRejected.Wrap(errors.New(providerErr)))
+		return
+	}
+
+	var req models.FederatedCallbackRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		problem.InvalidRequest(c, err)
+		return
+	}
+
+	cookieState, err := c.Cookie(federationStateCookie)
+	if err != nil || cookieState != req.State {
+		problem.Write(c, services.ErrInvalidState)
+		return
+	}
+	c.SetCookie(federationStateCookie, "", -1, "/api/v1/federation", "", c.Request.TLS != nil, true)
+
+	tokens, err := h.federationService.HandleCallback(c.Request.Context(), c.Param("provider"), req.Code, req.State)
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
@@ -0,0 +1,511 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"auth-service/config"
+	"auth-service/models"
+	"auth-service/services"
+	"auth-service/store"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	testProvider      = "mock"
+	testIdPClientID   = "auth-service"
+	testIdPSecret     = "segredo-do-provedor"
+	testIdPCode       = "codigo-valido"
+	testRedirectURL   = "http://localhost/api/v1/federation/mock/callback"
+	testIdPKeyID      = "chave-1"
+	testJWTSecret     = "abcdefghijklmnopqrstuvwxyz0123456789ABCD"
+	testSubject       = "sub-123"
+	testFederatedMail = "maria@example.com"
+)
+
+// mockIdP é um provedor OIDC mínimo: discovery, JWKS e token endpoint. O ID token
+// devolvido no token endpoint é montado a partir do nonce recebido no /authorize do
+// teste e pode ser adulterado por idToken.
+type mockIdP struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu       sync.Mutex
+	nonce    string
+	lastForm url.Values
+	idToken  func(claims jwt.MapClaims) (jwt.MapClaims, *rsa.PrivateKey)
+}
+
+func newMockIdP(t *testing.T) *mockIdP {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("erro ao gerar chave: %v", err)
+	}
+
+	idp := &mockIdP{key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{
+			"issuer":                 idp.server.URL,
+			"authorization_endpoint": idp.server.URL + "/authorize",
+			"token_endpoint":         idp.server.URL + "/token",
+			"jwks_uri":               idp.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": testIdPKeyID,
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", idp.handleToken)
+
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+	return idp
+}
+
+func (idp *mockIdP) handleToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+
+	idp.mu.Lock()
+	defer idp.mu.Unlock()
+	idp.lastForm = r.PostForm
+
+	if r.PostForm.Get("code") != testIdPCode || r.PostForm.Get("client_secret") != testIdPSecret {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":            idp.server.URL,
+		"aud":            testIdPClientID,
+		"sub":            testSubject,
+		"email":          testFederatedMail,
+		"email_verified": true,
+		"name":           "Maria",
+		"nonce":          idp.nonce,
+		"iat":            now.Unix(),
+		"exp":            now.Add(time.Minute).Unix(),
+	}
+	key := idp.key
+	if idp.idToken != nil {
+		claims, key = idp.idToken(claims)
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = testIdPKeyID
+	signed, err := token.SignedString(key)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"id_token": signed, "token_type": "Bearer"})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+type federationFixture struct {
+	idp    *mockIdP
+	stores *store.Stores
+	router *gin.Engine
+}
+
+func newFederationFixture(t *testing.T) *federationFixture {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	idp := newMockIdP(t)
+	cfg := &config.Config{
+		JWT: config.JWTConfig{Secret: testJWTSecret, ExpirationHours: 1},
+		Federation: config.FederationConfig{Providers: []config.FederatedProviderConfig{{
+			Name:         testProvider,
+			Issuer:       idp.server.URL,
+			ClientID:     testIdPClientID,
+			ClientSecret: testIdPSecret,
+			RedirectURL:  testRedirectURL,
+			Scopes:       []string{"openid", "email", "profile"},
+		}}},
+	}
+
+	stores := store.NewMemoryStores()
+	authService := services.NewAuthService(stores, cfg, nil)
+	handler := NewFederationHandler(services.NewFederationService(authService, cfg, idp.server.Client()))
+
+	router := gin.New()
+	router.GET("/api/v1/federation/:provider/authorize", handler.Authorize)
+	router.GET("/api/v1/federation/:provider/callback", handler.Callback)
+
+	return &federationFixture{idp: idp, stores: stores, router: router}
+}
+
+// authorize inicia o fluxo e devolve o cookie de state. O nonce enviado ao provedor é
+// repassado ao mock, como faria um provedor real.
+func (f *federationFixture) authorize(t *testing.T) *http.Cookie {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	f.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/federation/"+testProvider+"/authorize", nil))
+	if w.Code != http.StatusFound {
+		t.Fatalf("authorize: status %d, esperado %d: %s", w.Code, http.StatusFound, w.Body.String())
+	}
+
+	location, err := url.Parse(w.Header().Get("Location"))
+	if err != nil {
+		t.Fatalf("authorize: Location inválido: %v", err)
+	}
+
+	f.idp.mu.Lock()
+	f.idp.nonce = location.Query().Get("nonce")
+	f.idp.mu.Unlock()
+
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == federationStateCookie {
+			return cookie
+		}
+	}
+	t.Fatal("authorize: cookie de state ausente")
+	return nil
+}
+
+func (f *federationFixture) callback(code, state string, cookie *http.Cookie) *httptest.ResponseRecorder {
+	query := url.Values{"code": {code}, "state": {state}}
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/federation/"+testProvider+"/callback?"+query.Encode(), nil)
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+
+	w := httptest.NewRecorder()
+	f.router.ServeHTTP(w, req)
+	return w
+}
+
+func problemCode(t *testing.T, w *httptest.ResponseRecorder) string {
+	t.Helper()
+
+	var body struct {
+		Code string `json:"code"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("resposta não é um problem: %v: %s", err, w.Body.String())
+	}
+	return body.Code
+}
+
+func TestFederationAuthorizeRedirectsWithStateCookie(t *testing.T) {
+	f := newFederationFixture(t)
+
+	w := httptest.NewRecorder()
+	f.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/federation/"+testProvider+"/authorize", nil))
+	if w.Code != http.StatusFound {
+		t.Fatalf("status %d, esperado %d", w.Code, http.StatusFound)
+	}
+
+	location, err := url.Parse(w.Header().Get("Location"))
+	if err != nil {
+		t.Fatalf("Location inválido: %v", err)
+	}
+	if got := location.Scheme + "://" + location.Host + location.Path; got != f.idp.server.URL+"/authorize" {
+		t.Errorf("redirecionou para %s, esperado o authorization_endpoint do provedor", got)
+	}
+
+	query := location.Query()
+	expected := map[string]string{
+		"response_type": "code",
+		"client_id":     testIdPClientID,
+		"redirect_uri":  testRedirectURL,
+		"scope":         "openid email profile",
+	}
+	for name, value := range expected {
+		if query.Get(name) != value {
+			t.Errorf("%s = %q, esperado %q", name, query.Get(name), value)
+		}
+	}
+	if query.Get("nonce") == "" {
+		t.Error("nonce ausente na URL de autorização")
+	}
+
+	var cookie *http.Cookie
+	for _, c := range w.Result().Cookies() {
+		if c.Name == federationStateCookie {
+			cookie = c
+		}
+	}
+	if cookie == nil {
+		t.Fatal("cookie de state ausente")
+	}
+	if cookie.Value != query.Get("state") {
+		t.Error("o cookie não carrega o mesmo state enviado ao provedor")
+	}
+	if !cookie.HttpOnly || cookie.Path != "/api/v1/federation" || cookie.SameSite != http.SameSiteLaxMode {
+		t.Errorf("atributos do cookie: HttpOnly=%v Path=%q SameSite=%v", cookie.HttpOnly, cookie.Path, cookie.SameSite)
+	}
+}
+
+func TestFederationAuthorizeUnknownProvider(t *testing.T) {
+	f := newFederationFixture(t)
+
+	w := httptest.NewRecorder()
+	f.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/federation/desconhecido/authorize", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("status %d, esperado %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestFederationCallbackExchangesCodeAndCreatesUser(t *testing.T) {
+	f := newFederationFixture(t)
+	cookie := f.authorize(t)
+
+	w := f.callback(testIdPCode, cookie.Value, cookie)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d, esperado %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	var tokens models.TokenResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &tokens); err != nil || tokens.AccessToken == "" {
+		t.Fatalf("resposta sem access token: %v: %s", err, w.Body.String())
+	}
+
+	form := f.idp.lastForm
+	if form.Get("grant_type") != "authorization_code" || form.Get("redirect_uri") != testRedirectURL || form.Get("client_id") != testIdPClientID {
+		t.Errorf("troca do código com parâmetros inesperados: %v", form)
+	}
+
+	user, err := f.stores.Users.GetUserByIdentity(context.Background(), models.DefaultTenantID, testProvider, testSubject)
+	if err != nil {
+		t.Fatalf("identidade não vinculada: %v", err)
+	}
+	if user.Email != testFederatedMail || user.Password != "" {
+		t.Errorf("usuário criado com email %q e senha local %v", user.Email, user.Password != "")
+	}
+
+	// O state é de uso do navegador que iniciou o fluxo e o cookie é removido no callback
+	for _, c := range w.Result().Cookies() {
+		if c.Name == federationStateCookie && c.MaxAge >= 0 {
+			t.Error("cookie de state não foi removido")
+		}
+	}
+}
+
+func TestFederationCallbackRejectsStateMismatch(t *testing.T) {
+	f := newFederationFixture(t)
+	cookie := f.authorize(t)
+
+	tests := []struct {
+		name   string
+		state  string
+		cookie *http.Cookie
+	}{
+		{"sem cookie", cookie.Value, nil},
+		{"cookie de outro fluxo", cookie.Value, &http.Cookie{Name: federationStateCookie, Value: "outro"}},
+		{"state adulterado", "adulterado", &http.Cookie{Name: federationStateCookie, Value: "adulterado"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := f.callback(testIdPCode, tt.state, tt.cookie)
+			if w.Code != http.StatusUnauthorized || problemCode(t, w) != services.ErrInvalidState.Code {
+				t.Fatalf("status %d: %s", w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestFederationCallbackRejectsCodeRefusedByProvider(t *testing.T) {
+	f := newFederationFixture(t)
+	cookie := f.authorize(t)
+
+	w := f.callback("codigo-invalido", cookie.Value, cookie)
+	if w.Code != http.StatusUnauthorized || problemCode(t, w) != services.ErrProviderRejected.Code {
+		t.Fatalf("status %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestFederationCallbackVerifiesIDToken(t *testing.T) {
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("erro ao gerar chave: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		tamper  func(claims jwt.MapClaims, key *rsa.PrivateKey) (jwt.MapClaims, *rsa.PrivateKey)
+		errCode string
+	}{
+		{
+			name: "assinatura de outra chave",
+			tamper: func(claims jwt.MapClaims, _ *rsa.PrivateKey) (jwt.MapClaims, *rsa.PrivateKey) {
+				return claims, otherKey
+			},
+			errCode: services.ErrInvalidProviderToken.Code,
+		},
+		{
+			name: "audience de outro client",
+			tamper: func(claims jwt.MapClaims, key *rsa.PrivateKey) (jwt.MapClaims, *rsa.PrivateKey) {
+				claims["aud"] = "outro-client"
+				return claims, key
+			},
+			errCode: services.ErrInvalidProviderToken.Code,
+		},
+		{
+			name: "issuer divergente",
+			tamper: func(claims jwt.MapClaims, key *rsa.PrivateKey) (jwt.MapClaims, *rsa.PrivateKey) {
+				claims["iss"] = "https://outro-provedor.example.com"
+				return claims, key
+			},
+			errCode: services.ErrInvalidProviderToken.Code,
+		},
+		{
+			name: "token expirado",
+			tamper: func(claims jwt.MapClaims, key *rsa.PrivateKey) (jwt.MapClaims, *rsa.PrivateKey) {
+				claims["exp"] = time.Now().Add(-time.Minute).Unix()
+				return claims, key
+			},
+			errCode: services.ErrInvalidProviderToken.Code,
+		},
+		{
+			name: "nonce de outro fluxo",
+			tamper: func(claims jwt.MapClaims, key *rsa.PrivateKey) (jwt.MapClaims, *rsa.PrivateKey) {
+				claims["nonce"] = "outro-nonce"
+				return claims, key
+			},
+			errCode: services.ErrInvalidNonce.Code,
+		},
+		{
+			name: "sem nonce",
+			tamper: func(claims jwt.MapClaims, key *rsa.PrivateKey) (jwt.MapClaims, *rsa.PrivateKey) {
+				delete(claims, "nonce")
+				return claims, key
+			},
+			errCode: services.ErrInvalidNonce.Code,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFederationFixture(t)
+			f.idp.idToken = func(claims jwt.MapClaims) (jwt.MapClaims, *rsa.PrivateKey) {
+				return tt.tamper(claims, f.idp.key)
+			}
+			cookie := f.authorize(t)
+
+			w := f.callback(testIdPCode, cookie.Value, cookie)
+			if w.Code != http.StatusUnauthorized || problemCode(t, w) != tt.errCode {
+				t.Fatalf("status %d, esperado %s: %s", w.Code, tt.errCode, w.Body.String())
+			}
+
+			if _, err := f.stores.Users.GetUserByEmail(context.Background(), models.DefaultTenantID, testFederatedMail); err == nil {
+				t.Error("usuário criado a partir de um ID token inválido")
+			}
+		})
+	}
+}
+
+func TestFederationLinksExistingUserOnlyWithVerifiedEmail(t *testing.T) {
+	f := newFederationFixture(t)
+	ctx := context.Background()
+
+	now := time.Now()
+	existing := &models.User{
+		ID:        uuid.New(),
+		TenantID:  uuid.MustParse(models.DefaultTenantID),
+		Email:     testFederatedMail,
+		Password:  "hash-local",
+		Name:      "Maria",
+		Active:    true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := f.stores.Users.CreateUser(ctx, existing, nil, nil); err != nil {
+		t.Fatalf("erro ao criar usuário: %v", err)
+	}
+
+	for _, verified := range []interface{}{false, "false", nil} {
+		f.idp.idToken = func(claims jwt.MapClaims) (jwt.MapClaims, *rsa.PrivateKey) {
+			if verified == nil {
+				delete(claims, "email_verified")
+			} else {
+				claims["email_verified"] = verified
+			}
+			return claims, f.idp.key
+		}
+		cookie := f.authorize(t)
+
+		w := f.callback(testIdPCode, cookie.Value, cookie)
+		if w.Code != http.StatusUnauthorized || problemCode(t, w) != services.ErrEmailNotVerified.Code {
+			t.Fatalf("email_verified=%v: status %d: %s", verified, w.Code, w.Body.String())
+		}
+		if _, err := f.stores.Users.GetUserByIdentity(ctx, models.DefaultTenantID, testProvider, testSubject); err == nil {
+			t.Fatalf("email_verified=%v: identidade vinculada sem email verificado", verified)
+		}
+	}
+
+	// Alguns provedores enviam email_verified como string
+	f.idp.idToken = func(claims jwt.MapClaims) (jwt.MapClaims, *rsa.PrivateKey) {
+		claims["email_verified"] = "true"
+		return claims, f.idp.key
+	}
+	cookie := f.authorize(t)
+
+	w := f.callback(testIdPCode, cookie.Value, cookie)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", w.Code, w.Body.String())
+	}
+
+	linked, err := f.stores.Users.GetUserByIdentity(ctx, models.DefaultTenantID, testProvider, testSubject)
+	if err != nil {
+		t.Fatalf("identidade não vinculada: %v", err)
+	}
+	if linked.ID != existing.ID {
+		t.Errorf("identidade vinculada a %s, esperado o usuário existente %s", linked.ID, existing.ID)
+	}
+
+	// Com o vínculo feito, o login seguinte não depende mais do email
+	f.idp.idToken = func(claims jwt.MapClaims) (jwt.MapClaims, *rsa.PrivateKey) {
+		claims["email"] = "outro@example.com"
+		claims["email_verified"] = false
+		return claims, f.idp.key
+	}
+	cookie = f.authorize(t)
+
+	if w := f.callback(testIdPCode, cookie.Value, cookie); w.Code != http.StatusOK {
+		t.Fatalf("login com vínculo existente: status %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestFederationCallbackProviderError(t *testing.T) {
+	f := newFederationFixture(t)
+
+	w := httptest.NewRecorder()
+	f.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/federation/"+testProvider+"/callback?error=access_denied", nil))
+	if w.Code != http.StatusUnauthorized || !strings.Contains(w.Body.String(), services.ErrProviderRejected.Code) {
+		t.Fatalf("status %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"net/http"
+
+	"auth-service/models"
+	"auth-service/problem"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateInvitation godoc
+// @Summary Criar convite de cadastro
+// @Description Cria um código de convite com validade, limite de usos e papéis concedidos à conta. O código só é exibido nesta resposta.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param invitation body models.CreateInvitationRequest true "Papéis, limite de usos e validade"
+// @Success 201 {object} models.CreatedInvitation
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Router /admin/invitations [post]
+func (h *AuthHandler) CreateInvitation(c *gin.Context) {
+	var req models.CreateInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.InvalidRequest(c, err)
+		return
+	}
+
+	invitation, err := h.authService.CreateInvitation(c.Request.Context(), c.GetString("tenant_id"), c.GetString("user_id"), &req)
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, invitation)
+}
+
+// ListInvitations godoc
+// @Summary Listar convites
+// @Description Lista os convites não revogados da organização, com usos e validade
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string][]models.Invitation
+// @Failure 401 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Router /admin/invitations [get]
+func (h *AuthHandler) ListInvitations(c *gin.Context) {
+	invitations, err := h.authService.ListInvitations(c.Request.Context(), c.GetString("tenant_id"))
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"invitations": invitations})
+}
+
+// RevokeInvitation godoc
+// @Summary Revogar convite
+// @Description Impede novos cadastros com o convite
+// @Tags admin
+// @Security BearerAuth
+// @Param id path string true "ID do convite"
+// @Success 204
+// @Failure 401 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Router /admin/invitations/{id} [delete]
+func (h *AuthHandler) RevokeInvitation(c *gin.Context) {
+	if err := h.authService.RevokeInvitation(c.Request.Context(), c.GetString("tenant_id"), c.GetString("user_id"), c.Param("id")); err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
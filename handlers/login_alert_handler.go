@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"net/http"
+
+	"auth-service/models"
+	"auth-service/problem"
+	"auth-service/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LoginAlertHandler struct {
+	loginAlertService *services.LoginAlertService
+}
+
+func NewLoginAlertHandler(loginAlertService *services.LoginAlertService) *LoginAlertHandler {
+	return &LoginAlertHandler{
+		loginAlertService: loginAlertService,
+	}
+}
+
+// Report godoc
+// @Summary Denunciar acesso não reconhecido
+// @Description Recebe o token do link "não fui eu" enviado no aviso de novo dispositivo: encerra todas as sessões, invalida a senha atual e envia por email o link para definir uma nova. Cada link só pode ser usado uma vez.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.ReportLoginRequest true "Token do aviso"
+// @Success 202 {object} map[string]string
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Router /login/report [post]
+func (h *LoginAlertHandler) Report(c *gin.Context) {
+	var req models.ReportLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.InvalidRequest(c, err)
+		return
+	}
+
+	if err := h.loginAlertService.ReportLogin(c.Request.Context(), &req); err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "Sessões encerradas; enviamos por email um link para definir uma nova senha",
+	})
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"net/http"
+
+	"auth-service/models"
+	"auth-service/problem"
+	"auth-service/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MagicLinkHandler struct {
+	magicLinkService *services.MagicLinkService
+}
+
+func NewMagicLinkHandler(magicLinkService *services.MagicLinkService) *MagicLinkHandler {
+	return &MagicLinkHandler{
+		magicLinkService: magicLinkService,
+	}
+}
+
+// Request godoc
+// @Summary Pedir link de acesso
+// @Description Envia por email um link de uso único para entrar sem senha. A resposta é a mesma exista ou não uma conta com o email.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.MagicLinkRequest true "Email e client"
+// @Success 202 {object} map[string]string
+// @Failure 400 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 429 {object} problem.Problem
+// @Router /login/magic-link [post]
+func (h *MagicLinkHandler) Request(c *gin.Context) {
+	var req models.MagicLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.InvalidRequest(c, err)
+		return
+	}
+
+	if err := h.magicLinkService.RequestMagicLink(c.Request.Context(), &req); err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "Se houver uma conta com este email, um link de acesso foi enviado",
+	})
+}
+
+// Consume godoc
+// @Summary Entrar com link de acesso
+// @Description Troca o token recebido por email pelos tokens de acesso
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.ConsumeMagicLinkRequest true "Token do link e client"
+// @Success 200 {object} models.TokenResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Router /login/magic-link/consume [post]
+func (h *MagicLinkHandler) Consume(c *gin.Context) {
+	var req models.ConsumeMagicLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.InvalidRequest(c, err)
+		return
+	}
+
+	response, err := h.magicLinkService.ConsumeMagicLink(c.Request.Context(), &req)
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
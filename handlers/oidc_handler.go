@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"auth-service/problem"
+	"auth-service/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Discovery godoc
+// @Summary Documento de descoberta OpenID Connect
+// @Description Retorna os metadados do provedor OIDC
+// @Tags oidc
+// @Produce json
+// @Success 200 {object} models.OIDCDiscovery
+// @Router /.well-known/openid-configuration [get]
+func (h *AuthHandler) Discovery(c *gin.Context) {
+	c.JSON(http.StatusOK, h.authService.Discovery())
+}
+
+// JWKS godoc
+// @Summary Chaves públicas de assinatura
+// @Description Retorna o JWK Set usado para verificar ID tokens
+// @Tags oidc
+// @Produce json
+// @Success 200 {object} models.JSONWebKeySet
+// @Router /.well-known/jwks.json [get]
+func (h *AuthHandler) JWKS(c *gin.Context) {
+	c.JSON(http.StatusOK, h.authService.JWKS())
+}
+
+// UserInfo godoc
+// @Summary Dados do usuário (OIDC)
+// @Description Retorna as claims do usuário dono do access token
+// @Tags oidc
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.UserInfoResponse
+// @Failure 401 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Router /userinfo [get]
+func (h *AuthHandler) UserInfo(c *gin.Context) {
+	tokenParts := strings.Split(c.GetHeader("Authorization"), " ")
+	if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
+		c.Header("WWW-Authenticate", `Bearer error="invalid_request"`)
+		problem.Write(c, services.ErrMissingAuthToken)
+		return
+	}
+
+	info, err := h.authService.UserInfo(c.Request.Context(), tokenParts[1])
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInsufficientScope):
+			c.Header("WWW-Authenticate", `Bearer error="insufficient_scope", scope="openid"`)
+		case problem.From(err).Status == http.StatusUnauthorized:
+			c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+		}
+		problem.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, info)
+}
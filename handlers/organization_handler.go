@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+
+	"auth-service/models"
+	"auth-service/problem"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateOrganization godoc
+// @Summary Criar organização
+// @Description Cria uma organização (tenant) com usuários e clients próprios (somente administradores da organização padrão)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param organization body models.CreateOrganizationRequest true "Slug e nome da organização"
+// @Success 201 {object} models.Organization
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Router /admin/organizations [post]
+func (h *AuthHandler) CreateOrganization(c *gin.Context) {
+	var req models.CreateOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.InvalidRequest(c, err)
+		return
+	}
+
+	organization, err := h.authService.CreateOrganization(c.Request.Context(), c.GetString("user_id"), &req)
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, organization)
+}
+
+// ListOrganizations godoc
+// @Summary Listar organizações
+// @Description Lista todas as organizações (somente administradores da organização padrão)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string][]models.Organization
+// @Failure 401 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Router /admin/organizations [get]
+func (h *AuthHandler) ListOrganizations(c *gin.Context) {
+	organizations, err := h.authService.ListOrganizations(c.Request.Context())
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"organizations": organizations})
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"net/http"
+
+	"auth-service/models"
+	"auth-service/problem"
+	"auth-service/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PasswordResetHandler struct {
+	passwordResetService *services.PasswordResetService
+}
+
+func NewPasswordResetHandler(passwordResetService *services.PasswordResetService) *PasswordResetHandler {
+	return &PasswordResetHandler{
+		passwordResetService: passwordResetService,
+	}
+}
+
+// Forgot godoc
+// @Summary Pedir redefinição de senha
+// @Description Envia por email um link de uso único para definir uma nova senha. A resposta é a mesma exista ou não uma conta com o email.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.ForgotPasswordRequest true "Email e client"
+// @Success 202 {object} map[string]string
+// @Failure 400 {object} problem.Problem
+// @Failure 429 {object} problem.Problem
+// @Router /password/forgot [post]
+func (h *PasswordResetHandler) Forgot(c *gin.Context) {
+	var req models.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.InvalidRequest(c, err)
+		return
+	}
+
+	if err := h.passwordResetService.RequestPasswordReset(c.Request.Context(), &req); err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "Se houver uma conta com este email, um link de redefinição foi enviado",
+	})
+}
+
+// Reset godoc
+// @Summary Redefinir senha
+// @Description Define a nova senha com o token recebido por email e encerra as sessões abertas
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.ResetPasswordRequest true "Token do link e nova senha"
+// @Success 204
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Router /password/reset [post]
+func (h *PasswordResetHandler) Reset(c *gin.Context) {
+	var req models.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.InvalidRequest(c, err)
+		return
+	}
+
+	if err := h.passwordResetService.ResetPassword(c.Request.Context(), &req); err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"net/http"
+
+	"auth-service/models"
+	"auth-service/problem"
+	"auth-service/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreatePersonalToken godoc
+// @Summary Criar token de acesso pessoal
+// @Description Cria um token nomeado e com escopo para scripts e integrações. O token só é exibido nesta resposta.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param token body models.CreatePersonalTokenRequest true "Nome, escopo e validade do token"
+// @Success 201 {object} models.CreatedPersonalToken
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Router /auth/tokens [post]
+func (h *AuthHandler) CreatePersonalToken(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		problem.Write(c, services.ErrUnauthenticated)
+		return
+	}
+
+	// Um token de acesso pessoal não pode criar outros, para que um token vazado
+	// não sirva para prolongar o próprio acesso
+	if user.(*models.UserResponse).TokenType != models.TokenTypeSession {
+		problem.Write(c, services.ErrForbidden)
+		return
+	}
+
+	var req models.CreatePersonalTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.InvalidRequest(c, err)
+		return
+	}
+
+	token, err := h.authService.CreatePersonalToken(c.Request.Context(), c.GetString("user_id"), &req)
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, token)
+}
+
+// ListPersonalTokens godoc
+// @Summary Listar tokens de acesso pessoal
+// @Description Lista os tokens ativos do usuário autenticado, com prefixo e último uso
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string][]models.PersonalAccessToken
+// @Failure 401 {object} problem.Problem
+// @Router /auth/tokens [get]
+func (h *AuthHandler) ListPersonalTokens(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		problem.Write(c, services.ErrUnauthenticated)
+		return
+	}
+
+	tokens, err := h.authService.ListPersonalTokens(c.Request.Context(), userID)
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tokens": tokens})
+}
+
+// RevokePersonalToken godoc
+// @Summary Revogar token de acesso pessoal
+// @Description Revoga um token do usuário autenticado
+// @Tags auth
+// @Security BearerAuth
+// @Param id path string true "ID do token"
+// @Success 204
+// @Failure 401 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Router /auth/tokens/{id} [delete]
+func (h *AuthHandler) RevokePersonalToken(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		problem.Write(c, services.ErrUnauthenticated)
+		return
+	}
+
+	if err := h.authService.RevokePersonalToken(c.Request.Context(), userID, c.Param("id")); err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+
+	"auth-service/models"
+	"auth-service/problem"
+	"auth-service/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// sessionModeHeader ativa o modo de sessão do navegador no /login e no /refresh
+	sessionModeHeader = "X-Session-Mode"
+	sessionModeCookie = "cookie"
+
+	refreshCookieName = "refresh_token"
+	// Nomes padrão do suporte a XSRF do Angular, que lê o cookie e envia o header
+	csrfCookieName = "XSRF-TOKEN"
+	csrfHeaderName = "X-XSRF-TOKEN"
+)
+
+// EndSession godoc
+// @Summary Encerrar sessão do navegador
+// @Description Revoga o refresh token guardado no cookie e remove os cookies da sessão. Exige o header X-XSRF-TOKEN igual ao cookie XSRF-TOKEN.
+// @Tags auth
+// @Accept json
+// @Param request body models.EndSessionRequest true "Client"
+// @Success 204
+// @Failure 400 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Router /refresh [delete]
+func (h *AuthHandler) EndSession(c *gin.Context) {
+	var req models.EndSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.InvalidRequest(c, err)
+		return
+	}
+
+	if err := checkCSRF(c); err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	if refreshToken, err := c.Cookie(refreshCookieName); err == nil {
+		if err := h.authService.EndSession(c.Request.Context(), refreshToken, req.ClientID); err != nil {
+			problem.Write(c, err)
+			return
+		}
+	}
+
+	h.clearSessionCookies(c)
+	c.Status(http.StatusNoContent)
+}
+
+// cookieSession indica se o cliente pediu o modo de sessão do navegador
+func cookieSession(c *gin.Context) bool {
+	return strings.EqualFold(c.GetHeader(sessionModeHeader), sessionModeCookie)
+}
+
+// refreshWithCookie renova a sessão do navegador com o refresh token do cookie
+func (h *AuthHandler) refreshWithCookie(c *gin.Context) {
+	var req struct {
+		ClientID string `json:"client_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.InvalidRequest(c, err)
+		return
+	}
+
+	if err := checkCSRF(c); err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	refreshToken, err := c.Cookie(refreshCookieName)
+	if err != nil || refreshToken == "" {
+		problem.Write(c, services.ErrInvalidRefreshToken)
+		return
+	}
+
+	tokens, err := h.authService.RefreshToken(c.Request.Context(), &models.RefreshTokenRequest{
+		RefreshToken: refreshToken,
+		ClientID:     req.ClientID,
+	})
+	if err != nil {
+		// Um refresh token que não serve mais não deve continuar sendo enviado pelo navegador
+		if errors.Is(err, services.ErrInvalidRefreshToken) || errors.Is(err, services.ErrRefreshTokenRevoked) || errors.Is(err, services.ErrRefreshTokenExpired) {
+			h.clearSessionCookies(c)
+		}
+		problem.Write(c, err)
+		return
+	}
+
+	h.writeSession(c, tokens)
+}
+
+// writeSession move o refresh token para o cookie HttpOnly, emite um novo token CSRF e
+// responde apenas com o access token
+func (h *AuthHandler) writeSession(c *gin.Context, tokens *models.TokenResponse) {
+	csrfBytes := make([]byte, 32)
+	if _, err := rand.Read(csrfBytes); err != nil {
+		problem.Write(c, err)
+		return
+	}
+	csrfToken := hex.EncodeToString(csrfBytes)
+
+	maxAge := h.refreshCookieMaxAge
+	h.setCookie(c, refreshCookieName, tokens.RefreshToken, h.session.CookiePath, maxAge, true)
+	h.setCookie(c, csrfCookieName, csrfToken, "/", maxAge, false)
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, models.SessionTokenResponse{
+		AccessToken: tokens.AccessToken,
+		TokenType:   tokens.TokenType,
+		ExpiresIn:   tokens.ExpiresIn,
+		IDToken:     tokens.IDToken,
+		Scope:       tokens.Scope,
+		CSRFToken:   csrfToken,
+	})
+}
+
+func (h *AuthHandler) clearSessionCookies(c *gin.Context) {
+	h.setCookie(c, refreshCookieName, "", h.session.CookiePath, -1, true)
+	h.setCookie(c, csrfCookieName, "", "/", -1, false)
+}
+
+func (h *AuthHandler) setCookie(c *gin.Context, name, value, path string, maxAge int, httpOnly bool) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		Domain:   h.session.CookieDomain,
+		MaxAge:   maxAge,
+		Secure:   h.session.Secure,
+		HttpOnly: httpOnly,
+		SameSite: sameSiteMode(h.session.SameSite),
+	})
+}
+
+// checkCSRF compara o header com o cookie (double submit). Outra origem consegue fazer o
+// navegador enviar o cookie, mas não consegue lê-lo para repetir o valor no header.
+func checkCSRF(c *gin.Context) error {
+	cookie, err := c.Cookie(csrfCookieName)
+	header := c.GetHeader(csrfHeaderName)
+	if err != nil || cookie == "" || subtle.ConstantTimeCompare([]byte(cookie), []byte(header)) != 1 {
+		return services.ErrInvalidCSRFToken
+	}
+	return nil
+}
+
+func sameSiteMode(value string) http.SameSite {
+	switch strings.ToLower(value) {
+	case "lax":
+		return http.SameSiteLaxMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteStrictMode
+	}
+}
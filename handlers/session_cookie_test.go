@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"auth-service/config"
+	"auth-service/models"
+	"auth-service/services"
+	"auth-service/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// sessionFixture tem um usuário e um client e as rotas da sessão do navegador
+type sessionFixture struct {
+	router   *gin.Engine
+	clientID string
+}
+
+func newSessionFixture(t *testing.T) *sessionFixture {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+
+	cfg := &config.Config{
+		JWT:          config.JWTConfig{Secret: testJWTSecret, ExpirationHours: 1, RefreshExpirationHours: 24},
+		Registration: config.RegistrationConfig{Mode: config.RegistrationOpen},
+		Session:      config.SessionConfig{CookiePath: "/api/v1/refresh", SameSite: "strict", Secure: true},
+	}
+	authService := services.NewAuthService(store.NewMemoryStores(), cfg, nil)
+	if _, err := authService.Register(ctx, &models.RegisterRequest{Email: "ana@example.com", Password: "senha-segura-1", Name: "Ana"}); err != nil {
+		t.Fatalf("registro: %v", err)
+	}
+	client, err := authService.CreateClient(ctx, models.DefaultTenantID, "app", "")
+	if err != nil {
+		t.Fatalf("client: %v", err)
+	}
+
+	handler := NewAuthHandler(authService, cfg)
+	router := gin.New()
+	router.POST("/api/v1/login", handler.Login)
+	router.POST("/api/v1/refresh", handler.RefreshToken)
+	router.DELETE("/api/v1/refresh", handler.EndSession)
+
+	return &sessionFixture{router: router, clientID: client.ID.String()}
+}
+
+func (f *sessionFixture) send(method, path, body string, header map[string]string, cookies []*http.Cookie) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range header {
+		req.Header.Set(name, value)
+	}
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+
+	w := httptest.NewRecorder()
+	f.router.ServeHTTP(w, req)
+	return w
+}
+
+// login abre a sessão do navegador e devolve os cookies e o token CSRF da resposta
+func (f *sessionFixture) login(t *testing.T) (map[string]*http.Cookie, string) {
+	t.Helper()
+
+	w := f.send(http.MethodPost, "/api/v1/login",
+		`{"email":"ana@example.com","password":"senha-segura-1","client_id":"`+f.clientID+`"}`,
+		map[string]string{sessionModeHeader: sessionModeCookie}, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("login: status %d: %s", w.Code, w.Body.String())
+	}
+
+	var body models.SessionTokenResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	if body.CSRFToken == "" || body.AccessToken == "" || strings.Contains(w.Body.String(), "refresh_token") {
+		t.Fatalf("login: corpo %s", w.Body.String())
+	}
+	return cookiesByName(w), body.CSRFToken
+}
+
+func cookiesByName(w *httptest.ResponseRecorder) map[string]*http.Cookie {
+	cookies := make(map[string]*http.Cookie)
+	for _, cookie := range w.Result().Cookies() {
+		cookies[cookie.Name] = cookie
+	}
+	return cookies
+}
+
+func TestSessionLoginSetsCookies(t *testing.T) {
+	f := newSessionFixture(t)
+	cookies, csrfToken := f.login(t)
+
+	refresh := cookies[refreshCookieName]
+	if refresh == nil || refresh.Value == "" || !refresh.HttpOnly || !refresh.Secure || refresh.Path != "/api/v1/refresh" || refresh.SameSite != http.SameSiteStrictMode {
+		t.Errorf("cookie do refresh token = %+v", refresh)
+	}
+	csrf := cookies[csrfCookieName]
+	if csrf == nil || csrf.Value != csrfToken || csrf.HttpOnly || csrf.Path != "/" {
+		t.Errorf("cookie CSRF = %+v", csrf)
+	}
+}
+
+func TestSessionRefreshChecksCSRF(t *testing.T) {
+	f := newSessionFixture(t)
+	cookies, csrfToken := f.login(t)
+	body := `{"client_id":"` + f.clientID + `"}`
+
+	tests := []struct {
+		name    string
+		header  string
+		cookies []*http.Cookie
+		status  int
+		code    string
+	}{
+		{"sem header", "", []*http.Cookie{cookies[refreshCookieName], cookies[csrfCookieName]}, http.StatusForbidden, "INVALID_CSRF_TOKEN"},
+		{"header diferente do cookie", "outro-valor", []*http.Cookie{cookies[refreshCookieName], cookies[csrfCookieName]}, http.StatusForbidden, "INVALID_CSRF_TOKEN"},
+		{"sem cookie CSRF", csrfToken, []*http.Cookie{cookies[refreshCookieName]}, http.StatusForbidden, "INVALID_CSRF_TOKEN"},
+		{"sem cookie do refresh token", csrfToken, []*http.Cookie{cookies[csrfCookieName]}, http.StatusUnauthorized, "INVALID_REFRESH_TOKEN"},
+		{"header igual ao cookie", csrfToken, []*http.Cookie{cookies[refreshCookieName], cookies[csrfCookieName]}, http.StatusOK, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := map[string]string{sessionModeHeader: sessionModeCookie}
+			if tt.header != "" {
+				header[csrfHeaderName] = tt.header
+			}
+			w := f.send(http.MethodPost, "/api/v1/refresh", body, header, tt.cookies)
+			if w.Code != tt.status {
+				t.Fatalf("status %d, esperado %d: %s", w.Code, tt.status, w.Body.String())
+			}
+			if tt.code != "" {
+				if got := problemCode(t, w); got != tt.code {
+					t.Errorf("código %s, esperado %s", got, tt.code)
+				}
+				return
+			}
+
+			// A renovação troca o refresh token e o token CSRF
+			renewed := cookiesByName(w)
+			if renewed[refreshCookieName] == nil || renewed[refreshCookieName].Value == cookies[refreshCookieName].Value {
+				t.Error("refresh token do cookie não foi trocado")
+			}
+			if renewed[csrfCookieName] == nil || renewed[csrfCookieName].Value == csrfToken {
+				t.Error("token CSRF não foi trocado")
+			}
+		})
+	}
+}
+
+func TestEndSessionChecksCSRF(t *testing.T) {
+	f := newSessionFixture(t)
+	cookies, csrfToken := f.login(t)
+	body := `{"client_id":"` + f.clientID + `"}`
+	sent := []*http.Cookie{cookies[refreshCookieName], cookies[csrfCookieName]}
+
+	w := f.send(http.MethodDelete, "/api/v1/refresh", body, nil, sent)
+	if w.Code != http.StatusForbidden || problemCode(t, w) != "INVALID_CSRF_TOKEN" {
+		t.Fatalf("sem header: status %d: %s", w.Code, w.Body.String())
+	}
+
+	w = f.send(http.MethodDelete, "/api/v1/refresh", body, map[string]string{csrfHeaderName: csrfToken}, sent)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("status %d, esperado %d: %s", w.Code, http.StatusNoContent, w.Body.String())
+	}
+	for name, cookie := range cookiesByName(w) {
+		if cookie.MaxAge >= 0 {
+			t.Errorf("cookie %s não foi removido", name)
+		}
+	}
+
+	// O refresh token revogado não renova mais a sessão
+	w = f.send(http.MethodPost, "/api/v1/refresh", body, map[string]string{sessionModeHeader: sessionModeCookie, csrfHeaderName: csrfToken}, sent)
+	if w.Code == http.StatusOK {
+		t.Errorf("refresh depois do encerramento: status %d", w.Code)
+	}
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"net/http"
+
+	"auth-service/models"
+	"auth-service/problem"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetUser godoc
+// @Summary Consultar usuário
+// @Description Retorna um usuário da organização com seus papéis
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do usuário"
+// @Success 200 {object} models.UserResponse
+// @Failure 401 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Router /admin/users/{id} [get]
+func (h *AuthHandler) GetUser(c *gin.Context) {
+	user, err := h.authService.GetTenantUser(c.Request.Context(), c.GetString("tenant_id"), c.Param("id"))
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// UpdateUser godoc
+// @Summary Atualizar usuário
+// @Description Altera o nome ou ativa/desativa um usuário da organização. Publica user.updated ou, na desativação, user.deactivated.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do usuário"
+// @Param user body models.UpdateUserRequest true "Campos a alterar"
+// @Success 200 {object} models.UserResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Router /admin/users/{id} [patch]
+func (h *AuthHandler) UpdateUser(c *gin.Context) {
+	var req models.UpdateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.InvalidRequest(c, err)
+		return
+	}
+
+	user, err := h.authService.UpdateUser(c.Request.Context(), c.GetString("tenant_id"), c.GetString("user_id"), c.Param("id"), &req)
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// DeleteUser godoc
+// @Summary Remover usuário
+// @Description Remove o usuário com seus papéis, identidades e tokens. Publica user.deleted.
+// @Tags admin
+// @Security BearerAuth
+// @Param id path string true "ID do usuário"
+// @Success 204
+// @Failure 401 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Router /admin/users/{id} [delete]
+func (h *AuthHandler) DeleteUser(c *gin.Context) {
+	if err := h.authService.DeleteUser(c.Request.Context(), c.GetString("tenant_id"), c.GetString("user_id"), c.Param("id")); err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"auth-service/models"
+	"auth-service/problem"
+	"auth-service/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportBodyBytes limita o corpo da importação de usuários
+const maxImportBodyBytes = 8 << 20
+
+type UserImportHandler struct {
+	userImportService *services.UserImportService
+}
+
+func NewUserImportHandler(userImportService *services.UserImportService) *UserImportHandler {
+	return &UserImportHandler{
+		userImportService: userImportService,
+	}
+}
+
+// Import godoc
+// @Summary Importar usuários
+// @Description Cria ou atualiza, pelo email, os usuários de um arquivo CSV (com cabeçalho email,name,password_hash,roles,active) ou JSON. O password_hash só é usado na criação e é ignorado para contas existentes; usuários criados sem ele recebem por email um link para definir a senha. Com dry_run=true nada é gravado. O relatório traz o resultado de cada linha.
+// @Tags admin
+// @Accept text/csv,json
+// @Produce json
+// @Security BearerAuth
+// @Param format query string false "csv ou json (padrão: pelo Content-Type)"
+// @Param dry_run query bool false "Apenas validar"
+// @Success 200 {object} models.UserImportResult
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 413 {object} problem.Problem
+// @Router /admin/users/import [post]
+func (h *UserImportHandler) Import(c *gin.Context) {
+	format := c.Query("format")
+	if format == "" {
+		format = services.UserFileFormat(c.ContentType())
+	}
+	dryRun, _ := strconv.ParseBool(c.Query("dry_run"))
+
+	records, err := services.ParseUserFile(http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBodyBytes), format)
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	result, err := h.userImportService.ImportUsers(c.Request.Context(), c.GetString("tenant_id"), c.GetString("user_id"), records, dryRun)
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// Export godoc
+// @Summary Exportar usuários
+// @Description Exporta os usuários da organização no formato da importação, sem os hashes de senha
+// @Tags admin
+// @Produce text/csv,json
+// @Security BearerAuth
+// @Param format query string false "csv ou json (padrão: json)"
+// @Success 200 {array} models.UserRecord
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Router /admin/users/export [get]
+func (h *UserImportHandler) Export(c *gin.Context) {
+	format := c.DefaultQuery("format", models.UserFileJSON)
+	if format != models.UserFileCSV && format != models.UserFileJSON {
+		problem.Write(c, services.ErrInvalidImportFormat)
+		return
+	}
+
+	records, err := h.userImportService.ExportUsers(c.Request.Context(), c.GetString("tenant_id"))
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	contentType := "application/json"
+	if format == models.UserFileCSV {
+		contentType = "text/csv; charset=utf-8"
+	}
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", `attachment; filename="users.`+format+`"`)
+	c.Status(http.StatusOK)
+	if err := services.WriteUserFile(c.Writer, format, records); err != nil {
+		c.Error(err)
+	}
+}
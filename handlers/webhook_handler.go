@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"net/http"
+
+	"auth-service/models"
+	"auth-service/problem"
+	"auth-service/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookHandler struct {
+	webhookService *services.WebhookService
+}
+
+func NewWebhookHandler(webhookService *services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// CreateWebhook godoc
+// @Summary Registrar webhook
+// @Description Registra uma URL que recebe os eventos de usuários da organização. Cada entrega traz X-Webhook-ID, X-Webhook-Event, X-Webhook-Timestamp e X-Webhook-Signature ("sha256=" + HMAC-SHA256 de "<timestamp>.<corpo>" com o segredo). O segredo só é exibido nesta resposta.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param webhook body models.CreateWebhookRequest true "URL e tipos de evento (vazio: todos)"
+// @Success 201 {object} models.CreateWebhookResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Router /admin/webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	var req models.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.InvalidRequest(c, err)
+		return
+	}
+
+	webhook, err := h.webhookService.CreateWebhook(c.Request.Context(), c.GetString("tenant_id"), &req)
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, webhook)
+}
+
+// ListWebhooks godoc
+// @Summary Listar webhooks
+// @Description Lista os webhooks da organização
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string][]models.Webhook
+// @Failure 401 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Router /admin/webhooks [get]
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	webhooks, err := h.webhookService.ListWebhooks(c.Request.Context(), c.GetString("tenant_id"))
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"webhooks": webhooks})
+}
+
+// DeleteWebhook godoc
+// @Summary Remover webhook
+// @Description Remove o webhook e cancela as entregas pendentes para ele
+// @Tags admin
+// @Security BearerAuth
+// @Param id path string true "ID do webhook"
+// @Success 204
+// @Failure 401 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Router /admin/webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	if err := h.webhookService.DeleteWebhook(c.Request.Context(), c.GetString("tenant_id"), c.Param("id")); err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListOutboxEvents godoc
+// @Summary Consultar eventos publicados
+// @Description Lista os eventos de usuários gravados no outbox da organização, mais recentes primeiro
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param event_type query string false "Tipo do evento (ex: user.created)"
+// @Param aggregate_id query string false "ID do usuário"
+// @Param page query int false "Página (padrão: 1)"
+// @Param page_size query int false "Itens por página (padrão: 20, máximo: 100)"
+// @Success 200 {object} models.OutboxEventPage
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Router /admin/outbox [get]
+func (h *WebhookHandler) ListOutboxEvents(c *gin.Context) {
+	var query models.OutboxEventQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		problem.InvalidRequest(c, err)
+		return
+	}
+
+	page, err := h.webhookService.ListOutboxEvents(c.Request.Context(), c.GetString("tenant_id"), &query)
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// GetOutboxEvent godoc
+// @Summary Consultar evento publicado
+// @Description Retorna o evento com as entregas a cada webhook, tentativas e último erro
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do evento"
+// @Success 200 {object} models.OutboxEventDetail
+// @Failure 401 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Router /admin/outbox/{id} [get]
+func (h *WebhookHandler) GetOutboxEvent(c *gin.Context) {
+	event, err := h.webhookService.GetOutboxEvent(c.Request.Context(), c.GetString("tenant_id"), c.Param("id"))
+	if err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, event)
+}
+
+// ReplayOutboxEvent godoc
+// @Summary Reenviar evento
+// @Description Reenvia o evento a todos os webhooks ativos que o assinam, com o mesmo X-Webhook-ID
+// @Tags admin
+// @Security BearerAuth
+// @Param id path string true "ID do evento"
+// @Success 202
+// @Failure 401 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Router /admin/outbox/{id}/replay [post]
+func (h *WebhookHandler) ReplayOutboxEvent(c *gin.Context) {
+	if err := h.webhookService.ReplayOutboxEvent(c.Request.Context(), c.GetString("tenant_id"), c.Param("id")); err != nil {
+		problem.Write(c, err)
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
@@ -0,0 +1,97 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func ok(context.Context) error { return nil }
+
+func TestReadyHandler(t *testing.T) {
+	tests := []struct {
+		name   string
+		checks map[string]CheckFunc
+		drain  bool
+		status int
+		report string
+		failed string
+	}{
+		{"sem verificações", nil, false, http.StatusOK, "ready", ""},
+		{"dependências disponíveis", map[string]CheckFunc{"database": ok, "smtp": ok}, false, http.StatusOK, "ready", ""},
+		{"dependência fora do ar", map[string]CheckFunc{"database": ok, "smtp": func(context.Context) error { return errors.New("conexão recusada") }}, false, http.StatusServiceUnavailable, "not_ready", "smtp"},
+		{"desligando", map[string]CheckFunc{"database": ok}, true, http.StatusServiceUnavailable, "draining", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewChecker()
+			for name, check := range tt.checks {
+				checker.Register(name, check)
+			}
+			if tt.drain {
+				checker.Drain()
+			}
+
+			router := gin.New()
+			router.GET("/readyz", checker.ReadyHandler())
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			if w.Code != tt.status {
+				t.Errorf("status %d, esperado %d", w.Code, tt.status)
+			}
+			var report Report
+			if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+				t.Fatalf("corpo inválido: %v", err)
+			}
+			if report.Status != tt.report {
+				t.Errorf("status do relatório %s, esperado %s", report.Status, tt.report)
+			}
+			if !tt.drain && len(report.Checks) != len(tt.checks) {
+				t.Errorf("%d verificações no relatório, esperado %d", len(report.Checks), len(tt.checks))
+			}
+			if tt.failed != "" {
+				if result := report.Checks[tt.failed]; result.Status != "fail" || result.Error == "" {
+					t.Errorf("verificação %s = %+v", tt.failed, result)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckTimesOutSlowDependency(t *testing.T) {
+	checker := NewChecker()
+	checker.Register("lenta", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	report := checker.Check(context.Background())
+	if report.Status != "not_ready" || report.Checks["lenta"].Status != "fail" {
+		t.Errorf("relatório = %+v", report)
+	}
+}
+
+func TestLiveHandlerIgnoresDependencies(t *testing.T) {
+	checker := NewChecker()
+	checker.Register("database", func(context.Context) error { return errors.New("fora do ar") })
+	checker.Drain()
+
+	router := gin.New()
+	router.GET("/livez", checker.LiveHandler())
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/livez", nil))
+
+	if w.Code != http.StatusOK {
+		t.Errorf("status %d, esperado 200", w.Code)
+	}
+}
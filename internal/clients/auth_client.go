@@ -2,14 +2,23 @@ package clients
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"time"
 
 	"study-manager-service/internal/config"
+	"study-manager-service/internal/logging"
+	"study-manager-service/internal/metrics"
 	"study-manager-service/internal/models"
+
+	"study-manager-service/internal/authpb"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"google.golang.org/grpc"
 )
 
 // AuthClient representa o cliente para comunicação com o auth-service
@@ -17,26 +26,75 @@ type AuthClient struct {
 	baseURL    string
 	clientID   string
 	httpClient *http.Client
+	timeout    time.Duration
+
+	// Preenchidos quando AUTH_TRANSPORT=grpc
+	grpcConn   *grpc.ClientConn
+	grpcClient authpb.AuthServiceClient
+	grpcToken  string
 }
 
 // NewAuthClient cria um novo cliente de autenticação
-func NewAuthClient(cfg *config.Config) *AuthClient {
-	return &AuthClient{
+func NewAuthClient(cfg *config.Config) (*AuthClient, error) {
+	timeout := time.Duration(cfg.Auth.Timeout) * time.Second
+	client := &AuthClient{
 		baseURL:  cfg.Auth.ServiceURL,
 		clientID: cfg.Auth.ClientID,
 		httpClient: &http.Client{
-			Timeout: time.Duration(cfg.Auth.Timeout) * time.Second,
+			Timeout: timeout,
+			// Propaga o trace atual (traceparent) e cria um span por chamada
+			Transport: otelhttp.NewTransport(http.DefaultTransport),
 		},
+		timeout: timeout,
+	}
+
+	// A validação de tokens pode usar a API gRPC interna; o health check continua via HTTP
+	if cfg.Auth.Transport == "grpc" {
+		conn, err := dialAuthGRPC(cfg)
+		if err != nil {
+			return nil, err
+		}
+		client.grpcConn = conn
+		client.grpcClient = authpb.NewAuthServiceClient(conn)
+		client.grpcToken = cfg.Auth.GRPCToken
 	}
+
+	return client, nil
+}
+
+// Close encerra a conexão gRPC, se houver
+func (c *AuthClient) Close() error {
+	if c.grpcConn == nil {
+		return nil
+	}
+	return c.grpcConn.Close()
 }
 
 // ValidateToken valida um token JWT com o auth-service
-func (c *AuthClient) ValidateToken(token, clientID string) (*models.AuthUser, error) {
+func (c *AuthClient) ValidateToken(ctx context.Context, token, clientID string) (_ *models.AuthUser, err error) {
+	// Registrar latência e motivo das falhas
+	start := time.Now()
+	failure := "internal"
+	defer func() {
+		outcome := "success"
+		if err != nil {
+			outcome = "failure"
+			metrics.AuthValidateErrors.WithLabelValues(failure).Inc()
+		}
+		metrics.AuthValidateDuration.WithLabelValues(outcome).Observe(time.Since(start).Seconds())
+	}()
+
 	// Usar o clientID fornecido ou o configurado
 	if clientID == "" {
 		clientID = c.clientID
 	}
 
+	if c.grpcClient != nil {
+		var user *models.AuthUser
+		user, failure, err = c.validateTokenGRPC(ctx, token, clientID)
+		return user, err
+	}
+
 	// Preparar requisição
 	reqBody := models.ValidateTokenRequest{
 		Token:    token,
@@ -50,16 +108,21 @@ func (c *AuthClient) ValidateToken(token, clientID string) (*models.AuthUser, er
 
 	// Fazer requisição para o auth-service
 	url := fmt.Sprintf("%s/api/v1/validate", c.baseURL)
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("erro ao criar requisição: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	// Repassar o ID da requisição para correlacionar os logs dos dois serviços
+	if requestID := logging.RequestID(ctx); requestID != "" {
+		req.Header.Set("X-Request-ID", requestID)
+	}
 
 	// Executar requisição
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
+		failure = "transport"
 		return nil, fmt.Errorf("erro ao executar requisição: %w", err)
 	}
 	defer resp.Body.Close()
@@ -67,13 +130,20 @@ func (c *AuthClient) ValidateToken(token, clientID string) (*models.AuthUser, er
 	// Ler resposta
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
+		failure = "transport"
 		return nil, fmt.Errorf("erro ao ler resposta: %w", err)
 	}
 
 	// Verificar status da resposta
 	if resp.StatusCode != http.StatusOK {
+		failure = strconv.Itoa(resp.StatusCode)
+
+		// O auth-service responde erros no formato RFC 7807 (application/problem+json)
 		var errorResp map[string]interface{}
 		if err := json.Unmarshal(body, &errorResp); err == nil {
+			if detail, ok := errorResp["detail"].(string); ok {
+				return nil, fmt.Errorf("erro do auth-service (%v): %s", errorResp["code"], detail)
+			}
 			if errorMsg, ok := errorResp["error"].(string); ok {
 				return nil, fmt.Errorf("erro do auth-service: %s", errorMsg)
 			}
@@ -84,15 +154,18 @@ func (c *AuthClient) ValidateToken(token, clientID string) (*models.AuthUser, er
 	// Deserializar resposta
 	var authUser models.ValidateTokenResponse
 	if err := json.Unmarshal(body, &authUser); err != nil {
+		failure = "decode"
 		return nil, fmt.Errorf("erro ao deserializar resposta: %w", err)
 	}
 
 	// Converter para AuthUser
 	user := &models.AuthUser{
 		ID:        authUser.ID,
+		TenantID:  authUser.TenantID,
 		Email:     authUser.Email,
 		Name:      authUser.Name,
 		Active:    authUser.Active,
+		Scope:     authUser.Scope,
 		CreatedAt: authUser.CreatedAt,
 	}
 
@@ -100,9 +173,14 @@ func (c *AuthClient) ValidateToken(token, clientID string) (*models.AuthUser, er
 }
 
 // HealthCheck verifica se o auth-service está disponível
-func (c *AuthClient) HealthCheck() error {
-	url := fmt.Sprintf("%s/api/v1/health", c.baseURL)
-	resp, err := c.httpClient.Get(url)
+func (c *AuthClient) HealthCheck(ctx context.Context) error {
+	url := fmt.Sprintf("%s/livez", c.baseURL)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("erro ao criar requisição: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("erro ao verificar saúde do auth-service: %w", err)
 	}
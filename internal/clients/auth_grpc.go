@@ -0,0 +1,77 @@
+package clients
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"time"
+
+	"study-manager-service/internal/authpb"
+	"study-manager-service/internal/config"
+	"study-manager-service/internal/logging"
+	"study-manager-service/internal/models"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// dialAuthGRPC abre a conexão com a API gRPC do auth-service. A conexão é estabelecida
+// sob demanda e reaproveitada por todas as chamadas.
+func dialAuthGRPC(cfg *config.Config) (*grpc.ClientConn, error) {
+	creds := insecure.NewCredentials()
+	if cfg.Auth.GRPCTLS {
+		creds = credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})
+	}
+
+	conn, err := grpc.NewClient(cfg.Auth.GRPCAddress,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao configurar cliente gRPC do auth-service: %w", err)
+	}
+	return conn, nil
+}
+
+// validateTokenGRPC valida o token pela API gRPC. Retorna também o motivo da falha, para as métricas.
+func (c *AuthClient) validateTokenGRPC(ctx context.Context, token, clientID string) (*models.AuthUser, string, error) {
+	ctx, cancel := context.WithTimeout(c.outgoingContext(ctx), c.timeout)
+	defer cancel()
+
+	resp, err := c.grpcClient.ValidateToken(ctx, &authpb.ValidateTokenRequest{Token: token, ClientId: clientID})
+	if err != nil {
+		st := status.Convert(err)
+		switch st.Code() {
+		case codes.Unavailable, codes.DeadlineExceeded, codes.Canceled:
+			return nil, "transport", fmt.Errorf("erro ao executar chamada gRPC: %w", err)
+		}
+		return nil, st.Code().String(), fmt.Errorf("erro do auth-service (%s): %s", st.Code(), st.Message())
+	}
+
+	user := resp.GetUser()
+	return &models.AuthUser{
+		ID:        user.GetId(),
+		TenantID:  user.GetTenantId(),
+		Email:     user.GetEmail(),
+		Name:      user.GetName(),
+		Active:    user.GetActive(),
+		Scope:     resp.GetScope(),
+		CreatedAt: user.GetCreatedAt().AsTime().Format(time.RFC3339),
+	}, "", nil
+}
+
+// outgoingContext anexa o ID da requisição e o token da API interna aos metadados da chamada
+func (c *AuthClient) outgoingContext(ctx context.Context) context.Context {
+	if requestID := logging.RequestID(ctx); requestID != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-request-id", requestID)
+	}
+	if c.grpcToken != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+c.grpcToken)
+	}
+	return ctx
+}
@@ -1,7 +1,7 @@
 package config
 
 import (
-	"log"
+	"log/slog"
 	"os"
 	"strconv"
 	"strings"
@@ -16,12 +16,15 @@ type Config struct {
 	Auth     AuthConfig
 	Security SecurityConfig
 	Upload   UploadConfig
+	Tracing  TracingConfig
+	Logging  LoggingConfig
 }
 
 // ServerConfig configurações do servidor
 type ServerConfig struct {
-	Port string
-	Mode string
+	Port                 string
+	Mode                 string
+	ShutdownDelaySeconds int // tempo em /readyz 503 antes de parar de aceitar conexões
 }
 
 // DatabaseConfig configurações do banco de dados
@@ -31,9 +34,13 @@ type DatabaseConfig struct {
 
 // AuthConfig configurações do microserviço de autenticação
 type AuthConfig struct {
-	ServiceURL string
-	ClientID   string
-	Timeout    int // em segundos
+	ServiceURL  string
+	ClientID    string
+	Timeout     int    // em segundos
+	Transport   string // "http" ou "grpc", usado na validação de tokens
+	GRPCAddress string // host:porta da API gRPC do auth-service
+	GRPCToken   string // token compartilhado exigido pela API gRPC, se configurado
+	GRPCTLS     bool
 }
 
 // SecurityConfig configurações de segurança
@@ -53,25 +60,46 @@ type UploadConfig struct {
 	AllowedMimeTypes []string
 }
 
+// TracingConfig configurações de exportação de spans OpenTelemetry
+type TracingConfig struct {
+	Exporter     string // "none", "stdout", "file" ou "otlp"
+	FilePath     string // Para o exporter "file"
+	OTLPEndpoint string // Para o exporter "otlp" (host:porta do coletor, via HTTP)
+	OTLPInsecure bool
+	ServiceName  string
+	SampleRatio  float64 // fração dos traces iniciados aqui que são amostrados (0 a 1)
+}
+
+// LoggingConfig configurações do logger estruturado
+type LoggingConfig struct {
+	Level  string // "debug", "info", "warn" ou "error"
+	Format string // "text" ou "json"
+}
+
 // Load carrega as configurações do arquivo .env e variáveis de ambiente
 func Load() *Config {
 	// Carregar arquivo .env se existir
 	if err := godotenv.Load(); err != nil {
-		log.Println("Arquivo .env não encontrado, usando variáveis de ambiente do sistema")
+		slog.Info("Arquivo .env não encontrado, usando variáveis de ambiente do sistema")
 	}
 
 	return &Config{
 		Server: ServerConfig{
-			Port: getEnv("PORT", "8080"),
-			Mode: getEnv("GIN_MODE", "debug"),
+			Port:                 getEnv("PORT", "8080"),
+			Mode:                 getEnv("GIN_MODE", "debug"),
+			ShutdownDelaySeconds: getEnvAsInt("SHUTDOWN_DELAY_SECONDS", 0),
 		},
 		Database: DatabaseConfig{
 			Path: getEnv("DB_PATH", "study_manager.db"),
 		},
 		Auth: AuthConfig{
-			ServiceURL: getEnv("AUTH_SERVICE_URL", "http://localhost:8081"),
-			ClientID:   getEnv("CLIENT_ID", ""),
-			Timeout:    getEnvAsInt("AUTH_TIMEOUT_SECONDS", 30),
+			ServiceURL:  getEnv("AUTH_SERVICE_URL", "http://localhost:8081"),
+			ClientID:    getEnv("CLIENT_ID", ""),
+			Timeout:     getEnvAsInt("AUTH_TIMEOUT_SECONDS", 30),
+			Transport:   getEnv("AUTH_TRANSPORT", "http"),
+			GRPCAddress: getEnv("AUTH_GRPC_ADDRESS", "localhost:9090"),
+			GRPCToken:   getEnv("AUTH_GRPC_TOKEN", ""),
+			GRPCTLS:     getEnvAsBool("AUTH_GRPC_TLS", false),
 		},
 		Security: SecurityConfig{
 			RateLimitRequests:      getEnvAsInt("RATE_LIMIT_REQUESTS", 100),
@@ -86,6 +114,18 @@ func Load() *Config {
 			AllowedTypes:     getEnvAsSlice("ALLOWED_FILE_TYPES", []string{".pdf", ".doc", ".docx", ".txt", ".jpg", ".jpeg", ".png", ".gif"}),
 			AllowedMimeTypes: getEnvAsSlice("ALLOWED_MIME_TYPES", []string{"application/pdf", "application/msword", "application/vnd.openxmlformats-officedocument.wordprocessingml.document", "text/plain", "image/jpeg", "image/png", "image/gif"}),
 		},
+		Tracing: TracingConfig{
+			Exporter:     getEnv("TRACING_EXPORTER", "none"),
+			FilePath:     getEnv("TRACING_FILE", "traces.json"),
+			OTLPEndpoint: getEnv("TRACING_OTLP_ENDPOINT", "localhost:4318"),
+			OTLPInsecure: getEnvAsBool("TRACING_OTLP_INSECURE", true),
+			ServiceName:  getEnv("TRACING_SERVICE_NAME", "study-manager-service"),
+			SampleRatio:  getEnvAsFloat("TRACING_SAMPLE_RATIO", 1),
+		},
+		Logging: LoggingConfig{
+			Level:  getEnv("LOG_LEVEL", "info"),
+			Format: getEnv("LOG_FORMAT", "text"),
+		},
 	}
 }
 
@@ -124,3 +164,13 @@ func getEnvAsSlice(key string, defaultValue []string) []string {
 	}
 	return defaultValue
 }
+
+// getEnvAsFloat obtém uma variável de ambiente como float ou retorna um valor padrão
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
@@ -1,18 +1,33 @@
 package database
 
 import (
-	"log"
+	"context"
+	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 
 	"study-manager-service/internal/config"
 	"study-manager-service/internal/models"
+	"study-manager-service/internal/tracing"
 
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
 
+// schemaModels são os modelos cujas tabelas são mantidas pelo AutoMigrate
+var schemaModels = []interface{}{
+	&models.Student{},
+	&models.Subject{},
+	&models.Exam{},
+	&models.StudyContent{},
+	&models.Attachment{},
+	&models.ExamReference{},
+}
+
 // Database representa a conexão com o banco de dados
 type Database struct {
 	DB *gorm.DB
@@ -42,6 +57,11 @@ func NewDatabase(cfg *config.Config) (*Database, error) {
 		return nil, err
 	}
 
+	// Criar spans para as consultas
+	if err := db.Use(tracing.GormPlugin()); err != nil {
+		return nil, err
+	}
+
 	// Configurar pool de conexões
 	sqlDB, err := db.DB()
 	if err != nil {
@@ -51,7 +71,7 @@ func NewDatabase(cfg *config.Config) (*Database, error) {
 	sqlDB.SetMaxIdleConns(10)
 	sqlDB.SetMaxOpenConns(100)
 
-	log.Printf("Conexão com banco de dados SQLite estabelecida: %s", cfg.Database.Path)
+	slog.Info("Conexão com banco de dados SQLite estabelecida", "path", cfg.Database.Path)
 
 	return &Database{DB: db}, nil
 }
@@ -65,22 +85,41 @@ func (d *Database) Close() error {
 	return sqlDB.Close()
 }
 
+// Ping verifica se o banco de dados responde
+func (d *Database) Ping(ctx context.Context) error {
+	sqlDB, err := d.DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// CheckSchema retorna erro se alguma tabela dos modelos ainda não existir
+func (d *Database) CheckSchema(ctx context.Context) error {
+	migrator := d.DB.WithContext(ctx).Migrator()
+
+	var missing []string
+	for _, model := range schemaModels {
+		if !migrator.HasTable(model) {
+			missing = append(missing, reflect.TypeOf(model).Elem().Name())
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("tabelas ausentes: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
 // Migrate executa as migrações do banco de dados
 func (d *Database) Migrate() error {
-	err := d.DB.AutoMigrate(
-		&models.Student{},
-		&models.Subject{},
-		&models.Exam{},
-		&models.StudyContent{},
-		&models.Attachment{},
-		&models.ExamReference{},
-	)
+	err := d.DB.AutoMigrate(schemaModels...)
 
 	if err != nil {
 		return err
 	}
 
-	log.Println("Migrações do banco de dados executadas com sucesso")
+	slog.Info("Migrações do banco de dados executadas com sucesso")
 	return nil
 }
 
@@ -122,6 +161,6 @@ func (d *Database) CreateIndexes() error {
 		return err
 	}
 
-	log.Println("Índices do banco de dados criados com sucesso")
+	slog.Info("Índices do banco de dados criados com sucesso")
 	return nil
 }
@@ -27,20 +27,22 @@ func (h *ExamHandler) CreateExamHandler(c *gin.Context) {
 	var req models.ExamCreateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "dados inválidos",
-			"code":    "INVALID_DATA",
-			"message": err.Error(),
+			"error":      "dados inválidos",
+			"code":       "INVALID_DATA",
+			"message":    err.Error(),
+			"request_id": c.GetString("request_id"),
 		})
 		return
 	}
 
 	userID := c.GetString("user_id")
-	exam, err := h.examService.CreateExam(&req, userID)
+	exam, err := h.examService.CreateExam(c.Request.Context(), &req, userID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "erro ao criar prova/trabalho",
-			"code":    "CREATE_ERROR",
-			"message": err.Error(),
+			"error":      "erro ao criar prova/trabalho",
+			"code":       "CREATE_ERROR",
+			"message":    err.Error(),
+			"request_id": c.GetString("request_id"),
 		})
 		return
 	}
@@ -51,12 +53,13 @@ func (h *ExamHandler) CreateExamHandler(c *gin.Context) {
 // GetExamsHandler lista provas/trabalhos do usuário
 func (h *ExamHandler) GetExamsHandler(c *gin.Context) {
 	userID := c.GetString("user_id")
-	exams, err := h.examService.GetExams(userID)
+	exams, err := h.examService.GetExams(c.Request.Context(), userID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "erro ao buscar provas/trabalhos",
-			"code":    "FETCH_ERROR",
-			"message": err.Error(),
+			"error":      "erro ao buscar provas/trabalhos",
+			"code":       "FETCH_ERROR",
+			"message":    err.Error(),
+			"request_id": c.GetString("request_id"),
 		})
 		return
 	}
@@ -73,20 +76,22 @@ func (h *ExamHandler) GetExamByIDHandler(c *gin.Context) {
 	id, err := uuid.Parse(idStr)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "ID inválido",
-			"code":    "INVALID_ID",
-			"message": "ID deve ser um UUID válido",
+			"error":      "ID inválido",
+			"code":       "INVALID_ID",
+			"message":    "ID deve ser um UUID válido",
+			"request_id": c.GetString("request_id"),
 		})
 		return
 	}
 
 	userID := c.GetString("user_id")
-	exam, err := h.examService.GetExamByID(id, userID)
+	exam, err := h.examService.GetExamByID(c.Request.Context(), id, userID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
-			"error":   "prova/trabalho não encontrado",
-			"code":    "NOT_FOUND",
-			"message": err.Error(),
+			"error":      "prova/trabalho não encontrado",
+			"code":       "NOT_FOUND",
+			"message":    err.Error(),
+			"request_id": c.GetString("request_id"),
 		})
 		return
 	}
@@ -98,9 +103,10 @@ func (h *ExamHandler) GetExamByIDHandler(c *gin.Context) {
 func (h *ExamHandler) GetExamDetailsHandler(c *gin.Context) {
 	// Implementar busca com detalhes completos
 	c.JSON(http.StatusNotImplemented, gin.H{
-		"error":   "funcionalidade não implementada",
-		"code":    "NOT_IMPLEMENTED",
-		"message": "Busca de prova/trabalho com detalhes ainda não implementada",
+		"error":      "funcionalidade não implementada",
+		"code":       "NOT_IMPLEMENTED",
+		"message":    "Busca de prova/trabalho com detalhes ainda não implementada",
+		"request_id": c.GetString("request_id"),
 	})
 }
 
@@ -110,9 +116,10 @@ func (h *ExamHandler) UpdateExamHandler(c *gin.Context) {
 	id, err := uuid.Parse(idStr)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "ID inválido",
-			"code":    "INVALID_ID",
-			"message": "ID deve ser um UUID válido",
+			"error":      "ID inválido",
+			"code":       "INVALID_ID",
+			"message":    "ID deve ser um UUID válido",
+			"request_id": c.GetString("request_id"),
 		})
 		return
 	}
@@ -120,20 +127,22 @@ func (h *ExamHandler) UpdateExamHandler(c *gin.Context) {
 	var req models.ExamUpdateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "dados inválidos",
-			"code":    "INVALID_DATA",
-			"message": err.Error(),
+			"error":      "dados inválidos",
+			"code":       "INVALID_DATA",
+			"message":    err.Error(),
+			"request_id": c.GetString("request_id"),
 		})
 		return
 	}
 
 	userID := c.GetString("user_id")
-	exam, err := h.examService.UpdateExam(id, &req, userID)
+	exam, err := h.examService.UpdateExam(c.Request.Context(), id, &req, userID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "erro ao atualizar prova/trabalho",
-			"code":    "UPDATE_ERROR",
-			"message": err.Error(),
+			"error":      "erro ao atualizar prova/trabalho",
+			"code":       "UPDATE_ERROR",
+			"message":    err.Error(),
+			"request_id": c.GetString("request_id"),
 		})
 		return
 	}
@@ -147,20 +156,22 @@ func (h *ExamHandler) DeleteExamHandler(c *gin.Context) {
 	id, err := uuid.Parse(idStr)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "ID inválido",
-			"code":    "INVALID_ID",
-			"message": "ID deve ser um UUID válido",
+			"error":      "ID inválido",
+			"code":       "INVALID_ID",
+			"message":    "ID deve ser um UUID válido",
+			"request_id": c.GetString("request_id"),
 		})
 		return
 	}
 
 	userID := c.GetString("user_id")
-	err = h.examService.DeleteExam(id, userID)
+	err = h.examService.DeleteExam(c.Request.Context(), id, userID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "erro ao remover prova/trabalho",
-			"code":    "DELETE_ERROR",
-			"message": err.Error(),
+			"error":      "erro ao remover prova/trabalho",
+			"code":       "DELETE_ERROR",
+			"message":    err.Error(),
+			"request_id": c.GetString("request_id"),
 		})
 		return
 	}
@@ -172,8 +183,9 @@ func (h *ExamHandler) DeleteExamHandler(c *gin.Context) {
 func (h *ExamHandler) GetExamsBySubjectHandler(c *gin.Context) {
 	// Implementar busca por matéria
 	c.JSON(http.StatusNotImplemented, gin.H{
-		"error":   "funcionalidade não implementada",
-		"code":    "NOT_IMPLEMENTED",
-		"message": "Busca de provas/trabalhos por matéria ainda não implementada",
+		"error":      "funcionalidade não implementada",
+		"code":       "NOT_IMPLEMENTED",
+		"message":    "Busca de provas/trabalhos por matéria ainda não implementada",
+		"request_id": c.GetString("request_id"),
 	})
 }
@@ -27,9 +27,10 @@ func (h *StudentHandler) CreateStudentHandler(c *gin.Context) {
 	var req models.StudentCreateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "dados inválidos",
-			"code":    "INVALID_DATA",
-			"message": err.Error(),
+			"error":      "dados inválidos",
+			"code":       "INVALID_DATA",
+			"message":    err.Error(),
+			"request_id": c.GetString("request_id"),
 		})
 		return
 	}
@@ -38,19 +39,21 @@ func (h *StudentHandler) CreateStudentHandler(c *gin.Context) {
 	userID := c.GetHeader("X-User-ID")
 	if userID == "" {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "user_id não fornecido",
-			"code":    "MISSING_USER_ID",
-			"message": "Header X-User-ID é obrigatório para registro de estudante",
+			"error":      "user_id não fornecido",
+			"code":       "MISSING_USER_ID",
+			"message":    "Header X-User-ID é obrigatório para registro de estudante",
+			"request_id": c.GetString("request_id"),
 		})
 		return
 	}
 
-	student, err := h.studentService.CreateStudent(&req, userID)
+	student, err := h.studentService.CreateStudent(c.Request.Context(), &req, userID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "erro ao criar estudante",
-			"code":    "CREATE_ERROR",
-			"message": err.Error(),
+			"error":      "erro ao criar estudante",
+			"code":       "CREATE_ERROR",
+			"message":    err.Error(),
+			"request_id": c.GetString("request_id"),
 		})
 		return
 	}
@@ -64,19 +67,21 @@ func (h *StudentHandler) GetStudentByIDHandler(c *gin.Context) {
 	id, err := uuid.Parse(idStr)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "ID inválido",
-			"code":    "INVALID_ID",
-			"message": "ID deve ser um UUID válido",
+			"error":      "ID inválido",
+			"code":       "INVALID_ID",
+			"message":    "ID deve ser um UUID válido",
+			"request_id": c.GetString("request_id"),
 		})
 		return
 	}
 
-	student, err := h.studentService.GetStudentByID(id)
+	student, err := h.studentService.GetStudentByID(c.Request.Context(), id)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
-			"error":   "estudante não encontrado",
-			"code":    "NOT_FOUND",
-			"message": err.Error(),
+			"error":      "estudante não encontrado",
+			"code":       "NOT_FOUND",
+			"message":    err.Error(),
+			"request_id": c.GetString("request_id"),
 		})
 		return
 	}
@@ -89,19 +94,21 @@ func (h *StudentHandler) GetStudentByUserIDHandler(c *gin.Context) {
 	userID := c.Param("user_id")
 	if userID == "" {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "user_id não fornecido",
-			"code":    "MISSING_USER_ID",
-			"message": "user_id é obrigatório",
+			"error":      "user_id não fornecido",
+			"code":       "MISSING_USER_ID",
+			"message":    "user_id é obrigatório",
+			"request_id": c.GetString("request_id"),
 		})
 		return
 	}
 
-	student, err := h.studentService.GetStudentByUserID(userID)
+	student, err := h.studentService.GetStudentByUserID(c.Request.Context(), userID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
-			"error":   "estudante não encontrado",
-			"code":    "NOT_FOUND",
-			"message": err.Error(),
+			"error":      "estudante não encontrado",
+			"code":       "NOT_FOUND",
+			"message":    err.Error(),
+			"request_id": c.GetString("request_id"),
 		})
 		return
 	}
@@ -111,12 +118,13 @@ func (h *StudentHandler) GetStudentByUserIDHandler(c *gin.Context) {
 
 // GetAllStudentsHandler lista todos os estudantes
 func (h *StudentHandler) GetAllStudentsHandler(c *gin.Context) {
-	students, err := h.studentService.GetAllStudents()
+	students, err := h.studentService.GetAllStudents(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "erro ao buscar estudantes",
-			"code":    "FETCH_ERROR",
-			"message": err.Error(),
+			"error":      "erro ao buscar estudantes",
+			"code":       "FETCH_ERROR",
+			"message":    err.Error(),
+			"request_id": c.GetString("request_id"),
 		})
 		return
 	}
@@ -133,9 +141,10 @@ func (h *StudentHandler) UpdateStudentHandler(c *gin.Context) {
 	id, err := uuid.Parse(idStr)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "ID inválido",
-			"code":    "INVALID_ID",
-			"message": "ID deve ser um UUID válido",
+			"error":      "ID inválido",
+			"code":       "INVALID_ID",
+			"message":    "ID deve ser um UUID válido",
+			"request_id": c.GetString("request_id"),
 		})
 		return
 	}
@@ -143,15 +152,16 @@ func (h *StudentHandler) UpdateStudentHandler(c *gin.Context) {
 	var req models.StudentUpdateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "dados inválidos",
-			"code":    "INVALID_DATA",
-			"message": err.Error(),
+			"error":      "dados inválidos",
+			"code":       "INVALID_DATA",
+			"message":    err.Error(),
+			"request_id": c.GetString("request_id"),
 		})
 		return
 	}
 
 	userID := c.GetString("user_id")
-	student, err := h.studentService.UpdateStudent(id, &req, userID)
+	student, err := h.studentService.UpdateStudent(c.Request.Context(), id, &req, userID)
 	if err != nil {
 		status := http.StatusInternalServerError
 		if err.Error() == "estudante não encontrado" {
@@ -161,9 +171,10 @@ func (h *StudentHandler) UpdateStudentHandler(c *gin.Context) {
 		}
 
 		c.JSON(status, gin.H{
-			"error":   "erro ao atualizar estudante",
-			"code":    "UPDATE_ERROR",
-			"message": err.Error(),
+			"error":      "erro ao atualizar estudante",
+			"code":       "UPDATE_ERROR",
+			"message":    err.Error(),
+			"request_id": c.GetString("request_id"),
 		})
 		return
 	}
@@ -177,15 +188,16 @@ func (h *StudentHandler) DeleteStudentHandler(c *gin.Context) {
 	id, err := uuid.Parse(idStr)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "ID inválido",
-			"code":    "INVALID_ID",
-			"message": "ID deve ser um UUID válido",
+			"error":      "ID inválido",
+			"code":       "INVALID_ID",
+			"message":    "ID deve ser um UUID válido",
+			"request_id": c.GetString("request_id"),
 		})
 		return
 	}
 
 	userID := c.GetString("user_id")
-	err = h.studentService.DeleteStudent(id, userID)
+	err = h.studentService.DeleteStudent(c.Request.Context(), id, userID)
 	if err != nil {
 		status := http.StatusInternalServerError
 		if err.Error() == "estudante não encontrado" {
@@ -195,9 +207,10 @@ func (h *StudentHandler) DeleteStudentHandler(c *gin.Context) {
 		}
 
 		c.JSON(status, gin.H{
-			"error":   "erro ao remover estudante",
-			"code":    "DELETE_ERROR",
-			"message": err.Error(),
+			"error":      "erro ao remover estudante",
+			"code":       "DELETE_ERROR",
+			"message":    err.Error(),
+			"request_id": c.GetString("request_id"),
 		})
 		return
 	}
@@ -27,20 +27,22 @@ func (h *SubjectHandler) CreateSubjectHandler(c *gin.Context) {
 	var req models.SubjectCreateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "dados inválidos",
-			"code":    "INVALID_DATA",
-			"message": err.Error(),
+			"error":      "dados inválidos",
+			"code":       "INVALID_DATA",
+			"message":    err.Error(),
+			"request_id": c.GetString("request_id"),
 		})
 		return
 	}
 
 	userID := c.GetString("user_id")
-	subject, err := h.subjectService.CreateSubject(&req, userID)
+	subject, err := h.subjectService.CreateSubject(c.Request.Context(), &req, userID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "erro ao criar matéria",
-			"code":    "CREATE_ERROR",
-			"message": err.Error(),
+			"error":      "erro ao criar matéria",
+			"code":       "CREATE_ERROR",
+			"message":    err.Error(),
+			"request_id": c.GetString("request_id"),
 		})
 		return
 	}
@@ -51,12 +53,13 @@ func (h *SubjectHandler) CreateSubjectHandler(c *gin.Context) {
 // GetSubjectsHandler lista matérias do usuário
 func (h *SubjectHandler) GetSubjectsHandler(c *gin.Context) {
 	userID := c.GetString("user_id")
-	subjects, err := h.subjectService.GetSubjects(userID)
+	subjects, err := h.subjectService.GetSubjects(c.Request.Context(), userID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "erro ao buscar matérias",
-			"code":    "FETCH_ERROR",
-			"message": err.Error(),
+			"error":      "erro ao buscar matérias",
+			"code":       "FETCH_ERROR",
+			"message":    err.Error(),
+			"request_id": c.GetString("request_id"),
 		})
 		return
 	}
@@ -73,20 +76,22 @@ func (h *SubjectHandler) GetSubjectByIDHandler(c *gin.Context) {
 	id, err := uuid.Parse(idStr)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "ID inválido",
-			"code":    "INVALID_ID",
-			"message": "ID deve ser um UUID válido",
+			"error":      "ID inválido",
+			"code":       "INVALID_ID",
+			"message":    "ID deve ser um UUID válido",
+			"request_id": c.GetString("request_id"),
 		})
 		return
 	}
 
 	userID := c.GetString("user_id")
-	subject, err := h.subjectService.GetSubjectByID(id, userID)
+	subject, err := h.subjectService.GetSubjectByID(c.Request.Context(), id, userID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
-			"error":   "matéria não encontrada",
-			"code":    "NOT_FOUND",
-			"message": err.Error(),
+			"error":      "matéria não encontrada",
+			"code":       "NOT_FOUND",
+			"message":    err.Error(),
+			"request_id": c.GetString("request_id"),
 		})
 		return
 	}
@@ -98,9 +103,10 @@ func (h *SubjectHandler) GetSubjectByIDHandler(c *gin.Context) {
 func (h *SubjectHandler) GetSubjectWithExamsHandler(c *gin.Context) {
 	// Implementar busca com provas/trabalhos
 	c.JSON(http.StatusNotImplemented, gin.H{
-		"error":   "funcionalidade não implementada",
-		"code":    "NOT_IMPLEMENTED",
-		"message": "Busca de matéria com provas/trabalhos ainda não implementada",
+		"error":      "funcionalidade não implementada",
+		"code":       "NOT_IMPLEMENTED",
+		"message":    "Busca de matéria com provas/trabalhos ainda não implementada",
+		"request_id": c.GetString("request_id"),
 	})
 }
 
@@ -110,9 +116,10 @@ func (h *SubjectHandler) UpdateSubjectHandler(c *gin.Context) {
 	id, err := uuid.Parse(idStr)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "ID inválido",
-			"code":    "INVALID_ID",
-			"message": "ID deve ser um UUID válido",
+			"error":      "ID inválido",
+			"code":       "INVALID_ID",
+			"message":    "ID deve ser um UUID válido",
+			"request_id": c.GetString("request_id"),
 		})
 		return
 	}
@@ -120,20 +127,22 @@ func (h *SubjectHandler) UpdateSubjectHandler(c *gin.Context) {
 	var req models.SubjectUpdateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "dados inválidos",
-			"code":    "INVALID_DATA",
-			"message": err.Error(),
+			"error":      "dados inválidos",
+			"code":       "INVALID_DATA",
+			"message":    err.Error(),
+			"request_id": c.GetString("request_id"),
 		})
 		return
 	}
 
 	userID := c.GetString("user_id")
-	subject, err := h.subjectService.UpdateSubject(id, &req, userID)
+	subject, err := h.subjectService.UpdateSubject(c.Request.Context(), id, &req, userID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "erro ao atualizar matéria",
-			"code":    "UPDATE_ERROR",
-			"message": err.Error(),
+			"error":      "erro ao atualizar matéria",
+			"code":       "UPDATE_ERROR",
+			"message":    err.Error(),
+			"request_id": c.GetString("request_id"),
 		})
 		return
 	}
@@ -147,20 +156,22 @@ func (h *SubjectHandler) DeleteSubjectHandler(c *gin.Context) {
 	id, err := uuid.Parse(idStr)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "ID inválido",
-			"code":    "INVALID_ID",
-			"message": "ID deve ser um UUID válido",
+			"error":      "ID inválido",
+			"code":       "INVALID_ID",
+			"message":    "ID deve ser um UUID válido",
+			"request_id": c.GetString("request_id"),
 		})
 		return
 	}
 
 	userID := c.GetString("user_id")
-	err = h.subjectService.DeleteSubject(id, userID)
+	err = h.subjectService.DeleteSubject(c.Request.Context(), id, userID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "erro ao remover matéria",
-			"code":    "DELETE_ERROR",
-			"message": err.Error(),
+			"error":      "erro ao remover matéria",
+			"code":       "DELETE_ERROR",
+			"message":    err.Error(),
+			"request_id": c.GetString("request_id"),
 		})
 		return
 	}
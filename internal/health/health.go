@@ -0,0 +1,116 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// checkTimeout limita cada verificação de prontidão, para que uma dependência lenta
+// não segure a resposta além do timeout do balanceador
+const checkTimeout = 2 * time.Second
+
+// CheckFunc verifica uma dependência; um erro torna o serviço não pronto
+type CheckFunc func(ctx context.Context) error
+
+// CheckResult é o resultado de uma verificação em /readyz
+type CheckResult struct {
+	Status    string `json:"status"`
+	LatencyMS int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Report é a resposta de /readyz
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks,omitempty"`
+}
+
+type namedCheck struct {
+	name  string
+	check CheckFunc
+}
+
+// Checker agrega as verificações de prontidão do serviço
+type Checker struct {
+	checks   []namedCheck
+	draining atomic.Bool
+}
+
+func NewChecker() *Checker {
+	return &Checker{}
+}
+
+// Register adiciona uma verificação de prontidão. Deve ser chamado antes de servir requisições.
+func (c *Checker) Register(name string, check CheckFunc) {
+	c.checks = append(c.checks, namedCheck{name: name, check: check})
+}
+
+// Drain marca o serviço como não pronto, para que os balanceadores parem de enviar
+// requisições novas antes do desligamento
+func (c *Checker) Drain() {
+	c.draining.Store(true)
+}
+
+// Check executa todas as verificações em paralelo
+func (c *Checker) Check(ctx context.Context) Report {
+	if c.draining.Load() {
+		return Report{Status: "draining"}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	report := Report{Status: "ready", Checks: make(map[string]CheckResult, len(c.checks))}
+
+	for _, nc := range c.checks {
+		wg.Add(1)
+		go func(nc namedCheck) {
+			defer wg.Done()
+
+			start := time.Now()
+			err := nc.check(ctx)
+			result := CheckResult{Status: "ok", LatencyMS: time.Since(start).Milliseconds()}
+			if err != nil {
+				result.Status = "fail"
+				result.Error = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[nc.name] = result
+			if err != nil {
+				report.Status = "not_ready"
+			}
+		}(nc)
+	}
+	wg.Wait()
+
+	return report
+}
+
+// LiveHandler responde 200 enquanto o processo estiver de pé, sem consultar dependências
+func (c *Checker) LiveHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, gin.H{"status": "ok"})
+	}
+}
+
+// ReadyHandler responde 200 quando todas as dependências estão disponíveis e 503 caso contrário
+func (c *Checker) ReadyHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		report := c.Check(ctx.Request.Context())
+
+		status := http.StatusOK
+		if report.Status != "ready" {
+			status = http.StatusServiceUnavailable
+		}
+		ctx.JSON(status, report)
+	}
+}
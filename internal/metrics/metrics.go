@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "study_manager"
+
+var (
+	// HTTPRequestDuration mede a latência das requisições por rota
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Duração das requisições HTTP por método, rota e status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	// RateLimitRejections conta requisições recusadas pelo rate limiter
+	RateLimitRejections = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limit_rejections_total",
+		Help:      "Requisições recusadas por excesso de requisições.",
+	})
+
+	// AuthValidateDuration mede a latência das validações de token no auth-service
+	AuthValidateDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "auth_validate_duration_seconds",
+		Help:      "Duração das chamadas de validação de token ao auth-service.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"outcome"})
+
+	// AuthValidateErrors conta falhas na validação de token por motivo
+	// (transport, decode ou o status HTTP retornado pelo auth-service)
+	AuthValidateErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "auth_validate_errors_total",
+		Help:      "Falhas nas chamadas de validação de token ao auth-service.",
+	}, []string{"reason"})
+)
+
+// Handler expõe as métricas no formato texto do Prometheus
+func Handler() gin.HandlerFunc {
+	return gin.WrapH(promhttp.Handler())
+}
@@ -1,8 +1,7 @@
 package middleware
 
 import (
-	"encoding/json"
-	"log"
+	"log/slog"
 	"time"
 
 	"study-manager-service/internal/config"
@@ -59,24 +58,16 @@ func (al *AuditLogger) isSensitiveOperation(method string) bool {
 
 // logSensitiveOperation registra uma operação sensível
 func (al *AuditLogger) logSensitiveOperation(c *gin.Context, method, path string, duration time.Duration) {
-	userID := c.GetString("user_id")
-	clientIP := c.ClientIP()
-	userAgent := c.GetHeader("User-Agent")
-	statusCode := c.Writer.Status()
-
-	auditLog := map[string]interface{}{
-		"timestamp":    time.Now().UTC().Format(time.RFC3339),
-		"event_type":   "SENSITIVE_OPERATION",
-		"method":       method,
-		"path":         path,
-		"user_id":      userID,
-		"client_ip":    clientIP,
-		"user_agent":   userAgent,
-		"status_code":  statusCode,
-		"duration_ms":  duration.Milliseconds(),
-		"service":      "study-manager-service",
-	}
-
-	logJSON, _ := json.Marshal(auditLog)
-	log.Printf("AUDIT: %s", string(logJSON))
+	// O request_id e o trace_id são acrescentados pelo logger a partir do contexto
+	slog.InfoContext(c.Request.Context(), "AUDIT",
+		"event_type", "SENSITIVE_OPERATION",
+		"method", method,
+		"path", path,
+		"user_id", c.GetString("user_id"),
+		"client_ip", c.ClientIP(),
+		"user_agent", c.GetHeader("User-Agent"),
+		"status_code", c.Writer.Status(),
+		"duration_ms", duration.Milliseconds(),
+		"service", "study-manager-service",
+	)
 }
@@ -28,9 +28,10 @@ func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
 			c.JSON(http.StatusUnauthorized, gin.H{
-				"error":   "token de autorização não fornecido",
-				"code":    "MISSING_AUTH_TOKEN",
-				"message": "Header Authorization é obrigatório",
+				"error":      "token de autorização não fornecido",
+				"code":       "MISSING_AUTH_TOKEN",
+				"message":    "Header Authorization é obrigatório",
+				"request_id": c.GetString("request_id"),
 			})
 			c.Abort()
 			return
@@ -40,9 +41,10 @@ func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 		tokenParts := strings.Split(authHeader, " ")
 		if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
 			c.JSON(http.StatusUnauthorized, gin.H{
-				"error":   "formato de token inválido",
-				"code":    "INVALID_TOKEN_FORMAT",
-				"message": "Token deve estar no formato 'Bearer <token>'",
+				"error":      "formato de token inválido",
+				"code":       "INVALID_TOKEN_FORMAT",
+				"message":    "Token deve estar no formato 'Bearer <token>'",
+				"request_id": c.GetString("request_id"),
 			})
 			c.Abort()
 			return
@@ -58,21 +60,23 @@ func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 
 		if clientID == "" {
 			c.JSON(http.StatusBadRequest, gin.H{
-				"error":   "client_id não fornecido",
-				"code":    "MISSING_CLIENT_ID",
-				"message": "Header X-Client-ID ou parâmetro client_id é obrigatório",
+				"error":      "client_id não fornecido",
+				"code":       "MISSING_CLIENT_ID",
+				"message":    "Header X-Client-ID ou parâmetro client_id é obrigatório",
+				"request_id": c.GetString("request_id"),
 			})
 			c.Abort()
 			return
 		}
 
 		// Validar token com o auth-service
-		user, err := m.authClient.ValidateToken(token, clientID)
+		user, err := m.authClient.ValidateToken(c.Request.Context(), token, clientID)
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{
-				"error":   "token inválido",
-				"code":    "INVALID_TOKEN",
-				"message": err.Error(),
+				"error":      "token inválido",
+				"code":       "INVALID_TOKEN",
+				"message":    err.Error(),
+				"request_id": c.GetString("request_id"),
 			})
 			c.Abort()
 			return
@@ -81,9 +85,22 @@ func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 		// Verificar se o usuário está ativo
 		if !user.Active {
 			c.JSON(http.StatusUnauthorized, gin.H{
-				"error":   "usuário inativo",
-				"code":    "USER_INACTIVE",
-				"message": "Usuário não está ativo no sistema",
+				"error":      "usuário inativo",
+				"code":       "USER_INACTIVE",
+				"message":    "Usuário não está ativo no sistema",
+				"request_id": c.GetString("request_id"),
+			})
+			c.Abort()
+			return
+		}
+
+		// Tokens de acesso pessoal sem o escopo "write" só podem fazer leituras
+		if user.Scope != "" && !isReadOnlyMethod(c.Request.Method) && !hasScope(user.Scope, "write") {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":      "escopo insuficiente",
+				"code":       "INSUFFICIENT_SCOPE",
+				"message":    "Token de acesso pessoal sem o escopo 'write'",
+				"request_id": c.GetString("request_id"),
 			})
 			c.Abort()
 			return
@@ -108,9 +125,10 @@ func (m *AuthMiddleware) RequireClientID() gin.HandlerFunc {
 
 		if clientID == "" {
 			c.JSON(http.StatusBadRequest, gin.H{
-				"error":   "client_id não fornecido",
-				"code":    "MISSING_CLIENT_ID",
-				"message": "Header X-Client-ID ou parâmetro client_id é obrigatório",
+				"error":      "client_id não fornecido",
+				"code":       "MISSING_CLIENT_ID",
+				"message":    "Header X-Client-ID ou parâmetro client_id é obrigatório",
+				"request_id": c.GetString("request_id"),
 			})
 			c.Abort()
 			return
@@ -120,3 +138,16 @@ func (m *AuthMiddleware) RequireClientID() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+func isReadOnlyMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+func hasScope(scope, want string) bool {
+	for _, s := range strings.Fields(scope) {
+		if s == want {
+			return true
+		}
+	}
+	return false
+}
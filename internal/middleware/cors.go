@@ -14,8 +14,8 @@ func CORS(cfg *config.Config) gin.HandlerFunc {
 	config := cors.Config{
 		AllowOrigins:     cfg.Security.AllowedOrigins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Authorization", "X-Requested-With", "X-Client-ID"},
-		ExposeHeaders:    []string{"Content-Length", "X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset"},
+		AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Authorization", "X-Requested-With", "X-Client-ID", "X-Request-ID"},
+		ExposeHeaders:    []string{"Content-Length", "X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset", "X-Request-ID"},
 		AllowCredentials: true,
 		MaxAge:           12 * 60 * 60, // 12 horas
 	}
@@ -53,9 +53,10 @@ func ValidateOrigin(cfg *config.Config) gin.HandlerFunc {
 
 		if !allowed {
 			c.JSON(403, gin.H{
-				"error":   "origem não permitida",
-				"code":    "ORIGIN_NOT_ALLOWED",
-				"message": "Origem da requisição não está na lista de origens permitidas",
+				"error":      "origem não permitida",
+				"code":       "ORIGIN_NOT_ALLOWED",
+				"message":    "Origem da requisição não está na lista de origens permitidas",
+				"request_id": c.GetString("request_id"),
 			})
 			c.Abort()
 			return
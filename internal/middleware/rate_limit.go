@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"study-manager-service/internal/config"
+	"study-manager-service/internal/metrics"
 
 	"github.com/gin-gonic/gin"
 )
@@ -48,6 +49,8 @@ func (rl *RateLimiter) RateLimit() gin.HandlerFunc {
 
 		// Verificar se excedeu o limite
 		if len(rl.requests[clientIP]) >= rl.limit {
+			metrics.RateLimitRejections.Inc()
+
 			resetTime := rl.requests[clientIP][0].Add(rl.window)
 			c.Header("X-RateLimit-Limit", string(rune(rl.limit)))
 			c.Header("X-RateLimit-Remaining", "0")
@@ -55,10 +58,11 @@ func (rl *RateLimiter) RateLimit() gin.HandlerFunc {
 			c.Header("Retry-After", string(rune(int(rl.window.Seconds()))))
 
 			c.JSON(http.StatusTooManyRequests, gin.H{
-				"error":   "limite de requisições excedido",
-				"code":    "RATE_LIMIT_EXCEEDED",
-				"message": "Muitas requisições. Tente novamente mais tarde.",
+				"error":       "limite de requisições excedido",
+				"code":        "RATE_LIMIT_EXCEEDED",
+				"message":     "Muitas requisições. Tente novamente mais tarde.",
 				"retry_after": int(rl.window.Seconds()),
+				"request_id":  c.GetString("request_id"),
 			})
 			c.Abort()
 			return
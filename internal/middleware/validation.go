@@ -25,9 +25,10 @@ func (vm *ValidationMiddleware) ValidateInput() gin.HandlerFunc {
 		// Verificar tamanho da requisição
 		if c.Request.ContentLength > vm.cfg.Security.MaxRequestSize {
 			c.JSON(http.StatusRequestEntityTooLarge, gin.H{
-				"error":   "requisição muito grande",
-				"code":    "REQUEST_TOO_LARGE",
-				"message": "Tamanho da requisição excede o limite permitido",
+				"error":      "requisição muito grande",
+				"code":       "REQUEST_TOO_LARGE",
+				"message":    "Tamanho da requisição excede o limite permitido",
+				"request_id": c.GetString("request_id"),
 			})
 			c.Abort()
 			return
@@ -36,9 +37,10 @@ func (vm *ValidationMiddleware) ValidateInput() gin.HandlerFunc {
 		// Verificar headers suspeitos
 		if vm.hasSuspiciousHeaders(c) {
 			c.JSON(http.StatusBadRequest, gin.H{
-				"error":   "headers suspeitos detectados",
-				"code":    "SUSPICIOUS_HEADERS",
-				"message": "Headers maliciosos detectados na requisição",
+				"error":      "headers suspeitos detectados",
+				"code":       "SUSPICIOUS_HEADERS",
+				"message":    "Headers maliciosos detectados na requisição",
+				"request_id": c.GetString("request_id"),
 			})
 			c.Abort()
 			return
@@ -47,9 +49,10 @@ func (vm *ValidationMiddleware) ValidateInput() gin.HandlerFunc {
 		// Verificar user agent suspeito
 		if vm.hasSuspiciousUserAgent(c) {
 			c.JSON(http.StatusBadRequest, gin.H{
-				"error":   "user agent suspeito",
-				"code":    "SUSPICIOUS_USER_AGENT",
-				"message": "User agent malicioso detectado",
+				"error":      "user agent suspeito",
+				"code":       "SUSPICIOUS_USER_AGENT",
+				"message":    "User agent malicioso detectado",
+				"request_id": c.GetString("request_id"),
 			})
 			c.Abort()
 			return
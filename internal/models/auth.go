@@ -3,9 +3,11 @@ package models
 // AuthUser representa os dados do usuário autenticado vindos do auth-service
 type AuthUser struct {
 	ID        string `json:"id"`
+	TenantID  string `json:"tenant_id"` // organização do usuário no auth-service
 	Email     string `json:"email"`
 	Name      string `json:"name"`
 	Active    bool   `json:"active"`
+	Scope     string `json:"scope,omitempty"` // escopos do token de acesso pessoal; vazio para JWTs
 	CreatedAt string `json:"created_at"`
 }
 
@@ -18,8 +20,10 @@ type ValidateTokenRequest struct {
 // ValidateTokenResponse representa a resposta da validação de token
 type ValidateTokenResponse struct {
 	ID        string `json:"id"`
+	TenantID  string `json:"tenant_id"`
 	Email     string `json:"email"`
 	Name      string `json:"name"`
 	Active    bool   `json:"active"`
+	Scope     string `json:"scope"`
 	CreatedAt string `json:"created_at"`
 }
@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"context"
+
 	"study-manager-service/internal/models"
 
 	"github.com/google/uuid"
@@ -18,14 +20,14 @@ func NewExamRepository(db *gorm.DB) *ExamRepository {
 }
 
 // Create cria uma nova prova/trabalho
-func (r *ExamRepository) Create(exam *models.Exam) error {
-	return r.db.Create(exam).Error
+func (r *ExamRepository) Create(ctx context.Context, exam *models.Exam) error {
+	return r.db.WithContext(ctx).Create(exam).Error
 }
 
 // GetByID busca uma prova/trabalho por ID
-func (r *ExamRepository) GetByID(id uuid.UUID) (*models.Exam, error) {
+func (r *ExamRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Exam, error) {
 	var exam models.Exam
-	err := r.db.Where("id = ?", id).First(&exam).Error
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&exam).Error
 	if err != nil {
 		return nil, err
 	}
@@ -33,27 +35,27 @@ func (r *ExamRepository) GetByID(id uuid.UUID) (*models.Exam, error) {
 }
 
 // GetBySubjectID busca provas/trabalhos por Subject ID
-func (r *ExamRepository) GetBySubjectID(subjectID uuid.UUID) ([]models.Exam, error) {
+func (r *ExamRepository) GetBySubjectID(ctx context.Context, subjectID uuid.UUID) ([]models.Exam, error) {
 	var exams []models.Exam
-	err := r.db.Where("subject_id = ?", subjectID).Find(&exams).Error
+	err := r.db.WithContext(ctx).Where("subject_id = ?", subjectID).Find(&exams).Error
 	return exams, err
 }
 
 // GetByStudentID busca provas/trabalhos por Student ID
-func (r *ExamRepository) GetByStudentID(studentID uuid.UUID) ([]models.Exam, error) {
+func (r *ExamRepository) GetByStudentID(ctx context.Context, studentID uuid.UUID) ([]models.Exam, error) {
 	var exams []models.Exam
-	err := r.db.Joins("JOIN subjects ON exams.subject_id = subjects.id").
+	err := r.db.WithContext(ctx).Joins("JOIN subjects ON exams.subject_id = subjects.id").
 		Where("subjects.student_id = ?", studentID).
 		Find(&exams).Error
 	return exams, err
 }
 
 // Update atualiza uma prova/trabalho
-func (r *ExamRepository) Update(exam *models.Exam) error {
-	return r.db.Save(exam).Error
+func (r *ExamRepository) Update(ctx context.Context, exam *models.Exam) error {
+	return r.db.WithContext(ctx).Save(exam).Error
 }
 
 // Delete remove uma prova/trabalho (soft delete)
-func (r *ExamRepository) Delete(id uuid.UUID) error {
-	return r.db.Delete(&models.Exam{}, id).Error
+func (r *ExamRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&models.Exam{}, id).Error
 }
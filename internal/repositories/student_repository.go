@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"context"
+
 	"study-manager-service/internal/models"
 
 	"github.com/google/uuid"
@@ -18,14 +20,14 @@ func NewStudentRepository(db *gorm.DB) *StudentRepository {
 }
 
 // Create cria um novo estudante
-func (r *StudentRepository) Create(student *models.Student) error {
-	return r.db.Create(student).Error
+func (r *StudentRepository) Create(ctx context.Context, student *models.Student) error {
+	return r.db.WithContext(ctx).Create(student).Error
 }
 
 // GetByID busca um estudante por ID
-func (r *StudentRepository) GetByID(id uuid.UUID) (*models.Student, error) {
+func (r *StudentRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Student, error) {
 	var student models.Student
-	err := r.db.Where("id = ?", id).First(&student).Error
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&student).Error
 	if err != nil {
 		return nil, err
 	}
@@ -33,9 +35,9 @@ func (r *StudentRepository) GetByID(id uuid.UUID) (*models.Student, error) {
 }
 
 // GetByUserID busca um estudante por User ID
-func (r *StudentRepository) GetByUserID(userID string) (*models.Student, error) {
+func (r *StudentRepository) GetByUserID(ctx context.Context, userID string) (*models.Student, error) {
 	var student models.Student
-	err := r.db.Where("user_id = ?", userID).First(&student).Error
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&student).Error
 	if err != nil {
 		return nil, err
 	}
@@ -43,25 +45,25 @@ func (r *StudentRepository) GetByUserID(userID string) (*models.Student, error)
 }
 
 // GetAll busca todos os estudantes
-func (r *StudentRepository) GetAll() ([]models.Student, error) {
+func (r *StudentRepository) GetAll(ctx context.Context) ([]models.Student, error) {
 	var students []models.Student
-	err := r.db.Find(&students).Error
+	err := r.db.WithContext(ctx).Find(&students).Error
 	return students, err
 }
 
 // Update atualiza um estudante
-func (r *StudentRepository) Update(student *models.Student) error {
-	return r.db.Save(student).Error
+func (r *StudentRepository) Update(ctx context.Context, student *models.Student) error {
+	return r.db.WithContext(ctx).Save(student).Error
 }
 
 // Delete remove um estudante (soft delete)
-func (r *StudentRepository) Delete(id uuid.UUID) error {
-	return r.db.Delete(&models.Student{}, id).Error
+func (r *StudentRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&models.Student{}, id).Error
 }
 
 // ExistsByUserID verifica se existe um estudante com o User ID
-func (r *StudentRepository) ExistsByUserID(userID string) (bool, error) {
+func (r *StudentRepository) ExistsByUserID(ctx context.Context, userID string) (bool, error) {
 	var count int64
-	err := r.db.Model(&models.Student{}).Where("user_id = ?", userID).Count(&count).Error
+	err := r.db.WithContext(ctx).Model(&models.Student{}).Where("user_id = ?", userID).Count(&count).Error
 	return count > 0, err
 }
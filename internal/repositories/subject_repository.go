@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"context"
+
 	"study-manager-service/internal/models"
 
 	"github.com/google/uuid"
@@ -18,14 +20,14 @@ func NewSubjectRepository(db *gorm.DB) *SubjectRepository {
 }
 
 // Create cria uma nova matéria
-func (r *SubjectRepository) Create(subject *models.Subject) error {
-	return r.db.Create(subject).Error
+func (r *SubjectRepository) Create(ctx context.Context, subject *models.Subject) error {
+	return r.db.WithContext(ctx).Create(subject).Error
 }
 
 // GetByID busca uma matéria por ID
-func (r *SubjectRepository) GetByID(id uuid.UUID) (*models.Subject, error) {
+func (r *SubjectRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Subject, error) {
 	var subject models.Subject
-	err := r.db.Where("id = ?", id).First(&subject).Error
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&subject).Error
 	if err != nil {
 		return nil, err
 	}
@@ -33,22 +35,22 @@ func (r *SubjectRepository) GetByID(id uuid.UUID) (*models.Subject, error) {
 }
 
 // GetByStudentID busca matérias por Student ID
-func (r *SubjectRepository) GetByStudentID(studentID uuid.UUID) ([]models.Subject, error) {
+func (r *SubjectRepository) GetByStudentID(ctx context.Context, studentID uuid.UUID) ([]models.Subject, error) {
 	var subjects []models.Subject
-	err := r.db.Where("student_id = ?", studentID).Find(&subjects).Error
+	err := r.db.WithContext(ctx).Where("student_id = ?", studentID).Find(&subjects).Error
 	return subjects, err
 }
 
 // GetByIDWithExams busca uma matéria com suas provas/trabalhos
-func (r *SubjectRepository) GetByIDWithExams(id uuid.UUID) (*models.Subject, []models.Exam, error) {
+func (r *SubjectRepository) GetByIDWithExams(ctx context.Context, id uuid.UUID) (*models.Subject, []models.Exam, error) {
 	var subject models.Subject
-	err := r.db.Where("id = ?", id).First(&subject).Error
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&subject).Error
 	if err != nil {
 		return nil, nil, err
 	}
 
 	var exams []models.Exam
-	err = r.db.Where("subject_id = ?", id).Find(&exams).Error
+	err = r.db.WithContext(ctx).Where("subject_id = ?", id).Find(&exams).Error
 	if err != nil {
 		return nil, nil, err
 	}
@@ -57,18 +59,18 @@ func (r *SubjectRepository) GetByIDWithExams(id uuid.UUID) (*models.Subject, []m
 }
 
 // Update atualiza uma matéria
-func (r *SubjectRepository) Update(subject *models.Subject) error {
-	return r.db.Save(subject).Error
+func (r *SubjectRepository) Update(ctx context.Context, subject *models.Subject) error {
+	return r.db.WithContext(ctx).Save(subject).Error
 }
 
 // Delete remove uma matéria (soft delete)
-func (r *SubjectRepository) Delete(id uuid.UUID) error {
-	return r.db.Delete(&models.Subject{}, id).Error
+func (r *SubjectRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&models.Subject{}, id).Error
 }
 
 // ExistsByStudentID verifica se existe uma matéria com o Student ID
-func (r *SubjectRepository) ExistsByStudentID(studentID uuid.UUID) (bool, error) {
+func (r *SubjectRepository) ExistsByStudentID(ctx context.Context, studentID uuid.UUID) (bool, error) {
 	var count int64
-	err := r.db.Model(&models.Subject{}).Where("student_id = ?", studentID).Count(&count).Error
+	err := r.db.WithContext(ctx).Model(&models.Subject{}).Where("student_id = ?", studentID).Count(&count).Error
 	return count > 0, err
 }
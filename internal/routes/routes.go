@@ -5,10 +5,13 @@ import (
 
 	"study-manager-service/internal/config"
 	"study-manager-service/internal/handlers"
+	"study-manager-service/internal/health"
+	"study-manager-service/internal/metrics"
 	"study-manager-service/internal/middleware"
 	"study-manager-service/internal/services"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
 // SetupRoutes configura todas as rotas da aplicação
@@ -21,17 +24,27 @@ func SetupRoutes(
 	studentService *services.StudentService,
 	subjectService *services.SubjectService,
 	examService *services.ExamService,
+	checker *health.Checker,
 ) *gin.Engine {
-	router := gin.Default()
+	router := gin.New()
+	router.Use(gin.Recovery())
+
+	// Métricas Prometheus e sondas de liveness/readiness, registradas antes dos
+	// middlewares globais para não passarem por CORS, validação de origem e rate limiting
+	router.GET("/metrics", metrics.Handler())
+	router.GET("/livez", checker.LiveHandler())
+	router.GET("/readyz", checker.ReadyHandler())
 
 	// Middlewares globais
+	router.Use(middleware.RequestID())
+	router.Use(middleware.AccessLog())
+	router.Use(otelgin.Middleware(cfg.Tracing.ServiceName))
+	router.Use(middleware.Metrics())
 	router.Use(middleware.CORS(cfg))
 	router.Use(middleware.SecurityHeaders())
 	router.Use(middleware.ValidateOrigin(cfg))
 	router.Use(rateLimiter.RateLimit())
 	router.Use(validationMiddleware.ValidateInput())
-	router.Use(gin.Logger())
-	router.Use(gin.Recovery())
 
 	// Health check
 	router.GET("/api/v1/health", func(c *gin.Context) {
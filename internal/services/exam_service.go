@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"fmt"
 
 	"study-manager-service/internal/clients"
@@ -25,7 +26,7 @@ func NewExamService(examRepo *repositories.ExamRepository, authClient *clients.A
 }
 
 // CreateExam cria uma nova prova/trabalho
-func (s *ExamService) CreateExam(req *models.ExamCreateRequest, userID string) (*models.ExamResponse, error) {
+func (s *ExamService) CreateExam(ctx context.Context, req *models.ExamCreateRequest, userID string) (*models.ExamResponse, error) {
 	exam := &models.Exam{
 		ID:          uuid.New(),
 		SubjectID:   req.SubjectID,
@@ -43,7 +44,7 @@ func (s *ExamService) CreateExam(req *models.ExamCreateRequest, userID string) (
 		exam.Status = "pending"
 	}
 
-	if err := s.examRepo.Create(exam); err != nil {
+	if err := s.examRepo.Create(ctx, exam); err != nil {
 		return nil, fmt.Errorf("erro ao criar prova/trabalho: %w", err)
 	}
 
@@ -52,8 +53,8 @@ func (s *ExamService) CreateExam(req *models.ExamCreateRequest, userID string) (
 }
 
 // GetExams busca provas/trabalhos do usuário
-func (s *ExamService) GetExams(userID string) ([]models.ExamResponse, error) {
-	exams, err := s.examRepo.GetByStudentID(uuid.MustParse("00000000-0000-0000-0000-000000000000"))
+func (s *ExamService) GetExams(ctx context.Context, userID string) ([]models.ExamResponse, error) {
+	exams, err := s.examRepo.GetByStudentID(ctx, uuid.MustParse("00000000-0000-0000-0000-000000000000"))
 	if err != nil {
 		return nil, fmt.Errorf("erro ao buscar provas/trabalhos: %w", err)
 	}
@@ -67,8 +68,8 @@ func (s *ExamService) GetExams(userID string) ([]models.ExamResponse, error) {
 }
 
 // GetExamByID busca uma prova/trabalho por ID
-func (s *ExamService) GetExamByID(id uuid.UUID, userID string) (*models.ExamResponse, error) {
-	exam, err := s.examRepo.GetByID(id)
+func (s *ExamService) GetExamByID(ctx context.Context, id uuid.UUID, userID string) (*models.ExamResponse, error) {
+	exam, err := s.examRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("prova/trabalho não encontrado: %w", err)
 	}
@@ -78,8 +79,8 @@ func (s *ExamService) GetExamByID(id uuid.UUID, userID string) (*models.ExamResp
 }
 
 // UpdateExam atualiza uma prova/trabalho
-func (s *ExamService) UpdateExam(id uuid.UUID, req *models.ExamUpdateRequest, userID string) (*models.ExamResponse, error) {
-	exam, err := s.examRepo.GetByID(id)
+func (s *ExamService) UpdateExam(ctx context.Context, id uuid.UUID, req *models.ExamUpdateRequest, userID string) (*models.ExamResponse, error) {
+	exam, err := s.examRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("prova/trabalho não encontrado: %w", err)
 	}
@@ -100,7 +101,7 @@ func (s *ExamService) UpdateExam(id uuid.UUID, req *models.ExamUpdateRequest, us
 		exam.Status = req.Status
 	}
 
-	if err := s.examRepo.Update(exam); err != nil {
+	if err := s.examRepo.Update(ctx, exam); err != nil {
 		return nil, fmt.Errorf("erro ao atualizar prova/trabalho: %w", err)
 	}
 
@@ -109,13 +110,13 @@ func (s *ExamService) UpdateExam(id uuid.UUID, req *models.ExamUpdateRequest, us
 }
 
 // DeleteExam remove uma prova/trabalho
-func (s *ExamService) DeleteExam(id uuid.UUID, userID string) error {
-	_, err := s.examRepo.GetByID(id)
+func (s *ExamService) DeleteExam(ctx context.Context, id uuid.UUID, userID string) error {
+	_, err := s.examRepo.GetByID(ctx, id)
 	if err != nil {
 		return fmt.Errorf("prova/trabalho não encontrado: %w", err)
 	}
 
-	if err := s.examRepo.Delete(id); err != nil {
+	if err := s.examRepo.Delete(ctx, id); err != nil {
 		return fmt.Errorf("erro ao remover prova/trabalho: %w", err)
 	}
 
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"fmt"
 
 	"study-manager-service/internal/clients"
@@ -25,9 +26,9 @@ func NewStudentService(studentRepo *repositories.StudentRepository, authClient *
 }
 
 // CreateStudent cria um novo estudante
-func (s *StudentService) CreateStudent(req *models.StudentCreateRequest, userID string) (*models.StudentResponse, error) {
+func (s *StudentService) CreateStudent(ctx context.Context, req *models.StudentCreateRequest, userID string) (*models.StudentResponse, error) {
 	// Verificar se já existe um estudante com este user_id
-	exists, err := s.studentRepo.ExistsByUserID(userID)
+	exists, err := s.studentRepo.ExistsByUserID(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao verificar existência do estudante: %w", err)
 	}
@@ -44,7 +45,7 @@ func (s *StudentService) CreateStudent(req *models.StudentCreateRequest, userID
 		UserID: userID,
 	}
 
-	if err := s.studentRepo.Create(student); err != nil {
+	if err := s.studentRepo.Create(ctx, student); err != nil {
 		return nil, fmt.Errorf("erro ao criar estudante: %w", err)
 	}
 
@@ -53,8 +54,8 @@ func (s *StudentService) CreateStudent(req *models.StudentCreateRequest, userID
 }
 
 // GetStudentByUserID busca um estudante por User ID
-func (s *StudentService) GetStudentByUserID(userID string) (*models.StudentResponse, error) {
-	student, err := s.studentRepo.GetByUserID(userID)
+func (s *StudentService) GetStudentByUserID(ctx context.Context, userID string) (*models.StudentResponse, error) {
+	student, err := s.studentRepo.GetByUserID(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("estudante não encontrado: %w", err)
 	}
@@ -64,9 +65,9 @@ func (s *StudentService) GetStudentByUserID(userID string) (*models.StudentRespo
 }
 
 // UpdateStudent atualiza um estudante
-func (s *StudentService) UpdateStudent(id uuid.UUID, req *models.StudentUpdateRequest, userID string) (*models.StudentResponse, error) {
+func (s *StudentService) UpdateStudent(ctx context.Context, id uuid.UUID, req *models.StudentUpdateRequest, userID string) (*models.StudentResponse, error) {
 	// Buscar estudante
-	student, err := s.studentRepo.GetByID(id)
+	student, err := s.studentRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("estudante não encontrado: %w", err)
 	}
@@ -84,7 +85,7 @@ func (s *StudentService) UpdateStudent(id uuid.UUID, req *models.StudentUpdateRe
 		student.Email = req.Email
 	}
 
-	if err := s.studentRepo.Update(student); err != nil {
+	if err := s.studentRepo.Update(ctx, student); err != nil {
 		return nil, fmt.Errorf("erro ao atualizar estudante: %w", err)
 	}
 
@@ -93,9 +94,9 @@ func (s *StudentService) UpdateStudent(id uuid.UUID, req *models.StudentUpdateRe
 }
 
 // DeleteStudent remove um estudante
-func (s *StudentService) DeleteStudent(id uuid.UUID, userID string) error {
+func (s *StudentService) DeleteStudent(ctx context.Context, id uuid.UUID, userID string) error {
 	// Buscar estudante
-	student, err := s.studentRepo.GetByID(id)
+	student, err := s.studentRepo.GetByID(ctx, id)
 	if err != nil {
 		return fmt.Errorf("estudante não encontrado: %w", err)
 	}
@@ -105,7 +106,7 @@ func (s *StudentService) DeleteStudent(id uuid.UUID, userID string) error {
 		return fmt.Errorf("acesso negado: estudante não pertence ao usuário")
 	}
 
-	if err := s.studentRepo.Delete(id); err != nil {
+	if err := s.studentRepo.Delete(ctx, id); err != nil {
 		return fmt.Errorf("erro ao remover estudante: %w", err)
 	}
 
@@ -113,8 +114,8 @@ func (s *StudentService) DeleteStudent(id uuid.UUID, userID string) error {
 }
 
 // GetAllStudents busca todos os estudantes
-func (s *StudentService) GetAllStudents() ([]models.StudentResponse, error) {
-	students, err := s.studentRepo.GetAll()
+func (s *StudentService) GetAllStudents(ctx context.Context) ([]models.StudentResponse, error) {
+	students, err := s.studentRepo.GetAll(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao buscar estudantes: %w", err)
 	}
@@ -128,8 +129,8 @@ func (s *StudentService) GetAllStudents() ([]models.StudentResponse, error) {
 }
 
 // GetStudentByID busca um estudante por ID
-func (s *StudentService) GetStudentByID(id uuid.UUID) (*models.StudentResponse, error) {
-	student, err := s.studentRepo.GetByID(id)
+func (s *StudentService) GetStudentByID(ctx context.Context, id uuid.UUID) (*models.StudentResponse, error) {
+	student, err := s.studentRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("estudante não encontrado: %w", err)
 	}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"fmt"
 
 	"study-manager-service/internal/clients"
@@ -25,7 +26,7 @@ func NewSubjectService(subjectRepo *repositories.SubjectRepository, authClient *
 }
 
 // CreateSubject cria uma nova matéria
-func (s *SubjectService) CreateSubject(req *models.SubjectCreateRequest, userID string) (*models.SubjectResponse, error) {
+func (s *SubjectService) CreateSubject(ctx context.Context, req *models.SubjectCreateRequest, userID string) (*models.SubjectResponse, error) {
 	// Buscar estudante por user_id
 	// Aqui você precisaria de um repositório de estudantes
 	// Por simplicidade, vou assumir que existe um método para buscar por user_id
@@ -37,7 +38,7 @@ func (s *SubjectService) CreateSubject(req *models.SubjectCreateRequest, userID
 		Description: req.Description,
 	}
 
-	if err := s.subjectRepo.Create(subject); err != nil {
+	if err := s.subjectRepo.Create(ctx, subject); err != nil {
 		return nil, fmt.Errorf("erro ao criar matéria: %w", err)
 	}
 
@@ -46,9 +47,9 @@ func (s *SubjectService) CreateSubject(req *models.SubjectCreateRequest, userID
 }
 
 // GetSubjects busca matérias do usuário
-func (s *SubjectService) GetSubjects(userID string) ([]models.SubjectResponse, error) {
+func (s *SubjectService) GetSubjects(ctx context.Context, userID string) ([]models.SubjectResponse, error) {
 	// Implementar busca por user_id
-	subjects, err := s.subjectRepo.GetByStudentID(uuid.MustParse("00000000-0000-0000-0000-000000000000"))
+	subjects, err := s.subjectRepo.GetByStudentID(ctx, uuid.MustParse("00000000-0000-0000-0000-000000000000"))
 	if err != nil {
 		return nil, fmt.Errorf("erro ao buscar matérias: %w", err)
 	}
@@ -62,8 +63,8 @@ func (s *SubjectService) GetSubjects(userID string) ([]models.SubjectResponse, e
 }
 
 // GetSubjectByID busca uma matéria por ID
-func (s *SubjectService) GetSubjectByID(id uuid.UUID, userID string) (*models.SubjectResponse, error) {
-	subject, err := s.subjectRepo.GetByID(id)
+func (s *SubjectService) GetSubjectByID(ctx context.Context, id uuid.UUID, userID string) (*models.SubjectResponse, error) {
+	subject, err := s.subjectRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("matéria não encontrada: %w", err)
 	}
@@ -75,8 +76,8 @@ func (s *SubjectService) GetSubjectByID(id uuid.UUID, userID string) (*models.Su
 }
 
 // UpdateSubject atualiza uma matéria
-func (s *SubjectService) UpdateSubject(id uuid.UUID, req *models.SubjectUpdateRequest, userID string) (*models.SubjectResponse, error) {
-	subject, err := s.subjectRepo.GetByID(id)
+func (s *SubjectService) UpdateSubject(ctx context.Context, id uuid.UUID, req *models.SubjectUpdateRequest, userID string) (*models.SubjectResponse, error) {
+	subject, err := s.subjectRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("matéria não encontrada: %w", err)
 	}
@@ -90,7 +91,7 @@ func (s *SubjectService) UpdateSubject(id uuid.UUID, req *models.SubjectUpdateRe
 		subject.Description = req.Description
 	}
 
-	if err := s.subjectRepo.Update(subject); err != nil {
+	if err := s.subjectRepo.Update(ctx, subject); err != nil {
 		return nil, fmt.Errorf("erro ao atualizar matéria: %w", err)
 	}
 
@@ -99,15 +100,15 @@ func (s *SubjectService) UpdateSubject(id uuid.UUID, req *models.SubjectUpdateRe
 }
 
 // DeleteSubject remove uma matéria
-func (s *SubjectService) DeleteSubject(id uuid.UUID, userID string) error {
-	_, err := s.subjectRepo.GetByID(id)
+func (s *SubjectService) DeleteSubject(ctx context.Context, id uuid.UUID, userID string) error {
+	_, err := s.subjectRepo.GetByID(ctx, id)
 	if err != nil {
 		return fmt.Errorf("matéria não encontrada: %w", err)
 	}
 
 	// Verificar propriedade
 
-	if err := s.subjectRepo.Delete(id); err != nil {
+	if err := s.subjectRepo.Delete(ctx, id); err != nil {
 		return fmt.Errorf("erro ao remover matéria: %w", err)
 	}
 
@@ -0,0 +1,70 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestValidRequestID(t *testing.T) {
+	tests := []struct {
+		name      string
+		requestID string
+		valid     bool
+	}{
+		{"uuid", "3f8a2b1c-9d4e-4f6a-8b7c-1d2e3f4a5b6c", true},
+		{"texto curto", "abc-123", true},
+		{"vazio", "", false},
+		{"com espaço", "abc 123", false},
+		{"com quebra de linha", "abc\nlevel=ERROR", false},
+		{"não ASCII", "requisição", false},
+		{"no limite", strings.Repeat("a", maxRequestIDLength), true},
+		{"longo demais", strings.Repeat("a", maxRequestIDLength+1), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ValidRequestID(tt.requestID); got != tt.valid {
+				t.Errorf("ValidRequestID(%q) = %v, esperado %v", tt.requestID, got, tt.valid)
+			}
+		})
+	}
+}
+
+func TestContextHandlerAddsRequestAndTrace(t *testing.T) {
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	withTrace := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID}))
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		requestID string
+		traceID   string
+	}{
+		{"sem contexto", context.Background(), "", ""},
+		{"com request ID", WithRequestID(context.Background(), "req-1"), "req-1", ""},
+		{"com request ID e trace", WithRequestID(withTrace, "req-2"), "req-2", traceID.String()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(contextHandler{slog.NewJSONHandler(&buf, nil)}).With("service", "auth")
+			logger.InfoContext(tt.ctx, "teste")
+
+			var record map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+				t.Fatalf("log inválido: %v", err)
+			}
+			got, _ := record["request_id"].(string)
+			gotTrace, _ := record["trace_id"].(string)
+			if got != tt.requestID || gotTrace != tt.traceID || record["service"] != "auth" {
+				t.Errorf("registro = %v", record)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"auth-service/config"
+	"auth-service/metrics"
+	"auth-service/tracing"
+)
+
+// dialTimeout limita a conexão com o servidor SMTP quando o contexto não tem prazo
+const dialTimeout = 10 * time.Second
+
+// Message é um email em texto simples
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer envia emails transacionais (links de acesso, convites, avisos de segurança)
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// New cria o mailer do driver configurado
+func New(cfg *config.Config) (Mailer, error) {
+	var m Mailer
+	switch cfg.Mailer.Driver {
+	case "log":
+		m = logMailer{}
+	case "smtp":
+		m = &smtpMailer{
+			host:     cfg.Mailer.SMTPHost,
+			addr:     net.JoinHostPort(cfg.Mailer.SMTPHost, cfg.Mailer.SMTPPort),
+			from:     cfg.Mailer.From,
+			username: cfg.Mailer.SMTPUsername,
+			password: cfg.Mailer.SMTPPassword,
+		}
+	default:
+		return nil, fmt.Errorf("driver de email desconhecido: %s", cfg.Mailer.Driver)
+	}
+	return instrumented{next: m}, nil
+}
+
+// instrumented registra métricas e um span para cada envio
+type instrumented struct {
+	next Mailer
+}
+
+func (m instrumented) Send(ctx context.Context, msg Message) error {
+	ctx, span := tracing.Start(ctx, "mailer.send")
+	defer span.End()
+
+	err := m.next.Send(ctx, msg)
+	outcome := "success"
+	if err != nil {
+		outcome = "failure"
+		span.RecordError(err)
+	}
+	metrics.EmailsSent.WithLabelValues(outcome).Inc()
+	return err
+}
+
+// logMailer apenas registra a mensagem no log, para desenvolvimento
+type logMailer struct{}
+
+func (logMailer) Send(ctx context.Context, msg Message) error {
+	slog.InfoContext(ctx, "Email não enviado (driver log)", "to", msg.To, "subject", msg.Subject, "body", msg.Body)
+	return nil
+}
+
+// smtpMailer envia pelo servidor SMTP, usando STARTTLS quando o servidor oferece
+type smtpMailer struct {
+	host     string
+	addr     string
+	from     string
+	username string
+	password string
+}
+
+func (m *smtpMailer) Send(ctx context.Context, msg Message) error {
+	dialer := net.Dialer{Timeout: dialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", m.addr)
+	if err != nil {
+		return fmt.Errorf("erro ao conectar ao servidor SMTP: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("erro ao iniciar sessão SMTP: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: m.host, MinVersion: tls.VersionTLS12}); err != nil {
+			return fmt.Errorf("erro ao iniciar TLS: %w", err)
+		}
+	}
+	if m.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", m.username, m.password, m.host)); err != nil {
+			return fmt.Errorf("erro ao autenticar no servidor SMTP: %w", err)
+		}
+	}
+
+	if err := client.Mail(m.from); err != nil {
+		return fmt.Errorf("erro ao definir remetente: %w", err)
+	}
+	if err := client.Rcpt(msg.To); err != nil {
+		return fmt.Errorf("erro ao definir destinatário: %w", err)
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("erro ao enviar email: %w", err)
+	}
+	if _, err := w.Write(m.format(msg)); err != nil {
+		return fmt.Errorf("erro ao enviar email: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("erro ao enviar email: %w", err)
+	}
+
+	return client.Quit()
+}
+
+// format monta a mensagem RFC 5322 com corpo UTF-8
+func (m *smtpMailer) format(msg Message) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", m.from)
+	fmt.Fprintf(&buf, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return buf.Bytes()
+}
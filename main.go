@@ -2,7 +2,7 @@ package main
 
 import (
 	"context"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
@@ -12,10 +12,13 @@ import (
 	"study-manager-service/internal/clients"
 	"study-manager-service/internal/config"
 	"study-manager-service/internal/database"
+	"study-manager-service/internal/health"
+	"study-manager-service/internal/logging"
 	"study-manager-service/internal/middleware"
 	"study-manager-service/internal/repositories"
 	"study-manager-service/internal/routes"
 	"study-manager-service/internal/services"
+	"study-manager-service/internal/tracing"
 
 	"github.com/gin-gonic/gin"
 )
@@ -23,37 +26,54 @@ import (
 func main() {
 	// Carregar configurações
 	cfg := config.Load()
+	logging.Setup(cfg)
 
 	// Configurar modo do Gin
 	gin.SetMode(cfg.Server.Mode)
 
+	// Configurar exportação de traces
+	shutdownTracing, err := tracing.Setup(cfg)
+	if err != nil {
+		fatal("Erro ao configurar tracing", err)
+	}
+
 	// Conectar ao banco de dados
 	db, err := database.NewDatabase(cfg)
 	if err != nil {
-		log.Fatalf("Erro ao conectar ao banco de dados: %v", err)
+		fatal("Erro ao conectar ao banco de dados", err)
 	}
 	defer db.Close()
 
 	// Executar migrações
 	if err := db.Migrate(); err != nil {
-		log.Fatalf("Erro ao executar migrações: %v", err)
+		fatal("Erro ao executar migrações", err)
 	}
 
 	// Criar índices
 	if err := db.CreateIndexes(); err != nil {
-		log.Fatalf("Erro ao criar índices: %v", err)
+		fatal("Erro ao criar índices", err)
 	}
 
 	// Inicializar cliente de autenticação
-	authClient := clients.NewAuthClient(cfg)
+	authClient, err := clients.NewAuthClient(cfg)
+	if err != nil {
+		fatal("Erro ao criar cliente do auth-service", err)
+	}
+	defer authClient.Close()
 
 	// Verificar conectividade com auth-service
-	if err := authClient.HealthCheck(); err != nil {
-		log.Printf("Aviso: Auth-service não está disponível: %v", err)
+	if err := authClient.HealthCheck(context.Background()); err != nil {
+		slog.Warn("Auth-service não está disponível", "error", err)
 	} else {
-		log.Println("Conexão com auth-service estabelecida com sucesso")
+		slog.Info("Conexão com auth-service estabelecida com sucesso")
 	}
 
+	// Verificações de prontidão expostas em /readyz
+	checker := health.NewChecker()
+	checker.Register("database", db.Ping)
+	checker.Register("migrations", db.CheckSchema)
+	checker.Register("auth_service", authClient.HealthCheck)
+
 	// Inicializar repositórios
 	studentRepo := repositories.NewStudentRepository(db.DB)
 	subjectRepo := repositories.NewSubjectRepository(db.DB)
@@ -80,6 +100,7 @@ func main() {
 		studentService,
 		subjectService,
 		examService,
+		checker,
 	)
 
 	// Configurar servidor HTTP
@@ -94,18 +115,21 @@ func main() {
 
 	// Iniciar servidor em uma goroutine
 	go func() {
-		log.Printf("Study Manager Service iniciado na porta %s", cfg.Server.Port)
-		log.Printf("Ambiente: %s", cfg.Server.Mode)
-		log.Printf("Documentação da API disponível em: http://localhost:%s/api/v1", cfg.Server.Port)
+		slog.Info("Study Manager Service iniciado", "port", cfg.Server.Port, "mode", cfg.Server.Mode)
+		slog.Info("Documentação da API disponível em: http://localhost:" + cfg.Server.Port + "/api/v1")
 
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Erro ao iniciar servidor: %v", err)
+			fatal("Erro ao iniciar servidor", err)
 		}
 	}()
 
 	// Aguardar sinal de interrupção
 	<-quit
-	log.Println("Desligando Study Manager Service...")
+	slog.Info("Desligando Study Manager Service...")
+
+	// Responder 503 em /readyz e dar tempo aos balanceadores de retirar a instância
+	checker.Drain()
+	time.Sleep(time.Duration(cfg.Server.ShutdownDelaySeconds) * time.Second)
 
 	// Contexto com timeout para shutdown graceful
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -113,8 +137,19 @@ func main() {
 
 	// Tentar desligar o servidor gracefulmente
 	if err := srv.Shutdown(ctx); err != nil {
-		log.Fatalf("Erro ao desligar servidor: %v", err)
+		fatal("Erro ao desligar servidor", err)
 	}
 
-	log.Println("Study Manager Service desligado com sucesso")
+	// Enviar os spans pendentes antes de sair
+	if err := shutdownTracing(ctx); err != nil {
+		slog.Error("Erro ao finalizar tracing", "error", err)
+	}
+
+	slog.Info("Study Manager Service desligado com sucesso")
+}
+
+// fatal registra o erro e encerra o processo
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }
\ No newline at end of file
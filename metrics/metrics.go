@@ -0,0 +1,92 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "auth_service"
+
+var (
+	// Logins conta tentativas de login por resultado e motivo da falha (código do erro)
+	Logins = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "logins_total",
+		Help:      "Tentativas de login por resultado e motivo.",
+	}, []string{"outcome", "reason"})
+
+	// TokensIssued conta tokens emitidos por tipo (access, refresh, id, personal)
+	TokensIssued = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tokens_issued_total",
+		Help:      "Tokens emitidos por tipo.",
+	}, []string{"type"})
+
+	// TokenValidations conta validações de access token por resultado e motivo
+	TokenValidations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "token_validations_total",
+		Help:      "Validações de access token por resultado e motivo.",
+	}, []string{"outcome", "reason"})
+
+	// BcryptDuration mede o tempo gasto com hash e comparação de senhas
+	BcryptDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "bcrypt_duration_seconds",
+		Help:      "Duração das operações de bcrypt.",
+		Buckets:   []float64{.01, .025, .05, .1, .2, .4, .8, 1.6},
+	}, []string{"operation"})
+
+	// DBQueryDuration mede a latência das operações dos stores SQL
+	DBQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Duração das operações no banco de dados.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"operation"})
+
+	// RateLimitRejections conta requisições recusadas pelo rate limiter, por política
+	RateLimitRejections = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limit_rejections_total",
+		Help:      "Requisições recusadas por excesso de requisições, por política.",
+	}, []string{"policy"})
+
+	// EmailsSent conta emails enviados pelo mailer, por resultado
+	EmailsSent = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "emails_sent_total",
+		Help:      "Emails enviados por resultado.",
+	}, []string{"outcome"})
+
+	// WebhookDeliveries conta tentativas de entrega de webhooks por resultado (delivered, retry, failed)
+	WebhookDeliveries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "webhook_deliveries_total",
+		Help:      "Tentativas de entrega de webhooks por resultado.",
+	}, []string{"outcome"})
+
+	// TokensPurged conta registros vencidos removidos pelo janitor, por tabela
+	TokensPurged = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tokens_purged_total",
+		Help:      "Registros vencidos removidos pela limpeza periódica, por tabela.",
+	}, []string{"table"})
+)
+
+// Observe retorna uma função que, ao ser chamada, registra no histograma o tempo decorrido
+func Observe(histogram *prometheus.HistogramVec, label string) func() {
+	start := time.Now()
+	return func() {
+		histogram.WithLabelValues(label).Observe(time.Since(start).Seconds())
+	}
+}
+
+// Handler expõe as métricas no formato texto do Prometheus
+func Handler() gin.HandlerFunc {
+	return gin.WrapH(promhttp.Handler())
+}
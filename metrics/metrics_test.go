@@ -0,0 +1,36 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestObserveRecordsDuration(t *testing.T) {
+	before := testutil.CollectAndCount(BcryptDuration)
+	Observe(BcryptDuration, "teste")()
+	if after := testutil.CollectAndCount(BcryptDuration); after != before+1 {
+		t.Errorf("%d séries, esperado %d", after, before+1)
+	}
+}
+
+func TestHandlerExposesMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	Logins.WithLabelValues("success", "").Inc()
+
+	router := gin.New()
+	router.GET("/metrics", Handler())
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d", w.Code)
+	}
+	if body := w.Body.String(); !strings.Contains(body, `auth_service_logins_total{outcome="success",reason=""}`) {
+		t.Errorf("métrica de login ausente:\n%s", body)
+	}
+}
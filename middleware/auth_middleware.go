@@ -1,9 +1,10 @@
 package middleware
 
 import (
-	"net/http"
 	"strings"
 
+	"auth-service/models"
+	"auth-service/problem"
 	"auth-service/services"
 
 	"github.com/gin-gonic/gin"
@@ -24,16 +25,14 @@ func (m *AuthMiddleware) Authenticate() gin.HandlerFunc {
 		// Extrair token do header Authorization
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "token de autorização não fornecido"})
-			c.Abort()
+			problem.Abort(c, services.ErrMissingAuthToken)
 			return
 		}
 
 		// Verificar formato "Bearer <token>"
 		tokenParts := strings.Split(authHeader, " ")
 		if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "formato de token inválido"})
-			c.Abort()
+			problem.Abort(c, services.ErrInvalidTokenFormat)
 			return
 		}
 
@@ -46,22 +45,21 @@ func (m *AuthMiddleware) Authenticate() gin.HandlerFunc {
 		}
 
 		if clientID == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "client_id não fornecido"})
-			c.Abort()
+			problem.Abort(c, services.ErrMissingClientID)
 			return
 		}
 
 		// Validar token
-		user, err := m.authService.ValidateToken(token, clientID)
+		user, err := m.authService.ValidateToken(c.Request.Context(), token, clientID)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-			c.Abort()
+			problem.Abort(c, err)
 			return
 		}
 
 		// Adicionar informações do usuário ao contexto
 		c.Set("user", user)
 		c.Set("user_id", user.ID.String())
+		c.Set("tenant_id", user.TenantID.String())
 		c.Set("client_id", clientID)
 
 		c.Next()
@@ -98,7 +96,7 @@ func (m *AuthMiddleware) OptionalAuth() gin.HandlerFunc {
 		}
 
 		// Tentar validar token (opcional)
-		user, err := m.authService.ValidateToken(token, clientID)
+		user, err := m.authService.ValidateToken(c.Request.Context(), token, clientID)
 		if err != nil {
 			c.Next()
 			return
@@ -107,8 +105,63 @@ func (m *AuthMiddleware) OptionalAuth() gin.HandlerFunc {
 		// Adicionar informações do usuário ao contexto se válido
 		c.Set("user", user)
 		c.Set("user_id", user.ID.String())
+		c.Set("tenant_id", user.TenantID.String())
 		c.Set("client_id", clientID)
 
 		c.Next()
 	}
 }
+
+// RequireRole exige que o usuário autenticado possua o papel informado.
+// Deve ser usado depois de Authenticate.
+func (m *AuthMiddleware) RequireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, ok := c.Get("user")
+		if !ok {
+			problem.Abort(c, services.ErrUnauthenticated)
+			return
+		}
+
+		for _, userRole := range user.(*models.UserResponse).Roles {
+			if userRole == role {
+				c.Next()
+				return
+			}
+		}
+
+		problem.Abort(c, services.ErrForbidden)
+	}
+}
+
+// RequireSession recusa tokens de acesso pessoal, que servem à automação com os serviços
+// clientes: mesmo com o escopo "write", eles não gerenciam a conta, não aprovam
+// dispositivos e não usam as rotas administrativas. Deve ser usado depois de Authenticate.
+func (m *AuthMiddleware) RequireSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, ok := c.Get("user")
+		if !ok {
+			problem.Abort(c, services.ErrUnauthenticated)
+			return
+		}
+
+		if user.(*models.UserResponse).TokenType != models.TokenTypeSession {
+			problem.Abort(c, services.ErrSessionRequired)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireTenant exige que o usuário autenticado pertença à organização informada.
+// Deve ser usado depois de Authenticate.
+func (m *AuthMiddleware) RequireTenant(tenantID string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("tenant_id") != tenantID {
+			problem.Abort(c, services.ErrForbidden)
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"auth-service/config"
+	"auth-service/models"
+	"auth-service/services"
+	"auth-service/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequireSessionChecksTokenType(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{
+		JWT:          config.JWTConfig{Secret: "abcdefghijklmnopqrstuvwxyz0123456789ABCD", ExpirationHours: 1, RefreshExpirationHours: 24},
+		Registration: config.RegistrationConfig{Mode: config.RegistrationOpen},
+	}
+	authService := services.NewAuthService(store.NewMemoryStores(), cfg, nil)
+
+	user, err := authService.Register(ctx, &models.RegisterRequest{Email: "ana@example.com", Password: "senha-segura-1", Name: "Ana"})
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	client, err := authService.CreateClient(ctx, models.DefaultTenantID, "app", "")
+	if err != nil {
+		t.Fatalf("client: %v", err)
+	}
+	session, err := authService.Login(ctx, &models.LoginRequest{Email: "ana@example.com", Password: "senha-segura-1", ClientID: client.ID.String()})
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	personal, err := authService.CreatePersonalToken(ctx, user.ID.String(), &models.CreatePersonalTokenRequest{Name: "ci", Scope: "read write"})
+	if err != nil {
+		t.Fatalf("token pessoal: %v", err)
+	}
+
+	m := NewAuthMiddleware(authService)
+	router := gin.New()
+	router.GET("/conta", m.Authenticate(), m.RequireSession(), okHandler)
+
+	tests := []struct {
+		name   string
+		token  string
+		status int
+	}{
+		{"JWT de sessão", session.AccessToken, http.StatusNoContent},
+		{"token pessoal com escopo write", personal.Token, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/conta", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			req.Header.Set("X-Client-ID", client.ID.String())
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if w.Code != tt.status {
+				t.Errorf("status %d, esperado %d: %s", w.Code, tt.status, w.Body.String())
+			}
+		})
+	}
+}
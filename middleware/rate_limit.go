@@ -0,0 +1,220 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"auth-service/config"
+	"auth-service/metrics"
+	"auth-service/problem"
+	"auth-service/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxRateLimitBody limita quanto do corpo é lido para extrair o email
+const maxRateLimitBody = 64 << 10
+
+// sweepInterval é a frequência com que buckets cheios (equivalentes a novos) são descartados
+const sweepInterval = time.Minute
+
+// internalPolicies são as políticas das rotas chamadas pelos serviços internos, as únicas
+// em que as redes confiáveis ficam isentas. Login, cadastro e as demais continuam
+// limitadas mesmo se um proxy mal configurado fizer todos os clientes parecerem internos.
+var internalPolicies = map[string]bool{"validate": true}
+
+// RateLimiter aplica políticas de token bucket por rota
+type RateLimiter struct {
+	enabled  bool
+	trusted  []*net.IPNet
+	policies map[string]*tokenBuckets
+}
+
+func NewRateLimiter(cfg *config.Config) *RateLimiter {
+	rl := &RateLimiter{
+		enabled:  cfg.RateLimit.Enabled,
+		policies: make(map[string]*tokenBuckets),
+	}
+
+	for _, network := range cfg.RateLimit.TrustedNetworks {
+		network = strings.TrimSpace(network)
+		if !strings.Contains(network, "/") {
+			if ip := net.ParseIP(network); ip.To4() != nil {
+				network += "/32"
+			} else {
+				network += "/128"
+			}
+		}
+		if _, ipNet, err := net.ParseCIDR(network); err == nil {
+			rl.trusted = append(rl.trusted, ipNet)
+		}
+	}
+
+	for name, policy := range cfg.RateLimit.Policies() {
+		rl.policies[name] = newTokenBuckets(policy)
+	}
+
+	return rl
+}
+
+// Limit aplica a política informada (ex: "login") à rota
+func (rl *RateLimiter) Limit(name string) gin.HandlerFunc {
+	buckets, ok := rl.policies[name]
+	if !ok {
+		panic(fmt.Sprintf("política de rate limit desconhecida: %s", name))
+	}
+	exemptTrusted := internalPolicies[name]
+
+	return func(c *gin.Context) {
+		// c.ClientIP só considera o X-Forwarded-For vindo dos proxies em server.trusted_proxies
+		if !rl.enabled || (exemptTrusted && rl.isTrusted(c.ClientIP())) {
+			c.Next()
+			return
+		}
+
+		key := name + ":" + requestKey(c, buckets.policy.Key)
+		allowed, remaining, reset, retryAfter := buckets.take(key, time.Now())
+
+		c.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d;burst=%d", buckets.policy.Requests, buckets.policy.PeriodSeconds, buckets.policy.Burst))
+		c.Header("RateLimit-Limit", strconv.Itoa(buckets.policy.Burst))
+		c.Header("RateLimit-Remaining", strconv.Itoa(remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(reset)))
+
+		if !allowed {
+			metrics.RateLimitRejections.WithLabelValues(name).Inc()
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(retryAfter)))
+			problem.Abort(c, services.ErrRateLimited)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func (rl *RateLimiter) isTrusted(clientIP string) bool {
+	ip := net.ParseIP(clientIP)
+	if ip == nil {
+		return false
+	}
+	for _, network := range rl.trusted {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// requestKey identifica quem consome as fichas. Sem email ou usuário na requisição, cai
+// para o IP, para que omitir o campo não dê um bucket próprio ao cliente.
+func requestKey(c *gin.Context, key string) string {
+	switch key {
+	case "email":
+		if email := bodyFields(c).Email; email != "" {
+			return "email:" + strings.ToLower(strings.TrimSpace(email))
+		}
+	case "user":
+		// Definido pelo AuthMiddleware, que deve vir antes na rota
+		if userID := c.GetString("user_id"); userID != "" {
+			return "user:" + userID
+		}
+	}
+	return "ip:" + c.ClientIP()
+}
+
+type rateLimitFields struct {
+	Email string `json:"email"`
+}
+
+// bodyFields lê o email do corpo JSON e o restaura para o handler
+func bodyFields(c *gin.Context) rateLimitFields {
+	var fields rateLimitFields
+	if c.Request.Body == nil {
+		return fields
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxRateLimitBody))
+	c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), c.Request.Body))
+	if err != nil {
+		return fields
+	}
+
+	_ = json.Unmarshal(body, &fields)
+	return fields
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// tokenBuckets guarda um bucket por chave para uma política
+type tokenBuckets struct {
+	policy config.RateLimitPolicy
+	rate   float64 // fichas repostas por segundo
+
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBuckets(policy config.RateLimitPolicy) *tokenBuckets {
+	return &tokenBuckets{
+		policy:    policy,
+		rate:      float64(policy.Requests) / float64(policy.PeriodSeconds),
+		buckets:   make(map[string]*tokenBucket),
+		lastSweep: time.Now(),
+	}
+}
+
+// take consome uma ficha do bucket da chave. Retorna se a requisição foi aceita, as fichas
+// restantes, o tempo até o bucket encher de novo e, se recusada, quanto esperar pela próxima ficha.
+func (tb *tokenBuckets) take(key string, now time.Time) (allowed bool, remaining int, reset, retryAfter time.Duration) {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+
+	capacity := float64(tb.policy.Burst)
+
+	if now.Sub(tb.lastSweep) > sweepInterval {
+		for k, bucket := range tb.buckets {
+			if bucket.tokens+now.Sub(bucket.last).Seconds()*tb.rate >= capacity {
+				delete(tb.buckets, k)
+			}
+		}
+		tb.lastSweep = now
+	}
+
+	bucket, ok := tb.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: capacity, last: now}
+		tb.buckets[key] = bucket
+	}
+
+	bucket.tokens = math.Min(capacity, bucket.tokens+now.Sub(bucket.last).Seconds()*tb.rate)
+	bucket.last = now
+
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		allowed = true
+	} else {
+		retryAfter = tb.duration(1 - bucket.tokens)
+	}
+
+	return allowed, int(bucket.tokens), tb.duration(capacity - bucket.tokens), retryAfter
+}
+
+// duration converte uma quantidade de fichas no tempo necessário para repô-las
+func (tb *tokenBuckets) duration(tokens float64) time.Duration {
+	return time.Duration(tokens / tb.rate * float64(time.Second))
+}
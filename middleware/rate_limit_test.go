@@ -0,0 +1,207 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"auth-service/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func newTestRateLimiter(policies map[string]config.RateLimitPolicy) *RateLimiter {
+	rl := &RateLimiter{enabled: true, policies: make(map[string]*tokenBuckets)}
+	for name, policy := range policies {
+		rl.policies[name] = newTokenBuckets(policy)
+	}
+	return rl
+}
+
+// send faz a requisição com o IP remoto e o corpo informados
+func send(router *gin.Engine, method, path, remoteIP, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.RemoteAddr = remoteIP + ":40000"
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func okHandler(c *gin.Context) { c.Status(http.StatusNoContent) }
+
+func TestTokenBucketRefill(t *testing.T) {
+	buckets := newTokenBuckets(config.RateLimitPolicy{Requests: 1, PeriodSeconds: 10, Burst: 2})
+	start := time.Now()
+
+	tests := []struct {
+		name      string
+		at        time.Duration
+		allowed   bool
+		remaining int
+	}{
+		{"primeira ficha", 0, true, 1},
+		{"segunda ficha", 0, true, 0},
+		{"bucket vazio", time.Second, false, 0},
+		{"uma ficha reposta", 11 * time.Second, true, 0},
+		{"bucket cheio de novo", 40 * time.Second, true, 1},
+	}
+	for _, tt := range tests {
+		allowed, remaining, _, retryAfter := buckets.take("k", start.Add(tt.at))
+		if allowed != tt.allowed || remaining != tt.remaining {
+			t.Errorf("%s: allowed=%v remaining=%d, esperado allowed=%v remaining=%d", tt.name, allowed, remaining, tt.allowed, tt.remaining)
+		}
+		if !allowed && retryAfter <= 0 {
+			t.Errorf("%s: recusa sem Retry-After", tt.name)
+		}
+	}
+}
+
+func TestRateLimitHeaders(t *testing.T) {
+	rl := newTestRateLimiter(map[string]config.RateLimitPolicy{
+		"register": {Requests: 1, PeriodSeconds: 60, Burst: 1, Key: "ip"},
+	})
+	router := gin.New()
+	router.POST("/register", rl.Limit("register"), okHandler)
+
+	first := send(router, http.MethodPost, "/register", "203.0.113.1", "{}")
+	if first.Code != http.StatusNoContent {
+		t.Fatalf("primeira requisição: %d", first.Code)
+	}
+	for header, want := range map[string]string{
+		"RateLimit-Policy":    "1;w=60;burst=1",
+		"RateLimit-Limit":     "1",
+		"RateLimit-Remaining": "0",
+		"RateLimit-Reset":     "60",
+	} {
+		if got := first.Header().Get(header); got != want {
+			t.Errorf("%s = %q, esperado %q", header, got, want)
+		}
+	}
+
+	second := send(router, http.MethodPost, "/register", "203.0.113.1", "{}")
+	if second.Code != http.StatusTooManyRequests {
+		t.Fatalf("segunda requisição: %d, esperado 429", second.Code)
+	}
+	if second.Header().Get("Retry-After") != "60" {
+		t.Errorf("Retry-After = %q, esperado 60", second.Header().Get("Retry-After"))
+	}
+	if !strings.Contains(second.Body.String(), "RATE_LIMITED") {
+		t.Errorf("corpo sem o código do problema: %s", second.Body.String())
+	}
+
+	// Outro IP tem o próprio bucket
+	if w := send(router, http.MethodPost, "/register", "203.0.113.2", "{}"); w.Code != http.StatusNoContent {
+		t.Errorf("outro IP: %d", w.Code)
+	}
+}
+
+func TestRequestKey(t *testing.T) {
+	tests := []struct {
+		name   string
+		key    string
+		body   string
+		userID string
+		want   string
+	}{
+		{"email do corpo", "email", `{"email":" Ana@Example.com "}`, "", "email:ana@example.com"},
+		{"sem email cai para o IP", "email", `{"token":"x"}`, "", "ip:203.0.113.1"},
+		{"corpo inválido cai para o IP", "email", `não é json`, "", "ip:203.0.113.1"},
+		{"usuário autenticado", "user", `{"email":"outro@example.com"}`, "u-1", "user:u-1"},
+		{"sem usuário cai para o IP", "user", `{}`, "", "ip:203.0.113.1"},
+		{"ip ignora o corpo", "ip", `{"email":"ana@example.com"}`, "u-1", "ip:203.0.113.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			router := gin.New()
+			router.POST("/", func(c *gin.Context) {
+				if tt.userID != "" {
+					c.Set("user_id", tt.userID)
+				}
+				got = requestKey(c, tt.key)
+
+				// O corpo continua disponível para o handler
+				var fields rateLimitFields
+				if tt.name == "email do corpo" && (c.ShouldBindJSON(&fields) != nil || fields.Email == "") {
+					t.Error("corpo não foi restaurado para o handler")
+				}
+			})
+			send(router, http.MethodPost, "/", "203.0.113.1", tt.body)
+			if got != tt.want {
+				t.Errorf("chave %q, esperado %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// Emails diferentes a cada tentativa não escapam do limite por IP do /login nem do
+// limite das rotas que não recebem email
+func TestRateLimitIgnoresRotatingEmails(t *testing.T) {
+	rl := newTestRateLimiter(map[string]config.RateLimitPolicy{
+		"login":      {Requests: 5, PeriodSeconds: 60, Burst: 5, Key: "email"},
+		"login_ip":   {Requests: 3, PeriodSeconds: 60, Burst: 3, Key: "ip"},
+		"link_token": {Requests: 2, PeriodSeconds: 60, Burst: 2, Key: "ip"},
+	})
+	router := gin.New()
+	router.POST("/login", rl.Limit("login_ip"), rl.Limit("login"), okHandler)
+	router.POST("/password/reset", rl.Limit("link_token"), okHandler)
+
+	tests := []struct {
+		path    string
+		allowed int
+	}{
+		{"/login", 3},
+		{"/password/reset", 2},
+	}
+	for _, tt := range tests {
+		for i := 0; i < tt.allowed+1; i++ {
+			body := fmt.Sprintf(`{"email":"conta%d@example.com","token":"t"}`, i)
+			w := send(router, http.MethodPost, tt.path, "203.0.113.9", body)
+			want := http.StatusNoContent
+			if i == tt.allowed {
+				want = http.StatusTooManyRequests
+			}
+			if w.Code != want {
+				t.Errorf("%s tentativa %d: %d, esperado %d", tt.path, i+1, w.Code, want)
+			}
+		}
+	}
+}
+
+func TestRateLimitTrustedNetworks(t *testing.T) {
+	rl := newTestRateLimiter(map[string]config.RateLimitPolicy{
+		"validate": {Requests: 1, PeriodSeconds: 60, Burst: 1, Key: "ip"},
+		"login":    {Requests: 1, PeriodSeconds: 60, Burst: 1, Key: "ip"},
+	})
+	_, internal, _ := net.ParseCIDR("10.0.0.0/8")
+	rl.trusted = append(rl.trusted, internal)
+
+	router := gin.New()
+	router.POST("/validate", rl.Limit("validate"), okHandler)
+	router.POST("/login", rl.Limit("login"), okHandler)
+
+	tests := []struct {
+		path   string
+		ip     string
+		second int
+	}{
+		{"/validate", "10.1.2.3", http.StatusNoContent},
+		{"/validate", "203.0.113.5", http.StatusTooManyRequests},
+		{"/login", "10.1.2.3", http.StatusTooManyRequests},
+	}
+	for _, tt := range tests {
+		send(router, http.MethodPost, tt.path, tt.ip, "{}")
+		if w := send(router, http.MethodPost, tt.path, tt.ip, "{}"); w.Code != tt.second {
+			t.Errorf("%s de %s: %d, esperado %d", tt.path, tt.ip, w.Code, tt.second)
+		}
+	}
+}
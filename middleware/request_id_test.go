@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"auth-service/logging"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		keep   bool
+	}{
+		{"ID recebido", "req-123", true},
+		{"sem ID", "", false},
+		{"ID com espaço", "req 123", false},
+		{"ID longo demais", strings.Repeat("a", 65), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fromContext string
+			router := gin.New()
+			router.Use(RequestID())
+			router.GET("/", func(c *gin.Context) {
+				fromContext = logging.RequestID(c.Request.Context())
+				c.Status(http.StatusNoContent)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(RequestIDHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			got := w.Header().Get(RequestIDHeader)
+			if got != fromContext {
+				t.Errorf("resposta com %q e contexto com %q", got, fromContext)
+			}
+			if tt.keep && got != tt.header {
+				t.Errorf("ID %q, esperado o recebido %q", got, tt.header)
+			}
+			if !tt.keep {
+				if _, err := uuid.Parse(got); err != nil {
+					t.Errorf("ID gerado %q não é um UUID", got)
+				}
+			}
+		})
+	}
+}
+
+// capturingHandler guarda o nível e o request ID de cada registro de log
+type capturingHandler struct {
+	mu      sync.Mutex
+	records []capturedRecord
+}
+
+type capturedRecord struct {
+	level     slog.Level
+	requestID string
+}
+
+func (h *capturingHandler) Enabled(context.Context, slog.Level) bool { return true }
+func (h *capturingHandler) WithAttrs([]slog.Attr) slog.Handler       { return h }
+func (h *capturingHandler) WithGroup(string) slog.Handler            { return h }
+
+func (h *capturingHandler) Handle(ctx context.Context, record slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.records = append(h.records, capturedRecord{record.Level, logging.RequestID(ctx)})
+	return nil
+}
+
+func TestAccessLogLevelByStatus(t *testing.T) {
+	handler := &capturingHandler{}
+	previous := slog.Default()
+	slog.SetDefault(slog.New(handler))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	tests := []struct {
+		status int
+		level  slog.Level
+	}{
+		{http.StatusOK, slog.LevelInfo},
+		{http.StatusNotFound, slog.LevelWarn},
+		{http.StatusInternalServerError, slog.LevelError},
+	}
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			router := gin.New()
+			router.Use(RequestID(), AccessLog())
+			router.GET("/", func(c *gin.Context) { c.Status(tt.status) })
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(RequestIDHeader, "req-log")
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			last := handler.records[len(handler.records)-1]
+			if last.level != tt.level || last.requestID != "req-log" {
+				t.Errorf("registro %v com request ID %q, esperado %v com req-log", last.level, last.requestID, tt.level)
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"auth-service/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestInfo anexa IP e user agent ao contexto da requisição para a trilha de auditoria
+func RequestInfo() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := services.WithRequestInfo(c.Request.Context(), services.RequestInfo{
+			IP:        c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+		})
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
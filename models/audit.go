@@ -0,0 +1,92 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tipos de eventos de autenticação registrados na trilha de auditoria
+const (
+	EventRegister             = "register"
+	EventLogin                = "login"
+	EventFederatedLogin       = "federated_login"
+	EventTokenRefresh         = "token_refresh"
+	EventLogout               = "logout"
+	EventTokenValidation      = "token_validation"
+	EventClientCreated        = "admin.client_created"
+	EventClientUpdated        = "admin.client_updated"
+	EventRoleGranted          = "admin.role_granted"
+	EventRoleRevoked          = "admin.role_revoked"
+	EventPersonalTokenCreated = "personal_token_created"
+	EventPersonalTokenRevoked = "personal_token_revoked"
+	EventMagicLinkRequested   = "magic_link_requested"
+	EventMagicLinkLogin       = "magic_link_login"
+	EventInvitationCreated    = "admin.invitation_created"
+	EventInvitationRevoked    = "admin.invitation_revoked"
+	EventOrganizationCreated  = "admin.organization_created"
+	EventDeviceAuthorization  = "device_authorization"
+	EventDeviceVerification   = "device_verification"
+	EventDeviceLogin          = "device_login"
+	EventUserUpdated          = "admin.user_updated"
+	EventUserDeleted          = "admin.user_deleted"
+	EventWebhookCreated       = "admin.webhook_created"
+	EventWebhookDeleted       = "admin.webhook_deleted"
+	EventOutboxReplayed       = "admin.outbox_replayed"
+	EventClientSecretRotated  = "admin.client_secret_rotated"
+	EventSessionsRevoked      = "admin.sessions_revoked"
+	EventPasswordResetRequest = "password_reset_requested"
+	EventPasswordReset        = "password_reset"
+	EventPasswordChanged      = "password_changed"
+	EventUsersImported        = "admin.users_imported"
+	EventNewDeviceLogin       = "new_device_login"
+	EventLoginReported        = "login_reported"
+	EventAuthorize            = "authorize"
+	EventCodeLogin            = "authorization_code_login"
+)
+
+// Resultados possíveis de um evento
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+// RoleAdmin dá acesso às rotas administrativas
+const RoleAdmin = "admin"
+
+// AuthEvent é um registro da trilha de auditoria. Reason traz o código do erro
+// nas falhas ou o detalhe da ação administrativa (ex: o papel concedido).
+type AuthEvent struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	Type      string    `json:"event_type" db:"event_type"`
+	UserID    string    `json:"user_id,omitempty" db:"user_id"`
+	ClientID  string    `json:"client_id,omitempty" db:"client_id"`
+	IP        string    `json:"ip,omitempty" db:"ip"`
+	UserAgent string    `json:"user_agent,omitempty" db:"user_agent"`
+	Outcome   string    `json:"outcome" db:"outcome"`
+	Reason    string    `json:"reason,omitempty" db:"reason"`
+	RequestID string    `json:"request_id,omitempty" db:"request_id"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// AuthEventQuery são os filtros aceitos pela consulta administrativa de eventos
+type AuthEventQuery struct {
+	UserID    string    `form:"user_id" binding:"omitempty,uuid"`
+	ClientID  string    `form:"client_id" binding:"omitempty,uuid"`
+	EventType string    `form:"event_type"`
+	Outcome   string    `form:"outcome" binding:"omitempty,oneof=success failure"`
+	IP        string    `form:"ip"`
+	RequestID string    `form:"request_id"`
+	From      time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To        time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Page      int       `form:"page" binding:"omitempty,min=1"`
+	PageSize  int       `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+// AuthEventPage é uma página de eventos com o total que atende aos filtros
+type AuthEventPage struct {
+	Events   []AuthEvent `json:"events"`
+	Page     int         `json:"page"`
+	PageSize int         `json:"page_size"`
+	Total    int64       `json:"total"`
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GrantTypeDeviceCode é o grant_type da troca do device_code por tokens (RFC 8628)
+const GrantTypeDeviceCode = "urn:ietf:params:oauth:grant-type:device_code"
+
+// Situações de um DeviceCode
+const (
+	DeviceCodePending  = "pending"
+	DeviceCodeApproved = "approved"
+	DeviceCodeDenied   = "denied"
+)
+
+// DeviceCode é uma autorização de dispositivo em andamento. O dispositivo guarda o
+// device_code, do qual só o hash SHA-256 é armazenado; o usuário digita o user_code
+// na página de verificação. O registro é removido quando os tokens são emitidos.
+type DeviceCode struct {
+	ID             uuid.UUID  `json:"id" db:"id"`
+	DeviceCodeHash string     `json:"-" db:"device_code_hash"`
+	UserCode       string     `json:"user_code" db:"user_code"`
+	ClientID       uuid.UUID  `json:"client_id" db:"client_id"`
+	Scope          string     `json:"scope" db:"scope"`
+	Status         string     `json:"status" db:"status"`
+	UserID         *uuid.UUID `json:"user_id,omitempty" db:"user_id"`
+	Interval       int        `json:"interval" db:"interval_seconds"`
+	LastPolledAt   *time.Time `json:"last_polled_at,omitempty" db:"last_polled_at"`
+	AuthTime       *time.Time `json:"auth_time,omitempty" db:"auth_time"` // login da sessão que aprovou o pedido
+	ExpiresAt      time.Time  `json:"expires_at" db:"expires_at"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+}
+
+// DeviceAuthorizationRequest inicia a autorização de um dispositivo. Aceita JSON ou
+// application/x-www-form-urlencoded, como na RFC 8628.
+type DeviceAuthorizationRequest struct {
+	ClientID string `json:"client_id" form:"client_id" binding:"required"`
+	Scope    string `json:"scope" form:"scope"`
+}
+
+// DeviceAuthorizationResponse segue a seção 3.2 da RFC 8628
+type DeviceAuthorizationResponse struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int64  `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+// DeviceVerificationInfo descreve o pedido para a página de aprovação
+type DeviceVerificationInfo struct {
+	UserCode   string    `json:"user_code"`
+	ClientID   uuid.UUID `json:"client_id"`
+	ClientName string    `json:"client_name"`
+	Scope      string    `json:"scope"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// DeviceVerificationRequest aprova ou recusa o pedido identificado pelo user_code
+type DeviceVerificationRequest struct {
+	UserCode string `json:"user_code" binding:"required"`
+	Action   string `json:"action" binding:"required,oneof=approve deny"`
+}
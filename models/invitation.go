@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Invitation é um código de convite criado por um administrador. Cada cadastro com o
+// código consome um uso; a conta é criada na organização do convite e recebe os papéis. Apenas o hash SHA-256
+// do código é guardado.
+type Invitation struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	TenantID  uuid.UUID  `json:"tenant_id" db:"tenant_id"`
+	CodeHash  string     `json:"-" db:"code_hash"`
+	Prefix    string     `json:"prefix" db:"prefix"`
+	Roles     []string   `json:"roles" db:"roles"`
+	MaxUses   int        `json:"max_uses" db:"max_uses"`
+	Uses      int        `json:"uses" db:"uses"`
+	ExpiresAt time.Time  `json:"expires_at" db:"expires_at"`
+	RevokedAt *time.Time `json:"-" db:"revoked_at"`
+	CreatedBy *uuid.UUID `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+// CreateInvitationRequest cria um convite. Sem max_uses o convite vale para um único cadastro.
+type CreateInvitationRequest struct {
+	Roles          []string `json:"roles"`
+	MaxUses        int      `json:"max_uses" binding:"omitempty,min=1,max=10000"`
+	ExpiresInHours int      `json:"expires_in_hours" binding:"required,min=1,max=8760"`
+}
+
+// CreatedInvitation é a resposta da criação, a única vez em que o código aparece em claro
+type CreatedInvitation struct {
+	Invitation
+	Code string `json:"code"`
+}
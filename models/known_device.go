@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// KnownDevice é uma combinação de navegador e rede já usada pelo usuário para entrar. A
+// impressão é o SHA-256 do user agent com o prefixo do IP (/24 no IPv4, /48 no IPv6), de
+// modo que mudanças de IP dentro da mesma rede não geram avisos.
+type KnownDevice struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	UserID      uuid.UUID `json:"user_id" db:"user_id"`
+	Fingerprint string    `json:"-" db:"fingerprint"`
+	UserAgent   string    `json:"user_agent" db:"user_agent"`
+	IPPrefix    string    `json:"ip_prefix" db:"ip_prefix"`
+	FirstSeenAt time.Time `json:"first_seen_at" db:"first_seen_at"`
+	LastSeenAt  time.Time `json:"last_seen_at" db:"last_seen_at"`
+}
+
+// ReportLoginRequest denuncia, com o token do aviso enviado por email, um login que o
+// usuário não reconhece
+type ReportLoginRequest struct {
+	Token string `json:"token" binding:"required"`
+}
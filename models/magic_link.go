@@ -0,0 +1,14 @@
+package models
+
+// MagicLinkRequest pede o envio de um link de acesso por email
+type MagicLinkRequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	ClientID string `json:"client_id" binding:"required"`
+}
+
+// ConsumeMagicLinkRequest troca o token do link por tokens de acesso. O client deve
+// ser o mesmo que pediu o link.
+type ConsumeMagicLinkRequest struct {
+	Token    string `json:"token" binding:"required"`
+	ClientID string `json:"client_id" binding:"required"`
+}
@@ -0,0 +1,89 @@
+package models
+
+// GrantTypeAuthorizationCode é o grant_type da troca do código emitido no /oauth/authorize (RFC 6749)
+const GrantTypeAuthorizationCode = "authorization_code"
+
+type OIDCDiscovery struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	JWKSURI                           string   `json:"jwks_uri"`
+	UserInfoEndpoint                  string   `json:"userinfo_endpoint"`
+	DeviceAuthorizationEndpoint       string   `json:"device_authorization_endpoint"`
+	GrantTypesSupported               []string `json:"grant_types_supported"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+	SubjectTypesSupported             []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+	ScopesSupported                   []string `json:"scopes_supported"`
+	ClaimsSupported                   []string `json:"claims_supported"`
+}
+
+// AuthorizationRequest são os parâmetros do /oauth/authorize (RFC 6749, seção 4.1.1). O
+// PKCE (RFC 7636) é obrigatório e só o método S256 é aceito.
+type AuthorizationRequest struct {
+	ResponseType        string `json:"response_type" form:"response_type" binding:"required"`
+	ClientID            string `json:"client_id" form:"client_id" binding:"required"`
+	RedirectURI         string `json:"redirect_uri" form:"redirect_uri" binding:"required"`
+	Scope               string `json:"scope" form:"scope"`
+	State               string `json:"state" form:"state"`
+	Nonce               string `json:"nonce" form:"nonce"`
+	CodeChallenge       string `json:"code_challenge" form:"code_challenge"`
+	CodeChallengeMethod string `json:"code_challenge_method" form:"code_challenge_method"`
+}
+
+// AuthorizationResponse é o endereço do client, já com code e state, para onde a página
+// de autorização leva o navegador
+type AuthorizationResponse struct {
+	RedirectTo string `json:"redirect_to"`
+}
+
+// TokenRequest é a requisição ao /oauth/token: a consulta periódica do dispositivo
+// (device_code) ou a troca do código emitido no /oauth/authorize (authorization_code)
+type TokenRequest struct {
+	GrantType    string `json:"grant_type" form:"grant_type" binding:"required"`
+	ClientID     string `json:"client_id" form:"client_id" binding:"required"`
+	DeviceCode   string `json:"device_code" form:"device_code"`
+	Code         string `json:"code" form:"code"`
+	RedirectURI  string `json:"redirect_uri" form:"redirect_uri"`
+	CodeVerifier string `json:"code_verifier" form:"code_verifier"`
+}
+
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+type UserInfoResponse struct {
+	Sub       string `json:"sub"`
+	Email     string `json:"email,omitempty"`
+	Name      string `json:"name,omitempty"`
+	UpdatedAt int64  `json:"updated_at,omitempty"`
+}
+
+type IntrospectRequest struct {
+	Token    string `json:"token" binding:"required"`
+	ClientID string `json:"client_id" binding:"required"`
+}
+
+// IntrospectionResponse segue a RFC 7662; para tokens inválidos, apenas Active = false
+type IntrospectionResponse struct {
+	Active    bool   `json:"active"`
+	Sub       string `json:"sub,omitempty"`
+	ClientID  string `json:"client_id,omitempty"`
+	TenantID  string `json:"tenant_id,omitempty"`
+	Email     string `json:"email,omitempty"`
+	Scope     string `json:"scope,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+	Exp       int64  `json:"exp,omitempty"`
+	Iat       int64  `json:"iat,omitempty"`
+}
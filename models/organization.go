@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultTenantID é a organização que recebe os usuários e clients criados antes da
+// separação por tenant e os cadastros feitos sem client
+const DefaultTenantID = "00000000-0000-0000-0000-000000000001"
+
+// Organization é um tenant: usuários, clients e convites pertencem a exatamente um
+type Organization struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	Slug      string    `json:"slug" db:"slug"`
+	Name      string    `json:"name" db:"name"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type CreateOrganizationRequest struct {
+	Slug string `json:"slug" binding:"required,min=2,max=63"` // letras minúsculas, números e hífens
+	Name string `json:"name" binding:"required,max=255"`
+}
@@ -0,0 +1,140 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tipos de eventos publicados no outbox para os webhooks
+const (
+	OutboxUserCreated     = "user.created"
+	OutboxUserUpdated     = "user.updated"
+	OutboxUserDeactivated = "user.deactivated"
+	OutboxUserDeleted     = "user.deleted"
+)
+
+// OutboxEventTypes são os tipos de eventos que um webhook pode assinar
+var OutboxEventTypes = []string{OutboxUserCreated, OutboxUserUpdated, OutboxUserDeactivated, OutboxUserDeleted}
+
+// Situações de uma entrega de webhook
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed"
+)
+
+// OutboxEvent é um evento gravado na mesma transação da alteração que o originou. O
+// dispatcher o distribui aos webhooks da organização e preenche DispatchedAt.
+type OutboxEvent struct {
+	ID           uuid.UUID       `json:"id" db:"id"`
+	TenantID     uuid.UUID       `json:"tenant_id" db:"tenant_id"`
+	Type         string          `json:"event_type" db:"event_type"`
+	AggregateID  string          `json:"aggregate_id" db:"aggregate_id"` // ID do usuário afetado
+	Payload      json.RawMessage `json:"payload" db:"payload"`
+	DispatchedAt *time.Time      `json:"dispatched_at,omitempty" db:"dispatched_at"`
+	CreatedAt    time.Time       `json:"created_at" db:"created_at"`
+}
+
+// UserEventPayload é o conteúdo dos eventos user.*. Em user.deleted traz o usuário como
+// estava antes da remoção.
+type UserEventPayload struct {
+	ID        uuid.UUID `json:"id"`
+	TenantID  uuid.UUID `json:"tenant_id"`
+	Email     string    `json:"email"`
+	Name      string    `json:"name"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// WebhookEnvelope é o corpo enviado ao webhook
+type WebhookEnvelope struct {
+	ID        uuid.UUID       `json:"id"`
+	Type      string          `json:"type"`
+	TenantID  uuid.UUID       `json:"tenant_id"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Webhook recebe os eventos da organização assinados com HMAC-SHA256. O segredo é
+// guardado em claro porque é necessário para assinar cada entrega.
+type Webhook struct {
+	ID         uuid.UUID `json:"id" db:"id"`
+	TenantID   uuid.UUID `json:"tenant_id" db:"tenant_id"`
+	URL        string    `json:"url" db:"url"`
+	Secret     string    `json:"-" db:"secret"`
+	EventTypes []string  `json:"event_types" db:"event_types"` // vazio assina todos os tipos
+	Active     bool      `json:"active" db:"active"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Subscribes indica se o webhook deve receber eventos do tipo informado
+func (w *Webhook) Subscribes(eventType string) bool {
+	if len(w.EventTypes) == 0 {
+		return true
+	}
+	for _, t := range w.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateWebhookRequest registra um webhook na organização do administrador
+type CreateWebhookRequest struct {
+	URL        string   `json:"url" binding:"required,url"`
+	EventTypes []string `json:"event_types" binding:"dive,oneof=user.created user.updated user.deactivated user.deleted"`
+}
+
+// CreateWebhookResponse traz o segredo de assinatura, exibido apenas na criação
+type CreateWebhookResponse struct {
+	Webhook
+	Secret string `json:"secret"`
+}
+
+// WebhookDelivery acompanha a entrega de um evento a um webhook, com as tentativas já feitas
+type WebhookDelivery struct {
+	ID             uuid.UUID  `json:"id" db:"id"`
+	EventID        uuid.UUID  `json:"event_id" db:"event_id"`
+	WebhookID      uuid.UUID  `json:"webhook_id" db:"webhook_id"`
+	Status         string     `json:"status" db:"status"`
+	Attempts       int        `json:"attempts" db:"attempts"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at" db:"next_attempt_at"`
+	LastStatusCode int        `json:"last_status_code,omitempty" db:"last_status_code"`
+	LastError      string     `json:"last_error,omitempty" db:"last_error"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty" db:"delivered_at"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// OutboxEventDetail é o evento com as entregas feitas até agora
+type OutboxEventDetail struct {
+	OutboxEvent
+	Deliveries []WebhookDelivery `json:"deliveries"`
+}
+
+// OutboxEventQuery são os filtros da consulta administrativa do outbox
+type OutboxEventQuery struct {
+	EventType   string `form:"event_type"`
+	AggregateID string `form:"aggregate_id" binding:"omitempty,uuid"`
+	Page        int    `form:"page" binding:"omitempty,min=1"`
+	PageSize    int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+// OutboxEventPage é uma página de eventos do outbox com o total que atende aos filtros
+type OutboxEventPage struct {
+	Events   []OutboxEvent `json:"events"`
+	Page     int           `json:"page"`
+	PageSize int           `json:"page_size"`
+	Total    int64         `json:"total"`
+}
+
+// UpdateUserRequest altera dados de um usuário pela administração. Campos omitidos não mudam.
+type UpdateUserRequest struct {
+	Name   *string `json:"name" binding:"omitempty,min=1"`
+	Active *bool   `json:"active"`
+}
@@ -0,0 +1,14 @@
+package models
+
+// ForgotPasswordRequest pede o envio de um link para redefinir a senha. Sem client_id, a
+// conta é procurada na organização padrão.
+type ForgotPasswordRequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	ClientID string `json:"client_id"`
+}
+
+// ResetPasswordRequest define a nova senha com o token recebido por email
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=8"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Escopos que podem ser concedidos a tokens de acesso pessoal
+const (
+	PersonalTokenScopeRead  = "read"
+	PersonalTokenScopeWrite = "write"
+)
+
+// Tipos do token validado, informados em UserResponse.TokenType
+const (
+	TokenTypeSession  = "session"  // JWT emitido no login
+	TokenTypePersonal = "personal" // token de acesso pessoal
+)
+
+// PersonalAccessToken é um token de longa duração criado pelo usuário para scripts e
+// integrações. Apenas o hash SHA-256 é guardado; Prefix identifica o token nas listagens.
+type PersonalAccessToken struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	UserID     uuid.UUID  `json:"-" db:"user_id"`
+	Name       string     `json:"name" db:"name"`
+	TokenHash  string     `json:"-" db:"token_hash"`
+	Prefix     string     `json:"prefix" db:"prefix"`
+	Scope      string     `json:"scope" db:"scope"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" db:"last_used_at"`
+	RevokedAt  *time.Time `json:"-" db:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
+// CreatePersonalTokenRequest cria um token de acesso pessoal. Sem expires_in_days o token não expira.
+type CreatePersonalTokenRequest struct {
+	Name          string `json:"name" binding:"required,max=100"`
+	Scope         string `json:"scope" binding:"required"` // escopos separados por espaço: "read" e/ou "write"
+	ExpiresInDays int    `json:"expires_in_days" binding:"omitempty,min=1,max=365"`
+}
+
+// CreatedPersonalToken é a resposta da criação, a única vez em que o token aparece em claro
+type CreatedPersonalToken struct {
+	PersonalAccessToken
+	Token string `json:"token"`
+}
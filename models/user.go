@@ -8,6 +8,7 @@ import (
 
 type User struct {
 	ID        uuid.UUID `json:"id" db:"id"`
+	TenantID  uuid.UUID `json:"tenant_id" db:"tenant_id"`
 	Email     string    `json:"email" db:"email"`
 	Password  string    `json:"-" db:"password"`
 	Name      string    `json:"name" db:"name"`
@@ -17,35 +18,58 @@ type User struct {
 }
 
 type Client struct {
-	ID          uuid.UUID `json:"id" db:"id"`
-	Name        string    `json:"name" db:"name"`
-	Description string    `json:"description" db:"description"`
-	Secret      string    `json:"-" db:"secret"`
-	Active      bool      `json:"active" db:"active"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	ID               uuid.UUID `json:"id" db:"id"`
+	TenantID         uuid.UUID `json:"tenant_id" db:"tenant_id"`
+	Name             string    `json:"name" db:"name"`
+	Description      string    `json:"description" db:"description"`
+	Secret           string    `json:"-" db:"secret"`
+	Active           bool      `json:"active" db:"active"`
+	MagicLinkEnabled bool      `json:"magic_link_enabled" db:"magic_link_enabled"` // libera o login por link enviado por email
+	RedirectURIs     []string  `json:"redirect_uris" db:"redirect_uris"`           // endereços de retorno aceitos no /oauth/authorize
+	CreatedAt        time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// UpdateClientRequest altera as opções de um client. Campos omitidos não mudam.
+type UpdateClientRequest struct {
+	Active           *bool     `json:"active"`
+	MagicLinkEnabled *bool     `json:"magic_link_enabled"`
+	RedirectURIs     *[]string `json:"redirect_uris"` // substitui a lista inteira
 }
 
 type RefreshToken struct {
-	ID        uuid.UUID `json:"id" db:"id"`
-	UserID    uuid.UUID `json:"user_id" db:"user_id"`
-	ClientID  uuid.UUID `json:"client_id" db:"client_id"`
-	Token     string    `json:"token" db:"token"`
-	ExpiresAt time.Time `json:"expires_at" db:"expires_at"`
-	Revoked   bool      `json:"revoked" db:"revoked"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	ID        uuid.UUID  `json:"id" db:"id"`
+	UserID    uuid.UUID  `json:"user_id" db:"user_id"`
+	ClientID  uuid.UUID  `json:"client_id" db:"client_id"`
+	Token     string     `json:"token" db:"token"`
+	Scope     string     `json:"scope" db:"scope"`                   // escopos do login, repassados a cada renovação
+	AuthTime  *time.Time `json:"auth_time,omitempty" db:"auth_time"` // momento do login, mantido nas renovações
+	ExpiresAt time.Time  `json:"expires_at" db:"expires_at"`
+	Revoked   bool       `json:"revoked" db:"revoked"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
 }
 
+// MinPasswordLength é o tamanho mínimo de senha, o mesmo min=8 das requisições que recebem senha
+const MinPasswordLength = 8
+
 type RegisterRequest struct {
-	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required,min=8"`
-	Name     string `json:"name" binding:"required"`
+	Email      string `json:"email" binding:"required,email"`
+	Password   string `json:"password" binding:"required,min=8"`
+	Name       string `json:"name" binding:"required"`
+	InviteCode string `json:"invite_code"` // obrigatório no modo de cadastro invite_only
+	ClientID   string `json:"client_id"`   // define a organização da conta; sem ele, a organização padrão
 }
 
 type LoginRequest struct {
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required"`
+	ClientID string `json:"client_id"`
+	Scope    string `json:"scope"` // escopos separados por espaço, ex: "openid email profile"
+	Nonce    string `json:"nonce"`
+
+	// WithRefreshToken é definido pelo handler no modo de sessão por cookie
+	WithRefreshToken bool `json:"-"`
 }
 
 type TokenResponse struct {
@@ -53,6 +77,24 @@ type TokenResponse struct {
 	RefreshToken string `json:"refresh_token"`
 	TokenType    string `json:"token_type"`
 	ExpiresIn    int64  `json:"expires_in"`
+	IDToken      string `json:"id_token,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+}
+
+// SessionTokenResponse é a resposta do modo de sessão por cookie: o refresh token vai no
+// cookie HttpOnly e o corpo traz o token CSRF que deve acompanhar as renovações
+type SessionTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+	IDToken     string `json:"id_token,omitempty"`
+	Scope       string `json:"scope,omitempty"`
+	CSRFToken   string `json:"csrf_token"`
+}
+
+// EndSessionRequest encerra a sessão do navegador cujo refresh token está no cookie
+type EndSessionRequest struct {
+	ClientID string `json:"client_id" binding:"required"`
 }
 
 type RefreshTokenRequest struct {
@@ -66,16 +108,22 @@ type ValidateTokenRequest struct {
 }
 
 type UserResponse struct {
-	ID        uuid.UUID `json:"id"`
-	Email     string    `json:"email"`
-	Name      string    `json:"name"`
-	Active    bool      `json:"active"`
-	CreatedAt time.Time `json:"created_at"`
+	ID        uuid.UUID  `json:"id"`
+	TenantID  uuid.UUID  `json:"tenant_id"`
+	Email     string     `json:"email"`
+	Name      string     `json:"name"`
+	Active    bool       `json:"active"`
+	Roles     []string   `json:"roles,omitempty"`
+	Scope     string     `json:"scope,omitempty"`      // escopos do token de acesso pessoal; vazio para JWTs
+	TokenType string     `json:"token_type,omitempty"` // tipo do token validado: TokenTypeSession ou TokenTypePersonal
+	AuthTime  *time.Time `json:"auth_time,omitempty"`  // momento do login da sessão do JWT validado, quando conhecido
+	CreatedAt time.Time  `json:"created_at"`
 }
 
 type JWTCustomClaims struct {
 	UserID   string `json:"user_id"`
 	Email    string `json:"email"`
 	ClientID string `json:"client_id"`
+	TenantID string `json:"tenant_id"`
 	Type     string `json:"type"` // "access" ou "refresh"
 }
@@ -0,0 +1,49 @@
+package models
+
+// Formatos aceitos na importação e na exportação de usuários
+const (
+	UserFileCSV  = "csv"
+	UserFileJSON = "json"
+)
+
+// UserFileColumns são as colunas do CSV, na ordem usada pela exportação
+var UserFileColumns = []string{"email", "name", "password_hash", "roles", "active"}
+
+// UserRecord é um usuário no arquivo de importação. A exportação usa o mesmo formato, sem
+// o hash da senha. No CSV, os papéis são separados por espaço.
+type UserRecord struct {
+	Email        string   `json:"email"`
+	Name         string   `json:"name"`
+	PasswordHash string   `json:"password_hash,omitempty"` // hash bcrypt, usado só na criação; sem ele, o usuário recebe um link para definir a senha
+	Roles        []string `json:"roles,omitempty"`
+	Active       *bool    `json:"active,omitempty"` // omitido: ativo para novos usuários, inalterado para existentes
+}
+
+// Resultado de cada linha da importação
+const (
+	ImportCreated   = "created"
+	ImportUpdated   = "updated"
+	ImportUnchanged = "unchanged"
+	ImportFailed    = "failed"
+)
+
+// UserImportRow é o resultado de uma linha. Row conta a partir de 1, sem o cabeçalho do CSV.
+type UserImportRow struct {
+	Row    int    `json:"row"`
+	Email  string `json:"email"`
+	Action string `json:"action"`
+	Code   string `json:"code,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// UserImportResult resume a importação. Em dry_run nada é gravado e as ações indicam o que
+// seria feito.
+type UserImportResult struct {
+	DryRun    bool            `json:"dry_run"`
+	Total     int             `json:"total"`
+	Created   int             `json:"created"`
+	Updated   int             `json:"updated"`
+	Unchanged int             `json:"unchanged"`
+	Failed    int             `json:"failed"`
+	Rows      []UserImportRow `json:"rows"`
+}
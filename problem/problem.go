@@ -0,0 +1,187 @@
+package problem
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"auth-service/logging"
+	"auth-service/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ContentType é o media type de respostas de erro (RFC 7807)
+const ContentType = "application/problem+json"
+
+// Problem é o corpo de uma resposta de erro no formato RFC 7807
+type Problem struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail,omitempty"`
+	Instance  string `json:"instance,omitempty"`
+	Code      string `json:"code"`
+	Error     string `json:"error,omitempty"` // código de erro OAuth 2.0 (RFC 6749), só nos erros do /oauth/authorize e do /oauth/token
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// statusByCode é o único lugar que associa códigos de erro a status HTTP
+var statusByCode = map[string]int{
+	services.ErrInvalidRequest.Code:     http.StatusBadRequest,
+	services.ErrMissingAuthToken.Code:   http.StatusUnauthorized,
+	services.ErrInvalidTokenFormat.Code: http.StatusUnauthorized,
+	services.ErrMissingClientID.Code:    http.StatusBadRequest,
+	services.ErrUnauthenticated.Code:    http.StatusUnauthorized,
+	services.ErrForbidden.Code:          http.StatusForbidden,
+	services.ErrRateLimited.Code:        http.StatusTooManyRequests,
+	services.ErrInvalidCSRFToken.Code:   http.StatusForbidden,
+
+	services.ErrEmailInUse.Code:         http.StatusConflict,
+	services.ErrInvalidCredentials.Code: http.StatusUnauthorized,
+	services.ErrUserInactive.Code:       http.StatusUnauthorized,
+	services.ErrUserNotFound.Code:       http.StatusNotFound,
+	services.ErrUnknownRole.Code:        http.StatusBadRequest,
+	services.ErrCannotModifySelf.Code:   http.StatusConflict,
+
+	services.ErrInviteRequired.Code:        http.StatusForbidden,
+	services.ErrInvalidInvite.Code:         http.StatusForbidden,
+	services.ErrEmailDomainNotAllowed.Code: http.StatusForbidden,
+	services.ErrInvitationNotFound.Code:    http.StatusNotFound,
+
+	services.ErrClientNotFound.Code:     http.StatusUnauthorized,
+	services.ErrClientInactive.Code:     http.StatusUnauthorized,
+	services.ErrClientUnauthorized.Code: http.StatusUnauthorized,
+	services.ErrClientRequired.Code:     http.StatusBadRequest,
+	services.ErrTenantMismatch.Code:     http.StatusUnauthorized,
+
+	services.ErrOrganizationNotFound.Code: http.StatusNotFound,
+	services.ErrOrganizationExists.Code:   http.StatusConflict,
+
+	services.ErrInvalidToken.Code:          http.StatusUnauthorized,
+	services.ErrInvalidTokenType.Code:      http.StatusUnauthorized,
+	services.ErrInsufficientScope.Code:     http.StatusForbidden,
+	services.ErrSessionRequired.Code:       http.StatusForbidden,
+	services.ErrInvalidRefreshToken.Code:   http.StatusUnauthorized,
+	services.ErrRefreshTokenRevoked.Code:   http.StatusUnauthorized,
+	services.ErrRefreshTokenExpired.Code:   http.StatusUnauthorized,
+	services.ErrPersonalTokenNotFound.Code: http.StatusNotFound,
+	services.ErrInvalidScope.Code:          http.StatusBadRequest,
+
+	services.ErrProviderNotFound.Code:     http.StatusNotFound,
+	services.ErrProviderRejected.Code:     http.StatusUnauthorized,
+	services.ErrInvalidState.Code:         http.StatusUnauthorized,
+	services.ErrInvalidNonce.Code:         http.StatusUnauthorized,
+	services.ErrInvalidProviderToken.Code: http.StatusUnauthorized,
+	services.ErrEmailNotVerified.Code:     http.StatusUnauthorized,
+
+	services.ErrMagicLinkDisabled.Code: http.StatusForbidden,
+	services.ErrInvalidMagicLink.Code:  http.StatusUnauthorized,
+	services.ErrMagicLinkUsed.Code:     http.StatusUnauthorized,
+
+	services.ErrUnsupportedGrantType.Code: http.StatusBadRequest,
+	services.ErrAuthorizationPending.Code: http.StatusBadRequest,
+	services.ErrSlowDown.Code:             http.StatusBadRequest,
+	services.ErrAccessDenied.Code:         http.StatusBadRequest,
+	services.ErrDeviceCodeExpired.Code:    http.StatusBadRequest,
+	services.ErrInvalidDeviceCode.Code:    http.StatusBadRequest,
+	services.ErrInvalidUserCode.Code:      http.StatusNotFound,
+
+	services.ErrUnsupportedResponseType.Code:  http.StatusBadRequest,
+	services.ErrInvalidRedirectURI.Code:       http.StatusBadRequest,
+	services.ErrPKCERequired.Code:             http.StatusBadRequest,
+	services.ErrInvalidAuthorizationCode.Code: http.StatusBadRequest,
+
+	services.ErrWebhookNotFound.Code:     http.StatusNotFound,
+	services.ErrInvalidWebhookURL.Code:   http.StatusBadRequest,
+	services.ErrOutboxEventNotFound.Code: http.StatusNotFound,
+
+	services.ErrInvalidPasswordReset.Code: http.StatusUnauthorized,
+	services.ErrPasswordReused.Code:       http.StatusBadRequest,
+	services.ErrInvalidImportFormat.Code:  http.StatusBadRequest,
+	services.ErrImportTooLarge.Code:       http.StatusRequestEntityTooLarge,
+
+	services.ErrInvalidLoginReport.Code: http.StatusUnauthorized,
+}
+
+// oauthErrorByCode dá o campo "error" que clientes OAuth 2.0 esperam no /oauth/authorize
+// e no /oauth/token (RFC 6749, seção 5.2, e RFC 8628, seção 3.5)
+var oauthErrorByCode = map[string]string{
+	services.ErrUnsupportedGrantType.Code: "unsupported_grant_type",
+	services.ErrAuthorizationPending.Code: "authorization_pending",
+	services.ErrSlowDown.Code:             "slow_down",
+	services.ErrAccessDenied.Code:         "access_denied",
+	services.ErrDeviceCodeExpired.Code:    "expired_token",
+	services.ErrInvalidDeviceCode.Code:    "invalid_grant",
+
+	services.ErrUnsupportedResponseType.Code:  "unsupported_response_type",
+	services.ErrInvalidRedirectURI.Code:       "invalid_request",
+	services.ErrPKCERequired.Code:             "invalid_request",
+	services.ErrInvalidAuthorizationCode.Code: "invalid_grant",
+}
+
+// From converte um erro em Problem. Erros sem código viram 500 sem expor detalhes internos.
+func From(err error) Problem {
+	var serviceErr *services.Error
+	if !errors.As(err, &serviceErr) {
+		return Problem{
+			Type:   typeURI("INTERNAL_ERROR"),
+			Title:  "erro interno",
+			Status: http.StatusInternalServerError,
+			Detail: "erro inesperado ao processar a requisição",
+			Code:   "INTERNAL_ERROR",
+		}
+	}
+
+	status, ok := statusByCode[serviceErr.Code]
+	if !ok {
+		status = http.StatusInternalServerError
+	}
+
+	return Problem{
+		Type:   typeURI(serviceErr.Code),
+		Title:  serviceErr.Message,
+		Status: status,
+		Detail: serviceErr.Error(),
+		Code:   serviceErr.Code,
+		Error:  oauthErrorByCode[serviceErr.Code],
+	}
+}
+
+// Write responde com o Problem correspondente ao erro
+func Write(c *gin.Context, err error) {
+	p := build(c, err)
+	c.Header("Content-Type", ContentType)
+	c.JSON(p.Status, p)
+}
+
+// Abort responde com o Problem correspondente ao erro e interrompe a cadeia de middlewares
+func Abort(c *gin.Context, err error) {
+	p := build(c, err)
+	c.Header("Content-Type", ContentType)
+	c.AbortWithStatusJSON(p.Status, p)
+}
+
+// InvalidRequest responde 400 para corpos ou parâmetros que não passaram na validação
+func InvalidRequest(c *gin.Context, err error) {
+	Write(c, services.ErrInvalidRequest.Wrap(err))
+}
+
+func build(c *gin.Context, err error) Problem {
+	p := From(err)
+	p.Instance = c.Request.URL.Path
+	p.RequestID = logging.RequestID(c.Request.Context())
+
+	if p.Status >= http.StatusInternalServerError {
+		slog.ErrorContext(c.Request.Context(), "Erro ao processar requisição",
+			"method", c.Request.Method, "path", c.Request.URL.Path, "error", err)
+	}
+
+	return p
+}
+
+// typeURI identifica o tipo do problema a partir do código (ex: INVALID_TOKEN -> urn:auth-service:problem:invalid-token)
+func typeURI(code string) string {
+	return "urn:auth-service:problem:" + strings.ReplaceAll(strings.ToLower(code), "_", "-")
+}
@@ -0,0 +1,124 @@
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"auth-service/logging"
+	"auth-service/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func TestFrom(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+		oauth  string
+	}{
+		{"erro do serviço", services.ErrInvalidCredentials, http.StatusUnauthorized, "INVALID_CREDENTIALS", ""},
+		{"erro com causa", services.ErrInvalidRequest.Wrap(errors.New("campo obrigatório")), http.StatusBadRequest, "INVALID_REQUEST", ""},
+		{"erro embrulhado com %w", fmt.Errorf("login: %w", services.ErrUserInactive), http.StatusUnauthorized, "USER_INACTIVE", ""},
+		{"erro OAuth", services.ErrSlowDown, http.StatusBadRequest, "SLOW_DOWN", "slow_down"},
+		{"código OAuth de outro nome", services.ErrDeviceCodeExpired, http.StatusBadRequest, services.ErrDeviceCodeExpired.Code, "expired_token"},
+		{"erro sem código", errors.New("conexão recusada"), http.StatusInternalServerError, "INTERNAL_ERROR", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := From(tt.err)
+			if p.Status != tt.status || p.Code != tt.code || p.Error != tt.oauth {
+				t.Errorf("From = status %d code %s error %q, esperado %d %s %q", p.Status, p.Code, p.Error, tt.status, tt.code, tt.oauth)
+			}
+			if want := "urn:auth-service:problem:" + strings.ReplaceAll(strings.ToLower(tt.code), "_", "-"); p.Type != want {
+				t.Errorf("type = %s, esperado %s", p.Type, want)
+			}
+		})
+	}
+}
+
+func TestFromHidesInternalErrors(t *testing.T) {
+	p := From(errors.New("dial tcp 10.0.0.5:3306: conexão recusada"))
+	if strings.Contains(p.Detail, "10.0.0.5") || strings.Contains(p.Title, "10.0.0.5") {
+		t.Errorf("detalhes internos expostos: %+v", p)
+	}
+}
+
+// TestEveryServiceErrorHasStatus lê os códigos declarados em services/errors.go, para que
+// um erro novo sem status no mapa não vire 500 sem ninguém perceber
+func TestEveryServiceErrorHasStatus(t *testing.T) {
+	file, err := parser.ParseFile(token.NewFileSet(), "../services/errors.go", nil, 0)
+	if err != nil {
+		t.Fatalf("erro ao ler services/errors.go: %v", err)
+	}
+
+	var codes []string
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		if fn, ok := call.Fun.(*ast.Ident); !ok || fn.Name != "newError" {
+			return true
+		}
+		if lit, ok := call.Args[0].(*ast.BasicLit); ok {
+			code, _ := strconv.Unquote(lit.Value)
+			codes = append(codes, code)
+		}
+		return true
+	})
+
+	if len(codes) == 0 {
+		t.Fatal("nenhum código encontrado em services/errors.go")
+	}
+	for _, code := range codes {
+		if _, ok := statusByCode[code]; !ok {
+			t.Errorf("código %s sem status HTTP em statusByCode", code)
+		}
+	}
+	for code := range oauthErrorByCode {
+		if statusByCode[code] != http.StatusBadRequest {
+			t.Errorf("erro OAuth %s com status %d, esperado 400", code, statusByCode[code])
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	router := gin.New()
+	router.GET("/usuarios/:id", func(c *gin.Context) {
+		c.Request = c.Request.WithContext(logging.WithRequestID(c.Request.Context(), "req-1"))
+		Write(c, services.ErrUserNotFound)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/usuarios/42", nil))
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("status %d, esperado 404", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, ContentType) {
+		t.Errorf("Content-Type = %s, esperado %s", ct, ContentType)
+	}
+
+	var p Problem
+	if err := json.Unmarshal(w.Body.Bytes(), &p); err != nil {
+		t.Fatalf("corpo inválido: %v", err)
+	}
+	if p.Instance != "/usuarios/42" || p.RequestID != "req-1" || p.Code != "USER_NOT_FOUND" || p.Status != http.StatusNotFound {
+		t.Errorf("problem = %+v", p)
+	}
+}
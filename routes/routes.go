@@ -2,31 +2,58 @@ package routes
 
 import (
 	"auth-service/handlers"
+	"auth-service/health"
+	"auth-service/metrics"
 	"auth-service/middleware"
+	"auth-service/models"
 	"net/http"
 	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
-func SetupRoutes(authHandler *handlers.AuthHandler, authMiddleware *middleware.AuthMiddleware) *gin.Engine {
-	router := gin.Default()
+func SetupRoutes(authHandler *handlers.AuthHandler, federationHandler *handlers.FederationHandler, magicLinkHandler *handlers.MagicLinkHandler, auditHandler *handlers.AuditHandler, webhookHandler *handlers.WebhookHandler, passwordResetHandler *handlers.PasswordResetHandler, userImportHandler *handlers.UserImportHandler, loginAlertHandler *handlers.LoginAlertHandler, authMiddleware *middleware.AuthMiddleware, rateLimiter *middleware.RateLimiter, checker *health.Checker) *gin.Engine {
+	router := gin.New()
+	router.Use(gin.Recovery())
+
+	// Nenhum proxy é confiável por padrão: X-Forwarded-For é ignorado e o IP do cliente é o
+	// da conexão. O main aplica server.trusted_proxies.
+	router.SetTrustedProxies(nil)
+
+	// Métricas Prometheus e sondas de liveness/readiness, fora do CORS e da API versionada
+	router.GET("/metrics", metrics.Handler())
+	router.GET("/livez", checker.LiveHandler())
+	router.GET("/readyz", checker.ReadyHandler())
+
+	// Tracing: continua o trace recebido via traceparent ou inicia um novo
+	router.Use(otelgin.Middleware("auth-service"))
+
+	// ID da requisição e log de acesso estruturado
+	router.Use(middleware.RequestID())
+	router.Use(middleware.AccessLog())
 
 	// Configuração do CORS
 	corsConfig := cors.Config{
 		AllowOrigins:     []string{"http://localhost:4000", "http://127.0.0.1:4000", "http://localhost:4200", "http://127.0.0.1:4200"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Authorization", "X-Requested-With", "X-Client-ID"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Authorization", "X-Requested-With", "X-Client-ID", "X-Request-ID", "X-Session-Mode", "X-XSRF-TOKEN"},
+		ExposeHeaders:    []string{"Content-Length", "X-Request-ID", "RateLimit-Policy", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "Retry-After"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}
 	router.Use(cors.New(corsConfig))
 
 	// Middleware global
-	router.Use(gin.Logger())
-	router.Use(gin.Recovery())
+	router.Use(middleware.RequestInfo())
+
+	// Descoberta OpenID Connect
+	wellKnown := router.Group("/.well-known")
+	{
+		wellKnown.GET("/openid-configuration", authHandler.Discovery)
+		wellKnown.GET("/jwks.json", authHandler.JWKS)
+	}
 
 	// Rotas públicas
 	public := router.Group("/api/v1")
@@ -41,10 +68,35 @@ func SetupRoutes(authHandler *handlers.AuthHandler, authMiddleware *middleware.A
 
 		// Rotas de autenticação
 
-		public.POST("/register", authHandler.Register)
-		public.POST("/login", authHandler.Login)
-		public.POST("/refresh", authHandler.RefreshToken)
-		public.POST("/validate", authHandler.ValidateToken)
+		public.POST("/register", rateLimiter.Limit("register"), authHandler.Register)
+		// O limite por IP impede que um IP tente senhas em muitas contas; o por email, que
+		// muitos IPs tentem a mesma conta
+		public.POST("/login", rateLimiter.Limit("login_ip"), rateLimiter.Limit("login"), authHandler.Login)
+		public.POST("/login/magic-link", rateLimiter.Limit("magic_link"), magicLinkHandler.Request)
+		public.POST("/login/magic-link/consume", rateLimiter.Limit("link_token"), magicLinkHandler.Consume)
+		public.POST("/login/report", rateLimiter.Limit("link_token"), loginAlertHandler.Report)
+		public.POST("/password/forgot", rateLimiter.Limit("password_reset"), passwordResetHandler.Forgot)
+		public.POST("/password/reset", rateLimiter.Limit("link_token"), passwordResetHandler.Reset)
+		public.POST("/refresh", rateLimiter.Limit("refresh"), authHandler.RefreshToken)
+		public.DELETE("/refresh", authHandler.EndSession)
+		public.POST("/validate", rateLimiter.Limit("validate"), authHandler.ValidateToken)
+		public.POST("/introspect", rateLimiter.Limit("validate"), authHandler.Introspect)
+
+		// Fluxo authorization code com PKCE e autorização de dispositivos (RFC 8628)
+		public.GET("/oauth/authorize", authHandler.StartAuthorization)
+		public.POST("/oauth/device_authorization", rateLimiter.Limit("device_auth"), authHandler.DeviceAuthorization)
+		public.POST("/oauth/token", rateLimiter.Limit("device_token"), authHandler.Token)
+
+		// OpenID Connect
+		public.GET("/userinfo", authHandler.UserInfo)
+		public.POST("/userinfo", authHandler.UserInfo)
+
+		// Login federado via provedores OIDC externos
+		federation := public.Group("/federation/:provider")
+		{
+			federation.GET("/authorize", federationHandler.Authorize)
+			federation.GET("/callback", federationHandler.Callback)
+		}
 
 		// Rotas de clientes
 		clients := public.Group("/clients")
@@ -61,6 +113,59 @@ func SetupRoutes(authHandler *handlers.AuthHandler, authMiddleware *middleware.A
 		auth := protected.Group("/auth")
 		{
 			auth.GET("/profile", authHandler.GetProfile)
+			auth.GET("/activity", auditHandler.RecentActivity)
+
+			// Gerenciamento da conta: tokens de acesso pessoal não criam outros tokens nem trocam a senha
+			auth.PUT("/password", authMiddleware.RequireSession(), rateLimiter.Limit("password_change"), authHandler.ChangePassword)
+			auth.GET("/tokens", authMiddleware.RequireSession(), authHandler.ListPersonalTokens)
+			auth.POST("/tokens", authMiddleware.RequireSession(), authHandler.CreatePersonalToken)
+			auth.DELETE("/tokens/:id", authMiddleware.RequireSession(), authHandler.RevokePersonalToken)
+		}
+
+		// Autorização de clients e aprovação de dispositivos pelo usuário logado
+		oauth := protected.Group("/oauth")
+		oauth.Use(authMiddleware.RequireSession())
+		{
+			oauth.POST("/authorize", authHandler.Authorize)
+			oauth.GET("/device", authHandler.GetDeviceVerification)
+			oauth.POST("/device", authHandler.VerifyDevice)
+		}
+
+		// Rotas administrativas
+		admin := protected.Group("/admin")
+		admin.Use(authMiddleware.RequireSession(), authMiddleware.RequireRole(models.RoleAdmin))
+		{
+			admin.GET("/auth-events", auditHandler.ListEvents)
+			admin.POST("/clients", authHandler.CreateClient)
+			admin.PATCH("/clients/:id", authHandler.UpdateClient)
+
+			// Usuários da organização
+			admin.POST("/users/import", userImportHandler.Import)
+			admin.GET("/users/export", userImportHandler.Export)
+			admin.GET("/users/:id", authHandler.GetUser)
+			admin.PATCH("/users/:id", authHandler.UpdateUser)
+			admin.DELETE("/users/:id", authHandler.DeleteUser)
+
+			// Webhooks e eventos publicados no outbox
+			admin.GET("/webhooks", webhookHandler.ListWebhooks)
+			admin.POST("/webhooks", webhookHandler.CreateWebhook)
+			admin.DELETE("/webhooks/:id", webhookHandler.DeleteWebhook)
+			admin.GET("/outbox", webhookHandler.ListOutboxEvents)
+			admin.GET("/outbox/:id", webhookHandler.GetOutboxEvent)
+			admin.POST("/outbox/:id/replay", webhookHandler.ReplayOutboxEvent)
+
+			// Convites de cadastro
+			admin.GET("/invitations", authHandler.ListInvitations)
+			admin.POST("/invitations", authHandler.CreateInvitation)
+			admin.DELETE("/invitations/:id", authHandler.RevokeInvitation)
+
+			// Organizações só são gerenciadas pelos administradores da organização padrão
+			organizations := admin.Group("/organizations")
+			organizations.Use(authMiddleware.RequireTenant(models.DefaultTenantID))
+			{
+				organizations.GET("", authHandler.ListOrganizations)
+				organizations.POST("", authHandler.CreateOrganization)
+			}
 		}
 	}
 
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"auth-service/logging"
+	"auth-service/models"
+	"auth-service/store"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultEventPageSize = 20
+	maxUserAgentLength   = 512
+)
+
+// AuditService consulta a trilha de auditoria de autenticação
+type AuditService struct {
+	events store.EventStore
+}
+
+func NewAuditService(events store.EventStore) *AuditService {
+	return &AuditService{events: events}
+}
+
+// ListEvents retorna uma página de eventos da organização de acordo com os filtros administrativos
+func (s *AuditService) ListEvents(ctx context.Context, tenantID string, query *models.AuthEventQuery) (*models.AuthEventPage, error) {
+	page := query.Page
+	if page < 1 {
+		page = 1
+	}
+	pageSize := query.PageSize
+	if pageSize < 1 {
+		pageSize = defaultEventPageSize
+	}
+
+	events, total, err := s.events.ListEvents(ctx, store.EventFilter{
+		TenantID:  tenantID,
+		UserID:    query.UserID,
+		ClientID:  query.ClientID,
+		EventType: query.EventType,
+		Outcome:   query.Outcome,
+		IP:        query.IP,
+		RequestID: query.RequestID,
+		From:      query.From,
+		To:        query.To,
+		Limit:     pageSize,
+		Offset:    (page - 1) * pageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.AuthEventPage{
+		Events:   events,
+		Page:     page,
+		PageSize: pageSize,
+		Total:    total,
+	}, nil
+}
+
+// RecentActivity retorna os eventos mais recentes do próprio usuário
+func (s *AuditService) RecentActivity(ctx context.Context, userID string) ([]models.AuthEvent, error) {
+	events, _, err := s.events.ListEvents(ctx, store.EventFilter{
+		UserID: userID,
+		Limit:  defaultEventPageSize,
+	})
+	return events, err
+}
+
+// outcomeOf classifica o resultado de uma operação; em falhas, o motivo é o código do erro
+func outcomeOf(err error) (outcome, reason string) {
+	if err == nil {
+		return models.OutcomeSuccess, ""
+	}
+
+	var serviceErr *Error
+	if errors.As(err, &serviceErr) {
+		return models.OutcomeFailure, serviceErr.Code
+	}
+	return models.OutcomeFailure, "INTERNAL_ERROR"
+}
+
+// recordEvent grava um evento na trilha de auditoria, completando origem e resultado.
+// Falhas ao gravar são apenas registradas em log para não afetar a autenticação.
+func (s *AuthService) recordEvent(ctx context.Context, event *models.AuthEvent, err error) {
+	event.ID = uuid.New()
+	event.CreatedAt = time.Now()
+
+	// client_id vem da requisição; só é gravado se for um UUID válido
+	if _, parseErr := uuid.Parse(event.ClientID); parseErr != nil {
+		event.ClientID = ""
+	}
+
+	info := requestInfoFrom(ctx)
+	event.IP = info.IP
+	event.RequestID = logging.RequestID(ctx)
+	event.UserAgent = info.UserAgent
+	if len(event.UserAgent) > maxUserAgentLength {
+		event.UserAgent = event.UserAgent[:maxUserAgentLength]
+	}
+
+	event.Outcome = models.OutcomeSuccess
+	if err != nil {
+		event.Outcome, event.Reason = outcomeOf(err)
+	}
+
+	// O evento deve ser gravado mesmo que o cliente tenha abandonado a requisição
+	if err := s.events.CreateEvent(context.WithoutCancel(ctx), event); err != nil {
+		slog.ErrorContext(ctx, "Erro ao registrar evento de auditoria", "event_type", event.Type, "error", err)
+	}
+}
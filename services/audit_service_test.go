@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"auth-service/logging"
+	"auth-service/models"
+	"auth-service/store"
+)
+
+func TestLoginRecordsAuditEvent(t *testing.T) {
+	s, stores := newTestAuthService(t)
+	client := newTestClient(t, s)
+	user := register(t, s, "ana@example.com")
+
+	ctx := WithRequestInfo(context.Background(), RequestInfo{IP: "203.0.113.7", UserAgent: strings.Repeat("a", maxUserAgentLength+10)})
+	ctx = logging.WithRequestID(ctx, "req-1")
+
+	tests := []struct {
+		name     string
+		password string
+		outcome  string
+		reason   string
+	}{
+		{"senha correta", testPassword, models.OutcomeSuccess, ""},
+		{"senha errada", "senha-errada-1", models.OutcomeFailure, ErrInvalidCredentials.Code},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s.Login(ctx, &models.LoginRequest{Email: "ana@example.com", Password: tt.password, ClientID: client.ID.String()})
+
+			events, _, err := stores.Events.ListEvents(ctx, store.EventFilter{EventType: models.EventLogin, Limit: 1})
+			if err != nil || len(events) != 1 {
+				t.Fatalf("eventos = %v, %v", events, err)
+			}
+			event := events[0]
+			if event.Outcome != tt.outcome || event.Reason != tt.reason {
+				t.Errorf("resultado %s/%s, esperado %s/%s", event.Outcome, event.Reason, tt.outcome, tt.reason)
+			}
+			if event.UserID != user.ID.String() || event.ClientID != client.ID.String() {
+				t.Errorf("usuário %s client %s", event.UserID, event.ClientID)
+			}
+			if event.IP != "203.0.113.7" || event.RequestID != "req-1" || len(event.UserAgent) != maxUserAgentLength {
+				t.Errorf("origem: ip %s request_id %s user agent com %d bytes", event.IP, event.RequestID, len(event.UserAgent))
+			}
+		})
+	}
+}
+
+func TestRecordEventDropsInvalidClientID(t *testing.T) {
+	s, stores := newTestAuthService(t)
+	ctx := context.Background()
+
+	// O client_id vem da requisição e pode ser qualquer texto
+	s.Login(ctx, &models.LoginRequest{Email: "ninguem@example.com", Password: testPassword, ClientID: "não-é-uuid"})
+
+	events, _, _ := stores.Events.ListEvents(ctx, store.EventFilter{EventType: models.EventLogin, Limit: 1})
+	if len(events) != 1 || events[0].ClientID != "" {
+		t.Fatalf("eventos = %+v", events)
+	}
+}
+
+func TestAuditListEvents(t *testing.T) {
+	s, stores := newTestAuthService(t)
+	audit := NewAuditService(stores.Events)
+	ctx := context.Background()
+
+	org, err := s.CreateOrganization(ctx, "", &models.CreateOrganizationRequest{Slug: "escola", Name: "Escola"})
+	if err != nil {
+		t.Fatalf("organização: %v", err)
+	}
+	orgClient, err := s.CreateClient(ctx, org.ID.String(), "app", "")
+	if err != nil {
+		t.Fatalf("client: %v", err)
+	}
+
+	register(t, s, "ana@example.com")
+	register(t, s, "bia@example.com")
+	register(t, s, "caio@example.com")
+	if _, err := s.Register(ctx, &models.RegisterRequest{Email: "ana@example.com", Password: testPassword, Name: "Ana", ClientID: orgClient.ID.String()}); err != nil {
+		t.Fatalf("registro na organização: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		tenant string
+		query  models.AuthEventQuery
+		total  int64
+		events int
+	}{
+		{"primeira página", models.DefaultTenantID, models.AuthEventQuery{EventType: models.EventRegister, PageSize: 2}, 3, 2},
+		{"última página", models.DefaultTenantID, models.AuthEventQuery{EventType: models.EventRegister, Page: 2, PageSize: 2}, 3, 1},
+		{"outra organização", org.ID.String(), models.AuthEventQuery{EventType: models.EventRegister}, 1, 1},
+		{"filtro sem resultados", models.DefaultTenantID, models.AuthEventQuery{EventType: models.EventRegister, Outcome: models.OutcomeFailure}, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := audit.ListEvents(ctx, tt.tenant, &tt.query)
+			if err != nil {
+				t.Fatalf("list: %v", err)
+			}
+			if page.Total != tt.total || len(page.Events) != tt.events {
+				t.Errorf("total %d com %d eventos, esperado %d com %d", page.Total, len(page.Events), tt.total, tt.events)
+			}
+		})
+	}
+}
@@ -1,280 +1,546 @@
 package services
 
 import (
+	"context"
 	"crypto/rand"
-	"database/sql"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"time"
 
 	"auth-service/config"
-	"auth-service/database"
+	"auth-service/metrics"
 	"auth-service/models"
+	"auth-service/store"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
-	"golang.org/x/crypto/bcrypt"
 )
 
+// defaultClientID identifica logins feitos sem um client explícito
+const defaultClientID = "00000000-0000-0000-0000-000000000000"
+
 type AuthService struct {
-	db  *database.Database
-	cfg *config.Config
+	users          store.UserStore
+	clients        store.ClientStore
+	tokens         store.TokenStore
+	events         store.EventStore
+	personalTokens store.PersonalTokenStore
+	invitations    store.InvitationStore
+	organizations  store.OrganizationStore
+	deviceCodes    store.DeviceCodeStore
+	knownDevices   store.KnownDeviceStore
+	passwords      store.PasswordHistoryStore
+	codes          store.AuthorizationCodeStore
+	deviceNotifier NewDeviceNotifier
+	cfg            *config.Config
+	signingKey     *SigningKey
 }
 
-func NewAuthService(db *database.Database, cfg *config.Config) *AuthService {
+func NewAuthService(stores *store.Stores, cfg *config.Config, signingKey *SigningKey) *AuthService {
 	return &AuthService{
-		db:  db,
-		cfg: cfg,
+		users:          stores.Users,
+		clients:        stores.Clients,
+		tokens:         stores.Tokens,
+		events:         stores.Events,
+		personalTokens: stores.PersonalTokens,
+		invitations:    stores.Invitations,
+		organizations:  stores.Organizations,
+		deviceCodes:    stores.DeviceCodes,
+		knownDevices:   stores.KnownDevices,
+		passwords:      stores.Passwords,
+		codes:          stores.Codes,
+		cfg:            cfg,
+		signingKey:     signingKey,
 	}
 }
 
-func (s *AuthService) Register(req *models.RegisterRequest) (*models.UserResponse, error) {
-	// Verificar se o email já existe
-	var existingUser models.User
-	err := s.db.DB.QueryRow("SELECT id FROM users WHERE email = ?", req.Email).Scan(&existingUser.ID)
-	if err != sql.ErrNoRows {
+func (s *AuthService) Register(ctx context.Context, req *models.RegisterRequest) (_ *models.UserResponse, err error) {
+	event := &models.AuthEvent{Type: models.EventRegister, ClientID: req.ClientID}
+	defer func() { s.recordEvent(ctx, event, err) }()
+
+	// A conta é criada na organização do client
+	tenantID, err := s.tenantForClient(ctx, req.ClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Um convite válido dispensa as restrições do modo de cadastro
+	var invitation *models.Invitation
+	if req.InviteCode != "" {
+		if invitation, err = s.findInvitation(ctx, req.InviteCode); err != nil {
+			return nil, err
+		}
+		event.Reason = invitation.Prefix
+
+		// Sem client, a conta vai para a organização do convite; com client, as duas devem coincidir
+		if req.ClientID == "" {
+			tenantID = invitation.TenantID.String()
+		} else if invitation.TenantID.String() != tenantID {
+			return nil, ErrInvalidInvite
+		}
+	} else if err := s.checkSignupAllowed(req.Email); err != nil {
+		return nil, err
+	}
+
+	// Verificar se o email já existe na organização
+	_, err = s.users.GetUserByEmail(ctx, tenantID, req.Email)
+	if !errors.Is(err, store.ErrNotFound) {
 		if err == nil {
-			return nil, fmt.Errorf("email já está em uso")
+			return nil, ErrEmailInUse
 		}
 		return nil, fmt.Errorf("erro ao verificar email: %w", err)
 	}
 
 	// Hash da senha com bcrypt
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	hashedPassword, err := hashPassword(ctx, req.Password)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao criar hash da senha: %w", err)
 	}
 
 	// Criar usuário
-	userID := uuid.New()
 	now := time.Now()
+	user := &models.User{
+		ID:        uuid.New(),
+		TenantID:  uuid.MustParse(tenantID),
+		Email:     req.Email,
+		Password:  hashedPassword,
+		Name:      req.Name,
+		Active:    true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
 
-	_, err = s.db.DB.Exec(`
-		INSERT INTO users (id, email, password, name, active, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?)
-	`, userID, req.Email, hashedPassword, req.Name, true, now, now)
+	created, err := newUserEvent(models.OutboxUserCreated, user)
+	if err != nil {
+		return nil, err
+	}
 
+	// Com convite, o uso dele, a conta e os papéis pré-definidos são gravados juntos: o
+	// limite de usos vale para cadastros concorrentes e uma falha não consome o convite
+	var roles []string
+	if invitation != nil {
+		roles = invitation.Roles
+		err = s.users.CreateInvitedUser(ctx, user, invitation.ID, roles, now, created)
+	} else {
+		err = s.users.CreateUser(ctx, user, nil, created)
+	}
 	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, ErrInvalidInvite
+		}
+		if errors.Is(err, store.ErrDuplicate) {
+			return nil, ErrEmailInUse
+		}
 		return nil, fmt.Errorf("erro ao criar usuário: %w", err)
 	}
+	event.UserID = user.ID.String()
 
 	return &models.UserResponse{
-		ID:        userID,
-		Email:     req.Email,
-		Name:      req.Name,
+		ID:        user.ID,
+		TenantID:  user.TenantID,
+		Email:     user.Email,
+		Name:      user.Name,
 		Active:    true,
+		Roles:     roles,
 		CreatedAt: now,
 	}, nil
 }
 
-func (s *AuthService) Login(req *models.LoginRequest) (*models.TokenResponse, error) {
+func (s *AuthService) Login(ctx context.Context, req *models.LoginRequest) (_ *models.TokenResponse, err error) {
+	event := &models.AuthEvent{Type: models.EventLogin, ClientID: req.ClientID}
+	defer func() {
+		s.recordEvent(ctx, event, err)
+		metrics.Logins.WithLabelValues(outcomeOf(err)).Inc()
+	}()
+
+	// O usuário é procurado na organização do client
+	tenantID, err := s.tenantForClient(ctx, req.ClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	// O ID token tem o client como audiência; sem client_id, não há para quem emiti-lo
+	scope := normalizeScope(req.Scope)
+	if hasScope(scope, "openid") && req.ClientID == "" {
+		return nil, ErrClientRequired
+	}
+
 	// Buscar usuário
-	var user models.User
-	err := s.db.DB.QueryRow("SELECT id, email, password, name, active FROM users WHERE email = ?", req.Email).Scan(
-		&user.ID, &user.Email, &user.Password, &user.Name, &user.Active)
+	user, err := s.users.GetUserByEmail(ctx, tenantID, req.Email)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("credenciais inválidas")
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, ErrInvalidCredentials
 		}
 		return nil, fmt.Errorf("erro ao buscar usuário: %w", err)
 	}
+	event.UserID = user.ID.String()
 
 	if !user.Active {
-		return nil, fmt.Errorf("usuário inativo")
+		return nil, ErrUserInactive
+	}
+
+	// Usuários criados via provedor externo não possuem senha local
+	if user.Password == "" {
+		return nil, ErrInvalidCredentials
 	}
 
 	// Verificar senha com bcrypt
-	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password))
-	if err != nil {
-		return nil, fmt.Errorf("credenciais inválidas")
+	if err := checkPassword(ctx, user.Password, req.Password); err != nil {
+		return nil, ErrInvalidCredentials
 	}
 
-	// Usar um client ID padrão ou criar um cliente temporário
-	defaultClientID := "00000000-0000-0000-0000-000000000000"
+	authTime := time.Now()
+
+	// Usar o client informado (já verificado acima) ou um client ID padrão
+	clientID := defaultClientID
+	if req.ClientID != "" {
+		clientID = req.ClientID
+	}
+	event.ClientID = clientID
 
 	// Gerar tokens
-	accessToken, err := s.generateAccessToken(user, defaultClientID)
+	accessToken, err := s.generateAccessToken(*user, clientID, scope, authTime)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao gerar access token: %w", err)
 	}
 
 	// Para login simples, não geramos refresh token
-	return &models.TokenResponse{
+	response := &models.TokenResponse{
 		AccessToken:  accessToken,
 		RefreshToken: "",
 		TokenType:    "Bearer",
 		ExpiresIn:    int64(s.cfg.JWT.ExpirationHours * 3600), // segundos
-	}, nil
-}
+		Scope:        scope,
+	}
 
-func (s *AuthService) RefreshToken(req *models.RefreshTokenRequest) (*models.TokenResponse, error) {
-	// Verificar se o cliente existe
-	var client models.Client
-	err := s.db.DB.QueryRow("SELECT id, active FROM clients WHERE id = ?", req.ClientID).Scan(&client.ID, &client.Active)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("cliente não encontrado")
+	// Na sessão do navegador, o refresh token é o que mantém o usuário logado
+	if req.WithRefreshToken {
+		client, err := s.getActiveClient(ctx, clientID)
+		if err != nil {
+			return nil, err
+		}
+		response.RefreshToken, err = s.generateRefreshToken(ctx, user.ID, client.ID, scope, authTime)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao gerar refresh token: %w", err)
 		}
-		return nil, fmt.Errorf("erro ao verificar cliente: %w", err)
 	}
 
-	if !client.Active {
-		return nil, fmt.Errorf("cliente inativo")
+	if hasScope(scope, "openid") {
+		idToken, err := s.generateIDToken(*user, clientID, req.Nonce, authTime)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao gerar id token: %w", err)
+		}
+		response.IDToken = idToken
 	}
 
-	// Verificar refresh token
-	var refreshToken models.RefreshToken
-	err = s.db.DB.QueryRow(`
-		SELECT id, user_id, client_id, token, expires_at, revoked 
-		FROM refresh_tokens 
-		WHERE token = ? AND client_id = ?
-	`, req.RefreshToken, client.ID).Scan(
-		&refreshToken.ID, &refreshToken.UserID, &refreshToken.ClientID,
-		&refreshToken.Token, &refreshToken.ExpiresAt, &refreshToken.Revoked)
+	s.trackDevice(ctx, user, clientID)
+
+	return response, nil
+}
+
+func (s *AuthService) RefreshToken(ctx context.Context, req *models.RefreshTokenRequest) (_ *models.TokenResponse, err error) {
+	event := &models.AuthEvent{Type: models.EventTokenRefresh, ClientID: req.ClientID}
+	defer func() { s.recordEvent(ctx, event, err) }()
 
+	// Verificar se o cliente existe
+	client, err := s.getActiveClient(ctx, req.ClientID)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("refresh token inválido")
+		return nil, err
+	}
+
+	// Verificar refresh token
+	refreshToken, err := s.tokens.GetRefreshToken(ctx, req.RefreshToken, client.ID.String())
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, ErrInvalidRefreshToken
 		}
 		return nil, fmt.Errorf("erro ao verificar refresh token: %w", err)
 	}
+	event.UserID = refreshToken.UserID.String()
 
 	if refreshToken.Revoked {
-		return nil, fmt.Errorf("refresh token revogado")
+		return nil, ErrRefreshTokenRevoked
 	}
 
 	if time.Now().After(refreshToken.ExpiresAt) {
-		return nil, fmt.Errorf("refresh token expirado")
+		return nil, ErrRefreshTokenExpired
 	}
 
 	// Buscar usuário
-	var user models.User
-	err = s.db.DB.QueryRow("SELECT id, email, name, active FROM users WHERE id = ?", refreshToken.UserID).Scan(
-		&user.ID, &user.Email, &user.Name, &user.Active)
+	user, err := s.users.GetUserByID(ctx, refreshToken.UserID.String())
 	if err != nil {
 		return nil, fmt.Errorf("erro ao buscar usuário: %w", err)
 	}
 
 	if !user.Active {
-		return nil, fmt.Errorf("usuário inativo")
+		return nil, ErrUserInactive
 	}
 
 	// Revogar refresh token atual
-	_, err = s.db.DB.Exec("UPDATE refresh_tokens SET revoked = true WHERE id = ?", refreshToken.ID)
-	if err != nil {
+	if err := s.tokens.RevokeRefreshToken(ctx, refreshToken.ID); err != nil {
 		return nil, fmt.Errorf("erro ao revogar refresh token: %w", err)
 	}
 
-	// Gerar novos tokens
-	accessToken, err := s.generateAccessToken(user, client.ID.String())
+	// Gerar novos tokens com os escopos e o momento do login original
+	var authTime time.Time
+	if refreshToken.AuthTime != nil {
+		authTime = *refreshToken.AuthTime
+	}
+	accessToken, err := s.generateAccessToken(*user, client.ID.String(), refreshToken.Scope, authTime)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao gerar access token: %w", err)
 	}
 
-	newRefreshToken, err := s.generateRefreshToken(user.ID, client.ID)
+	newRefreshToken, err := s.generateRefreshToken(ctx, user.ID, client.ID, refreshToken.Scope, authTime)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao gerar refresh token: %w", err)
 	}
 
-	return &models.TokenResponse{
+	response := &models.TokenResponse{
 		AccessToken:  accessToken,
 		RefreshToken: newRefreshToken,
 		TokenType:    "Bearer",
 		ExpiresIn:    int64(s.cfg.JWT.ExpirationHours * 3600),
-	}, nil
+		Scope:        refreshToken.Scope,
+	}
+
+	// Na renovação não há nonce; auth_time continua sendo o do login que abriu a sessão
+	if hasScope(refreshToken.Scope, "openid") {
+		idToken, err := s.generateIDToken(*user, client.ID.String(), "", authTime)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao gerar id token: %w", err)
+		}
+		response.IDToken = idToken
+	}
+
+	return response, nil
 }
 
-func (s *AuthService) ValidateToken(tokenString, clientID string) (*models.UserResponse, error) {
-	// Verificar se o cliente existe
-	var client models.Client
-	err := s.db.DB.QueryRow("SELECT id, active FROM clients WHERE id = ?", clientID).Scan(&client.ID, &client.Active)
+// EndSession revoga o refresh token da sessão do navegador. Tokens desconhecidos ou já
+// revogados não são erro, para que o logout sempre consiga limpar o cookie.
+func (s *AuthService) EndSession(ctx context.Context, refreshToken, clientID string) (err error) {
+	event := &models.AuthEvent{Type: models.EventLogout, ClientID: clientID}
+	defer func() { s.recordEvent(ctx, event, err) }()
+
+	token, err := s.tokens.GetRefreshToken(ctx, refreshToken, clientID)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil
+	}
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("cliente não encontrado")
-		}
-		return nil, fmt.Errorf("erro ao verificar cliente: %w", err)
+		return fmt.Errorf("erro ao verificar refresh token: %w", err)
 	}
+	event.UserID = token.UserID.String()
 
-	if !client.Active {
-		return nil, fmt.Errorf("cliente inativo")
+	if token.Revoked {
+		return nil
 	}
 
-	// Validar token JWT
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("método de assinatura inesperado: %v", token.Header["alg"])
+	if err := s.tokens.RevokeRefreshToken(ctx, token.ID); err != nil {
+		return fmt.Errorf("erro ao revogar refresh token: %w", err)
+	}
+	return nil
+}
+
+func (s *AuthService) ValidateToken(ctx context.Context, tokenString, clientID string) (_ *models.UserResponse, err error) {
+	// Validações bem-sucedidas acontecem a cada requisição dos serviços clientes;
+	// apenas as falhas entram na trilha de auditoria
+	event := &models.AuthEvent{Type: models.EventTokenValidation, ClientID: clientID}
+	defer func() {
+		if err != nil {
+			s.recordEvent(ctx, event, err)
 		}
-		return []byte(s.cfg.JWT.Secret), nil
-	})
+		metrics.TokenValidations.WithLabelValues(outcomeOf(err)).Inc()
+	}()
 
+	// Verificar se o cliente existe
+	client, err := s.getActiveClient(ctx, clientID)
 	if err != nil {
-		return nil, fmt.Errorf("token inválido: %w", err)
+		return nil, err
 	}
 
-	if !token.Valid {
-		return nil, fmt.Errorf("token inválido")
+	// Tokens de acesso pessoal não são JWTs e valem para qualquer client ativo da organização do usuário
+	if isPersonalToken(tokenString) {
+		personalToken, user, err := s.authenticatePersonalToken(ctx, tokenString)
+		if err != nil {
+			return nil, err
+		}
+		event.UserID = user.ID.String()
+
+		if user.TenantID != client.TenantID {
+			return nil, ErrTenantMismatch
+		}
+
+		if !user.Active {
+			return nil, ErrUserInactive
+		}
+
+		response, err := s.userResponse(ctx, user)
+		if err != nil {
+			return nil, err
+		}
+		response.Scope = personalToken.Scope
+		response.TokenType = models.TokenTypePersonal
+		return response, nil
 	}
 
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
-		return nil, fmt.Errorf("claims inválidas")
+	// Validar token JWT
+	claims, err := s.parseAccessToken(tokenString)
+	if err != nil {
+		return nil, err
 	}
 
-	if claims["type"] != "access" {
-		return nil, fmt.Errorf("tipo de token inválido")
+	// Tokens de outra organização são recusados antes mesmo da checagem do client
+	if tokenTenant(claims) != client.TenantID.String() {
+		return nil, ErrTenantMismatch
 	}
 
 	if claims["client_id"] != clientID {
-		return nil, fmt.Errorf("cliente não autorizado")
+		return nil, ErrClientUnauthorized
 	}
 
 	userID, ok := claims["user_id"].(string)
 	if !ok {
-		return nil, fmt.Errorf("user_id inválido")
+		return nil, ErrInvalidToken
 	}
+	event.UserID = userID
 
 	// Buscar usuário
-	var user models.User
-	err = s.db.DB.QueryRow("SELECT id, email, name, active, created_at FROM users WHERE id = ?", userID).Scan(
-		&user.ID, &user.Email, &user.Name, &user.Active, &user.CreatedAt)
+	user, err := s.users.GetUserByID(ctx, userID)
 	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, ErrInvalidToken
+		}
 		return nil, fmt.Errorf("erro ao buscar usuário: %w", err)
 	}
 
+	if user.TenantID != client.TenantID {
+		return nil, ErrTenantMismatch
+	}
+
 	if !user.Active {
-		return nil, fmt.Errorf("usuário inativo")
+		return nil, ErrUserInactive
+	}
+
+	response, err := s.userResponse(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	response.TokenType = models.TokenTypeSession
+	if authTime, ok := claims["auth_time"].(float64); ok {
+		t := time.Unix(int64(authTime), 0)
+		response.AuthTime = &t
+	}
+	return response, nil
+}
+
+// userResponse monta a representação pública do usuário, com os papéis
+func (s *AuthService) userResponse(ctx context.Context, user *models.User) (*models.UserResponse, error) {
+	roles, err := s.users.GetUserRoles(ctx, user.ID.String())
+	if err != nil {
+		return nil, err
 	}
 
 	return &models.UserResponse{
 		ID:        user.ID,
+		TenantID:  user.TenantID,
 		Email:     user.Email,
 		Name:      user.Name,
 		Active:    user.Active,
+		Roles:     roles,
 		CreatedAt: user.CreatedAt,
 	}, nil
 }
 
-func (s *AuthService) generateAccessToken(user models.User, clientID string) (string, error) {
+// getActiveClient busca o client e verifica se está ativo
+func (s *AuthService) getActiveClient(ctx context.Context, clientID string) (*models.Client, error) {
+	client, err := s.clients.GetClient(ctx, clientID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, ErrClientNotFound
+		}
+		return nil, fmt.Errorf("erro ao verificar cliente: %w", err)
+	}
+
+	if !client.Active {
+		return nil, ErrClientInactive
+	}
+
+	return client, nil
+}
+
+func (s *AuthService) parseAccessToken(tokenString string) (jwt.MapClaims, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("método de assinatura inesperado: %v", token.Header["alg"])
+		}
+		return []byte(s.cfg.JWT.Secret), nil
+	})
+
+	if err != nil {
+		return nil, ErrInvalidToken.Wrap(err)
+	}
+
+	if !token.Valid {
+		return nil, ErrInvalidToken
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	if claims["type"] != "access" {
+		return nil, ErrInvalidTokenType
+	}
+
+	return claims, nil
+}
+
+// generateAccessToken emite o JWT de acesso. authTime é o momento do login que abriu a
+// sessão; quando conhecido, vai no claim auth_time para que decisões tomadas com o token
+// (como aprovar um dispositivo) saibam quando o usuário se autenticou.
+func (s *AuthService) generateAccessToken(user models.User, clientID, scope string, authTime time.Time) (string, error) {
 	claims := models.JWTCustomClaims{
 		UserID:   user.ID.String(),
 		Email:    user.Email,
 		ClientID: clientID,
+		TenantID: user.TenantID.String(),
 		Type:     "access",
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+	mapClaims := jwt.MapClaims{
+		"iss":       s.cfg.OIDC.Issuer,
+		"sub":       claims.UserID,
 		"user_id":   claims.UserID,
 		"email":     claims.Email,
 		"client_id": claims.ClientID,
+		"tenant_id": claims.TenantID,
 		"type":      claims.Type,
 		"exp":       time.Now().Add(time.Duration(s.cfg.JWT.ExpirationHours) * time.Hour).Unix(),
 		"iat":       time.Now().Unix(),
-	})
+	}
+	if scope != "" {
+		mapClaims["scope"] = scope
+	}
+	if !authTime.IsZero() {
+		mapClaims["auth_time"] = authTime.Unix()
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, mapClaims)
+
+	signed, err := token.SignedString([]byte(s.cfg.JWT.Secret))
+	if err != nil {
+		return "", err
+	}
 
-	return token.SignedString([]byte(s.cfg.JWT.Secret))
+	metrics.TokensIssued.WithLabelValues("access").Inc()
+	return signed, nil
 }
 
-func (s *AuthService) generateRefreshToken(userID, clientID uuid.UUID) (string, error) {
+// generateRefreshToken cria o refresh token da sessão. Os escopos e o momento do login
+// ficam guardados com ele para que cada renovação emita os mesmos tokens do login.
+func (s *AuthService) generateRefreshToken(ctx context.Context, userID, clientID uuid.UUID, scope string, authTime time.Time) (string, error) {
 	// Gerar token aleatório
 	tokenBytes := make([]byte, 32)
 	if _, err := rand.Read(tokenBytes); err != nil {
@@ -283,49 +549,140 @@ func (s *AuthService) generateRefreshToken(userID, clientID uuid.UUID) (string,
 	token := hex.EncodeToString(tokenBytes)
 
 	// Salvar no banco
-	refreshTokenID := uuid.New()
-	expiresAt := time.Now().Add(time.Duration(s.cfg.JWT.RefreshExpirationHours) * time.Hour)
 	now := time.Now()
+	refreshToken := &models.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    userID,
+		ClientID:  clientID,
+		Token:     token,
+		Scope:     scope,
+		ExpiresAt: now.Add(time.Duration(s.cfg.JWT.RefreshExpirationHours) * time.Hour),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if !authTime.IsZero() {
+		refreshToken.AuthTime = &authTime
+	}
 
-	_, err := s.db.DB.Exec(`
-		INSERT INTO refresh_tokens (id, user_id, client_id, token, expires_at, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?)
-	`, refreshTokenID, userID, clientID, token, expiresAt, now, now)
-
-	if err != nil {
+	if err := s.tokens.CreateRefreshToken(ctx, refreshToken); err != nil {
 		return "", fmt.Errorf("erro ao salvar refresh token: %w", err)
 	}
 
+	metrics.TokensIssued.WithLabelValues("refresh").Inc()
 	return token, nil
 }
 
-func (s *AuthService) CreateClient(name, description string) (*models.Client, error) {
-	// Gerar secret aleatório
-	secretBytes := make([]byte, 32)
-	if _, err := rand.Read(secretBytes); err != nil {
-		return nil, fmt.Errorf("erro ao gerar secret: %w", err)
-	}
-	secret := hex.EncodeToString(secretBytes)
-
-	clientID := uuid.New()
-	now := time.Now()
-
-	_, err := s.db.DB.Exec(`
-		INSERT INTO clients (id, name, description, secret, active, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?)
-	`, clientID, name, description, secret, true, now, now)
+// CreateClient cria um client na organização informada
+func (s *AuthService) CreateClient(ctx context.Context, tenantID, name, description string) (_ *models.Client, err error) {
+	event := &models.AuthEvent{Type: models.EventClientCreated}
+	defer func() { s.recordEvent(ctx, event, err) }()
 
+	secret, err := newClientSecret()
 	if err != nil {
-		return nil, fmt.Errorf("erro ao criar cliente: %w", err)
+		return nil, err
 	}
 
-	return &models.Client{
-		ID:          clientID,
+	now := time.Now()
+	client := &models.Client{
+		ID:          uuid.New(),
+		TenantID:    uuid.MustParse(tenantID),
 		Name:        name,
 		Description: description,
 		Secret:      secret,
 		Active:      true,
 		CreatedAt:   now,
 		UpdatedAt:   now,
-	}, nil
+	}
+
+	if err := s.clients.CreateClient(ctx, client); err != nil {
+		return nil, fmt.Errorf("erro ao criar cliente: %w", err)
+	}
+	event.ClientID = client.ID.String()
+
+	return client, nil
+}
+
+// UpdateClient altera as opções do client informado. Clients de outra organização
+// são tratados como inexistentes.
+func (s *AuthService) UpdateClient(ctx context.Context, tenantID, id string, req *models.UpdateClientRequest) (_ *models.Client, err error) {
+	event := &models.AuthEvent{Type: models.EventClientUpdated, ClientID: id}
+	defer func() { s.recordEvent(ctx, event, err) }()
+
+	client, err := s.getTenantClient(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Active != nil {
+		client.Active = *req.Active
+	}
+	if req.MagicLinkEnabled != nil {
+		client.MagicLinkEnabled = *req.MagicLinkEnabled
+	}
+	if req.RedirectURIs != nil {
+		for _, redirectURI := range *req.RedirectURIs {
+			if !validRedirectURI(redirectURI) {
+				return nil, ErrInvalidRedirectURI
+			}
+		}
+		client.RedirectURIs = *req.RedirectURIs
+	}
+	client.UpdatedAt = time.Now()
+
+	if err := s.clients.UpdateClient(ctx, client); err != nil {
+		return nil, fmt.Errorf("erro ao atualizar cliente: %w", err)
+	}
+
+	return client, nil
+}
+
+// ListClients lista os clients da organização
+func (s *AuthService) ListClients(ctx context.Context, tenantID string) ([]models.Client, error) {
+	return s.clients.ListClients(ctx, tenantID)
+}
+
+// RotateClientSecret gera um novo secret para o client. O secret anterior deixa de valer
+// imediatamente; o novo só é retornado aqui.
+func (s *AuthService) RotateClientSecret(ctx context.Context, tenantID, id string) (_ *models.Client, err error) {
+	event := &models.AuthEvent{Type: models.EventClientSecretRotated, ClientID: id}
+	defer func() { s.recordEvent(ctx, event, err) }()
+
+	client, err := s.getTenantClient(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if client.Secret, err = newClientSecret(); err != nil {
+		return nil, err
+	}
+	client.UpdatedAt = time.Now()
+
+	if err := s.clients.UpdateClient(ctx, client); err != nil {
+		return nil, fmt.Errorf("erro ao atualizar cliente: %w", err)
+	}
+
+	return client, nil
+}
+
+// getTenantClient busca o client; clients de outra organização são tratados como inexistentes
+func (s *AuthService) getTenantClient(ctx context.Context, tenantID, id string) (*models.Client, error) {
+	client, err := s.clients.GetClient(ctx, id)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, ErrClientNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar cliente: %w", err)
+	}
+	if client.TenantID.String() != tenantID {
+		return nil, ErrClientNotFound
+	}
+	return client, nil
+}
+
+func newClientSecret() (string, error) {
+	secretBytes := make([]byte, 32)
+	if _, err := rand.Read(secretBytes); err != nil {
+		return "", fmt.Errorf("erro ao gerar secret: %w", err)
+	}
+	return hex.EncodeToString(secretBytes), nil
 }
@@ -0,0 +1,340 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"auth-service/config"
+	"auth-service/models"
+	"auth-service/store"
+
+	"github.com/google/uuid"
+)
+
+const testPassword = "senha-segura-1"
+
+func newTestAuthService(t *testing.T) (*AuthService, *store.Stores) {
+	t.Helper()
+
+	cfg := &config.Config{
+		JWT:          config.JWTConfig{Secret: "abcdefghijklmnopqrstuvwxyz0123456789ABCD", ExpirationHours: 1, RefreshExpirationHours: 24},
+		Registration: config.RegistrationConfig{Mode: config.RegistrationOpen},
+	}
+	stores := store.NewMemoryStores()
+	return NewAuthService(stores, cfg, nil), stores
+}
+
+func newTestClient(t *testing.T, s *AuthService) *models.Client {
+	t.Helper()
+
+	client, err := s.CreateClient(context.Background(), models.DefaultTenantID, "app", "")
+	if err != nil {
+		t.Fatalf("erro ao criar client: %v", err)
+	}
+	return client
+}
+
+func register(t *testing.T, s *AuthService, email string) *models.UserResponse {
+	t.Helper()
+
+	user, err := s.Register(context.Background(), &models.RegisterRequest{Email: email, Password: testPassword, Name: "Ana"})
+	if err != nil {
+		t.Fatalf("erro ao registrar %s: %v", email, err)
+	}
+	return user
+}
+
+func TestRegisterAndLogin(t *testing.T) {
+	s, _ := newTestAuthService(t)
+	client := newTestClient(t, s)
+	ctx := context.Background()
+
+	user := register(t, s, "ana@example.com")
+	if user.TenantID.String() != models.DefaultTenantID || !user.Active {
+		t.Fatalf("usuário registrado com organização %s e ativo=%v", user.TenantID, user.Active)
+	}
+
+	tokens, err := s.Login(ctx, &models.LoginRequest{Email: "ana@example.com", Password: testPassword, ClientID: client.ID.String()})
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	if tokens.AccessToken == "" || tokens.RefreshToken != "" {
+		t.Errorf("login simples deve retornar só o access token: %+v", tokens)
+	}
+
+	validated, err := s.ValidateToken(ctx, tokens.AccessToken, client.ID.String())
+	if err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if validated.ID != user.ID {
+		t.Errorf("token validado para %s, esperado %s", validated.ID, user.ID)
+	}
+}
+
+func TestRegisterRejectsDuplicateEmail(t *testing.T) {
+	s, _ := newTestAuthService(t)
+	register(t, s, "ana@example.com")
+
+	_, err := s.Register(context.Background(), &models.RegisterRequest{Email: "ana@example.com", Password: testPassword, Name: "Outra"})
+	if !errors.Is(err, ErrEmailInUse) {
+		t.Fatalf("erro %v, esperado %v", err, ErrEmailInUse)
+	}
+}
+
+// O MemoryStore compara emails como o SQLStore ("email = ?"), diferenciando maiúsculas
+func TestEmailMatchingIsExact(t *testing.T) {
+	s, stores := newTestAuthService(t)
+	ctx := context.Background()
+	register(t, s, "Ana@example.com")
+
+	if _, err := stores.Users.GetUserByEmail(ctx, models.DefaultTenantID, "ana@example.com"); !errors.Is(err, store.ErrNotFound) {
+		t.Fatalf("busca com outra caixa: erro %v, esperado %v", err, store.ErrNotFound)
+	}
+
+	_, err := s.Login(ctx, &models.LoginRequest{Email: "ana@example.com", Password: testPassword})
+	if !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("login com outra caixa: erro %v, esperado %v", err, ErrInvalidCredentials)
+	}
+
+	if _, err := s.Login(ctx, &models.LoginRequest{Email: "Ana@example.com", Password: testPassword}); err != nil {
+		t.Fatalf("login com o email cadastrado: %v", err)
+	}
+}
+
+func TestLoginRejectsInvalidCredentials(t *testing.T) {
+	s, stores := newTestAuthService(t)
+	ctx := context.Background()
+	user := register(t, s, "ana@example.com")
+
+	tests := []struct {
+		name     string
+		email    string
+		password string
+		want     error
+	}{
+		{"senha errada", "ana@example.com", "senha-errada", ErrInvalidCredentials},
+		{"usuário inexistente", "ninguem@example.com", testPassword, ErrInvalidCredentials},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.Login(ctx, &models.LoginRequest{Email: tt.email, Password: tt.password})
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("erro %v, esperado %v", err, tt.want)
+			}
+		})
+	}
+
+	stored, err := stores.Users.GetUserByID(ctx, user.ID.String())
+	if err != nil {
+		t.Fatalf("erro ao buscar usuário: %v", err)
+	}
+	stored.Active = false
+	if err := stores.Users.UpdateUser(ctx, stored, nil, nil); err != nil {
+		t.Fatalf("erro ao desativar usuário: %v", err)
+	}
+
+	_, err = s.Login(ctx, &models.LoginRequest{Email: "ana@example.com", Password: testPassword})
+	if !errors.Is(err, ErrUserInactive) {
+		t.Fatalf("usuário inativo: erro %v, esperado %v", err, ErrUserInactive)
+	}
+}
+
+func TestRegisterInviteOnly(t *testing.T) {
+	s, _ := newTestAuthService(t)
+	s.cfg.Registration.Mode = config.RegistrationInviteOnly
+
+	_, err := s.Register(context.Background(), &models.RegisterRequest{Email: "ana@example.com", Password: testPassword, Name: "Ana"})
+	if !errors.Is(err, ErrInviteRequired) {
+		t.Fatalf("erro %v, esperado %v", err, ErrInviteRequired)
+	}
+}
+
+func TestRefreshTokenRotation(t *testing.T) {
+	s, _ := newTestAuthService(t)
+	client := newTestClient(t, s)
+	ctx := context.Background()
+	register(t, s, "ana@example.com")
+
+	tokens, err := s.Login(ctx, &models.LoginRequest{Email: "ana@example.com", Password: testPassword, ClientID: client.ID.String(), WithRefreshToken: true})
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	if tokens.RefreshToken == "" {
+		t.Fatal("login da sessão sem refresh token")
+	}
+
+	refreshed, err := s.RefreshToken(ctx, &models.RefreshTokenRequest{RefreshToken: tokens.RefreshToken, ClientID: client.ID.String()})
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if refreshed.RefreshToken == "" || refreshed.RefreshToken == tokens.RefreshToken {
+		t.Fatal("refresh deve emitir um novo refresh token")
+	}
+
+	// O refresh token usado é revogado na rotação
+	_, err = s.RefreshToken(ctx, &models.RefreshTokenRequest{RefreshToken: tokens.RefreshToken, ClientID: client.ID.String()})
+	if !errors.Is(err, ErrRefreshTokenRevoked) {
+		t.Fatalf("reuso: erro %v, esperado %v", err, ErrRefreshTokenRevoked)
+	}
+
+	// E só vale para o client que o recebeu
+	other := newTestClient(t, s)
+	_, err = s.RefreshToken(ctx, &models.RefreshTokenRequest{RefreshToken: refreshed.RefreshToken, ClientID: other.ID.String()})
+	if !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("outro client: erro %v, esperado %v", err, ErrInvalidRefreshToken)
+	}
+
+	if err := s.EndSession(ctx, refreshed.RefreshToken, client.ID.String()); err != nil {
+		t.Fatalf("logout: %v", err)
+	}
+	_, err = s.RefreshToken(ctx, &models.RefreshTokenRequest{RefreshToken: refreshed.RefreshToken, ClientID: client.ID.String()})
+	if !errors.Is(err, ErrRefreshTokenRevoked) {
+		t.Fatalf("após logout: erro %v, esperado %v", err, ErrRefreshTokenRevoked)
+	}
+}
+
+func TestValidateTokenRejectsOtherClient(t *testing.T) {
+	s, _ := newTestAuthService(t)
+	client := newTestClient(t, s)
+	other := newTestClient(t, s)
+	ctx := context.Background()
+	register(t, s, "ana@example.com")
+
+	tokens, err := s.Login(ctx, &models.LoginRequest{Email: "ana@example.com", Password: testPassword, ClientID: client.ID.String()})
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+
+	if _, err := s.ValidateToken(ctx, tokens.AccessToken, other.ID.String()); !errors.Is(err, ErrClientUnauthorized) {
+		t.Fatalf("outro client: erro %v, esperado %v", err, ErrClientUnauthorized)
+	}
+	if _, err := s.ValidateToken(ctx, "não-é-um-jwt", client.ID.String()); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("token inválido: erro %v, esperado %v", err, ErrInvalidToken)
+	}
+}
+
+func TestChangePasswordKeepsHistory(t *testing.T) {
+	s, stores := newTestAuthService(t)
+	s.cfg.Passwords.HistorySize = 2
+	ctx := context.Background()
+	user := register(t, s, "ana@example.com")
+
+	passwords := []string{testPassword, "senha-segura-2", "senha-segura-3", "senha-segura-4"}
+	for i := 1; i < len(passwords); i++ {
+		err := s.ChangePassword(ctx, user.ID.String(), &models.ChangePasswordRequest{CurrentPassword: passwords[i-1], NewPassword: passwords[i]})
+		if err != nil {
+			t.Fatalf("troca para %s: %v", passwords[i], err)
+		}
+	}
+
+	history, err := stores.Passwords.ListPasswordHistory(ctx, user.ID.String(), 10)
+	if err != nil {
+		t.Fatalf("histórico: %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("histórico com %d senhas, esperado 2", len(history))
+	}
+
+	err = s.ChangePassword(ctx, user.ID.String(), &models.ChangePasswordRequest{CurrentPassword: "senha-segura-4", NewPassword: "senha-segura-3"})
+	if !errors.Is(err, ErrPasswordReused) {
+		t.Fatalf("senha recente: erro %v, esperado %v", err, ErrPasswordReused)
+	}
+	if err := s.ChangePassword(ctx, user.ID.String(), &models.ChangePasswordRequest{CurrentPassword: "senha-segura-4", NewPassword: testPassword}); err != nil {
+		t.Fatalf("senha já podada do histórico: %v", err)
+	}
+}
+
+func TestRegisterWithInvitation(t *testing.T) {
+	s, stores := newTestAuthService(t)
+	s.cfg.Registration.Mode = config.RegistrationInviteOnly
+	ctx := context.Background()
+
+	invitation, err := s.CreateInvitation(ctx, models.DefaultTenantID, "", &models.CreateInvitationRequest{Roles: []string{models.RoleAdmin}, ExpiresInHours: 1})
+	if err != nil {
+		t.Fatalf("convite: %v", err)
+	}
+
+	user, err := s.Register(ctx, &models.RegisterRequest{Email: "ana@example.com", Password: testPassword, Name: "Ana", InviteCode: invitation.Code})
+	if err != nil {
+		t.Fatalf("cadastro com convite: %v", err)
+	}
+	roles, err := stores.Users.GetUserRoles(ctx, user.ID.String())
+	if err != nil || len(roles) != 1 || roles[0] != models.RoleAdmin {
+		t.Fatalf("papéis = %v (%v), esperado [%s]", roles, err, models.RoleAdmin)
+	}
+
+	// O convite vale para um único cadastro
+	_, err = s.Register(ctx, &models.RegisterRequest{Email: "bia@example.com", Password: testPassword, Name: "Bia", InviteCode: invitation.Code})
+	if !errors.Is(err, ErrInvalidInvite) {
+		t.Fatalf("segundo uso: erro %v, esperado %v", err, ErrInvalidInvite)
+	}
+}
+
+func TestCreateInvitedUserFailureKeepsInvitation(t *testing.T) {
+	s, stores := newTestAuthService(t)
+	ctx := context.Background()
+	existing := register(t, s, "ana@example.com")
+
+	invitation, err := s.CreateInvitation(ctx, models.DefaultTenantID, "", &models.CreateInvitationRequest{ExpiresInHours: 1})
+	if err != nil {
+		t.Fatalf("convite: %v", err)
+	}
+
+	// Email em uso (como em um cadastro concorrente): nada é gravado e o convite não é consumido
+	now := time.Now()
+	duplicate := &models.User{ID: uuid.New(), TenantID: existing.TenantID, Email: "ana@example.com", Active: true, CreatedAt: now, UpdatedAt: now}
+	err = stores.Users.CreateInvitedUser(ctx, duplicate, invitation.ID, []string{models.RoleAdmin}, now, nil)
+	if !errors.Is(err, store.ErrDuplicate) {
+		t.Fatalf("email em uso: erro %v, esperado %v", err, store.ErrDuplicate)
+	}
+	if roles, _ := stores.Users.GetUserRoles(ctx, duplicate.ID.String()); len(roles) != 0 {
+		t.Errorf("papéis gravados para o cadastro que falhou: %v", roles)
+	}
+
+	if _, err := s.Register(ctx, &models.RegisterRequest{Email: "bia@example.com", Password: testPassword, Name: "Bia", InviteCode: invitation.Code}); err != nil {
+		t.Fatalf("convite consumido pela falha anterior: %v", err)
+	}
+}
+
+func TestCreateAdmin(t *testing.T) {
+	s, stores := newTestAuthService(t)
+	ctx := context.Background()
+	register(t, s, "ana@example.com")
+
+	tests := []struct {
+		name     string
+		email    string
+		password string
+		want     error
+	}{
+		{"senha curta", "admin@example.com", "1234567", ErrInvalidRequest},
+		{"email em uso", "ana@example.com", testPassword, ErrEmailInUse},
+		{"administrador", "admin@example.com", testPassword, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			admin, err := s.CreateAdmin(ctx, models.DefaultTenantID, tt.email, "Admin", tt.password)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("erro %v, esperado %v", err, tt.want)
+			}
+			if tt.want != nil {
+				return
+			}
+			roles, err := stores.Users.GetUserRoles(ctx, admin.ID.String())
+			if err != nil || len(roles) != 1 || roles[0] != models.RoleAdmin {
+				t.Errorf("papéis = %v (%v), esperado [%s]", roles, err, models.RoleAdmin)
+			}
+		})
+	}
+
+	// Só o último caso cria usuário
+	if _, err := stores.Users.GetUserByEmail(ctx, models.DefaultTenantID, "admin@example.com"); err != nil {
+		t.Fatalf("administrador não encontrado: %v", err)
+	}
+	users, _ := stores.Users.ListUsers(ctx, models.DefaultTenantID)
+	if len(users) != 2 {
+		t.Errorf("%d usuários, esperado 2", len(users))
+	}
+}
@@ -0,0 +1,236 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"auth-service/models"
+	"auth-service/store"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// AuthorizationCodeTTL é o tempo que o client tem para trocar o código por tokens
+const AuthorizationCodeTTL = time.Minute
+
+// pkceMethodS256 é o único code_challenge_method aceito; o "plain" da RFC 7636 não protege
+// o código se ele vazar no redirecionamento
+const pkceMethodS256 = "S256"
+
+// CheckAuthorizationRequest valida o pedido do /oauth/authorize antes de o navegador seguir
+// para a página de autorização. Os erros não voltam ao client pelo redirect_uri, que só é
+// confiável depois de conferido com os cadastrados.
+func (s *AuthService) CheckAuthorizationRequest(ctx context.Context, req *models.AuthorizationRequest) (*models.Client, error) {
+	if req.ResponseType != "code" {
+		return nil, ErrUnsupportedResponseType
+	}
+
+	client, err := s.getActiveClient(ctx, req.ClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !slices.Contains(client.RedirectURIs, req.RedirectURI) {
+		return nil, ErrInvalidRedirectURI
+	}
+
+	if req.CodeChallenge == "" || req.CodeChallengeMethod != pkceMethodS256 {
+		return nil, ErrPKCERequired
+	}
+
+	return client, nil
+}
+
+// AuthorizationPageURL monta a URL da página do front-end que recebe o pedido, com os
+// parâmetros originais
+func (s *AuthService) AuthorizationPageURL(query url.Values) string {
+	return appendQuery(s.cfg.OIDC.AuthorizeURL, query)
+}
+
+// Authorize emite o código para o usuário autenticado e devolve o endereço do client para
+// onde o navegador segue. O código é um JWT de curta duração, de uso único, que amarra o
+// usuário, o client, o redirect_uri, os escopos, o nonce e o code_challenge.
+func (s *AuthService) Authorize(ctx context.Context, userID string, req *models.AuthorizationRequest) (_ *models.AuthorizationResponse, err error) {
+	event := &models.AuthEvent{Type: models.EventAuthorize, UserID: userID, ClientID: req.ClientID}
+	defer func() { s.recordEvent(ctx, event, err) }()
+
+	client, err := s.CheckAuthorizationRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.users.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar usuário: %w", err)
+	}
+
+	if user.TenantID != client.TenantID {
+		return nil, ErrTenantMismatch
+	}
+
+	if !user.Active {
+		return nil, ErrUserInactive
+	}
+
+	now := time.Now()
+	code, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"type":           "authorization_code",
+		"jti":            uuid.NewString(),
+		"sub":            user.ID.String(),
+		"client_id":      client.ID.String(),
+		"redirect_uri":   req.RedirectURI,
+		"scope":          normalizeScope(req.Scope),
+		"nonce":          req.Nonce,
+		"code_challenge": req.CodeChallenge,
+		"iat":            now.Unix(),
+		"exp":            now.Add(AuthorizationCodeTTL).Unix(),
+	}).SignedString([]byte(s.cfg.JWT.Secret))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gerar código de autorização: %w", err)
+	}
+
+	query := url.Values{"code": {code}}
+	if req.State != "" {
+		query.Set("state", req.State)
+	}
+
+	return &models.AuthorizationResponse{RedirectTo: appendQuery(req.RedirectURI, query)}, nil
+}
+
+// ExchangeAuthorizationCode troca o código do /oauth/authorize por tokens. O client prova
+// que iniciou o pedido com o code_verifier (PKCE) e o código só é aceito uma vez.
+func (s *AuthService) ExchangeAuthorizationCode(ctx context.Context, req *models.TokenRequest) (_ *models.TokenResponse, err error) {
+	event := &models.AuthEvent{Type: models.EventCodeLogin, ClientID: req.ClientID}
+	defer func() { s.recordEvent(ctx, event, err) }()
+
+	client, err := s.getActiveClient(ctx, req.ClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := s.parseAuthorizationCode(req.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims["client_id"] != client.ID.String() || claims["redirect_uri"] != req.RedirectURI {
+		return nil, ErrInvalidAuthorizationCode
+	}
+
+	challenge, _ := claims["code_challenge"].(string)
+	if !verifyCodeChallenge(challenge, req.CodeVerifier) {
+		return nil, ErrInvalidAuthorizationCode
+	}
+
+	userID, _ := claims["sub"].(string)
+	event.UserID = userID
+
+	jti, _ := claims["jti"].(string)
+	expiresAt, _ := claims.GetExpirationTime()
+	if err := s.codes.UseAuthorizationCode(ctx, jti, expiresAt.Time); err != nil {
+		if errors.Is(err, store.ErrDuplicate) {
+			return nil, ErrInvalidAuthorizationCode
+		}
+		return nil, fmt.Errorf("erro ao registrar uso do código de autorização: %w", err)
+	}
+
+	user, err := s.users.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, ErrInvalidAuthorizationCode
+		}
+		return nil, fmt.Errorf("erro ao buscar usuário: %w", err)
+	}
+
+	if !user.Active {
+		return nil, ErrUserInactive
+	}
+
+	scope, _ := claims["scope"].(string)
+	accessToken, err := s.generateAccessToken(*user, client.ID.String(), scope, time.Time{})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gerar access token: %w", err)
+	}
+
+	refreshToken, err := s.generateRefreshToken(ctx, user.ID, client.ID, scope, time.Time{})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gerar refresh token: %w", err)
+	}
+
+	response := &models.TokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(s.cfg.JWT.ExpirationHours * 3600),
+		Scope:        scope,
+	}
+
+	// O login aconteceu na sessão do front-end, em um momento que o código não conhece;
+	// o ID token sai sem auth_time
+	if hasScope(scope, "openid") {
+		nonce, _ := claims["nonce"].(string)
+		idToken, err := s.generateIDToken(*user, client.ID.String(), nonce, time.Time{})
+		if err != nil {
+			return nil, fmt.Errorf("erro ao gerar id token: %w", err)
+		}
+		response.IDToken = idToken
+	}
+
+	return response, nil
+}
+
+func (s *AuthService) parseAuthorizationCode(code string) (jwt.MapClaims, error) {
+	token, err := jwt.Parse(code, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("método de assinatura inesperado: %v", token.Header["alg"])
+		}
+		return []byte(s.cfg.JWT.Secret), nil
+	}, jwt.WithExpirationRequired(), jwt.WithIssuedAt())
+	if err != nil || !token.Valid {
+		return nil, ErrInvalidAuthorizationCode
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || claims["type"] != "authorization_code" {
+		return nil, ErrInvalidAuthorizationCode
+	}
+
+	return claims, nil
+}
+
+// verifyCodeChallenge confere o code_verifier com o code_challenge S256 (RFC 7636, seção 4.6)
+func verifyCodeChallenge(challenge, verifier string) bool {
+	if challenge == "" || verifier == "" {
+		return false
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	expected := base64.RawURLEncoding.EncodeToString(sum[:])
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(challenge)) == 1
+}
+
+// validRedirectURI aceita URLs absolutas sem fragmento (RFC 6749, seção 3.1.2)
+func validRedirectURI(raw string) bool {
+	parsed, err := url.Parse(raw)
+	return err == nil && parsed.Scheme != "" && parsed.Host != "" && parsed.Fragment == ""
+}
+
+// appendQuery acrescenta os parâmetros à URL, que pode já ter uma query
+func appendQuery(base string, query url.Values) string {
+	separator := "?"
+	if strings.Contains(base, "?") {
+		separator = "&"
+	}
+	return base + separator + query.Encode()
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"testing"
+
+	"auth-service/models"
+)
+
+const (
+	testRedirectURI  = "https://app.example.com/callback"
+	testCodeVerifier = "verificador-de-teste-com-pelo-menos-43-caracteres"
+)
+
+func testCodeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// newCodeFlowTestService prepara o serviço com chave de assinatura e um client com redirect_uri
+func newCodeFlowTestService(t *testing.T) (*AuthService, *models.Client) {
+	t.Helper()
+
+	s, client := newOIDCTestService(t)
+	redirectURIs := []string{testRedirectURI}
+	client, err := s.UpdateClient(context.Background(), models.DefaultTenantID, client.ID.String(), &models.UpdateClientRequest{RedirectURIs: &redirectURIs})
+	if err != nil {
+		t.Fatalf("erro ao cadastrar redirect_uri: %v", err)
+	}
+	return s, client
+}
+
+func authorizationRequest(clientID string) *models.AuthorizationRequest {
+	return &models.AuthorizationRequest{
+		ResponseType:        "code",
+		ClientID:            clientID,
+		RedirectURI:         testRedirectURI,
+		Scope:               "openid email",
+		State:               "estado-do-client",
+		Nonce:               "nonce-do-client",
+		CodeChallenge:       testCodeChallenge(testCodeVerifier),
+		CodeChallengeMethod: "S256",
+	}
+}
+
+// authorize emite o código para o usuário e devolve os parâmetros do redirecionamento
+func authorize(t *testing.T, s *AuthService, userID string, req *models.AuthorizationRequest) url.Values {
+	t.Helper()
+
+	response, err := s.Authorize(context.Background(), userID, req)
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+	redirect, err := url.Parse(response.RedirectTo)
+	if err != nil {
+		t.Fatalf("redirect_to inválido: %v", err)
+	}
+	if got := redirect.Scheme + "://" + redirect.Host + redirect.Path; got != testRedirectURI {
+		t.Fatalf("redirecionou para %s, esperado %s", got, testRedirectURI)
+	}
+	return redirect.Query()
+}
+
+func TestCheckAuthorizationRequest(t *testing.T) {
+	s, client := newCodeFlowTestService(t)
+
+	tests := []struct {
+		name   string
+		change func(req *models.AuthorizationRequest)
+		want   error
+	}{
+		{"response_type token", func(req *models.AuthorizationRequest) { req.ResponseType = "token" }, ErrUnsupportedResponseType},
+		{"redirect_uri não cadastrado", func(req *models.AuthorizationRequest) { req.RedirectURI = "https://evil.example.com/callback" }, ErrInvalidRedirectURI},
+		{"sem code_challenge", func(req *models.AuthorizationRequest) { req.CodeChallenge = "" }, ErrPKCERequired},
+		{"método plain", func(req *models.AuthorizationRequest) { req.CodeChallengeMethod = "plain" }, ErrPKCERequired},
+		{"client inexistente", func(req *models.AuthorizationRequest) { req.ClientID = "00000000-0000-0000-0000-00000000abcd" }, ErrClientNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := authorizationRequest(client.ID.String())
+			tt.change(req)
+			if _, err := s.CheckAuthorizationRequest(context.Background(), req); !errors.Is(err, tt.want) {
+				t.Fatalf("erro %v, esperado %v", err, tt.want)
+			}
+		})
+	}
+
+	if _, err := s.CheckAuthorizationRequest(context.Background(), authorizationRequest(client.ID.String())); err != nil {
+		t.Fatalf("pedido válido: %v", err)
+	}
+}
+
+func TestAuthorizationCodeFlow(t *testing.T) {
+	s, client := newCodeFlowTestService(t)
+	ctx := context.Background()
+	user := register(t, s, "ana@example.com")
+
+	query := authorize(t, s, user.ID.String(), authorizationRequest(client.ID.String()))
+	if query.Get("state") != "estado-do-client" || query.Get("code") == "" {
+		t.Fatalf("redirecionamento sem code ou state: %v", query)
+	}
+
+	exchange := &models.TokenRequest{
+		GrantType:    models.GrantTypeAuthorizationCode,
+		ClientID:     client.ID.String(),
+		Code:         query.Get("code"),
+		RedirectURI:  testRedirectURI,
+		CodeVerifier: "outro-verificador-que-nao-corresponde-ao-challenge",
+	}
+	if _, err := s.ExchangeAuthorizationCode(ctx, exchange); !errors.Is(err, ErrInvalidAuthorizationCode) {
+		t.Fatalf("code_verifier errado: erro %v, esperado %v", err, ErrInvalidAuthorizationCode)
+	}
+
+	exchange.CodeVerifier = testCodeVerifier
+	exchange.RedirectURI = "https://app.example.com/outro"
+	if _, err := s.ExchangeAuthorizationCode(ctx, exchange); !errors.Is(err, ErrInvalidAuthorizationCode) {
+		t.Fatalf("redirect_uri diferente: erro %v, esperado %v", err, ErrInvalidAuthorizationCode)
+	}
+
+	exchange.RedirectURI = testRedirectURI
+	tokens, err := s.ExchangeAuthorizationCode(ctx, exchange)
+	if err != nil {
+		t.Fatalf("troca do código: %v", err)
+	}
+	if tokens.AccessToken == "" || tokens.RefreshToken == "" || tokens.Scope != "openid email" {
+		t.Fatalf("resposta incompleta: %+v", tokens)
+	}
+
+	claims := parseIDToken(t, s, tokens.IDToken)
+	if claims["aud"] != client.ID.String() || claims["sub"] != user.ID.String() || claims["nonce"] != "nonce-do-client" {
+		t.Errorf("claims do ID token: %v", claims)
+	}
+
+	// O código vale uma única vez
+	if _, err := s.ExchangeAuthorizationCode(ctx, exchange); !errors.Is(err, ErrInvalidAuthorizationCode) {
+		t.Fatalf("reuso do código: erro %v, esperado %v", err, ErrInvalidAuthorizationCode)
+	}
+}
+
+func TestAuthorizationCodeIsBoundToClient(t *testing.T) {
+	s, client := newCodeFlowTestService(t)
+	other := newTestClient(t, s)
+	user := register(t, s, "ana@example.com")
+
+	query := authorize(t, s, user.ID.String(), authorizationRequest(client.ID.String()))
+
+	_, err := s.ExchangeAuthorizationCode(context.Background(), &models.TokenRequest{
+		GrantType:    models.GrantTypeAuthorizationCode,
+		ClientID:     other.ID.String(),
+		Code:         query.Get("code"),
+		RedirectURI:  testRedirectURI,
+		CodeVerifier: testCodeVerifier,
+	})
+	if !errors.Is(err, ErrInvalidAuthorizationCode) {
+		t.Fatalf("erro %v, esperado %v", err, ErrInvalidAuthorizationCode)
+	}
+}
+
+func TestUpdateClientRejectsInvalidRedirectURI(t *testing.T) {
+	s, client := newCodeFlowTestService(t)
+
+	for _, redirectURI := range []string{"/callback", "https://app.example.com/callback#frag", "nao é url"} {
+		redirectURIs := []string{redirectURI}
+		_, err := s.UpdateClient(context.Background(), models.DefaultTenantID, client.ID.String(), &models.UpdateClientRequest{RedirectURIs: &redirectURIs})
+		if !errors.Is(err, ErrInvalidRedirectURI) {
+			t.Errorf("%q: erro %v, esperado %v", redirectURI, err, ErrInvalidRedirectURI)
+		}
+	}
+}
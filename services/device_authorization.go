@@ -0,0 +1,322 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+
+	"auth-service/models"
+	"auth-service/store"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// userCodeAlphabet evita vogais e caracteres parecidos, como recomenda a RFC 8628 (seção 6.1)
+	userCodeAlphabet = "BCDFGHJKLMNPQRSTVWXZ"
+	userCodeLength   = 8
+	// slowDownIncrement é quanto o intervalo cresce a cada slow_down (RFC 8628, seção 3.5)
+	slowDownIncrement = 5
+	// userCodeAttempts limita as tentativas de gerar um user_code que ainda não esteja em uso
+	userCodeAttempts = 3
+)
+
+// StartDeviceAuthorization inicia a autorização de um dispositivo que não recebe a senha
+// do usuário. O dispositivo exibe o user_code e consulta /oauth/token com o device_code.
+func (s *AuthService) StartDeviceAuthorization(ctx context.Context, req *models.DeviceAuthorizationRequest) (_ *models.DeviceAuthorizationResponse, err error) {
+	event := &models.AuthEvent{Type: models.EventDeviceAuthorization, ClientID: req.ClientID}
+	defer func() { s.recordEvent(ctx, event, err) }()
+
+	client, err := s.getActiveClient(ctx, req.ClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	deviceBytes := make([]byte, 32)
+	if _, err := rand.Read(deviceBytes); err != nil {
+		return nil, fmt.Errorf("erro ao gerar código do dispositivo: %w", err)
+	}
+	deviceCode := hex.EncodeToString(deviceBytes)
+
+	now := time.Now()
+	code := &models.DeviceCode{
+		ID:             uuid.New(),
+		DeviceCodeHash: hashDeviceCode(deviceCode),
+		ClientID:       client.ID,
+		Scope:          normalizeScope(req.Scope),
+		Status:         models.DeviceCodePending,
+		Interval:       s.cfg.DeviceAuth.IntervalSeconds,
+		ExpiresAt:      now.Add(time.Duration(s.cfg.DeviceAuth.TTLMinutes) * time.Minute),
+		CreatedAt:      now,
+	}
+
+	// Um user_code repetido é improvável, mas possível enquanto o anterior não expira
+	for attempt := 1; ; attempt++ {
+		if code.UserCode, err = generateUserCode(); err != nil {
+			return nil, err
+		}
+		err = s.deviceCodes.CreateDeviceCode(ctx, code)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, store.ErrDuplicate) || attempt == userCodeAttempts {
+			return nil, fmt.Errorf("erro ao criar autorização de dispositivo: %w", err)
+		}
+	}
+
+	userCode := formatUserCode(code.UserCode)
+	return &models.DeviceAuthorizationResponse{
+		DeviceCode:              deviceCode,
+		UserCode:                userCode,
+		VerificationURI:         s.cfg.DeviceAuth.VerificationURL,
+		VerificationURIComplete: s.verificationURIComplete(userCode),
+		ExpiresIn:               int64(time.Until(code.ExpiresAt).Seconds()),
+		Interval:                code.Interval,
+	}, nil
+}
+
+// GetDeviceVerification descreve o pedido pendente para a página em que o usuário decide
+func (s *AuthService) GetDeviceVerification(ctx context.Context, userCode string) (*models.DeviceVerificationInfo, error) {
+	code, client, err := s.findPendingDeviceCode(ctx, userCode)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.DeviceVerificationInfo{
+		UserCode:   formatUserCode(code.UserCode),
+		ClientID:   client.ID,
+		ClientName: client.Name,
+		Scope:      code.Scope,
+		ExpiresAt:  code.ExpiresAt,
+	}, nil
+}
+
+// VerifyDevice registra a decisão do usuário autenticado sobre o pedido. Só usuários da
+// organização do client podem aprová-lo. authTime é o momento do login da sessão que
+// decide, usado como auth_time do ID token entregue ao dispositivo.
+func (s *AuthService) VerifyDevice(ctx context.Context, userID string, authTime *time.Time, req *models.DeviceVerificationRequest) (err error) {
+	event := &models.AuthEvent{Type: models.EventDeviceVerification, UserID: userID, Reason: req.Action}
+	defer func() { s.recordEvent(ctx, event, err) }()
+
+	code, client, err := s.findPendingDeviceCode(ctx, req.UserCode)
+	if err != nil {
+		return err
+	}
+	event.ClientID = client.ID.String()
+
+	user, err := s.users.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return ErrUserNotFound
+		}
+		return fmt.Errorf("erro ao buscar usuário: %w", err)
+	}
+
+	if user.TenantID != client.TenantID {
+		return ErrTenantMismatch
+	}
+
+	status := models.DeviceCodeDenied
+	if req.Action == "approve" {
+		status = models.DeviceCodeApproved
+	}
+
+	if err := s.deviceCodes.DecideDeviceCode(ctx, code.ID, user.ID, status, authTime, time.Now()); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return ErrInvalidUserCode
+		}
+		return err
+	}
+	return nil
+}
+
+// ExchangeDeviceCode responde às consultas do dispositivo: authorization_pending enquanto
+// o usuário não decide, slow_down se o intervalo não for respeitado e, depois da aprovação,
+// os tokens. Os tokens só são emitidos uma vez por device_code.
+func (s *AuthService) ExchangeDeviceCode(ctx context.Context, req *models.TokenRequest) (_ *models.TokenResponse, err error) {
+	// Consultas pendentes acontecem a cada poucos segundos; só o desfecho entra na auditoria
+	event := &models.AuthEvent{Type: models.EventDeviceLogin, ClientID: req.ClientID}
+	defer func() {
+		if !errors.Is(err, ErrAuthorizationPending) && !errors.Is(err, ErrSlowDown) {
+			s.recordEvent(ctx, event, err)
+		}
+	}()
+
+	if req.GrantType != models.GrantTypeDeviceCode {
+		return nil, ErrUnsupportedGrantType
+	}
+
+	client, err := s.getActiveClient(ctx, req.ClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := s.deviceCodes.GetDeviceCodeByHash(ctx, hashDeviceCode(req.DeviceCode))
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, ErrInvalidDeviceCode
+		}
+		return nil, err
+	}
+	if code.ClientID != client.ID {
+		return nil, ErrInvalidDeviceCode
+	}
+
+	now := time.Now()
+	if !code.ExpiresAt.After(now) {
+		return nil, ErrDeviceCodeExpired
+	}
+
+	interval := code.Interval
+	tooSoon := code.LastPolledAt != nil && now.Sub(*code.LastPolledAt) < time.Duration(interval)*time.Second
+	if tooSoon {
+		interval += slowDownIncrement
+	}
+	if err := s.deviceCodes.TouchDeviceCode(ctx, code.ID, now, interval); err != nil {
+		return nil, err
+	}
+	if tooSoon {
+		return nil, ErrSlowDown
+	}
+
+	switch code.Status {
+	case models.DeviceCodePending:
+		return nil, ErrAuthorizationPending
+	case models.DeviceCodeDenied:
+		return nil, ErrAccessDenied
+	}
+
+	event.UserID = code.UserID.String()
+
+	// Remover o pedido antes de emitir tokens impede que duas consultas concorrentes os recebam
+	if err := s.deviceCodes.ConsumeDeviceCode(ctx, code.ID); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, ErrInvalidDeviceCode
+		}
+		return nil, err
+	}
+
+	user, err := s.users.GetUserByID(ctx, code.UserID.String())
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, ErrInvalidDeviceCode
+		}
+		return nil, fmt.Errorf("erro ao buscar usuário: %w", err)
+	}
+
+	if !user.Active {
+		return nil, ErrUserInactive
+	}
+
+	// O login que vale para os tokens do dispositivo é o da sessão que aprovou o pedido
+	var authTime time.Time
+	if code.AuthTime != nil {
+		authTime = *code.AuthTime
+	}
+
+	accessToken, err := s.generateAccessToken(*user, client.ID.String(), code.Scope, authTime)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gerar access token: %w", err)
+	}
+
+	// Clientes de terminal ficam abertos por muito tempo, então recebem refresh token
+	refreshToken, err := s.generateRefreshToken(ctx, user.ID, client.ID, code.Scope, authTime)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gerar refresh token: %w", err)
+	}
+
+	response := &models.TokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(s.cfg.JWT.ExpirationHours * 3600),
+		Scope:        code.Scope,
+	}
+
+	if hasScope(code.Scope, "openid") {
+		idToken, err := s.generateIDToken(*user, client.ID.String(), "", authTime)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao gerar id token: %w", err)
+		}
+		response.IDToken = idToken
+	}
+
+	return response, nil
+}
+
+// findPendingDeviceCode busca o pedido pelo código digitado pelo usuário. Pedidos já
+// decididos ou expirados, ou de clients inativos, são tratados como inexistentes.
+func (s *AuthService) findPendingDeviceCode(ctx context.Context, userCode string) (*models.DeviceCode, *models.Client, error) {
+	code, err := s.deviceCodes.GetDeviceCodeByUserCode(ctx, normalizeUserCode(userCode))
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, nil, ErrInvalidUserCode
+		}
+		return nil, nil, err
+	}
+
+	if code.Status != models.DeviceCodePending || !code.ExpiresAt.After(time.Now()) {
+		return nil, nil, ErrInvalidUserCode
+	}
+
+	client, err := s.getActiveClient(ctx, code.ClientID.String())
+	if err != nil {
+		if errors.Is(err, ErrClientNotFound) || errors.Is(err, ErrClientInactive) {
+			return nil, nil, ErrInvalidUserCode
+		}
+		return nil, nil, err
+	}
+
+	return code, client, nil
+}
+
+// verificationURIComplete monta a URL de verificação já com o código, para QR codes
+func (s *AuthService) verificationURIComplete(userCode string) string {
+	separator := "?"
+	if strings.Contains(s.cfg.DeviceAuth.VerificationURL, "?") {
+		separator = "&"
+	}
+	return s.cfg.DeviceAuth.VerificationURL + separator + url.Values{"user_code": {userCode}}.Encode()
+}
+
+func generateUserCode() (string, error) {
+	alphabetSize := big.NewInt(int64(len(userCodeAlphabet)))
+	code := make([]byte, userCodeLength)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, alphabetSize)
+		if err != nil {
+			return "", fmt.Errorf("erro ao gerar código de verificação: %w", err)
+		}
+		code[i] = userCodeAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
+
+// normalizeUserCode aceita o código com ou sem hífen, espaços e em minúsculas
+func normalizeUserCode(userCode string) string {
+	var normalized strings.Builder
+	for _, r := range strings.ToUpper(userCode) {
+		if strings.ContainsRune(userCodeAlphabet, r) {
+			normalized.WriteRune(r)
+		}
+	}
+	return normalized.String()
+}
+
+// formatUserCode exibe o código em dois grupos (ex: "BDFG-HJKL")
+func formatUserCode(userCode string) string {
+	half := len(userCode) / 2
+	return userCode[:half] + "-" + userCode[half:]
+}
+
+func hashDeviceCode(deviceCode string) string {
+	sum := sha256.Sum256([]byte(deviceCode))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,216 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"auth-service/config"
+	"auth-service/models"
+)
+
+// newDeviceFlowTestService prepara o serviço com chave de assinatura, um client e a
+// autorização de dispositivos configurada
+func newDeviceFlowTestService(t *testing.T) (*AuthService, *models.Client) {
+	t.Helper()
+
+	s, client := newOIDCTestService(t)
+	s.cfg.DeviceAuth = config.DeviceAuthConfig{TTLMinutes: 10, IntervalSeconds: 5}
+	return s, client
+}
+
+func TestDeviceIDTokenUsesApproverAuthTime(t *testing.T) {
+	s, client := newDeviceFlowTestService(t)
+	ctx := context.Background()
+	register(t, s, "ana@example.com")
+
+	session, err := s.Login(ctx, &models.LoginRequest{Email: "ana@example.com", Password: testPassword, ClientID: client.ID.String()})
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	approver, err := s.ValidateToken(ctx, session.AccessToken, client.ID.String())
+	if err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if approver.AuthTime == nil {
+		t.Fatal("token de sessão sem auth_time")
+	}
+
+	// A sessão que aprova foi aberta bem antes da consulta do dispositivo
+	authTime := approver.AuthTime.Add(-time.Hour)
+
+	start, err := s.StartDeviceAuthorization(ctx, &models.DeviceAuthorizationRequest{ClientID: client.ID.String(), Scope: "openid"})
+	if err != nil {
+		t.Fatalf("início: %v", err)
+	}
+	if err := s.VerifyDevice(ctx, approver.ID.String(), &authTime, &models.DeviceVerificationRequest{UserCode: start.UserCode, Action: "approve"}); err != nil {
+		t.Fatalf("aprovação: %v", err)
+	}
+
+	tokens, err := s.ExchangeDeviceCode(ctx, &models.TokenRequest{GrantType: models.GrantTypeDeviceCode, DeviceCode: start.DeviceCode, ClientID: client.ID.String()})
+	if err != nil {
+		t.Fatalf("troca: %v", err)
+	}
+	claims := parseIDToken(t, s, tokens.IDToken)
+	if got, _ := claims["auth_time"].(float64); int64(got) != authTime.Unix() {
+		t.Errorf("auth_time = %v, esperado %d", claims["auth_time"], authTime.Unix())
+	}
+
+	// A renovação mantém o momento do login da sessão que aprovou
+	refreshed, err := s.RefreshToken(ctx, &models.RefreshTokenRequest{RefreshToken: tokens.RefreshToken, ClientID: client.ID.String()})
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	claims = parseIDToken(t, s, refreshed.IDToken)
+	if got, _ := claims["auth_time"].(float64); int64(got) != authTime.Unix() {
+		t.Errorf("auth_time na renovação = %v, esperado %d", claims["auth_time"], authTime.Unix())
+	}
+}
+
+// startDeviceFlow inicia um pedido e devolve o device_code e o user_code
+func startDeviceFlow(t *testing.T, s *AuthService, client *models.Client) *models.DeviceAuthorizationResponse {
+	t.Helper()
+
+	start, err := s.StartDeviceAuthorization(context.Background(), &models.DeviceAuthorizationRequest{ClientID: client.ID.String(), Scope: "openid"})
+	if err != nil {
+		t.Fatalf("início: %v", err)
+	}
+	return start
+}
+
+// waitInterval simula a espera do dispositivo, recuando a última consulta em um intervalo
+func waitInterval(t *testing.T, s *AuthService, deviceCode string) {
+	t.Helper()
+
+	ctx := context.Background()
+	code, err := s.deviceCodes.GetDeviceCodeByHash(ctx, hashDeviceCode(deviceCode))
+	if err != nil {
+		t.Fatalf("pedido: %v", err)
+	}
+	polledAt := time.Now().Add(-time.Duration(code.Interval) * time.Second)
+	if err := s.deviceCodes.TouchDeviceCode(ctx, code.ID, polledAt, code.Interval); err != nil {
+		t.Fatalf("touch: %v", err)
+	}
+}
+
+func TestDeviceCodePolling(t *testing.T) {
+	s, client := newDeviceFlowTestService(t)
+	ctx := context.Background()
+	user := register(t, s, "ana@example.com")
+	start := startDeviceFlow(t, s, client)
+	poll := &models.TokenRequest{GrantType: models.GrantTypeDeviceCode, DeviceCode: start.DeviceCode, ClientID: client.ID.String()}
+
+	steps := []struct {
+		name     string
+		before   func()
+		wantErr  error
+		interval int
+	}{
+		{"primeira consulta", nil, ErrAuthorizationPending, 5},
+		{"consulta antes do intervalo", nil, ErrSlowDown, 10},
+		{"de novo antes do novo intervalo", nil, ErrSlowDown, 15},
+		{"depois de esperar", func() { waitInterval(t, s, start.DeviceCode) }, ErrAuthorizationPending, 15},
+		{"depois da aprovação", func() {
+			waitInterval(t, s, start.DeviceCode)
+			if err := s.VerifyDevice(ctx, user.ID.String(), nil, &models.DeviceVerificationRequest{UserCode: start.UserCode, Action: "approve"}); err != nil {
+				t.Fatalf("aprovação: %v", err)
+			}
+		}, nil, 0},
+		{"device_code já usado", nil, ErrInvalidDeviceCode, 0},
+	}
+	for _, step := range steps {
+		if step.before != nil {
+			step.before()
+		}
+		tokens, err := s.ExchangeDeviceCode(ctx, poll)
+		if !errors.Is(err, step.wantErr) {
+			t.Fatalf("%s: erro %v, esperado %v", step.name, err, step.wantErr)
+		}
+		if step.interval > 0 {
+			code, _ := s.deviceCodes.GetDeviceCodeByHash(ctx, hashDeviceCode(start.DeviceCode))
+			if code.Interval != step.interval {
+				t.Errorf("%s: intervalo %d, esperado %d", step.name, code.Interval, step.interval)
+			}
+		}
+		if err == nil && (tokens.AccessToken == "" || tokens.RefreshToken == "" || tokens.IDToken == "") {
+			t.Errorf("%s: tokens incompletos %+v", step.name, tokens)
+		}
+	}
+}
+
+func TestDeviceCodeOutcomes(t *testing.T) {
+	s, client := newDeviceFlowTestService(t)
+	ctx := context.Background()
+	user := register(t, s, "ana@example.com")
+	other := newTestClient(t, s)
+
+	org, err := s.CreateOrganization(ctx, "", &models.CreateOrganizationRequest{Slug: "escola", Name: "Escola"})
+	if err != nil {
+		t.Fatalf("organização: %v", err)
+	}
+	orgClient, err := s.CreateClient(ctx, org.ID.String(), "app", "")
+	if err != nil {
+		t.Fatalf("client: %v", err)
+	}
+	outsider, err := s.Register(ctx, &models.RegisterRequest{Email: "bia@example.com", Password: testPassword, Name: "Bia", ClientID: orgClient.ID.String()})
+	if err != nil {
+		t.Fatalf("registro: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		decide    func(start *models.DeviceAuthorizationResponse) error
+		clientID  string
+		decideErr error
+		wantErr   error
+	}{
+		{"recusado", func(start *models.DeviceAuthorizationResponse) error {
+			return s.VerifyDevice(ctx, user.ID.String(), nil, &models.DeviceVerificationRequest{UserCode: start.UserCode, Action: "deny"})
+		}, client.ID.String(), nil, ErrAccessDenied},
+		{"consultado por outro client", func(start *models.DeviceAuthorizationResponse) error {
+			return s.VerifyDevice(ctx, user.ID.String(), nil, &models.DeviceVerificationRequest{UserCode: start.UserCode, Action: "approve"})
+		}, other.ID.String(), nil, ErrInvalidDeviceCode},
+		{"aprovado por usuário de outra organização", func(start *models.DeviceAuthorizationResponse) error {
+			return s.VerifyDevice(ctx, outsider.ID.String(), nil, &models.DeviceVerificationRequest{UserCode: start.UserCode, Action: "approve"})
+		}, client.ID.String(), ErrTenantMismatch, ErrAuthorizationPending},
+		{"user_code desconhecido", func(start *models.DeviceAuthorizationResponse) error {
+			return s.VerifyDevice(ctx, user.ID.String(), nil, &models.DeviceVerificationRequest{UserCode: "BCDF-GHJK", Action: "approve"})
+		}, client.ID.String(), ErrInvalidUserCode, ErrAuthorizationPending},
+		{"decidido duas vezes", func(start *models.DeviceAuthorizationResponse) error {
+			req := &models.DeviceVerificationRequest{UserCode: start.UserCode, Action: "approve"}
+			if err := s.VerifyDevice(ctx, user.ID.String(), nil, req); err != nil {
+				return err
+			}
+			req.Action = "deny"
+			return s.VerifyDevice(ctx, user.ID.String(), nil, req)
+		}, client.ID.String(), ErrInvalidUserCode, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := startDeviceFlow(t, s, client)
+			if err := tt.decide(start); !errors.Is(err, tt.decideErr) {
+				t.Fatalf("decisão: erro %v, esperado %v", err, tt.decideErr)
+			}
+
+			_, err := s.ExchangeDeviceCode(ctx, &models.TokenRequest{GrantType: models.GrantTypeDeviceCode, DeviceCode: start.DeviceCode, ClientID: tt.clientID})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("troca: erro %v, esperado %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExpiredDeviceCode(t *testing.T) {
+	s, client := newDeviceFlowTestService(t)
+	s.cfg.DeviceAuth.TTLMinutes = -1
+	start := startDeviceFlow(t, s, client)
+
+	_, err := s.ExchangeDeviceCode(context.Background(), &models.TokenRequest{GrantType: models.GrantTypeDeviceCode, DeviceCode: start.DeviceCode, ClientID: client.ID.String()})
+	if !errors.Is(err, ErrDeviceCodeExpired) {
+		t.Errorf("erro %v, esperado %v", err, ErrDeviceCodeExpired)
+	}
+	if _, err := s.GetDeviceVerification(context.Background(), start.UserCode); !errors.Is(err, ErrInvalidUserCode) {
+		t.Errorf("verificação de pedido expirado: erro %v, esperado %v", err, ErrInvalidUserCode)
+	}
+}
@@ -0,0 +1,126 @@
+package services
+
+// Error é um erro de negócio com um código estável, legível por máquina.
+// A mensagem pode mudar livremente; clientes e handlers devem se basear no código.
+type Error struct {
+	Code    string
+	Message string
+	cause   error
+}
+
+func newError(code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+func (e *Error) Error() string {
+	if e.cause != nil {
+		return e.Message + ": " + e.cause.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Is compara pelo código, de modo que errors.Is(err, ErrInvalidToken) funciona
+// mesmo quando o erro carrega uma causa
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// Wrap retorna uma cópia do erro com a causa informada
+func (e *Error) Wrap(cause error) *Error {
+	return &Error{Code: e.Code, Message: e.Message, cause: cause}
+}
+
+var (
+	// Requisição
+	ErrInvalidRequest     = newError("INVALID_REQUEST", "dados inválidos")
+	ErrMissingAuthToken   = newError("MISSING_AUTH_TOKEN", "token de autorização não fornecido")
+	ErrInvalidTokenFormat = newError("INVALID_TOKEN_FORMAT", "formato de token inválido")
+	ErrMissingClientID    = newError("MISSING_CLIENT_ID", "client_id não fornecido")
+	ErrUnauthenticated    = newError("UNAUTHENTICATED", "usuário não autenticado")
+	ErrForbidden          = newError("FORBIDDEN", "acesso negado")
+	ErrRateLimited        = newError("RATE_LIMITED", "limite de requisições excedido")
+	ErrInvalidCSRFToken   = newError("INVALID_CSRF_TOKEN", "token CSRF ausente ou inválido")
+
+	// Usuários e credenciais
+	ErrEmailInUse         = newError("EMAIL_IN_USE", "email já está em uso")
+	ErrInvalidCredentials = newError("INVALID_CREDENTIALS", "credenciais inválidas")
+	ErrUserInactive       = newError("USER_INACTIVE", "usuário inativo")
+	ErrUserNotFound       = newError("USER_NOT_FOUND", "usuário não encontrado")
+	ErrUnknownRole        = newError("UNKNOWN_ROLE", "papel desconhecido")
+	ErrCannotModifySelf   = newError("CANNOT_MODIFY_SELF", "administradores não podem desativar ou remover a própria conta")
+
+	// Cadastro
+	ErrInviteRequired        = newError("INVITE_REQUIRED", "convite obrigatório para o cadastro")
+	ErrInvalidInvite         = newError("INVALID_INVITE", "convite inválido ou expirado")
+	ErrEmailDomainNotAllowed = newError("EMAIL_DOMAIN_NOT_ALLOWED", "domínio de email não permitido para cadastro")
+	ErrInvitationNotFound    = newError("INVITATION_NOT_FOUND", "convite não encontrado")
+
+	// Clientes
+	ErrClientNotFound     = newError("CLIENT_NOT_FOUND", "cliente não encontrado")
+	ErrClientInactive     = newError("CLIENT_INACTIVE", "cliente inativo")
+	ErrClientUnauthorized = newError("CLIENT_UNAUTHORIZED", "cliente não autorizado")
+	ErrTenantMismatch     = newError("TENANT_MISMATCH", "token de outra organização")
+	ErrClientRequired     = newError("CLIENT_REQUIRED", "o escopo openid exige um client_id")
+
+	// Organizações
+	ErrOrganizationNotFound = newError("ORGANIZATION_NOT_FOUND", "organização não encontrada")
+	ErrOrganizationExists   = newError("ORGANIZATION_EXISTS", "já existe uma organização com este slug")
+
+	// Tokens
+	ErrInvalidToken          = newError("INVALID_TOKEN", "token inválido")
+	ErrInvalidTokenType      = newError("INVALID_TOKEN_TYPE", "tipo de token inválido")
+	ErrInsufficientScope     = newError("INSUFFICIENT_SCOPE", "escopo insuficiente")
+	ErrInvalidRefreshToken   = newError("INVALID_REFRESH_TOKEN", "refresh token inválido")
+	ErrRefreshTokenRevoked   = newError("REFRESH_TOKEN_REVOKED", "refresh token revogado")
+	ErrRefreshTokenExpired   = newError("REFRESH_TOKEN_EXPIRED", "refresh token expirado")
+	ErrPersonalTokenNotFound = newError("PERSONAL_TOKEN_NOT_FOUND", "token de acesso pessoal não encontrado")
+	ErrInvalidScope          = newError("INVALID_SCOPE", "escopo inválido")
+	ErrSessionRequired       = newError("SESSION_REQUIRED", "operação exige uma sessão; tokens de acesso pessoal não são aceitos")
+
+	// Login federado
+	ErrProviderNotFound     = newError("PROVIDER_NOT_FOUND", "provedor não encontrado")
+	ErrProviderRejected     = newError("PROVIDER_REJECTED", "provedor recusou a autenticação")
+	ErrInvalidState         = newError("INVALID_STATE", "state inválido")
+	ErrInvalidNonce         = newError("INVALID_NONCE", "nonce inválido")
+	ErrInvalidProviderToken = newError("INVALID_PROVIDER_TOKEN", "id token do provedor inválido")
+	ErrEmailNotVerified     = newError("EMAIL_NOT_VERIFIED", "email não verificado pelo provedor")
+
+	// Login por link
+	ErrMagicLinkDisabled = newError("MAGIC_LINK_DISABLED", "login por link não habilitado para o cliente")
+	ErrInvalidMagicLink  = newError("INVALID_MAGIC_LINK", "link de acesso inválido ou expirado")
+	ErrMagicLinkUsed     = newError("MAGIC_LINK_USED", "link de acesso já utilizado")
+
+	// Autorização de dispositivo (RFC 8628)
+	ErrUnsupportedGrantType = newError("UNSUPPORTED_GRANT_TYPE", "grant_type não suportado")
+	ErrAuthorizationPending = newError("AUTHORIZATION_PENDING", "autorização ainda não concedida pelo usuário")
+	ErrSlowDown             = newError("SLOW_DOWN", "consultas frequentes demais; aumente o intervalo")
+	ErrAccessDenied         = newError("ACCESS_DENIED", "autorização recusada pelo usuário")
+	ErrDeviceCodeExpired    = newError("DEVICE_CODE_EXPIRED", "código do dispositivo expirado")
+	ErrInvalidDeviceCode    = newError("INVALID_DEVICE_CODE", "código do dispositivo inválido")
+	ErrInvalidUserCode      = newError("INVALID_USER_CODE", "código de verificação inválido ou expirado")
+
+	// Fluxo authorization code (RFC 6749, com PKCE da RFC 7636)
+	ErrUnsupportedResponseType  = newError("UNSUPPORTED_RESPONSE_TYPE", "response_type não suportado; use code")
+	ErrInvalidRedirectURI       = newError("INVALID_REDIRECT_URI", "redirect_uri não cadastrado para o cliente")
+	ErrPKCERequired             = newError("PKCE_REQUIRED", "code_challenge com code_challenge_method S256 é obrigatório")
+	ErrInvalidAuthorizationCode = newError("INVALID_AUTHORIZATION_CODE", "código de autorização inválido, expirado ou já utilizado")
+
+	// Webhooks e outbox
+	ErrWebhookNotFound     = newError("WEBHOOK_NOT_FOUND", "webhook não encontrado")
+	ErrInvalidWebhookURL   = newError("INVALID_WEBHOOK_URL", "URL do webhook inválida; ela deve apontar para um endereço público e, em produção, usar https")
+	ErrOutboxEventNotFound = newError("OUTBOX_EVENT_NOT_FOUND", "evento do outbox não encontrado")
+
+	// Redefinição de senha e importação de usuários
+	ErrInvalidPasswordReset = newError("INVALID_PASSWORD_RESET", "link de redefinição de senha inválido, expirado ou já utilizado")
+	ErrPasswordReused       = newError("PASSWORD_REUSED", "a nova senha não pode repetir a atual nem as usadas recentemente")
+	ErrInvalidImportFormat  = newError("INVALID_IMPORT_FORMAT", "arquivo de importação inválido")
+	ErrImportTooLarge       = newError("IMPORT_TOO_LARGE", "arquivo de importação com linhas demais")
+
+	// Avisos de login em dispositivo novo
+	ErrInvalidLoginReport = newError("INVALID_LOGIN_REPORT", "link de denúncia de acesso inválido, expirado ou já utilizado")
+)
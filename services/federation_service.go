@@ -0,0 +1,449 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"auth-service/config"
+	"auth-service/models"
+	"auth-service/store"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// FederationStateTTL é o tempo máximo entre o redirecionamento e o callback
+const FederationStateTTL = 10 * time.Minute
+
+// jwksRefreshInterval limita a frequência de recarga das chaves de um provedor
+const jwksRefreshInterval = time.Minute
+
+// FederationService autentica usuários por meio de provedores OIDC externos (relying party)
+type FederationService struct {
+	authService *AuthService
+	httpClient  *http.Client
+	providers   map[string]*federatedProvider
+}
+
+type federatedProvider struct {
+	cfg config.FederatedProviderConfig
+
+	mu            sync.Mutex
+	metadata      *providerMetadata
+	keys          map[string]*rsa.PublicKey
+	keysFetchedAt time.Time
+}
+
+type providerMetadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+func NewFederationService(authService *AuthService, cfg *config.Config, httpClient *http.Client) *FederationService {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	providers := make(map[string]*federatedProvider, len(cfg.Federation.Providers))
+	for _, providerCfg := range cfg.Federation.Providers {
+		providers[providerCfg.Name] = &federatedProvider{cfg: providerCfg}
+	}
+
+	return &FederationService{
+		authService: authService,
+		httpClient:  httpClient,
+		providers:   providers,
+	}
+}
+
+// AuthorizationURL monta a URL de autorização do provedor e o state assinado que a acompanha
+func (s *FederationService) AuthorizationURL(ctx context.Context, providerName, clientID string) (string, string, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return "", "", ErrProviderNotFound
+	}
+
+	if clientID == "" {
+		clientID = defaultClientID
+	} else if _, err := s.authService.getActiveClient(ctx, clientID); err != nil {
+		return "", "", err
+	}
+
+	metadata, err := s.discover(provider)
+	if err != nil {
+		return "", "", err
+	}
+
+	nonceBytes := make([]byte, 16)
+	if _, err := rand.Read(nonceBytes); err != nil {
+		return "", "", fmt.Errorf("erro ao gerar nonce: %w", err)
+	}
+	nonce := hex.EncodeToString(nonceBytes)
+
+	state, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"type":      "federation_state",
+		"provider":  providerName,
+		"client_id": clientID,
+		"nonce":     nonce,
+		"exp":       time.Now().Add(FederationStateTTL).Unix(),
+	}).SignedString([]byte(s.authService.cfg.JWT.Secret))
+	if err != nil {
+		return "", "", fmt.Errorf("erro ao gerar state: %w", err)
+	}
+
+	query := url.Values{
+		"response_type": {"code"},
+		"client_id":     {provider.cfg.ClientID},
+		"redirect_uri":  {provider.cfg.RedirectURL},
+		"scope":         {strings.Join(provider.cfg.Scopes, " ")},
+		"state":         {state},
+		"nonce":         {nonce},
+	}
+
+	separator := "?"
+	if strings.Contains(metadata.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+
+	return metadata.AuthorizationEndpoint + separator + query.Encode(), state, nil
+}
+
+// HandleCallback troca o código pelo ID token do provedor, verifica-o e autentica o usuário local
+func (s *FederationService) HandleCallback(ctx context.Context, providerName, code, state string) (_ *models.TokenResponse, err error) {
+	event := &models.AuthEvent{Type: models.EventFederatedLogin, Reason: providerName}
+	defer func() { s.authService.recordEvent(ctx, event, err) }()
+
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return nil, ErrProviderNotFound
+	}
+
+	stateClaims, err := s.parseState(state, providerName)
+	if err != nil {
+		return nil, err
+	}
+	event.ClientID, _ = stateClaims["client_id"].(string)
+
+	metadata, err := s.discover(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	rawIDToken, err := s.exchangeCode(provider, metadata, code)
+	if err != nil {
+		return nil, err
+	}
+
+	idClaims, err := s.verifyIDToken(provider, metadata, rawIDToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if nonce, _ := idClaims["nonce"].(string); nonce == "" || nonce != stateClaims["nonce"] {
+		return nil, ErrInvalidNonce
+	}
+
+	subject, _ := idClaims["sub"].(string)
+	if subject == "" {
+		return nil, ErrInvalidProviderToken
+	}
+
+	email, _ := idClaims["email"].(string)
+	name, _ := idClaims["name"].(string)
+
+	// A conta é resolvida dentro da organização do client que iniciou o login
+	clientID, _ := stateClaims["client_id"].(string)
+	tenantID, err := s.authService.tenantForClient(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.resolveUser(ctx, tenantID, providerName, subject, email, name, isEmailVerified(idClaims))
+	if err != nil {
+		return nil, err
+	}
+	event.UserID = user.ID.String()
+
+	if !user.Active {
+		return nil, ErrUserInactive
+	}
+
+	accessToken, err := s.authService.generateAccessToken(*user, clientID, "", time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gerar access token: %w", err)
+	}
+
+	return &models.TokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: "",
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(s.authService.cfg.JWT.ExpirationHours * 3600),
+	}, nil
+}
+
+func (s *FederationService) parseState(state, providerName string) (jwt.MapClaims, error) {
+	token, err := jwt.Parse(state, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("método de assinatura inesperado: %v", token.Header["alg"])
+		}
+		return []byte(s.authService.cfg.JWT.Secret), nil
+	}, jwt.WithExpirationRequired())
+	if err != nil || !token.Valid {
+		return nil, ErrInvalidState
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || claims["type"] != "federation_state" || claims["provider"] != providerName {
+		return nil, ErrInvalidState
+	}
+
+	return claims, nil
+}
+
+// resolveUser encontra o usuário da organização vinculado à identidade externa, vinculando
+// por email verificado ou criando uma conta sem senha local quando necessário
+func (s *FederationService) resolveUser(ctx context.Context, tenantID, providerName, subject, email, name string, emailVerified bool) (*models.User, error) {
+	users := s.authService.users
+
+	user, err := users.GetUserByIdentity(ctx, tenantID, providerName, subject)
+	if err == nil {
+		return user, nil
+	}
+	if !errors.Is(err, store.ErrNotFound) {
+		return nil, fmt.Errorf("erro ao buscar identidade: %w", err)
+	}
+
+	// Sem vínculo prévio, só confiamos no email se o provedor o verificou
+	if email == "" || !emailVerified {
+		return nil, ErrEmailNotVerified
+	}
+
+	now := time.Now()
+
+	user, err = users.GetUserByEmail(ctx, tenantID, email)
+	if errors.Is(err, store.ErrNotFound) {
+		// Contas criadas pelo login federado seguem o mesmo modo de cadastro do /register
+		if err := s.authService.checkSignupAllowed(email); err != nil {
+			return nil, err
+		}
+
+		if name == "" {
+			name = email
+		}
+
+		// Usuários federados não possuem senha local
+		user = &models.User{
+			ID:        uuid.New(),
+			TenantID:  uuid.MustParse(tenantID),
+			Email:     email,
+			Password:  "",
+			Name:      name,
+			Active:    true,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		created, err := newUserEvent(models.OutboxUserCreated, user)
+		if err != nil {
+			return nil, err
+		}
+		if err := users.CreateUser(ctx, user, nil, created); err != nil {
+			return nil, fmt.Errorf("erro ao criar usuário: %w", err)
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("erro ao buscar usuário: %w", err)
+	}
+
+	identity := &models.UserIdentity{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		Provider:  providerName,
+		Subject:   subject,
+		Email:     email,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := users.CreateIdentity(ctx, identity); err != nil {
+		return nil, fmt.Errorf("erro ao vincular identidade: %w", err)
+	}
+
+	return user, nil
+}
+
+func (s *FederationService) discover(provider *federatedProvider) (*providerMetadata, error) {
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+
+	if provider.metadata != nil {
+		return provider.metadata, nil
+	}
+
+	var metadata providerMetadata
+	if err := s.getJSON(provider.cfg.Issuer+"/.well-known/openid-configuration", &metadata); err != nil {
+		return nil, fmt.Errorf("erro ao obter configuração do provedor: %w", err)
+	}
+
+	if strings.TrimSuffix(metadata.Issuer, "/") != provider.cfg.Issuer {
+		return nil, fmt.Errorf("erro ao obter configuração do provedor: issuer divergente %q", metadata.Issuer)
+	}
+
+	provider.metadata = &metadata
+	return provider.metadata, nil
+}
+
+func (s *FederationService) exchangeCode(provider *federatedProvider, metadata *providerMetadata, code string) (string, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {provider.cfg.RedirectURL},
+		"client_id":     {provider.cfg.ClientID},
+		"client_secret": {provider.cfg.ClientSecret},
+	}
+
+	resp, err := s.httpClient.PostForm(metadata.TokenEndpoint, form)
+	if err != nil {
+		return "", fmt.Errorf("erro ao trocar código com o provedor: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var tokenResp struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return "", fmt.Errorf("erro ao ler resposta do provedor: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", ErrProviderRejected.Wrap(errors.New(tokenResp.Error))
+	}
+
+	if tokenResp.IDToken == "" {
+		return "", fmt.Errorf("provedor não retornou id token")
+	}
+
+	return tokenResp.IDToken, nil
+}
+
+func (s *FederationService) verifyIDToken(provider *federatedProvider, metadata *providerMetadata, rawIDToken string) (jwt.MapClaims, error) {
+	token, err := jwt.Parse(rawIDToken, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, fmt.Errorf("método de assinatura inesperado: %v", token.Header["alg"])
+		}
+		kid, _ := token.Header["kid"].(string)
+		return s.publicKey(provider, metadata, kid)
+	},
+		jwt.WithIssuer(metadata.Issuer),
+		jwt.WithAudience(provider.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	)
+	if err != nil || !token.Valid {
+		return nil, ErrInvalidProviderToken
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, ErrInvalidProviderToken
+	}
+
+	return claims, nil
+}
+
+// publicKey retorna a chave do provedor com o kid informado, recarregando o JWKS
+// quando a chave ainda não é conhecida (rotação de chaves)
+func (s *FederationService) publicKey(provider *federatedProvider, metadata *providerMetadata, kid string) (*rsa.PublicKey, error) {
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+
+	if key, ok := provider.keys[kid]; ok {
+		return key, nil
+	}
+
+	if time.Since(provider.keysFetchedAt) < jwksRefreshInterval && provider.keys != nil {
+		return nil, fmt.Errorf("chave %q desconhecida", kid)
+	}
+
+	var jwks struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := s.getJSON(metadata.JWKSURI, &jwks); err != nil {
+		return nil, fmt.Errorf("erro ao obter chaves do provedor: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if jwk.Kty != "RSA" {
+			continue
+		}
+
+		n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+		if err != nil {
+			continue
+		}
+		e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+		if err != nil {
+			continue
+		}
+
+		keys[jwk.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+
+	provider.keys = keys
+	provider.keysFetchedAt = time.Now()
+
+	key, ok := keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("chave %q desconhecida", kid)
+	}
+
+	return key, nil
+}
+
+func (s *FederationService) getJSON(url string, target interface{}) error {
+	resp, err := s.httpClient.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status %d em %s", resp.StatusCode, url)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+// isEmailVerified aceita email_verified como booleano ou string, como alguns provedores enviam
+func isEmailVerified(claims jwt.MapClaims) bool {
+	switch v := claims["email_verified"].(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	default:
+		return false
+	}
+}
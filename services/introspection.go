@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"auth-service/models"
+	"auth-service/store"
+
+	"github.com/google/uuid"
+)
+
+// Introspect informa se o access token ou token de acesso pessoal está ativo para o client (RFC 7662).
+// Só retorna erro quando o próprio client é inválido; qualquer problema com o token
+// resulta em Active = false, sem revelar o motivo.
+func (s *AuthService) Introspect(ctx context.Context, tokenString, clientID string) (*models.IntrospectionResponse, error) {
+	client, err := s.getActiveClient(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	inactive := &models.IntrospectionResponse{Active: false}
+
+	// Tokens de acesso pessoal valem para qualquer client ativo da organização do usuário;
+	// exp é omitido quando não expiram
+	if isPersonalToken(tokenString) {
+		personalToken, user, err := s.authenticatePersonalToken(ctx, tokenString)
+		if errors.Is(err, ErrInvalidToken) {
+			return inactive, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !user.Active || user.TenantID != client.TenantID {
+			return inactive, nil
+		}
+
+		response := &models.IntrospectionResponse{
+			Active:    true,
+			Sub:       user.ID.String(),
+			ClientID:  clientID,
+			TenantID:  user.TenantID.String(),
+			Email:     user.Email,
+			Scope:     personalToken.Scope,
+			TokenType: "Bearer",
+			Iat:       personalToken.CreatedAt.Unix(),
+		}
+		if personalToken.ExpiresAt != nil {
+			response.Exp = personalToken.ExpiresAt.Unix()
+		}
+		return response, nil
+	}
+
+	claims, err := s.parseAccessToken(tokenString)
+	if err != nil || claims["client_id"] != clientID || tokenTenant(claims) != client.TenantID.String() {
+		return inactive, nil
+	}
+
+	userID, _ := claims["user_id"].(string)
+	user, err := s.users.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return inactive, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar usuário: %w", err)
+	}
+	if !user.Active || user.TenantID != client.TenantID {
+		return inactive, nil
+	}
+
+	scope, _ := claims["scope"].(string)
+	exp, _ := claims["exp"].(float64)
+	iat, _ := claims["iat"].(float64)
+
+	return &models.IntrospectionResponse{
+		Active:    true,
+		Sub:       userID,
+		ClientID:  clientID,
+		TenantID:  user.TenantID.String(),
+		Email:     user.Email,
+		Scope:     scope,
+		TokenType: "Bearer",
+		Exp:       int64(exp),
+		Iat:       int64(iat),
+	}, nil
+}
+
+// GetUser busca um usuário pelo ID, para uso dos serviços internos
+func (s *AuthService) GetUser(ctx context.Context, userID string) (*models.UserResponse, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, ErrInvalidRequest.Wrap(err)
+	}
+
+	user, err := s.users.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar usuário: %w", err)
+	}
+
+	return s.userResponse(ctx, user)
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"auth-service/config"
+	"auth-service/models"
+	"auth-service/store"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// invitationCodePrefix identifica códigos de convite (ex: "inv_1a2b...")
+	invitationCodePrefix = "inv_"
+	// invitationDisplayLength é quanto do código fica visível nas listagens
+	invitationDisplayLength = len(invitationCodePrefix) + 8
+)
+
+// CreateInvitation cria um convite de cadastro na organização, com os papéis que a conta receberá. O código
+// em claro só é devolvido aqui; depois disso apenas o hash fica guardado.
+func (s *AuthService) CreateInvitation(ctx context.Context, tenantID, createdBy string, req *models.CreateInvitationRequest) (_ *models.CreatedInvitation, err error) {
+	event := &models.AuthEvent{Type: models.EventInvitationCreated, UserID: createdBy}
+	defer func() { s.recordEvent(ctx, event, err) }()
+
+	roles := []string{}
+	for _, role := range req.Roles {
+		if !knownRoles[role] {
+			return nil, ErrUnknownRole.Wrap(errors.New(role))
+		}
+		if !slices.Contains(roles, role) {
+			roles = append(roles, role)
+		}
+	}
+
+	maxUses := req.MaxUses
+	if maxUses == 0 {
+		maxUses = 1
+	}
+
+	codeBytes := make([]byte, 16)
+	if _, err := rand.Read(codeBytes); err != nil {
+		return nil, fmt.Errorf("erro ao gerar código de convite: %w", err)
+	}
+	code := invitationCodePrefix + hex.EncodeToString(codeBytes)
+
+	now := time.Now()
+	invitation := models.Invitation{
+		ID:        uuid.New(),
+		TenantID:  uuid.MustParse(tenantID),
+		CodeHash:  hashInvitationCode(code),
+		Prefix:    code[:invitationDisplayLength],
+		Roles:     roles,
+		MaxUses:   maxUses,
+		ExpiresAt: now.Add(time.Duration(req.ExpiresInHours) * time.Hour),
+		CreatedAt: now,
+	}
+	if creator, err := uuid.Parse(createdBy); err == nil {
+		invitation.CreatedBy = &creator
+	}
+
+	if err := s.invitations.CreateInvitation(ctx, &invitation); err != nil {
+		return nil, fmt.Errorf("erro ao criar convite: %w", err)
+	}
+	event.Reason = invitation.Prefix
+
+	return &models.CreatedInvitation{Invitation: invitation, Code: code}, nil
+}
+
+// ListInvitations lista os convites não revogados da organização, sem o código
+func (s *AuthService) ListInvitations(ctx context.Context, tenantID string) ([]models.Invitation, error) {
+	return s.invitations.ListInvitations(ctx, tenantID)
+}
+
+// RevokeInvitation revoga um convite; cadastros já feitos com ele não são afetados
+func (s *AuthService) RevokeInvitation(ctx context.Context, tenantID, actorID, id string) (err error) {
+	event := &models.AuthEvent{Type: models.EventInvitationRevoked, UserID: actorID, Reason: id}
+	defer func() { s.recordEvent(ctx, event, err) }()
+
+	if err := s.invitations.RevokeInvitation(ctx, tenantID, id); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return ErrInvitationNotFound
+		}
+		return fmt.Errorf("erro ao revogar convite: %w", err)
+	}
+	return nil
+}
+
+// findInvitation busca um convite utilizável pelo código. Convites desconhecidos, revogados,
+// expirados ou esgotados resultam em ErrInvalidInvite.
+func (s *AuthService) findInvitation(ctx context.Context, code string) (*models.Invitation, error) {
+	invitation, err := s.invitations.GetInvitationByHash(ctx, hashInvitationCode(code))
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, ErrInvalidInvite
+		}
+		return nil, fmt.Errorf("erro ao buscar convite: %w", err)
+	}
+
+	if invitation.RevokedAt != nil || !time.Now().Before(invitation.ExpiresAt) || invitation.Uses >= invitation.MaxUses {
+		return nil, ErrInvalidInvite
+	}
+	return invitation, nil
+}
+
+// checkSignupAllowed aplica o modo de cadastro a uma conta criada sem convite
+func (s *AuthService) checkSignupAllowed(email string) error {
+	switch s.cfg.Registration.Mode {
+	case config.RegistrationInviteOnly:
+		return ErrInviteRequired
+	case config.RegistrationDomainAllowlist:
+		if !emailDomainAllowed(email, s.cfg.Registration.AllowedDomains) {
+			return ErrEmailDomainNotAllowed
+		}
+	}
+	return nil
+}
+
+// emailDomainAllowed compara o domínio do email com a lista, sem diferenciar maiúsculas.
+// Subdomínios não são aceitos implicitamente.
+func emailDomainAllowed(email string, allowed []string) bool {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	domain := email[at+1:]
+	for _, candidate := range allowed {
+		if strings.EqualFold(domain, strings.TrimSpace(candidate)) {
+			return true
+		}
+	}
+	return false
+}
+
+func hashInvitationCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"auth-service/config"
+	"auth-service/metrics"
+	"auth-service/store"
+)
+
+// PurgeFunc remove até limit registros vencidos antes de before e retorna quantos removeu
+type PurgeFunc func(ctx context.Context, before time.Time, limit int) (int64, error)
+
+// JanitorStats resume o que o janitor já removeu desde que o processo subiu
+type JanitorStats struct {
+	Runs    int64            `json:"runs"`
+	LastRun time.Time        `json:"last_run"`
+	Purged  map[string]int64 `json:"purged"`
+}
+
+type purgeTask struct {
+	name  string
+	purge PurgeFunc
+}
+
+// Janitor remove periodicamente tokens expirados e revogados, em lotes,
+// para não manter o banco (em especial o SQLite) travado por muito tempo
+type Janitor struct {
+	interval  time.Duration
+	retention time.Duration
+	batchSize int
+
+	tasks []purgeTask
+
+	mu    sync.Mutex
+	stats JanitorStats
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewJanitor(cfg *config.Config, stores *store.Stores) *Janitor {
+	j := &Janitor{
+		interval:  time.Duration(cfg.Cleanup.IntervalMinutes) * time.Minute,
+		retention: time.Duration(cfg.Cleanup.RetentionHours) * time.Hour,
+		batchSize: cfg.Cleanup.BatchSize,
+		stats:     JanitorStats{Purged: make(map[string]int64)},
+	}
+
+	j.Register("refresh_tokens", stores.Tokens.PurgeRefreshTokens)
+	j.Register("personal_access_tokens", stores.PersonalTokens.PurgePersonalTokens)
+	j.Register("magic_links", stores.MagicLinks.PurgeMagicLinks)
+	j.Register("authorization_codes", stores.Codes.PurgeAuthorizationCodes)
+	j.Register("invitations", stores.Invitations.PurgeInvitations)
+	j.Register("device_codes", stores.DeviceCodes.PurgeDeviceCodes)
+	j.Register("outbox_events", stores.Outbox.PurgeOutboxEvents)
+
+	return j
+}
+
+// Register adiciona um tipo de registro à limpeza periódica. Deve ser chamado antes de Start.
+func (j *Janitor) Register(name string, purge PurgeFunc) {
+	j.tasks = append(j.tasks, purgeTask{name: name, purge: purge})
+}
+
+// Start executa a limpeza em segundo plano até Stop ser chamado
+func (j *Janitor) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	j.cancel = cancel
+	j.done = make(chan struct{})
+
+	go func() {
+		defer close(j.done)
+
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		for {
+			j.RunOnce(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop interrompe a limpeza e aguarda o lote em andamento terminar
+func (j *Janitor) Stop() {
+	if j.cancel == nil {
+		return
+	}
+	j.cancel()
+	<-j.done
+}
+
+// RunOnce executa uma rodada completa de limpeza
+func (j *Janitor) RunOnce(ctx context.Context) {
+	before := time.Now().Add(-j.retention)
+
+	for _, task := range j.tasks {
+		var total int64
+
+		for ctx.Err() == nil {
+			purged, err := task.purge(ctx, before, j.batchSize)
+			if err != nil {
+				slog.Error("Erro na limpeza", "task", task.name, "error", err)
+				break
+			}
+
+			total += purged
+			metrics.TokensPurged.WithLabelValues(task.name).Add(float64(purged))
+			if purged < int64(j.batchSize) {
+				break
+			}
+		}
+
+		j.mu.Lock()
+		j.stats.Purged[task.name] += total
+		j.mu.Unlock()
+
+		if total > 0 {
+			slog.Info("Limpeza concluída", "task", task.name, "purged", total)
+		}
+	}
+
+	j.mu.Lock()
+	j.stats.Runs++
+	j.stats.LastRun = time.Now()
+	j.mu.Unlock()
+}
+
+// Stats retorna uma cópia dos contadores de remoção
+func (j *Janitor) Stats() JanitorStats {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	purged := make(map[string]int64, len(j.stats.Purged))
+	for name, count := range j.stats.Purged {
+		purged[name] = count
+	}
+
+	return JanitorStats{Runs: j.stats.Runs, LastRun: j.stats.LastRun, Purged: purged}
+}
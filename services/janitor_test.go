@@ -0,0 +1,35 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"auth-service/metrics"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestJanitorCountsPurgedRecords(t *testing.T) {
+	j := &Janitor{batchSize: 10, stats: JanitorStats{Purged: make(map[string]int64)}}
+
+	// Três lotes: dois cheios e um parcial, que encerra a tarefa
+	batches := []int64{10, 10, 3}
+	j.Register("test_records", func(ctx context.Context, before time.Time, limit int) (int64, error) {
+		purged := batches[0]
+		batches = batches[1:]
+		return purged, nil
+	})
+
+	counter := metrics.TokensPurged.WithLabelValues("test_records")
+	start := testutil.ToFloat64(counter)
+
+	j.RunOnce(context.Background())
+
+	if got := testutil.ToFloat64(counter) - start; got != 23 {
+		t.Errorf("tokens_purged_total{table=test_records} aumentou %v, esperado 23", got)
+	}
+	if stats := j.Stats(); stats.Purged["test_records"] != 23 || stats.Runs != 1 {
+		t.Errorf("stats = %+v", stats)
+	}
+}
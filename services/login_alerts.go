@@ -0,0 +1,270 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"auth-service/config"
+	"auth-service/mailer"
+	"auth-service/models"
+	"auth-service/store"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// NewDeviceNotifier é avisado quando um usuário entra por um dispositivo que ainda não conhecíamos
+type NewDeviceNotifier interface {
+	NotifyNewDevice(ctx context.Context, user *models.User, device *models.KnownDevice)
+}
+
+// SetDeviceNotifier registra quem avisa o usuário sobre logins em dispositivos novos. O
+// registro é feito depois da construção porque o notificador depende do próprio AuthService.
+func (s *AuthService) SetDeviceNotifier(notifier NewDeviceNotifier) {
+	s.deviceNotifier = notifier
+}
+
+// trackDevice registra o dispositivo do login e avisa o usuário quando ele é novo. O
+// primeiro dispositivo de cada conta só forma a base e não gera aviso. Falhas aqui não
+// impedem o login.
+func (s *AuthService) trackDevice(ctx context.Context, user *models.User, clientID string) {
+	if !s.cfg.LoginAlerts.Enabled {
+		return
+	}
+
+	info := requestInfoFrom(ctx)
+	prefix := ipPrefix(info.IP)
+	if prefix == "" {
+		return
+	}
+
+	userAgent := info.UserAgent
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+
+	now := time.Now()
+	device := &models.KnownDevice{
+		ID:          uuid.New(),
+		UserID:      user.ID,
+		Fingerprint: deviceFingerprint(userAgent, prefix),
+		UserAgent:   userAgent,
+		IPPrefix:    prefix,
+		FirstSeenAt: now,
+		LastSeenAt:  now,
+	}
+
+	isNew, err := s.knownDevices.TouchKnownDevice(ctx, device)
+	if err != nil {
+		slog.ErrorContext(ctx, "Erro ao registrar dispositivo do login", "user_id", user.ID, "error", err)
+		return
+	}
+	if !isNew {
+		return
+	}
+
+	count, err := s.knownDevices.CountKnownDevices(ctx, user.ID.String())
+	if err != nil {
+		slog.ErrorContext(ctx, "Erro ao contar dispositivos do usuário", "user_id", user.ID, "error", err)
+		return
+	}
+	if count <= 1 {
+		return
+	}
+
+	s.recordEvent(ctx, &models.AuthEvent{
+		Type:     models.EventNewDeviceLogin,
+		UserID:   user.ID.String(),
+		ClientID: clientID,
+	}, nil)
+
+	if s.deviceNotifier != nil {
+		s.deviceNotifier.NotifyNewDevice(ctx, user, device)
+	}
+}
+
+// ipPrefix reduz o IP à rede de origem (/24 no IPv4, /48 no IPv6); retorna vazio se o IP for inválido
+func ipPrefix(ip string) string {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return ""
+	}
+	if v4 := parsed.To4(); v4 != nil {
+		return (&net.IPNet{IP: v4.Mask(net.CIDRMask(24, 32)), Mask: net.CIDRMask(24, 32)}).String()
+	}
+	return (&net.IPNet{IP: parsed.Mask(net.CIDRMask(48, 128)), Mask: net.CIDRMask(48, 128)}).String()
+}
+
+func deviceFingerprint(userAgent, prefix string) string {
+	sum := sha256.Sum256([]byte(userAgent + "|" + prefix))
+	return hex.EncodeToString(sum[:])
+}
+
+// LoginAlertService avisa por email sobre logins em dispositivos novos e trata a denúncia
+// feita pelo link "não fui eu". Como no link de redefinição, o token carrega uma impressão
+// do hash da senha e deixa de valer quando a senha muda, o que a própria denúncia faz.
+type LoginAlertService struct {
+	authService    *AuthService
+	passwordResets *PasswordResetService
+	mailer         mailer.Mailer
+	ttl            time.Duration
+	reportURL      string
+}
+
+func NewLoginAlertService(authService *AuthService, cfg *config.Config, m mailer.Mailer, passwordResets *PasswordResetService) *LoginAlertService {
+	return &LoginAlertService{
+		authService:    authService,
+		passwordResets: passwordResets,
+		mailer:         m,
+		ttl:            time.Duration(cfg.LoginAlerts.ReportTTLHours) * time.Hour,
+		reportURL:      cfg.LoginAlerts.ReportURL,
+	}
+}
+
+// NotifyNewDevice envia o aviso em segundo plano, para não atrasar o login
+func (s *LoginAlertService) NotifyNewDevice(ctx context.Context, user *models.User, device *models.KnownDevice) {
+	token, err := s.newToken(user, device)
+	if err != nil {
+		slog.ErrorContext(ctx, "Erro ao gerar link de denúncia de acesso", "user_id", user.ID, "error", err)
+		return
+	}
+
+	userAgent := device.UserAgent
+	if userAgent == "" {
+		userAgent = "desconhecido"
+	}
+
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Novo acesso à sua conta",
+		Body: fmt.Sprintf("Olá, %s.\n\nSua conta foi acessada de um dispositivo que ainda não conhecíamos.\n\nQuando: %s\nNavegador: %s\nRede: %s\n\nSe foi você, não é preciso fazer nada. Se não foi, use o link abaixo: todas as sessões serão encerradas e será preciso definir uma nova senha.\n\n%s\n",
+			user.Name, device.LastSeenAt.UTC().Format("02/01/2006 15:04 MST"), userAgent, device.IPPrefix, s.link(token)),
+	}
+
+	sendCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), magicLinkSendTimeout)
+	go func() {
+		defer cancel()
+		if err := s.mailer.Send(sendCtx, msg); err != nil {
+			slog.ErrorContext(sendCtx, "Erro ao enviar aviso de novo acesso", "user_id", user.ID, "error", err)
+		}
+	}()
+}
+
+// ReportLogin trata a denúncia de um acesso não reconhecido: remove a senha atual, encerra
+// todas as sessões e tokens de acesso pessoal, cancela as autorizações de dispositivo,
+// esquece o dispositivo e envia o link para definir uma nova senha
+func (s *LoginAlertService) ReportLogin(ctx context.Context, req *models.ReportLoginRequest) (err error) {
+	event := &models.AuthEvent{Type: models.EventLoginReported}
+	defer func() { s.authService.recordEvent(ctx, event, err) }()
+
+	claims, err := s.parseToken(req.Token)
+	if err != nil {
+		return err
+	}
+
+	userID, _ := claims["sub"].(string)
+	event.UserID = userID
+
+	user, err := s.authService.users.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return ErrInvalidLoginReport
+		}
+		return fmt.Errorf("erro ao buscar usuário: %w", err)
+	}
+
+	// A senha mudou depois do aviso: a denúncia já foi feita ou o usuário já trocou a senha
+	if claims["pwd"] != passwordFingerprint(user.Password) {
+		return ErrInvalidLoginReport
+	}
+
+	// Sem senha local, o login fica bloqueado até o usuário usar o link de redefinição. A
+	// senha removida vai para o histórico e não pode ser escolhida de novo.
+	if err := s.authService.setPassword(ctx, user, ""); err != nil {
+		return err
+	}
+
+	// Encerrar tudo o que o intruso pode ter criado: sessões, tokens de acesso pessoal e
+	// dispositivos aprovados. Access tokens JWT já emitidos valem até expirar.
+	if _, err := s.authService.tokens.RevokeUserRefreshTokens(ctx, user.ID.String()); err != nil {
+		return fmt.Errorf("erro ao revogar sessões: %w", err)
+	}
+	if _, err := s.authService.personalTokens.RevokeUserPersonalTokens(ctx, user.ID.String()); err != nil {
+		return fmt.Errorf("erro ao revogar tokens de acesso pessoal: %w", err)
+	}
+	if _, err := s.authService.deviceCodes.DeleteUserDeviceCodes(ctx, user.ID.String()); err != nil {
+		return fmt.Errorf("erro ao remover autorizações de dispositivo: %w", err)
+	}
+
+	// O dispositivo esquecido volta a gerar aviso se for usado de novo
+	if deviceID, _ := claims["device"].(string); deviceID != "" {
+		if err := s.authService.knownDevices.DeleteKnownDevice(ctx, user.ID.String(), deviceID); err != nil && !errors.Is(err, store.ErrNotFound) {
+			return fmt.Errorf("erro ao remover dispositivo: %w", err)
+		}
+	}
+
+	if !user.Active {
+		return nil
+	}
+
+	token, err := s.passwordResets.newToken(user, s.passwordResets.ttl)
+	if err != nil {
+		return err
+	}
+
+	s.passwordResets.send(ctx, user, mailer.Message{
+		To:      user.Email,
+		Subject: "Defina uma nova senha",
+		Body: fmt.Sprintf("Olá, %s.\n\nRecebemos sua denúncia de um acesso não reconhecido. Encerramos todas as sessões e sua senha anterior deixou de valer.\n\nUse o link abaixo para definir uma nova senha. Ele vale por %d minutos; depois disso, peça outro em \"Esqueci minha senha\".\n\n%s\n",
+			user.Name, int(s.passwordResets.ttl.Minutes()), s.passwordResets.link(token)),
+	})
+	return nil
+}
+
+func (s *LoginAlertService) newToken(user *models.User, device *models.KnownDevice) (string, error) {
+	now := time.Now()
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"type":   "login_report",
+		"sub":    user.ID.String(),
+		"device": device.ID.String(),
+		"pwd":    passwordFingerprint(user.Password),
+		"iat":    now.Unix(),
+		"exp":    now.Add(s.ttl).Unix(),
+	}).SignedString([]byte(s.authService.cfg.JWT.Secret))
+}
+
+func (s *LoginAlertService) parseToken(tokenString string) (jwt.MapClaims, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("método de assinatura inesperado: %v", token.Header["alg"])
+		}
+		return []byte(s.authService.cfg.JWT.Secret), nil
+	}, jwt.WithExpirationRequired())
+	if err != nil || !token.Valid {
+		return nil, ErrInvalidLoginReport
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || claims["type"] != "login_report" {
+		return nil, ErrInvalidLoginReport
+	}
+
+	return claims, nil
+}
+
+// link monta a URL do frontend que recebe o token
+func (s *LoginAlertService) link(token string) string {
+	separator := "?"
+	if strings.Contains(s.reportURL, "?") {
+		separator = "&"
+	}
+	return s.reportURL + separator + url.Values{"token": {token}}.Encode()
+}
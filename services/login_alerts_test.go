@@ -0,0 +1,230 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"auth-service/models"
+	"auth-service/store"
+)
+
+const (
+	browserA = "Mozilla/5.0 (X11; Linux x86_64) Firefox/131.0"
+	browserB = "Mozilla/5.0 (iPhone; CPU iPhone OS 18_0) Safari/604.1"
+)
+
+// newLoginAlertTestService prepara o serviço com os avisos de novo dispositivo ligados
+func newLoginAlertTestService(t *testing.T) (*LoginAlertService, *AuthService, *capturingMailer, *store.Stores) {
+	t.Helper()
+
+	s, stores := newTestAuthService(t)
+	s.cfg.LoginAlerts.Enabled = true
+	s.cfg.LoginAlerts.ReportTTLHours = 24
+	s.cfg.LoginAlerts.ReportURL = "https://app.example.com/nao-fui-eu?via=email"
+	s.cfg.PasswordReset.TTLMinutes = 30
+	s.cfg.PasswordReset.LinkURL = "https://app.example.com/senha?via=email"
+	m := &capturingMailer{}
+
+	alerts := NewLoginAlertService(s, s.cfg, m, NewPasswordResetService(s, s.cfg, m))
+	s.SetDeviceNotifier(alerts)
+	return alerts, s, m, stores
+}
+
+// loginFrom entra com a conta de teste a partir do IP e navegador informados
+func loginFrom(t *testing.T, s *AuthService, client *models.Client, ip, userAgent string) *models.TokenResponse {
+	t.Helper()
+
+	ctx := WithRequestInfo(context.Background(), RequestInfo{IP: ip, UserAgent: userAgent})
+	tokens, err := s.Login(ctx, &models.LoginRequest{Email: "ana@example.com", Password: testPassword, ClientID: client.ID.String(), WithRefreshToken: true})
+	if err != nil {
+		t.Fatalf("login de %s: %v", ip, err)
+	}
+	return tokens
+}
+
+// waitForMessages espera os emails enviados em segundo plano e confere que não chegam outros
+func waitForMessages(t *testing.T, m *capturingMailer, want int) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		m.mu.Lock()
+		count := len(m.messages)
+		m.mu.Unlock()
+		if count > want {
+			t.Fatalf("%d emails enviados, esperado %d", count, want)
+		}
+		if count == want {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d emails enviados, esperado %d", count, want)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.messages) != want {
+		t.Fatalf("%d emails enviados, esperado %d", len(m.messages), want)
+	}
+}
+
+func TestIPPrefix(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want string
+	}{
+		{"203.0.113.7", "203.0.113.0/24"},
+		{"203.0.113.250", "203.0.113.0/24"},
+		{"::ffff:203.0.113.7", "203.0.113.0/24"},
+		{"2001:db8:1234:5678::1", "2001:db8:1234::/48"},
+		{"", ""},
+		{"invalido", ""},
+	}
+	for _, tt := range tests {
+		if got := ipPrefix(tt.ip); got != tt.want {
+			t.Errorf("ipPrefix(%q) = %q, esperado %q", tt.ip, got, tt.want)
+		}
+	}
+}
+
+func TestNewDeviceDetection(t *testing.T) {
+	_, s, m, stores := newLoginAlertTestService(t)
+	client := newTestClient(t, s)
+	register(t, s, "ana@example.com")
+
+	steps := []struct {
+		name      string
+		ip        string
+		userAgent string
+		alert     bool
+	}{
+		{"primeiro dispositivo", "203.0.113.7", browserA, false},
+		{"mesmo dispositivo", "203.0.113.7", browserA, false},
+		{"mesma rede, outro IP", "203.0.113.99", browserA, false},
+		{"outro navegador", "203.0.113.7", browserB, true},
+		{"outra rede", "198.51.100.20", browserA, true},
+		{"sem IP", "", browserB, false},
+	}
+	alerts := 0
+	for _, step := range steps {
+		loginFrom(t, s, client, step.ip, step.userAgent)
+		if step.alert {
+			alerts++
+		}
+
+		events, _, _ := stores.Events.ListEvents(context.Background(), store.EventFilter{EventType: models.EventNewDeviceLogin, Limit: 10})
+		if len(events) != alerts {
+			t.Fatalf("%s: %d eventos de novo dispositivo, esperado %d", step.name, len(events), alerts)
+		}
+	}
+	waitForMessages(t, m, alerts)
+
+	// Com os avisos desligados, nem o dispositivo é registrado
+	s.cfg.LoginAlerts.Enabled = false
+	loginFrom(t, s, client, "192.0.2.1", browserB)
+	waitForMessages(t, m, alerts)
+}
+
+func TestReportLoginRevokesEverything(t *testing.T) {
+	alerts, s, m, stores := newLoginAlertTestService(t)
+	s.cfg.DeviceAuth.TTLMinutes = 10
+	s.cfg.DeviceAuth.IntervalSeconds = 5
+	client := newTestClient(t, s)
+	ctx := context.Background()
+	user := register(t, s, "ana@example.com")
+
+	loginFrom(t, s, client, "203.0.113.7", browserA)
+	session := loginFrom(t, s, client, "198.51.100.20", browserB)
+	report := waitForLink(t, m)
+
+	personal, err := s.CreatePersonalToken(ctx, user.ID.String(), &models.CreatePersonalTokenRequest{Name: "ci", Scope: "read"})
+	if err != nil {
+		t.Fatalf("token pessoal: %v", err)
+	}
+	device, err := s.StartDeviceAuthorization(ctx, &models.DeviceAuthorizationRequest{ClientID: client.ID.String()})
+	if err != nil {
+		t.Fatalf("dispositivo: %v", err)
+	}
+	if err := s.VerifyDevice(ctx, user.ID.String(), nil, &models.DeviceVerificationRequest{UserCode: device.UserCode, Action: "approve"}); err != nil {
+		t.Fatalf("aprovação: %v", err)
+	}
+
+	if err := alerts.ReportLogin(ctx, &models.ReportLoginRequest{Token: report}); err != nil {
+		t.Fatalf("denúncia: %v", err)
+	}
+	waitForMessages(t, m, 2)
+
+	checks := []struct {
+		name    string
+		op      func() error
+		wantErr error
+	}{
+		{"senha anterior", func() error {
+			_, err := s.Login(ctx, &models.LoginRequest{Email: "ana@example.com", Password: testPassword, ClientID: client.ID.String()})
+			return err
+		}, ErrInvalidCredentials},
+		{"refresh token", func() error {
+			_, err := s.RefreshToken(ctx, &models.RefreshTokenRequest{RefreshToken: session.RefreshToken, ClientID: client.ID.String()})
+			return err
+		}, ErrRefreshTokenRevoked},
+		{"token de acesso pessoal", func() error {
+			_, err := s.ValidateToken(ctx, personal.Token, client.ID.String())
+			return err
+		}, ErrInvalidToken},
+		{"dispositivo aprovado", func() error {
+			_, err := s.ExchangeDeviceCode(ctx, &models.TokenRequest{GrantType: models.GrantTypeDeviceCode, DeviceCode: device.DeviceCode, ClientID: client.ID.String()})
+			return err
+		}, ErrInvalidDeviceCode},
+		{"mesmo link de novo", func() error {
+			return alerts.ReportLogin(ctx, &models.ReportLoginRequest{Token: report})
+		}, ErrInvalidLoginReport},
+	}
+	for _, tt := range checks {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.op(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("erro %v, esperado %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// O dispositivo denunciado foi esquecido; o primeiro continua conhecido
+	count, err := stores.KnownDevices.CountKnownDevices(ctx, user.ID.String())
+	if err != nil || count != 1 {
+		t.Errorf("dispositivos conhecidos = %d, %v; esperado 1", count, err)
+	}
+}
+
+func TestReportLoginRejectsInvalidTokens(t *testing.T) {
+	alerts, s, m, _ := newLoginAlertTestService(t)
+	client := newTestClient(t, s)
+	register(t, s, "ana@example.com")
+
+	loginFrom(t, s, client, "203.0.113.7", browserA)
+	session := loginFrom(t, s, client, "198.51.100.20", browserB)
+	report := waitForLink(t, m)
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"assinatura adulterada", report[:len(report)-2] + "xx"},
+		{"access token no lugar do link", session.AccessToken},
+		{"vazio", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := alerts.ReportLogin(context.Background(), &models.ReportLoginRequest{Token: tt.token})
+			if !errors.Is(err, ErrInvalidLoginReport) {
+				t.Errorf("erro %v, esperado %v", err, ErrInvalidLoginReport)
+			}
+		})
+	}
+
+	// As tentativas recusadas não mexem na conta
+	loginFrom(t, s, client, "203.0.113.7", browserA)
+}
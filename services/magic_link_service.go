@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"auth-service/config"
+	"auth-service/mailer"
+	"auth-service/metrics"
+	"auth-service/models"
+	"auth-service/store"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// magicLinkSendTimeout limita o envio do email, que acontece fora da requisição
+const magicLinkSendTimeout = 30 * time.Second
+
+// MagicLinkService autentica usuários por um link de uso único enviado por email
+type MagicLinkService struct {
+	authService *AuthService
+	mailer      mailer.Mailer
+	magicLinks  store.MagicLinkStore
+	ttl         time.Duration
+	linkURL     string
+}
+
+func NewMagicLinkService(authService *AuthService, cfg *config.Config, m mailer.Mailer, magicLinks store.MagicLinkStore) *MagicLinkService {
+	return &MagicLinkService{
+		authService: authService,
+		mailer:      m,
+		magicLinks:  magicLinks,
+		ttl:         time.Duration(cfg.MagicLink.TTLMinutes) * time.Minute,
+		linkURL:     cfg.MagicLink.LinkURL,
+	}
+}
+
+// RequestMagicLink envia o link de acesso para o email informado. Para não revelar quais
+// emails têm conta, o resultado é o mesmo quando o usuário não existe ou está inativo.
+func (s *MagicLinkService) RequestMagicLink(ctx context.Context, req *models.MagicLinkRequest) (err error) {
+	event := &models.AuthEvent{Type: models.EventMagicLinkRequested, ClientID: req.ClientID}
+	defer func() { s.authService.recordEvent(ctx, event, err) }()
+
+	client, err := s.getEnabledClient(ctx, req.ClientID)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.authService.users.GetUserByEmail(ctx, client.TenantID.String(), req.Email)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("erro ao buscar usuário: %w", err)
+	}
+	event.UserID = user.ID.String()
+
+	if !user.Active {
+		return nil
+	}
+
+	now := time.Now()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"type":      "magic_link",
+		"sub":       user.ID.String(),
+		"client_id": req.ClientID,
+		"jti":       uuid.NewString(),
+		"iat":       now.Unix(),
+		"exp":       now.Add(s.ttl).Unix(),
+	}).SignedString([]byte(s.authService.cfg.JWT.Secret))
+	if err != nil {
+		return fmt.Errorf("erro ao gerar link de acesso: %w", err)
+	}
+
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Seu link de acesso",
+		Body: fmt.Sprintf("Olá, %s.\n\nUse o link abaixo para entrar. Ele vale por %d minutos e só pode ser usado uma vez.\n\n%s\n\nSe você não pediu este link, ignore este email.\n",
+			user.Name, int(s.ttl.Minutes()), s.link(token)),
+	}
+
+	// O envio acontece em segundo plano para que o tempo de resposta não indique se a conta existe
+	sendCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), magicLinkSendTimeout)
+	go func() {
+		defer cancel()
+		if err := s.mailer.Send(sendCtx, msg); err != nil {
+			slog.ErrorContext(sendCtx, "Erro ao enviar link de acesso", "user_id", user.ID, "error", err)
+		}
+	}()
+
+	return nil
+}
+
+// ConsumeMagicLink troca o token do link pelos tokens de acesso. Cada link só pode ser
+// usado uma vez e apenas pelo client que o pediu.
+func (s *MagicLinkService) ConsumeMagicLink(ctx context.Context, req *models.ConsumeMagicLinkRequest) (_ *models.TokenResponse, err error) {
+	event := &models.AuthEvent{Type: models.EventMagicLinkLogin, ClientID: req.ClientID}
+	defer func() {
+		s.authService.recordEvent(ctx, event, err)
+		metrics.Logins.WithLabelValues(outcomeOf(err)).Inc()
+	}()
+
+	client, err := s.getEnabledClient(ctx, req.ClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := s.parseMagicLink(req.Token, req.ClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, _ := claims["sub"].(string)
+	event.UserID = userID
+
+	jti, _ := claims["jti"].(string)
+	expiresAt, err := claims.GetExpirationTime()
+	if err != nil || jti == "" {
+		return nil, ErrInvalidMagicLink
+	}
+
+	// Registrar o uso antes de emitir tokens impede que duas requisições concorrentes usem o mesmo link
+	if err := s.magicLinks.UseMagicLink(ctx, jti, expiresAt.Time); err != nil {
+		if errors.Is(err, store.ErrDuplicate) {
+			return nil, ErrMagicLinkUsed
+		}
+		return nil, err
+	}
+
+	user, err := s.authService.users.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, ErrInvalidMagicLink
+		}
+		return nil, fmt.Errorf("erro ao buscar usuário: %w", err)
+	}
+
+	if user.TenantID != client.TenantID {
+		return nil, ErrInvalidMagicLink
+	}
+
+	if !user.Active {
+		return nil, ErrUserInactive
+	}
+
+	accessToken, err := s.authService.generateAccessToken(*user, req.ClientID, "", time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gerar access token: %w", err)
+	}
+
+	return &models.TokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: "",
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(s.authService.cfg.JWT.ExpirationHours * 3600),
+	}, nil
+}
+
+// getEnabledClient busca o client ativo e verifica se ele aceita login por link
+func (s *MagicLinkService) getEnabledClient(ctx context.Context, clientID string) (*models.Client, error) {
+	client, err := s.authService.getActiveClient(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !client.MagicLinkEnabled {
+		return nil, ErrMagicLinkDisabled
+	}
+
+	return client, nil
+}
+
+func (s *MagicLinkService) parseMagicLink(tokenString, clientID string) (jwt.MapClaims, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("método de assinatura inesperado: %v", token.Header["alg"])
+		}
+		return []byte(s.authService.cfg.JWT.Secret), nil
+	}, jwt.WithExpirationRequired())
+	if err != nil || !token.Valid {
+		return nil, ErrInvalidMagicLink
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || claims["type"] != "magic_link" || claims["client_id"] != clientID {
+		return nil, ErrInvalidMagicLink
+	}
+
+	return claims, nil
+}
+
+// link monta a URL do frontend que recebe o token
+func (s *MagicLinkService) link(token string) string {
+	separator := "?"
+	if strings.Contains(s.linkURL, "?") {
+		separator = "&"
+	}
+	return s.linkURL + separator + url.Values{"token": {token}}.Encode()
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"auth-service/models"
+)
+
+// newMagicLinkTestService prepara o serviço com um client que aceita login por link
+func newMagicLinkTestService(t *testing.T) (*MagicLinkService, *AuthService, *capturingMailer, *models.Client) {
+	t.Helper()
+
+	s, stores := newTestAuthService(t)
+	s.cfg.MagicLink.TTLMinutes = 15
+	s.cfg.MagicLink.LinkURL = "https://app.example.com/login?via=email"
+	m := &capturingMailer{}
+
+	client := newTestClient(t, s)
+	enabled := true
+	if _, err := s.UpdateClient(context.Background(), models.DefaultTenantID, client.ID.String(), &models.UpdateClientRequest{MagicLinkEnabled: &enabled}); err != nil {
+		t.Fatalf("erro ao habilitar link de acesso: %v", err)
+	}
+	return NewMagicLinkService(s, s.cfg, m, stores.MagicLinks), s, m, client
+}
+
+// waitForLink espera o email enviado em segundo plano e extrai o token do link
+func waitForLink(t *testing.T, m *capturingMailer) string {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		m.mu.Lock()
+		count := len(m.messages)
+		var body string
+		if count > 0 {
+			body = m.messages[count-1].Body
+		}
+		m.mu.Unlock()
+
+		for _, field := range strings.Fields(body) {
+			if link, err := url.Parse(field); err == nil && link.Query().Get("token") != "" {
+				if link.Query().Get("via") != "email" {
+					t.Errorf("link perdeu a query da página: %s", field)
+				}
+				return link.Query().Get("token")
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("email com o link não enviado")
+	return ""
+}
+
+func TestMagicLinkIsSingleUse(t *testing.T) {
+	magicLinks, _, m, client := newMagicLinkTestService(t)
+	ctx := context.Background()
+	register(t, magicLinks.authService, "ana@example.com")
+
+	if err := magicLinks.RequestMagicLink(ctx, &models.MagicLinkRequest{Email: "ana@example.com", ClientID: client.ID.String()}); err != nil {
+		t.Fatalf("pedido: %v", err)
+	}
+	token := waitForLink(t, m)
+
+	// Duas requisições concorrentes com o mesmo link: só uma recebe tokens
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = magicLinks.ConsumeMagicLink(ctx, &models.ConsumeMagicLinkRequest{Token: token, ClientID: client.ID.String()})
+		}(i)
+	}
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case !errors.Is(err, ErrMagicLinkUsed):
+			t.Errorf("erro %v, esperado %v", err, ErrMagicLinkUsed)
+		}
+	}
+	if succeeded != 1 {
+		t.Fatalf("%d consumos bem-sucedidos, esperado 1", succeeded)
+	}
+
+	if _, err := magicLinks.ConsumeMagicLink(ctx, &models.ConsumeMagicLinkRequest{Token: token, ClientID: client.ID.String()}); !errors.Is(err, ErrMagicLinkUsed) {
+		t.Errorf("reuso: erro %v, esperado %v", err, ErrMagicLinkUsed)
+	}
+}
+
+func TestConsumeMagicLinkRejectsInvalidTokens(t *testing.T) {
+	magicLinks, s, m, client := newMagicLinkTestService(t)
+	ctx := context.Background()
+	register(t, s, "ana@example.com")
+
+	if err := magicLinks.RequestMagicLink(ctx, &models.MagicLinkRequest{Email: "ana@example.com", ClientID: client.ID.String()}); err != nil {
+		t.Fatalf("pedido: %v", err)
+	}
+	token := waitForLink(t, m)
+
+	other := newTestClient(t, s)
+	enabled := true
+	if _, err := s.UpdateClient(ctx, models.DefaultTenantID, other.ID.String(), &models.UpdateClientRequest{MagicLinkEnabled: &enabled}); err != nil {
+		t.Fatalf("client: %v", err)
+	}
+	session, err := s.Login(ctx, &models.LoginRequest{Email: "ana@example.com", Password: testPassword, ClientID: client.ID.String()})
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		token    string
+		clientID string
+		wantErr  error
+	}{
+		{"outro client", token, other.ID.String(), ErrInvalidMagicLink},
+		{"access token no lugar do link", session.AccessToken, client.ID.String(), ErrInvalidMagicLink},
+		{"assinatura adulterada", token[:len(token)-2] + "xx", client.ID.String(), ErrInvalidMagicLink},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := magicLinks.ConsumeMagicLink(ctx, &models.ConsumeMagicLinkRequest{Token: tt.token, ClientID: tt.clientID})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("erro %v, esperado %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// As tentativas recusadas não gastam o link
+	if _, err := magicLinks.ConsumeMagicLink(ctx, &models.ConsumeMagicLinkRequest{Token: token, ClientID: client.ID.String()}); err != nil {
+		t.Errorf("consumo depois das recusas: %v", err)
+	}
+}
+
+func TestRequestMagicLinkDoesNotRevealAccounts(t *testing.T) {
+	magicLinks, _, m, client := newMagicLinkTestService(t)
+
+	if err := magicLinks.RequestMagicLink(context.Background(), &models.MagicLinkRequest{Email: "ninguem@example.com", ClientID: client.ID.String()}); err != nil {
+		t.Fatalf("pedido para email sem conta: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.messages) != 0 {
+		t.Errorf("%d emails enviados para um email sem conta", len(m.messages))
+	}
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"auth-service/metrics"
+	"auth-service/models"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestAuthenticationMetrics(t *testing.T) {
+	s, _ := newTestAuthService(t)
+	client := newTestClient(t, s)
+	register(t, s, "ana@example.com")
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		password string
+		labels   []string
+	}{
+		{"login com sucesso", testPassword, []string{models.OutcomeSuccess, ""}},
+		{"senha errada", "senha-errada-1", []string{models.OutcomeFailure, ErrInvalidCredentials.Code}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logins := metrics.Logins.WithLabelValues(tt.labels...)
+			before := testutil.ToFloat64(logins)
+			s.Login(ctx, &models.LoginRequest{Email: "ana@example.com", Password: tt.password, ClientID: client.ID.String()})
+			if got := testutil.ToFloat64(logins) - before; got != 1 {
+				t.Errorf("logins_total%v cresceu %v, esperado 1", tt.labels, got)
+			}
+		})
+	}
+
+	issued := metrics.TokensIssued.WithLabelValues("access")
+	before := testutil.ToFloat64(issued)
+	tokens, err := s.Login(ctx, &models.LoginRequest{Email: "ana@example.com", Password: testPassword, ClientID: client.ID.String()})
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	if got := testutil.ToFloat64(issued) - before; got != 1 {
+		t.Errorf("tokens_issued_total{type=access} cresceu %v, esperado 1", got)
+	}
+
+	validations := []struct {
+		token  string
+		labels []string
+	}{
+		{tokens.AccessToken, []string{models.OutcomeSuccess, ""}},
+		{"não-é-um-jwt", []string{models.OutcomeFailure, ErrInvalidToken.Code}},
+	}
+	for _, v := range validations {
+		counter := metrics.TokenValidations.WithLabelValues(v.labels...)
+		before := testutil.ToFloat64(counter)
+		s.ValidateToken(ctx, v.token, client.ID.String())
+		if got := testutil.ToFloat64(counter) - before; got != 1 {
+			t.Errorf("token_validations_total%v cresceu %v, esperado 1", v.labels, got)
+		}
+	}
+}
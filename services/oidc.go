@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"auth-service/metrics"
+	"auth-service/models"
+	"auth-service/store"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// supportedScopes lista os escopos OIDC reconhecidos pelo serviço
+var supportedScopes = []string{"openid", "email", "profile"}
+
+// Discovery retorna o documento publicado em /.well-known/openid-configuration
+func (s *AuthService) Discovery() *models.OIDCDiscovery {
+	issuer := s.cfg.OIDC.Issuer
+
+	// Os clients são públicos (SPAs e CLIs): no /oauth/token, o PKCE faz o papel do client_secret
+	return &models.OIDCDiscovery{
+		Issuer:                            issuer,
+		AuthorizationEndpoint:             issuer + "/api/v1/oauth/authorize",
+		TokenEndpoint:                     issuer + "/api/v1/oauth/token",
+		JWKSURI:                           issuer + "/.well-known/jwks.json",
+		UserInfoEndpoint:                  issuer + "/api/v1/userinfo",
+		DeviceAuthorizationEndpoint:       issuer + "/api/v1/oauth/device_authorization",
+		GrantTypesSupported:               []string{models.GrantTypeAuthorizationCode, models.GrantTypeDeviceCode},
+		ResponseTypesSupported:            []string{"code"},
+		CodeChallengeMethodsSupported:     []string{pkceMethodS256},
+		TokenEndpointAuthMethodsSupported: []string{"none"},
+		SubjectTypesSupported:             []string{"public"},
+		IDTokenSigningAlgValuesSupported:  []string{"RS256"},
+		ScopesSupported:                   supportedScopes,
+		ClaimsSupported:                   []string{"iss", "sub", "aud", "exp", "iat", "auth_time", "nonce", "email", "name"},
+	}
+}
+
+// JWKS retorna as chaves públicas usadas para verificar ID tokens, incluindo a anterior
+// à última rotação e a próxima, já publicada
+func (s *AuthService) JWKS() *models.JSONWebKeySet {
+	keys := []models.JSONWebKey{s.signingKey.JWK()}
+	if s.signingKey.Previous != nil {
+		keys = append(keys, s.signingKey.Previous.JWK())
+	}
+	if s.signingKey.Next != nil {
+		keys = append(keys, s.signingKey.Next.JWK())
+	}
+	return &models.JSONWebKeySet{Keys: keys}
+}
+
+// UserInfo retorna as claims do usuário dono do access token, conforme os escopos concedidos
+func (s *AuthService) UserInfo(ctx context.Context, tokenString string) (*models.UserInfoResponse, error) {
+	claims, err := s.parseAccessToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	scope, _ := claims["scope"].(string)
+	if !hasScope(scope, "openid") {
+		return nil, ErrInsufficientScope
+	}
+
+	userID, ok := claims["user_id"].(string)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	user, err := s.users.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, ErrInvalidToken
+		}
+		return nil, fmt.Errorf("erro ao buscar usuário: %w", err)
+	}
+
+	if !user.Active {
+		return nil, ErrUserInactive
+	}
+
+	// O serviço não confirma emails, então email_verified não é informado
+	info := &models.UserInfoResponse{Sub: user.ID.String()}
+	if hasScope(scope, "email") {
+		info.Email = user.Email
+	}
+	if hasScope(scope, "profile") {
+		info.Name = user.Name
+		info.UpdatedAt = user.UpdatedAt.Unix()
+	}
+
+	return info, nil
+}
+
+// generateIDToken emite o ID token para o client, que é a audiência. auth_time só é
+// incluído quando o momento do login é conhecido (não é o caso na renovação da sessão).
+func (s *AuthService) generateIDToken(user models.User, clientID, nonce string, authTime time.Time) (string, error) {
+	if clientID == "" || clientID == defaultClientID {
+		return "", ErrClientRequired
+	}
+
+	now := time.Now()
+
+	claims := jwt.MapClaims{
+		"iss":   s.cfg.OIDC.Issuer,
+		"sub":   user.ID.String(),
+		"aud":   clientID,
+		"email": user.Email,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Duration(s.cfg.JWT.ExpirationHours) * time.Hour).Unix(),
+	}
+	if !authTime.IsZero() {
+		claims["auth_time"] = authTime.Unix()
+	}
+	if nonce != "" {
+		claims["nonce"] = nonce
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = s.signingKey.ID
+
+	signed, err := token.SignedString(s.signingKey.PrivateKey)
+	if err != nil {
+		return "", err
+	}
+
+	metrics.TokensIssued.WithLabelValues("id").Inc()
+	return signed, nil
+}
+
+// normalizeScope remove escopos desconhecidos e duplicados, preservando a ordem
+func normalizeScope(scope string) string {
+	var granted []string
+	for _, requested := range strings.Fields(scope) {
+		for _, supported := range supportedScopes {
+			if requested == supported && !hasScope(strings.Join(granted, " "), requested) {
+				granted = append(granted, requested)
+			}
+		}
+	}
+	return strings.Join(granted, " ")
+}
+
+func hasScope(scope, want string) bool {
+	for _, s := range strings.Fields(scope) {
+		if s == want {
+			return true
+		}
+	}
+	return false
+}
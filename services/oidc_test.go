@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"auth-service/models"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// newOIDCTestService prepara o serviço com chave de assinatura e um client
+func newOIDCTestService(t *testing.T) (*AuthService, *models.Client) {
+	t.Helper()
+
+	s, _ := newTestAuthService(t)
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("erro ao gerar chave: %v", err)
+	}
+	s.signingKey = newSigningKey(key)
+	return s, newTestClient(t, s)
+}
+
+func TestRefreshKeepsScopeAndIssuesIDToken(t *testing.T) {
+	s, client := newOIDCTestService(t)
+	ctx := context.Background()
+	register(t, s, "ana@example.com")
+
+	tokens, err := s.Login(ctx, &models.LoginRequest{
+		Email: "ana@example.com", Password: testPassword, ClientID: client.ID.String(),
+		Scope: "openid profile", Nonce: "n-1", WithRefreshToken: true,
+	})
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	if tokens.IDToken == "" {
+		t.Fatal("login com openid sem ID token")
+	}
+
+	refreshed, err := s.RefreshToken(ctx, &models.RefreshTokenRequest{RefreshToken: tokens.RefreshToken, ClientID: client.ID.String()})
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if refreshed.Scope != "openid profile" || refreshed.IDToken == "" {
+		t.Fatalf("refresh perdeu os escopos: scope=%q id_token=%v", refreshed.Scope, refreshed.IDToken != "")
+	}
+
+	claims := parseIDToken(t, s, refreshed.IDToken)
+	if claims["aud"] != client.ID.String() {
+		t.Errorf("aud = %v, esperado %s", claims["aud"], client.ID)
+	}
+	if _, ok := claims["nonce"]; ok {
+		t.Error("ID token da renovação não deve repetir o nonce do login")
+	}
+}
+
+func TestLoginWithOpenIDRequiresClient(t *testing.T) {
+	s, _ := newOIDCTestService(t)
+	register(t, s, "ana@example.com")
+
+	_, err := s.Login(context.Background(), &models.LoginRequest{Email: "ana@example.com", Password: testPassword, Scope: "openid"})
+	if !errors.Is(err, ErrClientRequired) {
+		t.Fatalf("erro %v, esperado %v", err, ErrClientRequired)
+	}
+}
+
+func TestDiscoveryEndpoints(t *testing.T) {
+	s, _ := newOIDCTestService(t)
+	s.cfg.OIDC.Issuer = "https://auth.example.com"
+
+	discovery := s.Discovery()
+	if discovery.AuthorizationEndpoint != "https://auth.example.com/api/v1/oauth/authorize" ||
+		discovery.TokenEndpoint != "https://auth.example.com/api/v1/oauth/token" {
+		t.Errorf("endpoints: %s %s", discovery.AuthorizationEndpoint, discovery.TokenEndpoint)
+	}
+	if len(discovery.ResponseTypesSupported) != 1 || discovery.ResponseTypesSupported[0] != "code" {
+		t.Errorf("response_types_supported = %v, esperado [code]", discovery.ResponseTypesSupported)
+	}
+}
+
+func TestUserInfoOmitsEmailVerified(t *testing.T) {
+	s, client := newOIDCTestService(t)
+	ctx := context.Background()
+	register(t, s, "ana@example.com")
+
+	tokens, err := s.Login(ctx, &models.LoginRequest{
+		Email: "ana@example.com", Password: testPassword, ClientID: client.ID.String(), Scope: "openid email",
+	})
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+
+	info, err := s.UserInfo(ctx, tokens.AccessToken)
+	if err != nil {
+		t.Fatalf("userinfo: %v", err)
+	}
+	body, _ := json.Marshal(info)
+	if info.Email != "ana@example.com" || strings.Contains(string(body), "email_verified") {
+		t.Errorf("userinfo = %s", body)
+	}
+	if slices.Contains(s.Discovery().ClaimsSupported, "email_verified") {
+		t.Error("discovery anuncia email_verified")
+	}
+}
+
+func parseIDToken(t *testing.T, s *AuthService, idToken string) jwt.MapClaims {
+	t.Helper()
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(idToken, claims, func(token *jwt.Token) (interface{}, error) {
+		return &s.signingKey.PrivateKey.PublicKey, nil
+	}, jwt.WithValidMethods([]string{"RS256"}))
+	if err != nil {
+		t.Fatalf("ID token inválido: %v", err)
+	}
+	return claims
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"auth-service/models"
+	"auth-service/store"
+
+	"github.com/google/uuid"
+)
+
+// organizationSlugPattern aceita slugs como "escola-central" ou "colegio2"
+var organizationSlugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// CreateOrganization cria uma organização (tenant) vazia. Clients e convites são criados
+// depois pelos administradores da própria organização.
+func (s *AuthService) CreateOrganization(ctx context.Context, actorID string, req *models.CreateOrganizationRequest) (_ *models.Organization, err error) {
+	event := &models.AuthEvent{Type: models.EventOrganizationCreated, UserID: actorID, Reason: req.Slug}
+	defer func() { s.recordEvent(ctx, event, err) }()
+
+	slug := strings.TrimSpace(req.Slug)
+	if !organizationSlugPattern.MatchString(slug) {
+		return nil, ErrInvalidRequest.Wrap(fmt.Errorf("slug inválido: %q", slug))
+	}
+
+	now := time.Now()
+	organization := &models.Organization{
+		ID:        uuid.New(),
+		Slug:      slug,
+		Name:      strings.TrimSpace(req.Name),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := s.organizations.CreateOrganization(ctx, organization); err != nil {
+		if errors.Is(err, store.ErrDuplicate) {
+			return nil, ErrOrganizationExists
+		}
+		return nil, fmt.Errorf("erro ao criar organização: %w", err)
+	}
+
+	return organization, nil
+}
+
+// ListOrganizations lista todas as organizações, por slug
+func (s *AuthService) ListOrganizations(ctx context.Context) ([]models.Organization, error) {
+	return s.organizations.ListOrganizations(ctx)
+}
+
+// GetOrganizationBySlug busca a organização pelo slug
+func (s *AuthService) GetOrganizationBySlug(ctx context.Context, slug string) (*models.Organization, error) {
+	organization, err := s.organizations.GetOrganizationBySlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, ErrOrganizationNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar organização: %w", err)
+	}
+	return organization, nil
+}
+
+// tenantForClient retorna a organização do client informado. Sem client (ou com o client
+// padrão), vale a organização padrão.
+func (s *AuthService) tenantForClient(ctx context.Context, clientID string) (string, error) {
+	if clientID == "" || clientID == defaultClientID {
+		return models.DefaultTenantID, nil
+	}
+
+	client, err := s.getActiveClient(ctx, clientID)
+	if err != nil {
+		return "", err
+	}
+	return client.TenantID.String(), nil
+}
+
+// tokenTenant lê o claim tenant_id. Tokens emitidos antes da separação por organização
+// não têm o claim e pertencem à organização padrão.
+func tokenTenant(claims map[string]interface{}) string {
+	if tenantID, ok := claims["tenant_id"].(string); ok && tenantID != "" {
+		return tenantID
+	}
+	return models.DefaultTenantID
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"auth-service/models"
+)
+
+// tenantFixture tem o mesmo email cadastrado na organização padrão e em outra
+type tenantFixture struct {
+	org            *models.Organization
+	defaultClient  *models.Client
+	orgClient      *models.Client
+	defaultUser    *models.UserResponse
+	orgUser        *models.UserResponse
+	defaultSession *models.TokenResponse
+	orgSession     *models.TokenResponse
+}
+
+func newTenantFixture(t *testing.T, s *AuthService) *tenantFixture {
+	t.Helper()
+	ctx := context.Background()
+
+	org, err := s.CreateOrganization(ctx, "", &models.CreateOrganizationRequest{Slug: "escola", Name: "Escola"})
+	if err != nil {
+		t.Fatalf("organização: %v", err)
+	}
+	f := &tenantFixture{org: org, defaultClient: newTestClient(t, s)}
+	if f.orgClient, err = s.CreateClient(ctx, org.ID.String(), "app da escola", ""); err != nil {
+		t.Fatalf("client da organização: %v", err)
+	}
+
+	f.defaultUser = register(t, s, "ana@example.com")
+	if f.orgUser, err = s.Register(ctx, &models.RegisterRequest{Email: "ana@example.com", Password: testPassword, Name: "Ana", ClientID: f.orgClient.ID.String()}); err != nil {
+		t.Fatalf("registro na organização: %v", err)
+	}
+
+	login := func(client *models.Client) *models.TokenResponse {
+		tokens, err := s.Login(ctx, &models.LoginRequest{Email: "ana@example.com", Password: testPassword, ClientID: client.ID.String(), WithRefreshToken: true})
+		if err != nil {
+			t.Fatalf("login: %v", err)
+		}
+		return tokens
+	}
+	f.defaultSession = login(f.defaultClient)
+	f.orgSession = login(f.orgClient)
+	return f
+}
+
+func TestSameEmailInTwoOrganizations(t *testing.T) {
+	s, _ := newTestAuthService(t)
+	f := newTenantFixture(t, s)
+
+	if f.orgUser.ID == f.defaultUser.ID || f.orgUser.TenantID != f.org.ID {
+		t.Fatalf("usuário da organização = %+v", f.orgUser)
+	}
+
+	_, err := s.Register(context.Background(), &models.RegisterRequest{Email: "ana@example.com", Password: testPassword, Name: "Ana", ClientID: f.orgClient.ID.String()})
+	if !errors.Is(err, ErrEmailInUse) {
+		t.Errorf("registro repetido na organização: erro %v, esperado %v", err, ErrEmailInUse)
+	}
+}
+
+func TestTokensStayInTheirOrganization(t *testing.T) {
+	s, _ := newTestAuthService(t)
+	f := newTenantFixture(t, s)
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		token    string
+		clientID string
+		user     *models.UserResponse
+		wantErr  error
+	}{
+		{"organização no próprio client", f.orgSession.AccessToken, f.orgClient.ID.String(), f.orgUser, nil},
+		{"padrão no próprio client", f.defaultSession.AccessToken, f.defaultClient.ID.String(), f.defaultUser, nil},
+		{"organização no client padrão", f.orgSession.AccessToken, f.defaultClient.ID.String(), nil, ErrTenantMismatch},
+		{"padrão no client da organização", f.defaultSession.AccessToken, f.orgClient.ID.String(), nil, ErrTenantMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, err := s.ValidateToken(ctx, tt.token, tt.clientID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("erro %v, esperado %v", err, tt.wantErr)
+			}
+			if tt.user != nil && user.ID != tt.user.ID {
+				t.Errorf("usuário %s, esperado %s", user.ID, tt.user.ID)
+			}
+
+			introspection, err := s.Introspect(ctx, tt.token, tt.clientID)
+			if err != nil {
+				t.Fatalf("introspecção: %v", err)
+			}
+			if introspection.Active != (tt.wantErr == nil) {
+				t.Errorf("introspecção ativa = %v", introspection.Active)
+			}
+		})
+	}
+
+	_, err := s.RefreshToken(ctx, &models.RefreshTokenRequest{RefreshToken: f.orgSession.RefreshToken, ClientID: f.defaultClient.ID.String()})
+	if !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("refresh em outra organização: erro %v, esperado %v", err, ErrInvalidRefreshToken)
+	}
+}
+
+func TestAdminOperationsStayInTheirOrganization(t *testing.T) {
+	s, _ := newTestAuthService(t)
+	f := newTenantFixture(t, s)
+	ctx := context.Background()
+	orgID := f.org.ID.String()
+
+	tests := []struct {
+		name    string
+		op      func() error
+		wantErr error
+	}{
+		{"papel para usuário da própria organização", func() error { return s.GrantRole(ctx, orgID, "ana@example.com", models.RoleAdmin) }, nil},
+		{"buscar usuário de outra organização", func() error {
+			_, err := s.GetTenantUser(ctx, orgID, f.defaultUser.ID.String())
+			return err
+		}, ErrUserNotFound},
+		{"remover usuário de outra organização", func() error { return s.DeleteUser(ctx, orgID, f.orgUser.ID.String(), f.defaultUser.ID.String()) }, ErrUserNotFound},
+		{"alterar client de outra organização", func() error {
+			_, err := s.UpdateClient(ctx, orgID, f.defaultClient.ID.String(), &models.UpdateClientRequest{})
+			return err
+		}, ErrClientNotFound},
+		{"trocar segredo de client de outra organização", func() error {
+			_, err := s.RotateClientSecret(ctx, orgID, f.defaultClient.ID.String())
+			return err
+		}, ErrClientNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.op(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("erro %v, esperado %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// O papel concedido na organização não vale para a conta de mesmo email na padrão
+	user, err := s.ValidateToken(ctx, f.defaultSession.AccessToken, f.defaultClient.ID.String())
+	if err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if len(user.Roles) != 0 {
+		t.Errorf("papéis na organização padrão = %v", user.Roles)
+	}
+
+	clients, err := s.ListClients(ctx, orgID)
+	if err != nil || len(clients) != 1 || clients[0].ID != f.orgClient.ID {
+		t.Errorf("clients da organização = %+v, %v", clients, err)
+	}
+}
+
+func TestCreateOrganizationValidatesSlug(t *testing.T) {
+	s, _ := newTestAuthService(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		slug    string
+		wantErr error
+	}{
+		{"escola", nil},
+		{"escola", ErrOrganizationExists},
+		{"Bad Slug", ErrInvalidRequest},
+	}
+	for _, tt := range tests {
+		_, err := s.CreateOrganization(ctx, "", &models.CreateOrganizationRequest{Slug: tt.slug, Name: "Escola"})
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("slug %q: erro %v, esperado %v", tt.slug, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package services
+
+import (
+	"context"
+
+	"auth-service/metrics"
+	"auth-service/tracing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// hashPassword gera o hash bcrypt da senha
+func hashPassword(ctx context.Context, password string) (string, error) {
+	_, span := tracing.Start(ctx, "bcrypt.hash")
+	defer span.End()
+	defer metrics.Observe(metrics.BcryptDuration, "hash")()
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	return string(hash), err
+}
+
+// checkPassword compara a senha com o hash bcrypt armazenado
+func checkPassword(ctx context.Context, hash, password string) error {
+	_, span := tracing.Start(ctx, "bcrypt.compare")
+	defer span.End()
+	defer metrics.Observe(metrics.BcryptDuration, "compare")()
+
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+}
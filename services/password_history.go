@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"auth-service/models"
+	"auth-service/store"
+
+	"github.com/google/uuid"
+)
+
+// ChangePassword troca a senha do usuário autenticado, que confirma a senha atual, e
+// encerra as sessões abertas com a senha anterior
+func (s *AuthService) ChangePassword(ctx context.Context, userID string, req *models.ChangePasswordRequest) (err error) {
+	event := &models.AuthEvent{Type: models.EventPasswordChanged, UserID: userID}
+	defer func() { s.recordEvent(ctx, event, err) }()
+
+	user, err := s.users.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return ErrUserNotFound
+		}
+		return fmt.Errorf("erro ao buscar usuário: %w", err)
+	}
+
+	// Sem senha local (conta federada ou senha invalidada), a senha é definida pelo link de redefinição
+	if user.Password == "" {
+		return ErrInvalidCredentials
+	}
+	if err := checkPassword(ctx, user.Password, req.CurrentPassword); err != nil {
+		return ErrInvalidCredentials
+	}
+
+	if err := s.checkPasswordReuse(ctx, user, req.NewPassword); err != nil {
+		return err
+	}
+
+	hashedPassword, err := hashPassword(ctx, req.NewPassword)
+	if err != nil {
+		return fmt.Errorf("erro ao criar hash da senha: %w", err)
+	}
+
+	if err := s.setPassword(ctx, user, hashedPassword); err != nil {
+		return err
+	}
+
+	if _, err := s.tokens.RevokeUserRefreshTokens(ctx, user.ID.String()); err != nil {
+		return fmt.Errorf("erro ao revogar sessões: %w", err)
+	}
+	return nil
+}
+
+// checkPasswordReuse recusa a senha se ela for a atual ou uma das guardadas no histórico.
+// Cada comparação é um bcrypt, por isso o histórico é limitado na configuração.
+func (s *AuthService) checkPasswordReuse(ctx context.Context, user *models.User, password string) error {
+	var hashes []string
+	if user.Password != "" {
+		hashes = append(hashes, user.Password)
+	}
+
+	if s.cfg.Passwords.HistorySize > 0 {
+		previous, err := s.passwords.ListPasswordHistory(ctx, user.ID.String(), s.cfg.Passwords.HistorySize)
+		if err != nil {
+			return fmt.Errorf("erro ao buscar histórico de senhas: %w", err)
+		}
+		hashes = append(hashes, previous...)
+	}
+
+	for _, hash := range hashes {
+		if checkPassword(ctx, hash, password) == nil {
+			return ErrPasswordReused
+		}
+	}
+	return nil
+}
+
+// setPassword grava o novo hash e, na mesma transação, guarda o anterior no histórico,
+// que é podado ao tamanho configurado. Um hash vazio deixa o usuário sem senha local.
+func (s *AuthService) setPassword(ctx context.Context, user *models.User, hash string) error {
+	now := time.Now()
+
+	var previous *models.PasswordHistoryEntry
+	if user.Password != "" && user.Password != hash && s.cfg.Passwords.HistorySize > 0 {
+		previous = &models.PasswordHistoryEntry{
+			ID:           uuid.New(),
+			UserID:       user.ID,
+			PasswordHash: user.Password,
+			CreatedAt:    now,
+		}
+	}
+
+	if err := s.users.UpdateUserPassword(ctx, user.ID, hash, now, previous, s.cfg.Passwords.HistorySize); err != nil {
+		return fmt.Errorf("erro ao atualizar senha: %w", err)
+	}
+	user.Password = hash
+	return nil
+}
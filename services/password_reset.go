@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"auth-service/config"
+	"auth-service/mailer"
+	"auth-service/models"
+	"auth-service/store"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// PasswordResetService envia links para redefinir a senha e aplica a nova senha. O token
+// carrega uma impressão do hash atual, então deixa de valer assim que a senha muda: cada
+// link só pode ser usado uma vez sem que seja preciso guardá-lo.
+type PasswordResetService struct {
+	authService *AuthService
+	mailer      mailer.Mailer
+	ttl         time.Duration
+	inviteTTL   time.Duration
+	linkURL     string
+}
+
+func NewPasswordResetService(authService *AuthService, cfg *config.Config, m mailer.Mailer) *PasswordResetService {
+	return &PasswordResetService{
+		authService: authService,
+		mailer:      m,
+		ttl:         time.Duration(cfg.PasswordReset.TTLMinutes) * time.Minute,
+		inviteTTL:   time.Duration(cfg.PasswordReset.InviteTTLHours) * time.Hour,
+		linkURL:     cfg.PasswordReset.LinkURL,
+	}
+}
+
+// RequestPasswordReset envia o link de redefinição para o email informado. Assim como no
+// link de acesso, o resultado é o mesmo quando o usuário não existe ou está inativo.
+func (s *PasswordResetService) RequestPasswordReset(ctx context.Context, req *models.ForgotPasswordRequest) (err error) {
+	event := &models.AuthEvent{Type: models.EventPasswordResetRequest, ClientID: req.ClientID}
+	defer func() { s.authService.recordEvent(ctx, event, err) }()
+
+	tenantID, err := s.authService.tenantForClient(ctx, req.ClientID)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.authService.users.GetUserByEmail(ctx, tenantID, req.Email)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("erro ao buscar usuário: %w", err)
+	}
+	event.UserID = user.ID.String()
+
+	if !user.Active {
+		return nil
+	}
+
+	token, err := s.newToken(user, s.ttl)
+	if err != nil {
+		return err
+	}
+
+	s.send(ctx, user, mailer.Message{
+		To:      user.Email,
+		Subject: "Redefinição de senha",
+		Body: fmt.Sprintf("Olá, %s.\n\nUse o link abaixo para definir uma nova senha. Ele vale por %d minutos e só pode ser usado uma vez.\n\n%s\n\nSe você não pediu a redefinição, ignore este email; sua senha continua a mesma.\n",
+			user.Name, int(s.ttl.Minutes()), s.link(token)),
+	})
+	return nil
+}
+
+// SendPasswordSetup envia ao usuário recém-criado sem senha o link para defini-la, com a
+// validade mais longa dos convites. O envio é síncrono para que a importação possa
+// informar as falhas.
+func (s *PasswordResetService) SendPasswordSetup(ctx context.Context, user *models.User) error {
+	token, err := s.newToken(user, s.inviteTTL)
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Sua conta foi criada",
+		Body: fmt.Sprintf("Olá, %s.\n\nUma conta foi criada para você. Use o link abaixo para definir sua senha. Ele vale por %d horas e só pode ser usado uma vez.\n\n%s\n",
+			user.Name, int(s.inviteTTL.Hours()), s.link(token)),
+	})
+}
+
+// ResetPassword troca a senha do dono do token e revoga as sessões abertas com a senha anterior
+func (s *PasswordResetService) ResetPassword(ctx context.Context, req *models.ResetPasswordRequest) (err error) {
+	event := &models.AuthEvent{Type: models.EventPasswordReset}
+	defer func() { s.authService.recordEvent(ctx, event, err) }()
+
+	claims, err := s.parseToken(req.Token)
+	if err != nil {
+		return err
+	}
+
+	userID, _ := claims["sub"].(string)
+	event.UserID = userID
+
+	user, err := s.authService.users.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return ErrInvalidPasswordReset
+		}
+		return fmt.Errorf("erro ao buscar usuário: %w", err)
+	}
+
+	// A senha mudou depois da emissão do link: ele já foi usado ou foi substituído
+	if claims["pwd"] != passwordFingerprint(user.Password) {
+		return ErrInvalidPasswordReset
+	}
+
+	if !user.Active {
+		return ErrUserInactive
+	}
+
+	if err := s.authService.checkPasswordReuse(ctx, user, req.Password); err != nil {
+		return err
+	}
+
+	hashedPassword, err := hashPassword(ctx, req.Password)
+	if err != nil {
+		return fmt.Errorf("erro ao criar hash da senha: %w", err)
+	}
+
+	if err := s.authService.setPassword(ctx, user, hashedPassword); err != nil {
+		return err
+	}
+
+	if _, err := s.authService.tokens.RevokeUserRefreshTokens(ctx, user.ID.String()); err != nil {
+		return fmt.Errorf("erro ao revogar sessões: %w", err)
+	}
+	return nil
+}
+
+func (s *PasswordResetService) newToken(user *models.User, ttl time.Duration) (string, error) {
+	now := time.Now()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"type": "password_reset",
+		"sub":  user.ID.String(),
+		"pwd":  passwordFingerprint(user.Password),
+		"iat":  now.Unix(),
+		"exp":  now.Add(ttl).Unix(),
+	}).SignedString([]byte(s.authService.cfg.JWT.Secret))
+	if err != nil {
+		return "", fmt.Errorf("erro ao gerar link de redefinição: %w", err)
+	}
+	return token, nil
+}
+
+func (s *PasswordResetService) parseToken(tokenString string) (jwt.MapClaims, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("método de assinatura inesperado: %v", token.Header["alg"])
+		}
+		return []byte(s.authService.cfg.JWT.Secret), nil
+	}, jwt.WithExpirationRequired())
+	if err != nil || !token.Valid {
+		return nil, ErrInvalidPasswordReset
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || claims["type"] != "password_reset" {
+		return nil, ErrInvalidPasswordReset
+	}
+
+	return claims, nil
+}
+
+// send envia o email em segundo plano, para que o tempo de resposta não indique se a conta existe
+func (s *PasswordResetService) send(ctx context.Context, user *models.User, msg mailer.Message) {
+	sendCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), magicLinkSendTimeout)
+	go func() {
+		defer cancel()
+		if err := s.mailer.Send(sendCtx, msg); err != nil {
+			slog.ErrorContext(sendCtx, "Erro ao enviar link de redefinição de senha", "user_id", user.ID, "error", err)
+		}
+	}()
+}
+
+// link monta a URL do frontend que recebe o token
+func (s *PasswordResetService) link(token string) string {
+	separator := "?"
+	if strings.Contains(s.linkURL, "?") {
+		separator = "&"
+	}
+	return s.linkURL + separator + url.Values{"token": {token}}.Encode()
+}
+
+// passwordFingerprint resume o hash da senha sem expô-lo no token
+func passwordFingerprint(hash string) string {
+	sum := sha256.Sum256([]byte(hash))
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"auth-service/metrics"
+	"auth-service/models"
+	"auth-service/store"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// personalTokenPrefix distingue tokens de acesso pessoal de JWTs sem precisar consultar o banco
+	personalTokenPrefix = "pat_"
+	// personalTokenDisplayLength é quanto do token fica visível nas listagens (ex: "pat_1a2b3c4d")
+	personalTokenDisplayLength = len(personalTokenPrefix) + 8
+	// personalTokenTouchInterval evita uma escrita no banco a cada validação do mesmo token
+	personalTokenTouchInterval = time.Minute
+)
+
+// personalTokenScopes são os escopos que podem ser concedidos a tokens de acesso pessoal
+var personalTokenScopes = []string{models.PersonalTokenScopeRead, models.PersonalTokenScopeWrite}
+
+// CreatePersonalToken cria um token de acesso pessoal para o usuário. O token em claro
+// só é devolvido aqui; depois disso apenas o hash fica guardado.
+func (s *AuthService) CreatePersonalToken(ctx context.Context, userID string, req *models.CreatePersonalTokenRequest) (_ *models.CreatedPersonalToken, err error) {
+	event := &models.AuthEvent{Type: models.EventPersonalTokenCreated, UserID: userID}
+	defer func() { s.recordEvent(ctx, event, err) }()
+
+	owner, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, ErrUnauthenticated
+	}
+
+	scope, err := normalizePersonalTokenScope(req.Scope)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenBytes := make([]byte, 32)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return nil, fmt.Errorf("erro ao gerar token: %w", err)
+	}
+	token := personalTokenPrefix + hex.EncodeToString(tokenBytes)
+
+	now := time.Now()
+	personalToken := models.PersonalAccessToken{
+		ID:        uuid.New(),
+		UserID:    owner,
+		Name:      strings.TrimSpace(req.Name),
+		TokenHash: hashPersonalToken(token),
+		Prefix:    token[:personalTokenDisplayLength],
+		Scope:     scope,
+		CreatedAt: now,
+	}
+	if req.ExpiresInDays > 0 {
+		expiresAt := now.AddDate(0, 0, req.ExpiresInDays)
+		personalToken.ExpiresAt = &expiresAt
+	}
+
+	if err := s.personalTokens.CreatePersonalToken(ctx, &personalToken); err != nil {
+		return nil, fmt.Errorf("erro ao criar token de acesso pessoal: %w", err)
+	}
+	event.Reason = personalToken.Prefix
+
+	metrics.TokensIssued.WithLabelValues("personal").Inc()
+	return &models.CreatedPersonalToken{PersonalAccessToken: personalToken, Token: token}, nil
+}
+
+// ListPersonalTokens lista os tokens de acesso pessoal ativos do usuário, sem o valor do token
+func (s *AuthService) ListPersonalTokens(ctx context.Context, userID string) ([]models.PersonalAccessToken, error) {
+	return s.personalTokens.ListPersonalTokens(ctx, userID)
+}
+
+// RevokePersonalToken revoga um token de acesso pessoal do próprio usuário
+func (s *AuthService) RevokePersonalToken(ctx context.Context, userID, tokenID string) (err error) {
+	event := &models.AuthEvent{Type: models.EventPersonalTokenRevoked, UserID: userID}
+	defer func() { s.recordEvent(ctx, event, err) }()
+
+	if err := s.personalTokens.RevokePersonalToken(ctx, tokenID, userID); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return ErrPersonalTokenNotFound
+		}
+		return fmt.Errorf("erro ao revogar token de acesso pessoal: %w", err)
+	}
+	return nil
+}
+
+// authenticatePersonalToken busca o token pelo hash, verifica se ainda vale e registra o uso.
+// Token desconhecido, revogado ou expirado resulta em ErrInvalidToken.
+func (s *AuthService) authenticatePersonalToken(ctx context.Context, tokenString string) (*models.PersonalAccessToken, *models.User, error) {
+	token, err := s.personalTokens.GetPersonalTokenByHash(ctx, hashPersonalToken(tokenString))
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, nil, ErrInvalidToken
+		}
+		return nil, nil, fmt.Errorf("erro ao buscar token de acesso pessoal: %w", err)
+	}
+
+	now := time.Now()
+	if token.RevokedAt != nil || (token.ExpiresAt != nil && now.After(*token.ExpiresAt)) {
+		return nil, nil, ErrInvalidToken
+	}
+
+	user, err := s.users.GetUserByID(ctx, token.UserID.String())
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, nil, ErrInvalidToken
+		}
+		return nil, nil, fmt.Errorf("erro ao buscar usuário: %w", err)
+	}
+
+	// Falhar ao registrar o último uso não deve negar o acesso
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= personalTokenTouchInterval {
+		if err := s.personalTokens.TouchPersonalToken(ctx, token.ID, now); err != nil {
+			slog.WarnContext(ctx, "Erro ao registrar uso do token de acesso pessoal", "token_id", token.ID, "error", err)
+		}
+	}
+
+	return token, user, nil
+}
+
+func isPersonalToken(tokenString string) bool {
+	return strings.HasPrefix(tokenString, personalTokenPrefix)
+}
+
+func hashPersonalToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// normalizePersonalTokenScope remove duplicados e rejeita escopos desconhecidos ou vazios
+func normalizePersonalTokenScope(scope string) (string, error) {
+	var granted []string
+	for _, requested := range strings.Fields(scope) {
+		known := false
+		for _, supported := range personalTokenScopes {
+			known = known || requested == supported
+		}
+		if !known {
+			return "", ErrInvalidScope.Wrap(fmt.Errorf("escopo desconhecido: %s", requested))
+		}
+		if !hasScope(strings.Join(granted, " "), requested) {
+			granted = append(granted, requested)
+		}
+	}
+	if len(granted) == 0 {
+		return "", ErrInvalidScope
+	}
+	return strings.Join(granted, " "), nil
+}
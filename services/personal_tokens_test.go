@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"auth-service/models"
+
+	"github.com/google/uuid"
+)
+
+func TestNormalizePersonalTokenScope(t *testing.T) {
+	tests := []struct {
+		scope   string
+		want    string
+		wantErr bool
+	}{
+		{"read", "read", false},
+		{"read write", "read write", false},
+		{" write  read write ", "write read", false},
+		{"", "", true},
+		{"admin", "", true},
+		{"read openid", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.scope, func(t *testing.T) {
+			got, err := normalizePersonalTokenScope(tt.scope)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("normalizePersonalTokenScope(%q) = %q, %v; esperado %q (erro: %v)", tt.scope, got, err, tt.want, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidScope) {
+				t.Errorf("erro %v, esperado %v", err, ErrInvalidScope)
+			}
+		})
+	}
+}
+
+func TestPersonalTokenIsStoredHashed(t *testing.T) {
+	s, stores := newTestAuthService(t)
+	ctx := context.Background()
+	user := register(t, s, "ana@example.com")
+
+	created, err := s.CreatePersonalToken(ctx, user.ID.String(), &models.CreatePersonalTokenRequest{Name: " ci ", Scope: "read"})
+	if err != nil {
+		t.Fatalf("criação: %v", err)
+	}
+	if !strings.HasPrefix(created.Token, personalTokenPrefix) || created.Prefix != created.Token[:personalTokenDisplayLength] {
+		t.Errorf("token %q com prefixo %q", created.Token, created.Prefix)
+	}
+
+	stored, err := stores.PersonalTokens.GetPersonalTokenByHash(ctx, hashPersonalToken(created.Token))
+	if err != nil {
+		t.Fatalf("token não encontrado pelo hash: %v", err)
+	}
+	if stored.TokenHash == created.Token || strings.Contains(stored.TokenHash, created.Token[len(personalTokenPrefix):]) {
+		t.Error("token guardado em claro")
+	}
+	if stored.Name != "ci" || stored.Scope != "read" || stored.ExpiresAt != nil {
+		t.Errorf("token guardado = %+v", stored)
+	}
+
+	listed, err := s.ListPersonalTokens(ctx, user.ID.String())
+	if err != nil || len(listed) != 1 || listed[0].TokenHash != stored.TokenHash {
+		t.Fatalf("listagem = %+v, %v", listed, err)
+	}
+}
+
+func TestValidatePersonalToken(t *testing.T) {
+	s, stores := newTestAuthService(t)
+	client := newTestClient(t, s)
+	ctx := context.Background()
+	user := register(t, s, "ana@example.com")
+
+	active, err := s.CreatePersonalToken(ctx, user.ID.String(), &models.CreatePersonalTokenRequest{Name: "ativo", Scope: "read write", ExpiresInDays: 30})
+	if err != nil {
+		t.Fatalf("criação: %v", err)
+	}
+	revoked, err := s.CreatePersonalToken(ctx, user.ID.String(), &models.CreatePersonalTokenRequest{Name: "revogado", Scope: "read"})
+	if err != nil {
+		t.Fatalf("criação: %v", err)
+	}
+	if err := s.RevokePersonalToken(ctx, user.ID.String(), revoked.ID.String()); err != nil {
+		t.Fatalf("revogação: %v", err)
+	}
+
+	// Um token expirado só pode ser criado direto no store
+	expired := personalTokenPrefix + "expirado"
+	past := time.Now().Add(-time.Hour)
+	if err := stores.PersonalTokens.CreatePersonalToken(ctx, &models.PersonalAccessToken{
+		ID: uuid.New(), UserID: user.ID, Name: "expirado", TokenHash: hashPersonalToken(expired),
+		Prefix: expired, Scope: "read", ExpiresAt: &past, CreatedAt: past.Add(-time.Hour),
+	}); err != nil {
+		t.Fatalf("token expirado: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		scope   string
+		wantErr error
+	}{
+		{"ativo", active.Token, "read write", nil},
+		{"revogado", revoked.Token, "", ErrInvalidToken},
+		{"expirado", expired, "", ErrInvalidToken},
+		{"desconhecido", personalTokenPrefix + "desconhecido", "", ErrInvalidToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.ValidateToken(ctx, tt.token, client.ID.String())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("erro %v, esperado %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.ID != user.ID || got.Scope != tt.scope || got.TokenType != models.TokenTypePersonal {
+				t.Errorf("usuário %s escopo %q tipo %q", got.ID, got.Scope, got.TokenType)
+			}
+
+			introspection, err := s.Introspect(ctx, tt.token, client.ID.String())
+			if err != nil || !introspection.Active || introspection.Scope != tt.scope {
+				t.Errorf("introspecção = %+v, %v", introspection, err)
+			}
+		})
+	}
+
+	listed, _ := s.ListPersonalTokens(ctx, user.ID.String())
+	for _, token := range listed {
+		if token.ID == active.ID && token.LastUsedAt == nil {
+			t.Error("uso do token não registrado")
+		}
+	}
+}
+
+func TestRevokePersonalTokenOfAnotherUser(t *testing.T) {
+	s, _ := newTestAuthService(t)
+	ctx := context.Background()
+	ana := register(t, s, "ana@example.com")
+	bia := register(t, s, "bia@example.com")
+
+	created, err := s.CreatePersonalToken(ctx, ana.ID.String(), &models.CreatePersonalTokenRequest{Name: "ci", Scope: "read"})
+	if err != nil {
+		t.Fatalf("criação: %v", err)
+	}
+	if err := s.RevokePersonalToken(ctx, bia.ID.String(), created.ID.String()); !errors.Is(err, ErrPersonalTokenNotFound) {
+		t.Fatalf("erro %v, esperado %v", err, ErrPersonalTokenNotFound)
+	}
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"auth-service/models"
+	"auth-service/store"
+)
+
+// knownRoles são os papéis que podem ser concedidos a usuários
+var knownRoles = map[string]bool{
+	models.RoleAdmin: true,
+}
+
+// GrantRole concede um papel ao usuário com o email informado na organização. Conceder
+// um papel que o usuário já possui não é erro.
+func (s *AuthService) GrantRole(ctx context.Context, tenantID, email, role string) (err error) {
+	event := &models.AuthEvent{Type: models.EventRoleGranted, Reason: role}
+	defer func() { s.recordEvent(ctx, event, err) }()
+
+	if !knownRoles[role] {
+		return ErrUnknownRole
+	}
+
+	user, err := s.getUserByEmail(ctx, tenantID, email)
+	if err != nil {
+		return err
+	}
+	event.UserID = user.ID.String()
+
+	if err := s.users.AddUserRole(ctx, user.ID.String(), role); err != nil && !errors.Is(err, store.ErrDuplicate) {
+		return err
+	}
+	return nil
+}
+
+// RevokeRole remove um papel do usuário com o email informado na organização
+func (s *AuthService) RevokeRole(ctx context.Context, tenantID, email, role string) (err error) {
+	event := &models.AuthEvent{Type: models.EventRoleRevoked, Reason: role}
+	defer func() { s.recordEvent(ctx, event, err) }()
+
+	user, err := s.getUserByEmail(ctx, tenantID, email)
+	if err != nil {
+		return err
+	}
+	event.UserID = user.ID.String()
+
+	if err := s.users.RemoveUserRole(ctx, user.ID.String(), role); err != nil && !errors.Is(err, store.ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+func (s *AuthService) getUserByEmail(ctx context.Context, tenantID, email string) (*models.User, error) {
+	user, err := s.users.GetUserByEmail(ctx, tenantID, email)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar usuário: %w", err)
+	}
+	return user, nil
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"os"
+
+	"auth-service/models"
+)
+
+// SigningKey é a chave RSA usada para assinar ID tokens (RS256). Previous, quando
+// presente, é a chave anterior à última rotação: não assina mais, mas continua publicada
+// no JWKS para que ID tokens já emitidos possam ser verificados. Next é a chave que
+// passará a assinar na próxima rotação: já publicada, para que os clients a tenham em
+// cache antes do primeiro ID token assinado com ela.
+type SigningKey struct {
+	ID         string
+	PrivateKey *rsa.PrivateKey
+	Previous   *SigningKey
+	Next       *SigningKey
+}
+
+// Sufixos dos arquivos das chaves anterior e próxima, ao lado da chave atual
+const (
+	previousKeySuffix = ".previous"
+	nextKeySuffix     = ".next"
+)
+
+// LoadSigningKey carrega a chave de um arquivo PEM (PKCS#1 ou PKCS#8), junto com a
+// anterior e a próxima, se houver. Sem caminho configurado, gera uma chave efêmera, válida
+// apenas enquanto o processo estiver no ar.
+func LoadSigningKey(path string) (*SigningKey, error) {
+	if path == "" {
+		slog.Warn("OIDC_SIGNING_KEY_PATH não configurado, gerando chave de assinatura efêmera")
+		privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao gerar chave de assinatura: %w", err)
+		}
+		return newSigningKey(privateKey), nil
+	}
+
+	key, err := readSigningKey(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := os.Stat(path + previousKeySuffix); err == nil {
+		if key.Previous, err = readSigningKey(path + previousKeySuffix); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := os.Stat(path + nextKeySuffix); err == nil {
+		if key.Next, err = readSigningKey(path + nextKeySuffix); err != nil {
+			return nil, err
+		}
+	}
+
+	return key, nil
+}
+
+// rename é substituído nos testes para simular falhas do sistema de arquivos
+var rename = os.Rename
+
+// RotateSigningKey faz a rotação em etapas. Com uma chave já publicada em path.next, ela
+// passa a assinar (path), a atual vai para path.previous, substituindo a anterior, e uma
+// nova chave é gerada em path.next. Sem path.next, a nova chave é apenas publicada e a
+// atual continua assinando. Os processos em execução só veem a mudança ao serem
+// reiniciados; entre duas rotações, os clients precisam ter atualizado o JWKS.
+func RotateSigningKey(path string) (*SigningKey, error) {
+	if path == "" {
+		return nil, fmt.Errorf("OIDC_SIGNING_KEY_PATH não configurado")
+	}
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gerar chave de assinatura: %w", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao serializar chave de assinatura: %w", err)
+	}
+
+	// Grava em arquivo temporário para não deixar a chave pela metade se algo falhar
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		return nil, fmt.Errorf("erro ao gravar chave de assinatura: %w", err)
+	}
+
+	// Sem chave atual não há tokens a preservar: a nova chave assina desde já
+	if _, err := os.Stat(path); err != nil {
+		if err := rename(tmp, path); err != nil {
+			return nil, fmt.Errorf("erro ao instalar chave de assinatura: %w", err)
+		}
+		return LoadSigningKey(path)
+	}
+
+	if _, err := os.Stat(path + nextKeySuffix); err == nil {
+		if err := rename(path, path+previousKeySuffix); err != nil {
+			os.Remove(tmp)
+			return nil, fmt.Errorf("erro ao preservar chave anterior: %w", err)
+		}
+		if err := rename(path+nextKeySuffix, path); err != nil {
+			// Devolve a chave atual ao lugar, para o serviço não ficar sem chave de assinatura
+			if restoreErr := rename(path+previousKeySuffix, path); restoreErr != nil {
+				err = errors.Join(err, fmt.Errorf("erro ao restaurar a chave atual: %w", restoreErr))
+			}
+			os.Remove(tmp)
+			return nil, fmt.Errorf("erro ao promover a próxima chave: %w", err)
+		}
+	}
+	if err := rename(tmp, path+nextKeySuffix); err != nil {
+		return nil, fmt.Errorf("erro ao publicar a próxima chave: %w", err)
+	}
+
+	return LoadSigningKey(path)
+}
+
+func readSigningKey(path string) (*SigningKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler chave de assinatura: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("chave de assinatura não está em formato PEM")
+	}
+
+	if privateKey, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return newSigningKey(privateKey), nil
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao interpretar chave de assinatura: %w", err)
+	}
+
+	privateKey, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("chave de assinatura deve ser RSA")
+	}
+
+	return newSigningKey(privateKey), nil
+}
+
+func newSigningKey(privateKey *rsa.PrivateKey) *SigningKey {
+	der, _ := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	sum := sha256.Sum256(der)
+
+	return &SigningKey{
+		ID:         base64.RawURLEncoding.EncodeToString(sum[:12]),
+		PrivateKey: privateKey,
+	}
+}
+
+// JWK retorna a parte pública da chave no formato JSON Web Key
+func (k *SigningKey) JWK() models.JSONWebKey {
+	publicKey := k.PrivateKey.PublicKey
+
+	return models.JSONWebKey{
+		Kty: "RSA",
+		Use: "sig",
+		Alg: "RS256",
+		Kid: k.ID,
+		N:   base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+	}
+}
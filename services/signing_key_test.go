@@ -0,0 +1,98 @@
+package services
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func jwksKids(s *AuthService) []string {
+	var kids []string
+	for _, key := range s.JWKS().Keys {
+		kids = append(kids, key.Kid)
+	}
+	return kids
+}
+
+func TestRotateSigningKeyIsStaged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "signing.pem")
+
+	first, err := RotateSigningKey(path)
+	if err != nil {
+		t.Fatalf("primeira chave: %v", err)
+	}
+	if first.Previous != nil || first.Next != nil {
+		t.Fatalf("instalação deve gravar só a chave atual: %+v", first)
+	}
+
+	// A primeira rotação apenas publica a próxima chave; a atual continua assinando
+	staged, err := RotateSigningKey(path)
+	if err != nil {
+		t.Fatalf("publicação: %v", err)
+	}
+	if staged.ID != first.ID || staged.Next == nil || staged.Previous != nil {
+		t.Fatalf("kid=%s next=%v previous=%v, esperado kid=%s com next publicado", staged.ID, staged.Next, staged.Previous, first.ID)
+	}
+
+	s, _ := newTestAuthService(t)
+	s.signingKey = staged
+	if kids := jwksKids(s); len(kids) != 2 || kids[0] != first.ID || kids[1] != staged.Next.ID {
+		t.Errorf("JWKS = %v, esperado [%s %s]", kids, first.ID, staged.Next.ID)
+	}
+
+	// A seguinte passa a assinar com a chave publicada e publica outra
+	promoted, err := RotateSigningKey(path)
+	if err != nil {
+		t.Fatalf("promoção: %v", err)
+	}
+	if promoted.ID != staged.Next.ID || promoted.Previous == nil || promoted.Previous.ID != first.ID || promoted.Next == nil {
+		t.Fatalf("kid=%s previous=%v, esperado kid=%s e previous=%s", promoted.ID, promoted.Previous, staged.Next.ID, first.ID)
+	}
+	if promoted.Next.ID == promoted.ID {
+		t.Error("a nova chave publicada repete a atual")
+	}
+
+	loaded, err := LoadSigningKey(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if loaded.ID != promoted.ID || loaded.Previous.ID != first.ID || loaded.Next.ID != promoted.Next.ID {
+		t.Errorf("chaves recarregadas diferem das gravadas")
+	}
+}
+
+func TestRotateSigningKeyRestoresCurrentKeyWhenPromotionFails(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "signing.pem")
+	if _, err := RotateSigningKey(path); err != nil {
+		t.Fatalf("primeira chave: %v", err)
+	}
+	staged, err := RotateSigningKey(path)
+	if err != nil {
+		t.Fatalf("publicação: %v", err)
+	}
+
+	// Falha só na promoção de path.next para path
+	rename = func(from, to string) error {
+		if from == path+nextKeySuffix {
+			return errors.New("disco cheio")
+		}
+		return os.Rename(from, to)
+	}
+	t.Cleanup(func() { rename = os.Rename })
+
+	if _, err := RotateSigningKey(path); err == nil {
+		t.Fatal("rotação deveria falhar")
+	}
+
+	loaded, err := LoadSigningKey(path)
+	if err != nil {
+		t.Fatalf("chave atual perdida: %v", err)
+	}
+	if loaded.ID != staged.ID || loaded.Next == nil || loaded.Next.ID != staged.Next.ID {
+		t.Errorf("kid=%s next=%v, esperado kid=%s e next=%s", loaded.ID, loaded.Next, staged.ID, staged.Next.ID)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("arquivo temporário não removido: %v", err)
+	}
+}
@@ -0,0 +1,386 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/mail"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"auth-service/models"
+	"auth-service/store"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// maxImportRows limita o tamanho de uma importação, que é processada linha a linha
+const maxImportRows = 5000
+
+// UserImportService importa e exporta os usuários de uma organização em CSV ou JSON
+type UserImportService struct {
+	authService    *AuthService
+	passwordResets *PasswordResetService
+}
+
+func NewUserImportService(authService *AuthService, passwordResets *PasswordResetService) *UserImportService {
+	return &UserImportService{
+		authService:    authService,
+		passwordResets: passwordResets,
+	}
+}
+
+// ImportUsers cria ou atualiza, pelo email, os usuários do arquivo. Cada linha é
+// independente: uma linha inválida não impede as demais e aparece no relatório com o
+// código do erro. Importar o mesmo arquivo de novo não altera nada.
+func (s *UserImportService) ImportUsers(ctx context.Context, tenantID, actorID string, records []models.UserRecord, dryRun bool) (_ *models.UserImportResult, err error) {
+	if len(records) > maxImportRows {
+		return nil, ErrImportTooLarge
+	}
+
+	result := &models.UserImportResult{DryRun: dryRun, Total: len(records), Rows: make([]models.UserImportRow, 0, len(records))}
+	if !dryRun {
+		event := &models.AuthEvent{Type: models.EventUsersImported, UserID: actorID}
+		defer func() {
+			if result != nil {
+				event.Reason = fmt.Sprintf("created=%d updated=%d unchanged=%d failed=%d",
+					result.Created, result.Updated, result.Unchanged, result.Failed)
+			}
+			s.authService.recordEvent(ctx, event, err)
+		}()
+	}
+
+	seen := make(map[string]int, len(records))
+	for i, record := range records {
+		row := models.UserImportRow{Row: i + 1, Email: strings.TrimSpace(record.Email)}
+
+		action, rowErr := s.importRecord(ctx, tenantID, actorID, record, seen, row.Row, dryRun)
+		if rowErr != nil {
+			_, row.Code = outcomeOf(rowErr)
+			row.Error = rowErr.Error()
+		}
+		row.Action = action
+
+		switch action {
+		case models.ImportCreated:
+			result.Created++
+		case models.ImportUpdated:
+			result.Updated++
+		case models.ImportUnchanged:
+			result.Unchanged++
+		default:
+			result.Failed++
+		}
+		result.Rows = append(result.Rows, row)
+	}
+
+	return result, nil
+}
+
+// importRecord aplica uma linha. Um erro com ação diferente de failed indica que a conta
+// foi criada, mas o email para definir a senha não foi enviado.
+func (s *UserImportService) importRecord(ctx context.Context, tenantID, actorID string, record models.UserRecord, seen map[string]int, rowNumber int, dryRun bool) (string, error) {
+	// O email normalizado é o mesmo na detecção de repetidos, na busca e na criação
+	record.Email = strings.ToLower(strings.TrimSpace(record.Email))
+	record.Name = strings.TrimSpace(record.Name)
+	record.PasswordHash = strings.TrimSpace(record.PasswordHash)
+	record.Roles = slices.Compact(slices.Sorted(slices.Values(record.Roles)))
+
+	if err := validateUserRecord(record); err != nil {
+		return models.ImportFailed, err
+	}
+
+	if first, ok := seen[record.Email]; ok {
+		return models.ImportFailed, ErrInvalidRequest.Wrap(fmt.Errorf("email repetido no arquivo (linha %d)", first))
+	}
+	seen[record.Email] = rowNumber
+
+	user, err := s.authService.users.GetUserByEmail(ctx, tenantID, record.Email)
+	if errors.Is(err, store.ErrNotFound) {
+		return s.createImportedUser(ctx, tenantID, record, dryRun)
+	}
+	if err != nil {
+		return models.ImportFailed, fmt.Errorf("erro ao buscar usuário: %w", err)
+	}
+	return s.updateImportedUser(ctx, actorID, user, record, dryRun)
+}
+
+func (s *UserImportService) createImportedUser(ctx context.Context, tenantID string, record models.UserRecord, dryRun bool) (string, error) {
+	if dryRun {
+		return models.ImportCreated, nil
+	}
+
+	now := time.Now()
+	user := &models.User{
+		ID:        uuid.New(),
+		TenantID:  uuid.MustParse(tenantID),
+		Email:     record.Email,
+		Password:  record.PasswordHash,
+		Name:      record.Name,
+		Active:    record.Active == nil || *record.Active,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	created, err := newUserEvent(models.OutboxUserCreated, user)
+	if err != nil {
+		return models.ImportFailed, err
+	}
+	if err := s.authService.users.CreateUser(ctx, user, record.Roles, created); err != nil {
+		if errors.Is(err, store.ErrDuplicate) {
+			return models.ImportFailed, ErrEmailInUse
+		}
+		return models.ImportFailed, fmt.Errorf("erro ao criar usuário: %w", err)
+	}
+
+	// Sem hash, a conta só pode ser usada depois que o usuário definir a senha pelo link
+	if user.Password == "" && user.Active {
+		if err := s.passwordResets.SendPasswordSetup(ctx, user); err != nil {
+			slog.WarnContext(ctx, "Erro ao enviar link para definir a senha", "user_id", user.ID, "error", err)
+			return models.ImportCreated, fmt.Errorf("usuário criado, mas o email para definir a senha não foi enviado: %w", err)
+		}
+	}
+
+	return models.ImportCreated, nil
+}
+
+func (s *UserImportService) updateImportedUser(ctx context.Context, actorID string, user *models.User, record models.UserRecord, dryRun bool) (string, error) {
+	roles, err := s.authService.users.GetUserRoles(ctx, user.ID.String())
+	if err != nil {
+		return models.ImportFailed, fmt.Errorf("erro ao buscar papéis do usuário: %w", err)
+	}
+
+	// Papéis ausentes do arquivo não são removidos
+	var missingRoles []string
+	for _, role := range record.Roles {
+		if !containsString(roles, role) {
+			missingRoles = append(missingRoles, role)
+		}
+	}
+
+	eventType := models.OutboxUserUpdated
+	profileChanged := record.Name != user.Name
+	if record.Active != nil && *record.Active != user.Active {
+		profileChanged = true
+		if !*record.Active {
+			if user.ID.String() == actorID {
+				return models.ImportFailed, ErrCannotModifySelf
+			}
+			eventType = models.OutboxUserDeactivated
+		}
+	}
+
+	// password_hash só vale na criação: aplicá-lo a uma conta existente deixaria quem importa
+	// o arquivo entrar como qualquer usuário, inclusive outros administradores
+	if !profileChanged && len(missingRoles) == 0 {
+		return models.ImportUnchanged, nil
+	}
+	if dryRun {
+		return models.ImportUpdated, nil
+	}
+
+	// Perfil e papéis são gravados juntos, e updated_at muda também quando só os papéis
+	// mudam; só a mudança de perfil gera evento no outbox
+	user.UpdatedAt = time.Now()
+	var outboxEvent *models.OutboxEvent
+	if profileChanged {
+		user.Name = record.Name
+		if record.Active != nil {
+			user.Active = *record.Active
+		}
+
+		outboxEvent, err = newUserEvent(eventType, user)
+		if err != nil {
+			return models.ImportFailed, err
+		}
+	}
+	if err := s.authService.users.UpdateUser(ctx, user, missingRoles, outboxEvent); err != nil {
+		return models.ImportFailed, fmt.Errorf("erro ao atualizar usuário: %w", err)
+	}
+
+	return models.ImportUpdated, nil
+}
+
+// ExportUsers retorna os usuários da organização no formato da importação, sem os hashes
+func (s *UserImportService) ExportUsers(ctx context.Context, tenantID string) ([]models.UserRecord, error) {
+	users, err := s.authService.users.ListUsers(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]models.UserRecord, 0, len(users))
+	for _, user := range users {
+		roles, err := s.authService.users.GetUserRoles(ctx, user.ID.String())
+		if err != nil {
+			return nil, fmt.Errorf("erro ao buscar papéis do usuário: %w", err)
+		}
+
+		active := user.Active
+		records = append(records, models.UserRecord{
+			Email:  user.Email,
+			Name:   user.Name,
+			Roles:  roles,
+			Active: &active,
+		})
+	}
+	return records, nil
+}
+
+func validateUserRecord(record models.UserRecord) error {
+	if address, err := mail.ParseAddress(record.Email); err != nil || address.Address != record.Email {
+		return ErrInvalidRequest.Wrap(fmt.Errorf("email inválido: %q", record.Email))
+	}
+	if record.Name == "" {
+		return ErrInvalidRequest.Wrap(errors.New("nome obrigatório"))
+	}
+	if record.PasswordHash != "" {
+		if _, err := bcrypt.Cost([]byte(record.PasswordHash)); err != nil {
+			return ErrInvalidRequest.Wrap(errors.New("password_hash não é um hash bcrypt"))
+		}
+	}
+	for _, role := range record.Roles {
+		if !knownRoles[role] {
+			return ErrUnknownRole
+		}
+	}
+	return nil
+}
+
+// ParseUserFile lê os usuários de um arquivo CSV (com cabeçalho) ou JSON (lista de objetos)
+func ParseUserFile(r io.Reader, format string) ([]models.UserRecord, error) {
+	switch format {
+	case models.UserFileJSON:
+		var records []models.UserRecord
+		decoder := json.NewDecoder(r)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&records); err != nil {
+			return nil, ErrInvalidImportFormat.Wrap(err)
+		}
+		if len(records) > maxImportRows {
+			return nil, ErrImportTooLarge
+		}
+		return records, nil
+
+	case models.UserFileCSV:
+		return parseUserCSV(r)
+
+	default:
+		return nil, ErrInvalidImportFormat.Wrap(fmt.Errorf("formato desconhecido: %q (use csv ou json)", format))
+	}
+}
+
+func parseUserCSV(r io.Reader) ([]models.UserRecord, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, ErrInvalidImportFormat.Wrap(fmt.Errorf("cabeçalho ausente: %w", err))
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		// Planilhas costumam gravar o BOM do UTF-8 no início do arquivo
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if !containsString(models.UserFileColumns, name) {
+			return nil, ErrInvalidImportFormat.Wrap(fmt.Errorf("coluna desconhecida: %q", name))
+		}
+		columns[name] = i
+	}
+	if _, ok := columns["email"]; !ok {
+		return nil, ErrInvalidImportFormat.Wrap(errors.New("coluna email obrigatória"))
+	}
+
+	field := func(row []string, name string) string {
+		if i, ok := columns[name]; ok {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+
+	var records []models.UserRecord
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, ErrInvalidImportFormat.Wrap(err)
+		}
+		if len(records) == maxImportRows {
+			return nil, ErrImportTooLarge
+		}
+
+		record := models.UserRecord{
+			Email:        field(row, "email"),
+			Name:         field(row, "name"),
+			PasswordHash: field(row, "password_hash"),
+			Roles:        strings.Fields(field(row, "roles")),
+		}
+		if value := field(row, "active"); value != "" {
+			active, err := strconv.ParseBool(value)
+			if err != nil {
+				line, _ := reader.FieldPos(0)
+				return nil, ErrInvalidImportFormat.Wrap(fmt.Errorf("linha %d: active inválido: %q", line, value))
+			}
+			record.Active = &active
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// WriteUserFile grava os usuários no formato da importação
+func WriteUserFile(w io.Writer, format string, records []models.UserRecord) error {
+	switch format {
+	case models.UserFileJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(records)
+
+	case models.UserFileCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(models.UserFileColumns); err != nil {
+			return err
+		}
+		for _, record := range records {
+			active := ""
+			if record.Active != nil {
+				active = strconv.FormatBool(*record.Active)
+			}
+			if err := writer.Write([]string{record.Email, record.Name, record.PasswordHash, strings.Join(record.Roles, " "), active}); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+
+	default:
+		return ErrInvalidImportFormat.Wrap(fmt.Errorf("formato desconhecido: %q (use csv ou json)", format))
+	}
+}
+
+// UserFileFormat escolhe o formato pelo nome do arquivo ou pelo Content-Type; na dúvida, JSON
+func UserFileFormat(nameOrContentType string) string {
+	value := strings.ToLower(nameOrContentType)
+	if strings.HasSuffix(value, ".csv") || strings.Contains(value, "csv") {
+		return models.UserFileCSV
+	}
+	return models.UserFileJSON
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,254 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+
+	"auth-service/mailer"
+	"auth-service/models"
+	"auth-service/store"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// capturingMailer guarda as mensagens em vez de enviá-las
+type capturingMailer struct {
+	mu       sync.Mutex
+	messages []mailer.Message
+}
+
+func (m *capturingMailer) Send(ctx context.Context, msg mailer.Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = append(m.messages, msg)
+	return nil
+}
+
+func newTestImportService(t *testing.T) (*UserImportService, *AuthService, *store.Stores) {
+	t.Helper()
+
+	s, stores := newTestAuthService(t)
+	return NewUserImportService(s, NewPasswordResetService(s, s.cfg, &capturingMailer{})), s, stores
+}
+
+func TestImportNormalizesEmail(t *testing.T) {
+	imports, _, stores := newTestImportService(t)
+	ctx := context.Background()
+
+	result, err := imports.ImportUsers(ctx, models.DefaultTenantID, "", []models.UserRecord{
+		{Email: " Ana@Example.com ", Name: "Ana"},
+	}, false)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if result.Created != 1 {
+		t.Fatalf("resultado: %+v", result)
+	}
+	if _, err := stores.Users.GetUserByEmail(ctx, models.DefaultTenantID, "ana@example.com"); err != nil {
+		t.Fatalf("usuário não gravado com o email normalizado: %v", err)
+	}
+
+	// Com outra caixa, a linha encontra o usuário já importado em vez de criar outro
+	result, err = imports.ImportUsers(ctx, models.DefaultTenantID, "", []models.UserRecord{
+		{Email: "ANA@example.com", Name: "Ana"},
+	}, false)
+	if err != nil {
+		t.Fatalf("reimport: %v", err)
+	}
+	if result.Unchanged != 1 || result.Created != 0 {
+		t.Errorf("reimport: %+v", result)
+	}
+}
+
+func TestImportGrantsRoles(t *testing.T) {
+	imports, _, stores := newTestImportService(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		record models.UserRecord
+		action string
+		roles  []string
+	}{
+		{"criação com papel repetido", models.UserRecord{Email: "ana@example.com", Name: "Ana", Roles: []string{models.RoleAdmin, models.RoleAdmin}}, models.ImportCreated, []string{models.RoleAdmin}},
+		{"criação sem papéis", models.UserRecord{Email: "bia@example.com", Name: "Bia"}, models.ImportCreated, nil},
+		{"só papéis mudam", models.UserRecord{Email: "bia@example.com", Name: "Bia", Roles: []string{models.RoleAdmin}}, models.ImportUpdated, []string{models.RoleAdmin}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := imports.ImportUsers(ctx, models.DefaultTenantID, "", []models.UserRecord{tt.record}, false)
+			if err != nil {
+				t.Fatalf("import: %v", err)
+			}
+			if row := result.Rows[0]; row.Action != tt.action {
+				t.Fatalf("ação %s (%s), esperado %s", row.Action, row.Error, tt.action)
+			}
+
+			user, err := stores.Users.GetUserByEmail(ctx, models.DefaultTenantID, tt.record.Email)
+			if err != nil {
+				t.Fatalf("usuário não gravado: %v", err)
+			}
+			roles, _ := stores.Users.GetUserRoles(ctx, user.ID.String())
+			if !slices.Equal(roles, tt.roles) {
+				t.Errorf("papéis = %v, esperado %v", roles, tt.roles)
+			}
+		})
+	}
+}
+
+// importFile tem uma linha de cada caso sobre uma conta já cadastrada (ana)
+func importFile(t *testing.T) []models.UserRecord {
+	t.Helper()
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("outra-senha-1"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("hash: %v", err)
+	}
+	inactive := false
+	return []models.UserRecord{
+		{Email: "bia@example.com", Name: "Bia"},
+		{Email: "caio@example.com", Name: "Caio", PasswordHash: string(hash)},
+		{Email: "ana@example.com", Name: "Ana Souza", PasswordHash: string(hash), Roles: []string{models.RoleAdmin}},
+		{Email: "dani@example.com", Name: "Dani", Active: &inactive},
+		{Email: "bia@example.com", Name: "Bia de novo"},
+		{Email: "invalido", Name: "Sem email"},
+		{Email: "eva@example.com", Name: "Eva", Roles: []string{"superuser"}},
+	}
+}
+
+var importActions = []string{
+	models.ImportCreated, models.ImportCreated, models.ImportUpdated, models.ImportCreated,
+	models.ImportFailed, models.ImportFailed, models.ImportFailed,
+}
+
+func TestImportDryRunWritesNothing(t *testing.T) {
+	m := &capturingMailer{}
+	s, stores := newTestAuthService(t)
+	imports := NewUserImportService(s, NewPasswordResetService(s, s.cfg, m))
+	ctx := context.Background()
+	ana := register(t, s, "ana@example.com")
+
+	result, err := imports.ImportUsers(ctx, models.DefaultTenantID, "", importFile(t), true)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if !result.DryRun || result.Created != 3 || result.Updated != 1 || result.Failed != 3 {
+		t.Errorf("resultado: %+v", result)
+	}
+	for i, row := range result.Rows {
+		if row.Action != importActions[i] {
+			t.Errorf("linha %d: ação %s (%s), esperado %s", row.Row, row.Action, row.Error, importActions[i])
+		}
+	}
+
+	users, _ := stores.Users.ListUsers(ctx, models.DefaultTenantID)
+	if len(users) != 1 || users[0].Name != ana.Name {
+		t.Errorf("usuários depois do dry_run = %+v", users)
+	}
+	if roles, _ := stores.Users.GetUserRoles(ctx, ana.ID.String()); len(roles) != 0 {
+		t.Errorf("papéis depois do dry_run = %v", roles)
+	}
+	if events, _, _ := stores.Events.ListEvents(ctx, store.EventFilter{EventType: models.EventUsersImported, Limit: 10}); len(events) != 0 {
+		t.Errorf("dry_run auditado: %+v", events)
+	}
+	if len(m.messages) != 0 {
+		t.Errorf("%d emails enviados no dry_run", len(m.messages))
+	}
+}
+
+func TestImportUpsert(t *testing.T) {
+	m := &capturingMailer{}
+	s, stores := newTestAuthService(t)
+	imports := NewUserImportService(s, NewPasswordResetService(s, s.cfg, m))
+	client := newTestClient(t, s)
+	ctx := context.Background()
+	register(t, s, "ana@example.com")
+
+	result, err := imports.ImportUsers(ctx, models.DefaultTenantID, "", importFile(t), false)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	for i, row := range result.Rows {
+		if row.Action != importActions[i] {
+			t.Errorf("linha %d: ação %s (%s), esperado %s", row.Row, row.Action, row.Error, importActions[i])
+		}
+	}
+	if code := result.Rows[4].Code; code != ErrInvalidRequest.Code {
+		t.Errorf("email repetido: código %s", code)
+	}
+	if code := result.Rows[6].Code; code != ErrUnknownRole.Code {
+		t.Errorf("papel desconhecido: código %s", code)
+	}
+
+	// O hash do arquivo vale para a conta criada, mas não substitui a senha de quem já existia
+	loginTests := []struct {
+		email    string
+		password string
+		wantErr  error
+	}{
+		{"caio@example.com", "outra-senha-1", nil},
+		{"ana@example.com", testPassword, nil},
+		{"ana@example.com", "outra-senha-1", ErrInvalidCredentials},
+	}
+	for _, tt := range loginTests {
+		_, err := s.Login(ctx, &models.LoginRequest{Email: tt.email, Password: tt.password, ClientID: client.ID.String()})
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("login de %s com %q: erro %v, esperado %v", tt.email, tt.password, err, tt.wantErr)
+		}
+	}
+
+	ana, _ := stores.Users.GetUserByEmail(ctx, models.DefaultTenantID, "ana@example.com")
+	if roles, _ := stores.Users.GetUserRoles(ctx, ana.ID.String()); ana.Name != "Ana Souza" || !slices.Equal(roles, []string{models.RoleAdmin}) {
+		t.Errorf("ana atualizada = %s %v", ana.Name, roles)
+	}
+	if dani, _ := stores.Users.GetUserByEmail(ctx, models.DefaultTenantID, "dani@example.com"); dani == nil || dani.Active {
+		t.Errorf("dani = %+v, esperado inativa", dani)
+	}
+
+	// Só bia recebe o link: caio tem hash e dani foi importada inativa
+	m.mu.Lock()
+	if len(m.messages) != 1 || m.messages[0].To != "bia@example.com" {
+		t.Errorf("emails = %+v", m.messages)
+	}
+	m.mu.Unlock()
+
+	// Importar o mesmo arquivo de novo não muda nada, e um papel fora do arquivo continua
+	result, err = imports.ImportUsers(ctx, models.DefaultTenantID, "", importFile(t)[:4], false)
+	if err != nil {
+		t.Fatalf("reimport: %v", err)
+	}
+	if result.Unchanged != 4 {
+		t.Errorf("reimport: %+v", result)
+	}
+	result, err = imports.ImportUsers(ctx, models.DefaultTenantID, "", []models.UserRecord{{Email: "ana@example.com", Name: "Ana Souza"}}, false)
+	if err != nil || result.Unchanged != 1 {
+		t.Fatalf("import sem papéis: %+v, %v", result, err)
+	}
+	if roles, _ := stores.Users.GetUserRoles(ctx, ana.ID.String()); !slices.Equal(roles, []string{models.RoleAdmin}) {
+		t.Errorf("papéis depois do import sem papéis = %v", roles)
+	}
+
+	events, _, _ := stores.Events.ListEvents(ctx, store.EventFilter{EventType: models.EventUsersImported, Limit: 10})
+	if len(events) != 3 {
+		t.Errorf("%d importações auditadas, esperado 3", len(events))
+	}
+}
+
+func TestImportCannotDeactivateSelf(t *testing.T) {
+	imports, s, _ := newTestImportService(t)
+	admin := register(t, s, "ana@example.com")
+
+	inactive := false
+	result, err := imports.ImportUsers(context.Background(), models.DefaultTenantID, admin.ID.String(), []models.UserRecord{
+		{Email: "ana@example.com", Name: "Ana", Active: &inactive},
+	}, false)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if row := result.Rows[0]; row.Action != models.ImportFailed || row.Code != ErrCannotModifySelf.Code {
+		t.Errorf("linha = %+v", row)
+	}
+}
@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"auth-service/models"
+	"auth-service/store"
+
+	"github.com/google/uuid"
+)
+
+// GetTenantUser retorna o usuário da organização com seus papéis
+func (s *AuthService) GetTenantUser(ctx context.Context, tenantID, id string) (*models.UserResponse, error) {
+	user, err := s.findTenantUser(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	roles, err := s.users.GetUserRoles(ctx, user.ID.String())
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar papéis do usuário: %w", err)
+	}
+
+	return &models.UserResponse{
+		ID:        user.ID,
+		TenantID:  user.TenantID,
+		Email:     user.Email,
+		Name:      user.Name,
+		Active:    user.Active,
+		Roles:     roles,
+		CreatedAt: user.CreatedAt,
+	}, nil
+}
+
+// UpdateUser altera o nome ou a situação de um usuário da organização. Desativar publica
+// user.deactivated; as demais alterações publicam user.updated.
+func (s *AuthService) UpdateUser(ctx context.Context, tenantID, actorID, id string, req *models.UpdateUserRequest) (_ *models.UserResponse, err error) {
+	event := &models.AuthEvent{Type: models.EventUserUpdated, UserID: id}
+	defer func() { s.recordEvent(ctx, event, err) }()
+
+	user, err := s.findTenantUser(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	eventType := models.OutboxUserUpdated
+	if req.Active != nil && !*req.Active && user.Active {
+		if user.ID.String() == actorID {
+			return nil, ErrCannotModifySelf
+		}
+		eventType = models.OutboxUserDeactivated
+	}
+	event.Reason = eventType
+
+	if req.Name != nil {
+		user.Name = *req.Name
+	}
+	if req.Active != nil {
+		user.Active = *req.Active
+	}
+	user.UpdatedAt = time.Now()
+
+	outboxEvent, err := newUserEvent(eventType, user)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.users.UpdateUser(ctx, user, nil, outboxEvent); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("erro ao atualizar usuário: %w", err)
+	}
+
+	return s.GetTenantUser(ctx, tenantID, id)
+}
+
+// DeleteUser remove um usuário da organização com seus papéis, identidades e tokens
+func (s *AuthService) DeleteUser(ctx context.Context, tenantID, actorID, id string) (err error) {
+	event := &models.AuthEvent{Type: models.EventUserDeleted, UserID: id}
+	defer func() { s.recordEvent(ctx, event, err) }()
+
+	user, err := s.findTenantUser(ctx, tenantID, id)
+	if err != nil {
+		return err
+	}
+	if user.ID.String() == actorID {
+		return ErrCannotModifySelf
+	}
+
+	outboxEvent, err := newUserEvent(models.OutboxUserDeleted, user)
+	if err != nil {
+		return err
+	}
+	if err := s.users.DeleteUser(ctx, user.ID, outboxEvent); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return ErrUserNotFound
+		}
+		return fmt.Errorf("erro ao remover usuário: %w", err)
+	}
+	return nil
+}
+
+// CreateAdmin cria um usuário administrador na organização, sem passar pelas restrições
+// do modo de cadastro. Usado pelo authctl para criar o primeiro administrador.
+func (s *AuthService) CreateAdmin(ctx context.Context, tenantID, email, name, password string) (_ *models.UserResponse, err error) {
+	event := &models.AuthEvent{Type: models.EventRoleGranted, Reason: models.RoleAdmin}
+	defer func() { s.recordEvent(ctx, event, err) }()
+
+	// Sem a validação de binding do /register, o tamanho mínimo da senha é conferido aqui
+	if utf8.RuneCountInString(password) < models.MinPasswordLength {
+		return nil, ErrInvalidRequest.Wrap(fmt.Errorf("a senha deve ter pelo menos %d caracteres", models.MinPasswordLength))
+	}
+
+	hashedPassword, err := hashPassword(ctx, password)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar hash da senha: %w", err)
+	}
+
+	now := time.Now()
+	user := &models.User{
+		ID:        uuid.New(),
+		TenantID:  uuid.MustParse(tenantID),
+		Email:     email,
+		Password:  hashedPassword,
+		Name:      name,
+		Active:    true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	created, err := newUserEvent(models.OutboxUserCreated, user)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.users.CreateUser(ctx, user, []string{models.RoleAdmin}, created); err != nil {
+		if errors.Is(err, store.ErrDuplicate) {
+			return nil, ErrEmailInUse
+		}
+		return nil, fmt.Errorf("erro ao criar usuário: %w", err)
+	}
+	event.UserID = user.ID.String()
+
+	return s.GetTenantUser(ctx, tenantID, user.ID.String())
+}
+
+// RevokeUserSessions revoga todos os refresh tokens do usuário com o email informado e
+// retorna quantos foram revogados. Access tokens já emitidos valem até expirar.
+func (s *AuthService) RevokeUserSessions(ctx context.Context, tenantID, email string) (_ int64, err error) {
+	event := &models.AuthEvent{Type: models.EventSessionsRevoked}
+	defer func() { s.recordEvent(ctx, event, err) }()
+
+	user, err := s.getUserByEmail(ctx, tenantID, email)
+	if err != nil {
+		return 0, err
+	}
+	event.UserID = user.ID.String()
+
+	revoked, err := s.tokens.RevokeUserRefreshTokens(ctx, user.ID.String())
+	if err != nil {
+		return 0, fmt.Errorf("erro ao revogar sessões: %w", err)
+	}
+	return revoked, nil
+}
+
+// findTenantUser busca o usuário pelo ID; usuários de outra organização são tratados como inexistentes
+func (s *AuthService) findTenantUser(ctx context.Context, tenantID, id string) (*models.User, error) {
+	user, err := s.users.GetUserByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar usuário: %w", err)
+	}
+	if user.TenantID.String() != tenantID {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+// newUserEvent monta o evento do outbox com o estado atual do usuário
+func newUserEvent(eventType string, user *models.User) (*models.OutboxEvent, error) {
+	payload, err := json.Marshal(models.UserEventPayload{
+		ID:        user.ID,
+		TenantID:  user.TenantID,
+		Email:     user.Email,
+		Name:      user.Name,
+		Active:    user.Active,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao serializar evento: %w", err)
+	}
+
+	return &models.OutboxEvent{
+		ID:          uuid.New(),
+		TenantID:    user.TenantID,
+		Type:        eventType,
+		AggregateID: user.ID.String(),
+		Payload:     payload,
+		CreatedAt:   time.Now(),
+	}, nil
+}
@@ -0,0 +1,361 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"syscall"
+	"time"
+
+	"auth-service/config"
+	"auth-service/metrics"
+	"auth-service/models"
+	"auth-service/store"
+
+	"github.com/google/uuid"
+)
+
+// Headers enviados em cada entrega. A assinatura é o HMAC-SHA256, em hexadecimal, de
+// "<X-Webhook-Timestamp>.<corpo>" com o segredo do webhook.
+const (
+	webhookIDHeader        = "X-Webhook-ID"
+	webhookEventHeader     = "X-Webhook-Event"
+	webhookTimestampHeader = "X-Webhook-Timestamp"
+	webhookSignatureHeader = "X-Webhook-Signature"
+
+	// maxWebhookError limita a mensagem de erro guardada na entrega
+	maxWebhookError = 512
+)
+
+// WebhookDispatcher distribui os eventos do outbox aos webhooks da organização e faz as
+// entregas, repetindo as que falham com espera exponencial. A entrega é "pelo menos uma
+// vez": o receptor deve ignorar eventos repetidos pelo X-Webhook-ID.
+type WebhookDispatcher struct {
+	outbox   store.OutboxStore
+	webhooks store.WebhookStore
+	client   *http.Client
+
+	interval    time.Duration
+	timeout     time.Duration
+	batchSize   int
+	maxAttempts int
+	backoffBase time.Duration
+	backoffMax  time.Duration
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewWebhookDispatcher(cfg *config.Config, outbox store.OutboxStore, webhooks store.WebhookStore) *WebhookDispatcher {
+	timeout := time.Duration(cfg.Webhooks.TimeoutSeconds) * time.Second
+	return &WebhookDispatcher{
+		outbox:      outbox,
+		webhooks:    webhooks,
+		client:      newWebhookClient(timeout, cfg.Webhooks.AllowPrivateNetworks),
+		interval:    time.Duration(cfg.Webhooks.PollIntervalSeconds) * time.Second,
+		timeout:     timeout,
+		batchSize:   cfg.Webhooks.BatchSize,
+		maxAttempts: cfg.Webhooks.MaxAttempts,
+		backoffBase: time.Duration(cfg.Webhooks.BackoffBaseSeconds) * time.Second,
+		backoffMax:  time.Duration(cfg.Webhooks.BackoffMaxSeconds) * time.Second,
+	}
+}
+
+// Start executa o dispatcher em segundo plano até Stop ser chamado
+func (d *WebhookDispatcher) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	d.cancel = cancel
+	d.done = make(chan struct{})
+
+	go func() {
+		defer close(d.done)
+
+		ticker := time.NewTicker(d.interval)
+		defer ticker.Stop()
+
+		for {
+			d.RunOnce(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop interrompe o dispatcher e aguarda a rodada em andamento terminar
+func (d *WebhookDispatcher) Stop() {
+	if d.cancel == nil {
+		return
+	}
+	d.cancel()
+	<-d.done
+}
+
+// RunOnce distribui os eventos novos e faz as entregas vencidas
+func (d *WebhookDispatcher) RunOnce(ctx context.Context) {
+	if err := d.dispatchEvents(ctx); err != nil {
+		slog.Error("Erro ao distribuir eventos do outbox", "error", err)
+	}
+	if err := d.deliverDue(ctx); err != nil {
+		slog.Error("Erro ao entregar webhooks", "error", err)
+	}
+}
+
+// dispatchEvents cria uma entrega por webhook ativo que assina o tipo do evento. Eventos
+// de organizações sem webhooks são apenas marcados como distribuídos.
+func (d *WebhookDispatcher) dispatchEvents(ctx context.Context) error {
+	events, err := d.outbox.ListUndispatchedEvents(ctx, d.batchSize)
+	if err != nil {
+		return err
+	}
+
+	webhooksByTenant := make(map[uuid.UUID][]models.Webhook)
+	for _, event := range events {
+		webhooks, ok := webhooksByTenant[event.TenantID]
+		if !ok {
+			if webhooks, err = d.webhooks.ListWebhooks(ctx, event.TenantID.String()); err != nil {
+				return err
+			}
+			webhooksByTenant[event.TenantID] = webhooks
+		}
+
+		now := time.Now()
+		var deliveries []models.WebhookDelivery
+		for _, webhook := range webhooks {
+			if !webhook.Active || !webhook.Subscribes(event.Type) {
+				continue
+			}
+			deliveries = append(deliveries, models.WebhookDelivery{
+				ID:            uuid.New(),
+				EventID:       event.ID,
+				WebhookID:     webhook.ID,
+				Status:        models.DeliveryPending,
+				NextAttemptAt: now,
+				CreatedAt:     now,
+				UpdatedAt:     now,
+			})
+		}
+
+		// ErrNotFound: outra instância já distribuiu o evento
+		if err := d.outbox.DispatchOutboxEvent(ctx, event.ID, deliveries, now); err != nil && !errors.Is(err, store.ErrNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *WebhookDispatcher) deliverDue(ctx context.Context) error {
+	deliveries, err := d.outbox.ListDueDeliveries(ctx, time.Now(), d.batchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, delivery := range deliveries {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		// A reserva impede que outra instância faça a mesma tentativa enquanto esta não termina
+		now := time.Now()
+		if err := d.outbox.ClaimDelivery(ctx, delivery.ID, now, now.Add(2*d.timeout)); err != nil {
+			if errors.Is(err, store.ErrNotFound) {
+				continue
+			}
+			return err
+		}
+
+		d.attempt(ctx, delivery)
+	}
+	return nil
+}
+
+// attempt faz uma tentativa de entrega e registra o resultado
+func (d *WebhookDispatcher) attempt(ctx context.Context, delivery models.WebhookDelivery) {
+	statusCode, err := d.send(ctx, delivery)
+
+	now := time.Now()
+	delivery.Attempts++
+	delivery.LastStatusCode = statusCode
+	delivery.UpdatedAt = now
+
+	outcome := "delivered"
+	switch {
+	case err == nil:
+		delivery.Status = models.DeliveryDelivered
+		delivery.DeliveredAt = &now
+		delivery.LastError = ""
+	case delivery.Attempts >= d.maxAttempts:
+		outcome = "failed"
+		delivery.Status = models.DeliveryFailed
+		delivery.LastError = truncate(err.Error(), maxWebhookError)
+	default:
+		outcome = "retry"
+		delivery.NextAttemptAt = now.Add(d.backoff(delivery.Attempts))
+		delivery.LastError = truncate(err.Error(), maxWebhookError)
+	}
+	metrics.WebhookDeliveries.WithLabelValues(outcome).Inc()
+
+	if outcome != "delivered" {
+		slog.Warn("Falha na entrega de webhook", "delivery_id", delivery.ID, "webhook_id", delivery.WebhookID,
+			"attempts", delivery.Attempts, "outcome", outcome, "error", err)
+	}
+
+	// A atualização deve ser gravada mesmo durante o desligamento
+	if err := d.outbox.UpdateDelivery(context.WithoutCancel(ctx), &delivery); err != nil {
+		slog.Error("Erro ao registrar entrega de webhook", "delivery_id", delivery.ID, "error", err)
+	}
+}
+
+// send envia o evento assinado e retorna o status HTTP recebido. Respostas fora da faixa
+// 2xx são tratadas como falha.
+func (d *WebhookDispatcher) send(ctx context.Context, delivery models.WebhookDelivery) (int, error) {
+	webhook, err := d.webhooks.GetWebhook(ctx, delivery.WebhookID.String())
+	if err != nil {
+		return 0, fmt.Errorf("erro ao buscar webhook: %w", err)
+	}
+	if !webhook.Active {
+		return 0, errors.New("webhook inativo")
+	}
+
+	event, err := d.outbox.GetOutboxEvent(ctx, delivery.EventID.String())
+	if err != nil {
+		return 0, fmt.Errorf("erro ao buscar evento do outbox: %w", err)
+	}
+
+	body, err := json.Marshal(models.WebhookEnvelope{
+		ID:        event.ID,
+		Type:      event.Type,
+		TenantID:  event.TenantID,
+		CreatedAt: event.CreatedAt,
+		Data:      event.Payload,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("erro ao serializar evento: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("erro ao montar requisição: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "auth-service-webhooks")
+	req.Header.Set(webhookIDHeader, event.ID.String())
+	req.Header.Set(webhookEventHeader, event.Type)
+	req.Header.Set(webhookTimestampHeader, timestamp)
+	req.Header.Set(webhookSignatureHeader, "sha256="+signWebhook(webhook.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook respondeu %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// newWebhookClient cria o cliente HTTP das entregas. As URLs são cadastradas pelos
+// administradores das organizações, então o cliente só conecta em endereços públicos (o
+// endereço é conferido na conexão, depois da resolução do DNS) e não segue redirecionamentos,
+// que levariam a entrega para um destino não verificado. Respostas 3xx contam como falha.
+func newWebhookClient(timeout time.Duration, allowPrivateNetworks bool) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivateNetworks {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil || !publicAddress(addrPort.Addr()) {
+				return fmt.Errorf("conexão recusada: %s não é um endereço público", address)
+			}
+			return nil
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	// Com um proxy, o dialer veria apenas o endereço do proxy
+	transport.Proxy = nil
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// nonPublicPrefixes complementa os métodos de netip.Addr com faixas reservadas que também
+// não devem receber entregas
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),     // "esta rede"
+	netip.MustParsePrefix("100.64.0.0/10"), // CGNAT, usada também por metadados de nuvem
+	netip.MustParsePrefix("192.0.0.0/24"),  // atribuições do IETF
+	netip.MustParsePrefix("198.18.0.0/15"), // testes de desempenho
+	netip.MustParsePrefix("240.0.0.0/4"),   // reservada, inclui o broadcast
+	netip.MustParsePrefix("64:ff9b::/96"),  // NAT64, que alcança qualquer IPv4
+}
+
+// publicAddress indica se o endereço é roteável na internet: recusa loopback, redes
+// privadas, link-local (onde ficam os metadados de nuvem, 169.254.169.254), multicast e
+// as faixas reservadas acima
+func publicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || addr.IsUnspecified() || addr.IsLoopback() || addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return false
+	}
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// backoff dobra a espera a cada tentativa, até backoffMax
+func (d *WebhookDispatcher) backoff(attempts int) time.Duration {
+	wait := d.backoffBase
+	for i := 1; i < attempts && wait < d.backoffMax; i++ {
+		wait *= 2
+	}
+	if wait > d.backoffMax {
+		wait = d.backoffMax
+	}
+	return wait
+}
+
+// signWebhook assina o corpo junto com o timestamp, para que o receptor possa recusar
+// entregas antigas reenviadas por terceiros
+func signWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func truncate(value string, max int) string {
+	if len(value) > max {
+		return value[:max]
+	}
+	return value
+}
@@ -0,0 +1,212 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"auth-service/config"
+	"auth-service/models"
+	"auth-service/store"
+
+	"github.com/google/uuid"
+)
+
+const (
+	webhookSecretPrefix   = "whsec_"
+	defaultOutboxPageSize = 20
+)
+
+// WebhookService gerencia os webhooks da organização e a consulta do outbox
+type WebhookService struct {
+	authService *AuthService
+	cfg         *config.Config
+	outbox      store.OutboxStore
+	webhooks    store.WebhookStore
+}
+
+func NewWebhookService(authService *AuthService, cfg *config.Config, outbox store.OutboxStore, webhooks store.WebhookStore) *WebhookService {
+	return &WebhookService{
+		authService: authService,
+		cfg:         cfg,
+		outbox:      outbox,
+		webhooks:    webhooks,
+	}
+}
+
+// CreateWebhook registra um webhook na organização. O segredo de assinatura só é
+// retornado aqui.
+func (s *WebhookService) CreateWebhook(ctx context.Context, tenantID string, req *models.CreateWebhookRequest) (_ *models.CreateWebhookResponse, err error) {
+	event := &models.AuthEvent{Type: models.EventWebhookCreated}
+	defer func() { s.authService.recordEvent(ctx, event, err) }()
+
+	if err := s.checkWebhookURL(ctx, req.URL); err != nil {
+		return nil, err
+	}
+
+	secretBytes := make([]byte, 32)
+	if _, err := rand.Read(secretBytes); err != nil {
+		return nil, fmt.Errorf("erro ao gerar segredo do webhook: %w", err)
+	}
+	secret := webhookSecretPrefix + hex.EncodeToString(secretBytes)
+
+	now := time.Now()
+	webhook := &models.Webhook{
+		ID:         uuid.New(),
+		TenantID:   uuid.MustParse(tenantID),
+		URL:        req.URL,
+		Secret:     secret,
+		EventTypes: req.EventTypes,
+		Active:     true,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if webhook.EventTypes == nil {
+		webhook.EventTypes = []string{}
+	}
+	event.Reason = webhook.ID.String()
+
+	if err := s.webhooks.CreateWebhook(ctx, webhook); err != nil {
+		return nil, fmt.Errorf("erro ao criar webhook: %w", err)
+	}
+
+	return &models.CreateWebhookResponse{Webhook: *webhook, Secret: secret}, nil
+}
+
+// ListWebhooks lista os webhooks da organização
+func (s *WebhookService) ListWebhooks(ctx context.Context, tenantID string) ([]models.Webhook, error) {
+	return s.webhooks.ListWebhooks(ctx, tenantID)
+}
+
+// DeleteWebhook remove o webhook e as entregas ainda pendentes para ele
+func (s *WebhookService) DeleteWebhook(ctx context.Context, tenantID, id string) (err error) {
+	event := &models.AuthEvent{Type: models.EventWebhookDeleted, Reason: id}
+	defer func() { s.authService.recordEvent(ctx, event, err) }()
+
+	if err := s.webhooks.DeleteWebhook(ctx, tenantID, id); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return ErrWebhookNotFound
+		}
+		return fmt.Errorf("erro ao remover webhook: %w", err)
+	}
+	return nil
+}
+
+// ListOutboxEvents retorna uma página dos eventos da organização, mais recentes primeiro
+func (s *WebhookService) ListOutboxEvents(ctx context.Context, tenantID string, query *models.OutboxEventQuery) (*models.OutboxEventPage, error) {
+	page := query.Page
+	if page < 1 {
+		page = 1
+	}
+	pageSize := query.PageSize
+	if pageSize < 1 {
+		pageSize = defaultOutboxPageSize
+	}
+
+	events, total, err := s.outbox.ListOutboxEvents(ctx, store.OutboxFilter{
+		TenantID:    tenantID,
+		EventType:   query.EventType,
+		AggregateID: query.AggregateID,
+		Limit:       pageSize,
+		Offset:      (page - 1) * pageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.OutboxEventPage{
+		Events:   events,
+		Page:     page,
+		PageSize: pageSize,
+		Total:    total,
+	}, nil
+}
+
+// GetOutboxEvent retorna o evento com o histórico de entregas
+func (s *WebhookService) GetOutboxEvent(ctx context.Context, tenantID, id string) (*models.OutboxEventDetail, error) {
+	event, err := s.getTenantEvent(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries, err := s.outbox.ListDeliveries(ctx, event.ID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.OutboxEventDetail{OutboxEvent: *event, Deliveries: deliveries}, nil
+}
+
+// ReplayOutboxEvent devolve o evento à fila do dispatcher, que cria novas entregas para
+// os webhooks ativos que o assinam. As entregas anteriores permanecem no histórico.
+func (s *WebhookService) ReplayOutboxEvent(ctx context.Context, tenantID, id string) (err error) {
+	event := &models.AuthEvent{Type: models.EventOutboxReplayed, Reason: id}
+	defer func() { s.authService.recordEvent(ctx, event, err) }()
+
+	outboxEvent, err := s.getTenantEvent(ctx, tenantID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.outbox.ResetOutboxEvent(ctx, outboxEvent.ID); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return ErrOutboxEventNotFound
+		}
+		return fmt.Errorf("erro ao reenfileirar evento: %w", err)
+	}
+	return nil
+}
+
+// getTenantEvent busca o evento; eventos de outra organização são tratados como inexistentes
+func (s *WebhookService) getTenantEvent(ctx context.Context, tenantID, id string) (*models.OutboxEvent, error) {
+	event, err := s.outbox.GetOutboxEvent(ctx, id)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, ErrOutboxEventNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar evento do outbox: %w", err)
+	}
+	if event.TenantID.String() != tenantID {
+		return nil, ErrOutboxEventNotFound
+	}
+	return event, nil
+}
+
+// checkWebhookURL exige uma URL absoluta (em produção, apenas https) cujo host resolva
+// somente para endereços públicos. O dispatcher confere de novo o endereço de cada conexão,
+// já que o DNS pode mudar depois do cadastro.
+func (s *WebhookService) checkWebhookURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Hostname() == "" {
+		return ErrInvalidWebhookURL
+	}
+	switch u.Scheme {
+	case "https":
+	case "http":
+		if s.cfg.IsProduction() {
+			return ErrInvalidWebhookURL
+		}
+	default:
+		return ErrInvalidWebhookURL
+	}
+
+	if s.cfg.Webhooks.AllowPrivateNetworks {
+		return nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", u.Hostname())
+	if err != nil || len(addrs) == 0 {
+		return ErrInvalidWebhookURL
+	}
+	for _, addr := range addrs {
+		if !publicAddress(addr) {
+			return ErrInvalidWebhookURL
+		}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"auth-service/config"
+)
+
+func TestPublicAddress(t *testing.T) {
+	tests := []struct {
+		addr   string
+		public bool
+	}{
+		{"8.8.8.8", true},
+		{"2606:4700:4700::1111", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.0.10", false},
+		{"169.254.169.254", false},
+		{"100.100.100.200", false},
+		{"0.0.0.0", false},
+		{"fd00:ec2::254", false},
+		{"fe80::1", false},
+		{"::ffff:127.0.0.1", false},
+		{"64:ff9b::a00:1", false},
+	}
+	for _, tt := range tests {
+		if got := publicAddress(netip.MustParseAddr(tt.addr)); got != tt.public {
+			t.Errorf("publicAddress(%s) = %v, esperado %v", tt.addr, got, tt.public)
+		}
+	}
+}
+
+func TestCheckWebhookURLRejectsInternalHosts(t *testing.T) {
+	s := &WebhookService{cfg: &config.Config{}}
+
+	for _, rawURL := range []string{
+		"http://localhost:8080/hook",
+		"http://127.0.0.1/hook",
+		"http://[::1]/hook",
+		"http://169.254.169.254/latest/meta-data/",
+		"https://10.0.0.5/hook",
+		"ftp://8.8.8.8/hook",
+	} {
+		if err := s.checkWebhookURL(context.Background(), rawURL); !errors.Is(err, ErrInvalidWebhookURL) {
+			t.Errorf("%s: erro %v, esperado %v", rawURL, err, ErrInvalidWebhookURL)
+		}
+	}
+
+	if err := s.checkWebhookURL(context.Background(), "https://8.8.8.8/hook"); err != nil {
+		t.Errorf("endereço público: %v", err)
+	}
+
+	s.cfg.Webhooks.AllowPrivateNetworks = true
+	if err := s.checkWebhookURL(context.Background(), "http://localhost:8080/hook"); err != nil {
+		t.Errorf("redes privadas liberadas: %v", err)
+	}
+}
+
+func TestWebhookClientRefusesPrivateAddresses(t *testing.T) {
+	called := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer srv.Close()
+
+	if _, err := newWebhookClient(time.Second, false).Post(srv.URL, "application/json", nil); err == nil || called {
+		t.Fatalf("o cliente conectou em %s", srv.URL)
+	}
+}
+
+func TestWebhookClientDoesNotFollowRedirects(t *testing.T) {
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("o redirecionamento foi seguido")
+	}))
+	defer internal.Close()
+
+	redirect := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, internal.URL, http.StatusTemporaryRedirect)
+	}))
+	defer redirect.Close()
+
+	resp, err := newWebhookClient(time.Second, true).Post(redirect.URL, "application/json", nil)
+	if err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusTemporaryRedirect {
+		t.Fatalf("status %d, esperado %d", resp.StatusCode, http.StatusTemporaryRedirect)
+	}
+}
@@ -0,0 +1,1229 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"auth-service/models"
+
+	"github.com/google/uuid"
+)
+
+// MemoryStore implementa os stores em memória, para testes e execução sem banco
+type MemoryStore struct {
+	mu            sync.RWMutex
+	users         map[uuid.UUID]models.User
+	identities    map[string]models.UserIdentity // chave: provider + "|" + subject + "|" + user_id
+	clients       map[uuid.UUID]models.Client
+	refreshTokens map[uuid.UUID]models.RefreshToken
+	roles         map[string]map[string]bool // chave: user_id
+	events        []models.AuthEvent
+	personal      map[uuid.UUID]models.PersonalAccessToken
+	magicLinks    map[string]time.Time // chave: jti; valor: expiração
+	codes         map[string]time.Time // códigos de autorização usados; chave: jti; valor: expiração
+	invitations   map[uuid.UUID]models.Invitation
+	organizations map[uuid.UUID]models.Organization
+	deviceCodes   map[uuid.UUID]models.DeviceCode
+	outbox        []models.OutboxEvent // em ordem de criação
+	deliveries    map[uuid.UUID]models.WebhookDelivery
+	webhooks      map[uuid.UUID]models.Webhook
+	knownDevices  map[uuid.UUID]models.KnownDevice
+	passwords     map[uuid.UUID][]models.PasswordHistoryEntry // chave: usuário; mais recentes primeiro
+}
+
+func NewMemoryStore() *MemoryStore {
+	now := time.Now()
+	return &MemoryStore{
+		users:         make(map[uuid.UUID]models.User),
+		identities:    make(map[string]models.UserIdentity),
+		clients:       make(map[uuid.UUID]models.Client),
+		refreshTokens: make(map[uuid.UUID]models.RefreshToken),
+		roles:         make(map[string]map[string]bool),
+		personal:      make(map[uuid.UUID]models.PersonalAccessToken),
+		magicLinks:    make(map[string]time.Time),
+		codes:         make(map[string]time.Time),
+		invitations:   make(map[uuid.UUID]models.Invitation),
+		deviceCodes:   make(map[uuid.UUID]models.DeviceCode),
+		deliveries:    make(map[uuid.UUID]models.WebhookDelivery),
+		knownDevices:  make(map[uuid.UUID]models.KnownDevice),
+		passwords:     make(map[uuid.UUID][]models.PasswordHistoryEntry),
+		webhooks:      make(map[uuid.UUID]models.Webhook),
+		organizations: map[uuid.UUID]models.Organization{
+			uuid.MustParse(models.DefaultTenantID): {
+				ID:        uuid.MustParse(models.DefaultTenantID),
+				Slug:      "default",
+				Name:      "Organização padrão",
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+		},
+	}
+}
+
+// NewMemoryStores retorna todos os stores apoiados em um único MemoryStore
+func NewMemoryStores() *Stores {
+	memoryStore := NewMemoryStore()
+	return &Stores{
+		Users:          memoryStore,
+		Clients:        memoryStore,
+		Tokens:         memoryStore,
+		Events:         memoryStore,
+		PersonalTokens: memoryStore,
+		MagicLinks:     memoryStore,
+		Invitations:    memoryStore,
+		Organizations:  memoryStore,
+		DeviceCodes:    memoryStore,
+		Outbox:         memoryStore,
+		Webhooks:       memoryStore,
+		KnownDevices:   memoryStore,
+		Passwords:      memoryStore,
+		Codes:          memoryStore,
+	}
+}
+
+func (s *MemoryStore) CreateUser(ctx context.Context, user *models.User, roles []string, event *models.OutboxEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.users[user.ID]; exists {
+		return ErrDuplicate
+	}
+	// Emails são comparados exatamente, como no "email = ?" do SQLStore
+	for _, existing := range s.users {
+		if existing.TenantID == user.TenantID && existing.Email == user.Email {
+			return ErrDuplicate
+		}
+	}
+
+	s.users[user.ID] = *user
+	s.grantRoles(user.ID.String(), roles)
+	s.appendOutbox(event)
+	return nil
+}
+
+func (s *MemoryStore) CreateInvitedUser(ctx context.Context, user *models.User, invitationID uuid.UUID, roles []string, now time.Time, event *models.OutboxEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Tudo é conferido antes de alterar os mapas, para que uma falha não deixe nada gravado
+	invitation, ok := s.invitations[invitationID]
+	if !ok || invitation.RevokedAt != nil || !invitation.ExpiresAt.After(now) || invitation.Uses >= invitation.MaxUses {
+		return ErrNotFound
+	}
+	if _, exists := s.users[user.ID]; exists {
+		return ErrDuplicate
+	}
+	for _, existing := range s.users {
+		if existing.TenantID == user.TenantID && existing.Email == user.Email {
+			return ErrDuplicate
+		}
+	}
+
+	invitation.Uses++
+	s.invitations[invitationID] = invitation
+	s.users[user.ID] = *user
+	s.grantRoles(user.ID.String(), roles)
+	s.appendOutbox(event)
+	return nil
+}
+
+// grantRoles concede os papéis ao usuário; deve ser chamado com s.mu bloqueado
+func (s *MemoryStore) grantRoles(userID string, roles []string) {
+	if len(roles) == 0 {
+		return
+	}
+	if s.roles[userID] == nil {
+		s.roles[userID] = make(map[string]bool)
+	}
+	for _, role := range roles {
+		s.roles[userID][role] = true
+	}
+}
+
+func (s *MemoryStore) UpdateUser(ctx context.Context, user *models.User, roles []string, event *models.OutboxEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.users[user.ID]
+	if !ok {
+		return ErrNotFound
+	}
+
+	existing.Name = user.Name
+	existing.Active = user.Active
+	existing.UpdatedAt = user.UpdatedAt
+	s.users[user.ID] = existing
+	s.grantRoles(user.ID.String(), roles)
+	s.appendOutbox(event)
+	return nil
+}
+
+func (s *MemoryStore) UpdateUserPassword(ctx context.Context, id uuid.UUID, password string, updatedAt time.Time, previous *models.PasswordHistoryEntry, keep int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[id]
+	if !ok {
+		return ErrNotFound
+	}
+
+	user.Password = password
+	user.UpdatedAt = updatedAt
+	s.users[id] = user
+
+	if previous != nil {
+		history := append([]models.PasswordHistoryEntry{*previous}, s.passwords[previous.UserID]...)
+		if len(history) > keep {
+			history = history[:keep]
+		}
+		s.passwords[previous.UserID] = history
+	}
+	return nil
+}
+
+func (s *MemoryStore) DeleteUser(ctx context.Context, id uuid.UUID, event *models.OutboxEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[id]; !ok {
+		return ErrNotFound
+	}
+
+	delete(s.users, id)
+	delete(s.roles, id.String())
+	for key, identity := range s.identities {
+		if identity.UserID == id {
+			delete(s.identities, key)
+		}
+	}
+	for tokenID, token := range s.refreshTokens {
+		if token.UserID == id {
+			delete(s.refreshTokens, tokenID)
+		}
+	}
+	for tokenID, token := range s.personal {
+		if token.UserID == id {
+			delete(s.personal, tokenID)
+		}
+	}
+	for codeID, code := range s.deviceCodes {
+		if code.UserID != nil && *code.UserID == id {
+			delete(s.deviceCodes, codeID)
+		}
+	}
+	for deviceID, device := range s.knownDevices {
+		if device.UserID == id {
+			delete(s.knownDevices, deviceID)
+		}
+	}
+	delete(s.passwords, id)
+	for invitationID, invitation := range s.invitations {
+		if invitation.CreatedBy != nil && *invitation.CreatedBy == id {
+			invitation.CreatedBy = nil
+			s.invitations[invitationID] = invitation
+		}
+	}
+	s.appendOutbox(event)
+	return nil
+}
+
+func (s *MemoryStore) GetUserByID(ctx context.Context, id string) (*models.User, error) {
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	user, ok := s.users[userID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &user, nil
+}
+
+func (s *MemoryStore) GetUserByEmail(ctx context.Context, tenantID, email string) (*models.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, user := range s.users {
+		if user.TenantID.String() == tenantID && user.Email == email {
+			return &user, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (s *MemoryStore) ListUsers(ctx context.Context, tenantID string) ([]models.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	users := []models.User{}
+	for _, user := range s.users {
+		if user.TenantID.String() == tenantID {
+			users = append(users, user)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].Email < users[j].Email })
+	return users, nil
+}
+
+func (s *MemoryStore) GetUserByIdentity(ctx context.Context, tenantID, provider, subject string) (*models.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, identity := range s.identities {
+		if identity.Provider != provider || identity.Subject != subject {
+			continue
+		}
+		if user, ok := s.users[identity.UserID]; ok && user.TenantID.String() == tenantID {
+			return &user, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (s *MemoryStore) CreateIdentity(ctx context.Context, identity *models.UserIdentity) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := identity.Provider + "|" + identity.Subject + "|" + identity.UserID.String()
+	if _, exists := s.identities[key]; exists {
+		return ErrDuplicate
+	}
+
+	s.identities[key] = *identity
+	return nil
+}
+
+func (s *MemoryStore) GetUserRoles(ctx context.Context, userID string) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var roles []string
+	for role := range s.roles[userID] {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	return roles, nil
+}
+
+func (s *MemoryStore) AddUserRole(ctx context.Context, userID, role string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.roles[userID][role] {
+		return ErrDuplicate
+	}
+	if s.roles[userID] == nil {
+		s.roles[userID] = make(map[string]bool)
+	}
+	s.roles[userID][role] = true
+	return nil
+}
+
+func (s *MemoryStore) RemoveUserRole(ctx context.Context, userID, role string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.roles[userID][role] {
+		return ErrNotFound
+	}
+	delete(s.roles[userID], role)
+	return nil
+}
+
+func (s *MemoryStore) CreateClient(ctx context.Context, client *models.Client) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.clients[client.ID]; exists {
+		return ErrDuplicate
+	}
+
+	s.clients[client.ID] = *client
+	return nil
+}
+
+func (s *MemoryStore) GetClient(ctx context.Context, id string) (*models.Client, error) {
+	clientID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	client, ok := s.clients[clientID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &client, nil
+}
+
+func (s *MemoryStore) UpdateClient(ctx context.Context, client *models.Client) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.clients[client.ID]; !ok {
+		return ErrNotFound
+	}
+
+	s.clients[client.ID] = *client
+	return nil
+}
+
+func (s *MemoryStore) ListClients(ctx context.Context, tenantID string) ([]models.Client, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	clients := []models.Client{}
+	for _, client := range s.clients {
+		if client.TenantID.String() == tenantID {
+			clients = append(clients, client)
+		}
+	}
+	sort.Slice(clients, func(i, j int) bool { return clients[i].CreatedAt.Before(clients[j].CreatedAt) })
+	return clients, nil
+}
+
+func (s *MemoryStore) CreateRefreshToken(ctx context.Context, token *models.RefreshToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.refreshTokens {
+		if existing.Token == token.Token {
+			return ErrDuplicate
+		}
+	}
+
+	s.refreshTokens[token.ID] = *token
+	return nil
+}
+
+func (s *MemoryStore) GetRefreshToken(ctx context.Context, token, clientID string) (*models.RefreshToken, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, refreshToken := range s.refreshTokens {
+		if refreshToken.Token == token && refreshToken.ClientID.String() == clientID {
+			return &refreshToken, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (s *MemoryStore) RevokeRefreshToken(ctx context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	refreshToken, ok := s.refreshTokens[id]
+	if !ok {
+		return ErrNotFound
+	}
+
+	refreshToken.Revoked = true
+	refreshToken.UpdatedAt = time.Now()
+	s.refreshTokens[id] = refreshToken
+	return nil
+}
+
+func (s *MemoryStore) RevokeUserRefreshTokens(ctx context.Context, userID string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var revoked int64
+	now := time.Now()
+	for id, refreshToken := range s.refreshTokens {
+		if refreshToken.UserID.String() == userID && !refreshToken.Revoked {
+			refreshToken.Revoked = true
+			refreshToken.UpdatedAt = now
+			s.refreshTokens[id] = refreshToken
+			revoked++
+		}
+	}
+	return revoked, nil
+}
+
+func (s *MemoryStore) PurgeRefreshTokens(ctx context.Context, before time.Time, limit int) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var purged int64
+	for id, refreshToken := range s.refreshTokens {
+		if purged >= int64(limit) {
+			break
+		}
+		if refreshToken.ExpiresAt.Before(before) || (refreshToken.Revoked && refreshToken.UpdatedAt.Before(before)) {
+			delete(s.refreshTokens, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func (s *MemoryStore) CreatePersonalToken(ctx context.Context, token *models.PersonalAccessToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.personal {
+		if existing.TokenHash == token.TokenHash {
+			return ErrDuplicate
+		}
+	}
+
+	s.personal[token.ID] = *token
+	return nil
+}
+
+func (s *MemoryStore) GetPersonalTokenByHash(ctx context.Context, tokenHash string) (*models.PersonalAccessToken, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, token := range s.personal {
+		if token.TokenHash == tokenHash {
+			return &token, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (s *MemoryStore) ListPersonalTokens(ctx context.Context, userID string) ([]models.PersonalAccessToken, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tokens := []models.PersonalAccessToken{}
+	for _, token := range s.personal {
+		if token.UserID.String() == userID && token.RevokedAt == nil {
+			tokens = append(tokens, token)
+		}
+	}
+	sort.Slice(tokens, func(i, j int) bool { return tokens[i].CreatedAt.After(tokens[j].CreatedAt) })
+	return tokens, nil
+}
+
+func (s *MemoryStore) RevokeUserPersonalTokens(ctx context.Context, userID string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var revoked int64
+	now := time.Now()
+	for id, token := range s.personal {
+		if token.UserID.String() == userID && token.RevokedAt == nil {
+			token.RevokedAt = &now
+			s.personal[id] = token
+			revoked++
+		}
+	}
+	return revoked, nil
+}
+
+func (s *MemoryStore) RevokePersonalToken(ctx context.Context, id, userID string) error {
+	tokenID, err := uuid.Parse(id)
+	if err != nil {
+		return ErrNotFound
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token, ok := s.personal[tokenID]
+	if !ok || token.UserID.String() != userID || token.RevokedAt != nil {
+		return ErrNotFound
+	}
+
+	now := time.Now()
+	token.RevokedAt = &now
+	s.personal[tokenID] = token
+	return nil
+}
+
+func (s *MemoryStore) TouchPersonalToken(ctx context.Context, id uuid.UUID, usedAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token, ok := s.personal[id]
+	if !ok {
+		return ErrNotFound
+	}
+
+	token.LastUsedAt = &usedAt
+	s.personal[id] = token
+	return nil
+}
+
+func (s *MemoryStore) PurgePersonalTokens(ctx context.Context, before time.Time, limit int) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var purged int64
+	for id, token := range s.personal {
+		if purged >= int64(limit) {
+			break
+		}
+		if (token.ExpiresAt != nil && token.ExpiresAt.Before(before)) || (token.RevokedAt != nil && token.RevokedAt.Before(before)) {
+			delete(s.personal, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func (s *MemoryStore) UseMagicLink(ctx context.Context, jti string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, used := s.magicLinks[jti]; used {
+		return ErrDuplicate
+	}
+
+	s.magicLinks[jti] = expiresAt
+	return nil
+}
+
+func (s *MemoryStore) PurgeMagicLinks(ctx context.Context, before time.Time, limit int) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var purged int64
+	for jti, expiresAt := range s.magicLinks {
+		if purged >= int64(limit) {
+			break
+		}
+		if expiresAt.Before(before) {
+			delete(s.magicLinks, jti)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func (s *MemoryStore) UseAuthorizationCode(ctx context.Context, jti string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, used := s.codes[jti]; used {
+		return ErrDuplicate
+	}
+
+	s.codes[jti] = expiresAt
+	return nil
+}
+
+func (s *MemoryStore) PurgeAuthorizationCodes(ctx context.Context, before time.Time, limit int) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var purged int64
+	for jti, expiresAt := range s.codes {
+		if purged >= int64(limit) {
+			break
+		}
+		if expiresAt.Before(before) {
+			delete(s.codes, jti)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func (s *MemoryStore) CreateEvent(ctx context.Context, event *models.AuthEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.events = append(s.events, *event)
+	return nil
+}
+
+func (s *MemoryStore) ListEvents(ctx context.Context, filter EventFilter) ([]models.AuthEvent, int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	matches := func(event models.AuthEvent) bool {
+		return (filter.TenantID == "" || s.eventInTenant(event, filter.TenantID)) &&
+			(filter.UserID == "" || event.UserID == filter.UserID) &&
+			(filter.ClientID == "" || event.ClientID == filter.ClientID) &&
+			(filter.EventType == "" || event.Type == filter.EventType) &&
+			(filter.Outcome == "" || event.Outcome == filter.Outcome) &&
+			(filter.IP == "" || event.IP == filter.IP) &&
+			(filter.RequestID == "" || event.RequestID == filter.RequestID) &&
+			(filter.From.IsZero() || !event.CreatedAt.Before(filter.From)) &&
+			(filter.To.IsZero() || event.CreatedAt.Before(filter.To))
+	}
+
+	// Eventos são anexados em ordem cronológica; percorrer de trás para frente
+	// entrega os mais recentes primeiro
+	events := []models.AuthEvent{}
+	var total int64
+	for i := len(s.events) - 1; i >= 0; i-- {
+		if !matches(s.events[i]) {
+			continue
+		}
+		if total >= int64(filter.Offset) && len(events) < filter.Limit {
+			events = append(events, s.events[i])
+		}
+		total++
+	}
+	return events, total, nil
+}
+
+func (s *MemoryStore) CreateInvitation(ctx context.Context, invitation *models.Invitation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.invitations {
+		if existing.CodeHash == invitation.CodeHash {
+			return ErrDuplicate
+		}
+	}
+
+	s.invitations[invitation.ID] = *invitation
+	return nil
+}
+
+func (s *MemoryStore) GetInvitationByHash(ctx context.Context, codeHash string) (*models.Invitation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, invitation := range s.invitations {
+		if invitation.CodeHash == codeHash {
+			return &invitation, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (s *MemoryStore) ListInvitations(ctx context.Context, tenantID string) ([]models.Invitation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	invitations := []models.Invitation{}
+	for _, invitation := range s.invitations {
+		if invitation.TenantID.String() == tenantID && invitation.RevokedAt == nil {
+			invitations = append(invitations, invitation)
+		}
+	}
+	sort.Slice(invitations, func(i, j int) bool { return invitations[i].CreatedAt.After(invitations[j].CreatedAt) })
+	return invitations, nil
+}
+
+func (s *MemoryStore) RevokeInvitation(ctx context.Context, tenantID, id string) error {
+	invitationID, err := uuid.Parse(id)
+	if err != nil {
+		return ErrNotFound
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	invitation, ok := s.invitations[invitationID]
+	if !ok || invitation.TenantID.String() != tenantID || invitation.RevokedAt != nil {
+		return ErrNotFound
+	}
+
+	now := time.Now()
+	invitation.RevokedAt = &now
+	s.invitations[invitationID] = invitation
+	return nil
+}
+
+func (s *MemoryStore) PurgeInvitations(ctx context.Context, before time.Time, limit int) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var purged int64
+	for id, invitation := range s.invitations {
+		if purged >= int64(limit) {
+			break
+		}
+		if invitation.ExpiresAt.Before(before) || (invitation.RevokedAt != nil && invitation.RevokedAt.Before(before)) {
+			delete(s.invitations, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func (s *MemoryStore) CreateOrganization(ctx context.Context, organization *models.Organization) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.organizations {
+		if existing.ID == organization.ID || existing.Slug == organization.Slug {
+			return ErrDuplicate
+		}
+	}
+
+	s.organizations[organization.ID] = *organization
+	return nil
+}
+
+func (s *MemoryStore) GetOrganization(ctx context.Context, id string) (*models.Organization, error) {
+	organizationID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	organization, ok := s.organizations[organizationID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &organization, nil
+}
+
+func (s *MemoryStore) GetOrganizationBySlug(ctx context.Context, slug string) (*models.Organization, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, organization := range s.organizations {
+		if organization.Slug == slug {
+			return &organization, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (s *MemoryStore) ListOrganizations(ctx context.Context) ([]models.Organization, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	organizations := []models.Organization{}
+	for _, organization := range s.organizations {
+		organizations = append(organizations, organization)
+	}
+	sort.Slice(organizations, func(i, j int) bool { return organizations[i].Slug < organizations[j].Slug })
+	return organizations, nil
+}
+
+func (s *MemoryStore) CreateDeviceCode(ctx context.Context, code *models.DeviceCode) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.deviceCodes {
+		if existing.DeviceCodeHash == code.DeviceCodeHash || existing.UserCode == code.UserCode {
+			return ErrDuplicate
+		}
+	}
+
+	s.deviceCodes[code.ID] = *code
+	return nil
+}
+
+func (s *MemoryStore) GetDeviceCodeByHash(ctx context.Context, deviceCodeHash string) (*models.DeviceCode, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, code := range s.deviceCodes {
+		if code.DeviceCodeHash == deviceCodeHash {
+			return &code, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (s *MemoryStore) GetDeviceCodeByUserCode(ctx context.Context, userCode string) (*models.DeviceCode, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, code := range s.deviceCodes {
+		if code.UserCode == userCode {
+			return &code, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (s *MemoryStore) TouchDeviceCode(ctx context.Context, id uuid.UUID, polledAt time.Time, interval int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	code, ok := s.deviceCodes[id]
+	if !ok {
+		return ErrNotFound
+	}
+
+	code.LastPolledAt = &polledAt
+	code.Interval = interval
+	s.deviceCodes[id] = code
+	return nil
+}
+
+func (s *MemoryStore) DecideDeviceCode(ctx context.Context, id, userID uuid.UUID, status string, authTime *time.Time, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	code, ok := s.deviceCodes[id]
+	if !ok || code.Status != models.DeviceCodePending || !code.ExpiresAt.After(now) {
+		return ErrNotFound
+	}
+
+	code.Status = status
+	code.UserID = &userID
+	code.AuthTime = authTime
+	s.deviceCodes[id] = code
+	return nil
+}
+
+func (s *MemoryStore) DeleteUserDeviceCodes(ctx context.Context, userID string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deleted int64
+	for id, code := range s.deviceCodes {
+		if code.UserID != nil && code.UserID.String() == userID {
+			delete(s.deviceCodes, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (s *MemoryStore) ConsumeDeviceCode(ctx context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	code, ok := s.deviceCodes[id]
+	if !ok || code.Status != models.DeviceCodeApproved {
+		return ErrNotFound
+	}
+
+	delete(s.deviceCodes, id)
+	return nil
+}
+
+func (s *MemoryStore) PurgeDeviceCodes(ctx context.Context, before time.Time, limit int) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var purged int64
+	for id, code := range s.deviceCodes {
+		if purged >= int64(limit) {
+			break
+		}
+		if code.ExpiresAt.Before(before) {
+			delete(s.deviceCodes, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// eventInTenant indica se o usuário ou o client do evento pertence à organização.
+// Deve ser chamado com s.mu já travado.
+func (s *MemoryStore) eventInTenant(event models.AuthEvent, tenantID string) bool {
+	if userID, err := uuid.Parse(event.UserID); err == nil {
+		if user, ok := s.users[userID]; ok && user.TenantID.String() == tenantID {
+			return true
+		}
+	}
+	if clientID, err := uuid.Parse(event.ClientID); err == nil {
+		if client, ok := s.clients[clientID]; ok && client.TenantID.String() == tenantID {
+			return true
+		}
+	}
+	return false
+}
+
+// appendOutbox grava o evento, se informado. Deve ser chamado com s.mu já travado.
+func (s *MemoryStore) appendOutbox(event *models.OutboxEvent) {
+	if event != nil {
+		s.outbox = append(s.outbox, *event)
+	}
+}
+
+func (s *MemoryStore) ListOutboxEvents(ctx context.Context, filter OutboxFilter) ([]models.OutboxEvent, int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	events := []models.OutboxEvent{}
+	var total int64
+	for i := len(s.outbox) - 1; i >= 0; i-- {
+		event := s.outbox[i]
+		if (filter.TenantID != "" && event.TenantID.String() != filter.TenantID) ||
+			(filter.EventType != "" && event.Type != filter.EventType) ||
+			(filter.AggregateID != "" && event.AggregateID != filter.AggregateID) {
+			continue
+		}
+		if total >= int64(filter.Offset) && len(events) < filter.Limit {
+			events = append(events, event)
+		}
+		total++
+	}
+	return events, total, nil
+}
+
+func (s *MemoryStore) GetOutboxEvent(ctx context.Context, id string) (*models.OutboxEvent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, event := range s.outbox {
+		if event.ID.String() == id {
+			return &event, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (s *MemoryStore) ListUndispatchedEvents(ctx context.Context, limit int) ([]models.OutboxEvent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	events := []models.OutboxEvent{}
+	for _, event := range s.outbox {
+		if len(events) >= limit {
+			break
+		}
+		if event.DispatchedAt == nil {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (s *MemoryStore) DispatchOutboxEvent(ctx context.Context, eventID uuid.UUID, deliveries []models.WebhookDelivery, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.outbox {
+		if s.outbox[i].ID != eventID {
+			continue
+		}
+		if s.outbox[i].DispatchedAt != nil {
+			return ErrNotFound
+		}
+		s.outbox[i].DispatchedAt = &now
+		for _, delivery := range deliveries {
+			s.deliveries[delivery.ID] = delivery
+		}
+		return nil
+	}
+	return ErrNotFound
+}
+
+func (s *MemoryStore) ResetOutboxEvent(ctx context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.outbox {
+		if s.outbox[i].ID == id {
+			s.outbox[i].DispatchedAt = nil
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (s *MemoryStore) ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]models.WebhookDelivery, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	deliveries := []models.WebhookDelivery{}
+	for _, delivery := range s.deliveries {
+		if delivery.Status == models.DeliveryPending && !delivery.NextAttemptAt.After(now) {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].NextAttemptAt.Before(deliveries[j].NextAttemptAt) })
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, nil
+}
+
+func (s *MemoryStore) ClaimDelivery(ctx context.Context, id uuid.UUID, now, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delivery, ok := s.deliveries[id]
+	if !ok || delivery.Status != models.DeliveryPending || delivery.NextAttemptAt.After(now) {
+		return ErrNotFound
+	}
+	delivery.NextAttemptAt = until
+	s.deliveries[id] = delivery
+	return nil
+}
+
+func (s *MemoryStore) UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.deliveries[delivery.ID]; !ok {
+		return ErrNotFound
+	}
+	s.deliveries[delivery.ID] = *delivery
+	return nil
+}
+
+func (s *MemoryStore) ListDeliveries(ctx context.Context, eventID string) ([]models.WebhookDelivery, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	deliveries := []models.WebhookDelivery{}
+	for _, delivery := range s.deliveries {
+		if delivery.EventID.String() == eventID {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].CreatedAt.Before(deliveries[j].CreatedAt) })
+	return deliveries, nil
+}
+
+func (s *MemoryStore) PurgeOutboxEvents(ctx context.Context, before time.Time, limit int) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pending := make(map[uuid.UUID]bool)
+	for _, delivery := range s.deliveries {
+		if delivery.Status == models.DeliveryPending {
+			pending[delivery.EventID] = true
+		}
+	}
+
+	var purged int64
+	kept := s.outbox[:0]
+	for _, event := range s.outbox {
+		if purged < int64(limit) && event.DispatchedAt != nil && event.DispatchedAt.Before(before) && !pending[event.ID] {
+			for id, delivery := range s.deliveries {
+				if delivery.EventID == event.ID {
+					delete(s.deliveries, id)
+				}
+			}
+			purged++
+			continue
+		}
+		kept = append(kept, event)
+	}
+	s.outbox = kept
+	return purged, nil
+}
+
+func (s *MemoryStore) CreateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.webhooks[webhook.ID]; exists {
+		return ErrDuplicate
+	}
+	s.webhooks[webhook.ID] = *webhook
+	return nil
+}
+
+func (s *MemoryStore) GetWebhook(ctx context.Context, id string) (*models.Webhook, error) {
+	webhookID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	webhook, ok := s.webhooks[webhookID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &webhook, nil
+}
+
+func (s *MemoryStore) ListWebhooks(ctx context.Context, tenantID string) ([]models.Webhook, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	webhooks := []models.Webhook{}
+	for _, webhook := range s.webhooks {
+		if webhook.TenantID.String() == tenantID {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	sort.Slice(webhooks, func(i, j int) bool { return webhooks[i].CreatedAt.Before(webhooks[j].CreatedAt) })
+	return webhooks, nil
+}
+
+func (s *MemoryStore) DeleteWebhook(ctx context.Context, tenantID, id string) error {
+	webhookID, err := uuid.Parse(id)
+	if err != nil {
+		return ErrNotFound
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	webhook, ok := s.webhooks[webhookID]
+	if !ok || webhook.TenantID.String() != tenantID {
+		return ErrNotFound
+	}
+	delete(s.webhooks, webhookID)
+	for id, delivery := range s.deliveries {
+		if delivery.WebhookID == webhookID {
+			delete(s.deliveries, id)
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) TouchKnownDevice(ctx context.Context, device *models.KnownDevice) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, existing := range s.knownDevices {
+		if existing.UserID == device.UserID && existing.Fingerprint == device.Fingerprint {
+			existing.LastSeenAt = device.LastSeenAt
+			s.knownDevices[id] = existing
+			*device = existing
+			return false, nil
+		}
+	}
+
+	s.knownDevices[device.ID] = *device
+	return true, nil
+}
+
+func (s *MemoryStore) CountKnownDevices(ctx context.Context, userID string) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	count := 0
+	for _, device := range s.knownDevices {
+		if device.UserID.String() == userID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (s *MemoryStore) DeleteKnownDevice(ctx context.Context, userID, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for deviceID, device := range s.knownDevices {
+		if deviceID.String() == id && device.UserID.String() == userID {
+			delete(s.knownDevices, deviceID)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (s *MemoryStore) ListPasswordHistory(ctx context.Context, userID string, limit int) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, nil
+	}
+
+	var hashes []string
+	for _, entry := range s.passwords[id] {
+		if len(hashes) == limit {
+			break
+		}
+		hashes = append(hashes, entry.PasswordHash)
+	}
+	return hashes, nil
+}
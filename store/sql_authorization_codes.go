@@ -0,0 +1,42 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+func (s *SQLStore) UseAuthorizationCode(ctx context.Context, jti string, expiresAt time.Time) error {
+	ctx, end := s.observe(ctx, "use_authorization_code")
+	defer end()
+
+	// A chave primária em jti garante que só uma das trocas concorrentes consome o código
+	_, err := s.db.DB.ExecContext(ctx, `
+		INSERT INTO used_authorization_codes (jti, expires_at, used_at)
+		VALUES (?, ?, ?)
+	`, jti, expiresAt, time.Now())
+	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrDuplicate
+		}
+		return fmt.Errorf("erro ao registrar uso do código de autorização: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLStore) PurgeAuthorizationCodes(ctx context.Context, before time.Time, limit int) (int64, error) {
+	ctx, end := s.observe(ctx, "purge_authorization_codes")
+	defer end()
+
+	result, err := s.db.DB.ExecContext(ctx, `
+		DELETE FROM used_authorization_codes WHERE jti IN (
+			SELECT jti FROM (
+				SELECT jti FROM used_authorization_codes WHERE expires_at < ? LIMIT ?
+			) AS batch
+		)
+	`, before, limit)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao remover códigos de autorização usados: %w", err)
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,107 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"auth-service/models"
+)
+
+const clientColumns = "id, tenant_id, name, description, secret, active, magic_link_enabled, redirect_uris, created_at, updated_at"
+
+func (s *SQLStore) CreateClient(ctx context.Context, client *models.Client) error {
+	ctx, end := s.observe(ctx, "create_client")
+	defer end()
+
+	_, err := s.db.DB.ExecContext(ctx, `
+		INSERT INTO clients (id, tenant_id, name, description, secret, active, magic_link_enabled, redirect_uris, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, client.ID, client.TenantID, client.Name, client.Description, client.Secret, client.Active, client.MagicLinkEnabled,
+		strings.Join(client.RedirectURIs, " "), client.CreatedAt, client.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("erro ao criar cliente: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLStore) GetClient(ctx context.Context, id string) (*models.Client, error) {
+	ctx, end := s.observe(ctx, "get_client")
+	defer end()
+
+	client, err := scanClient(s.db.DB.QueryRowContext(ctx, "SELECT "+clientColumns+" FROM clients WHERE id = ?", id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("erro ao verificar cliente: %w", err)
+	}
+	return client, nil
+}
+
+func (s *SQLStore) ListClients(ctx context.Context, tenantID string) ([]models.Client, error) {
+	ctx, end := s.observe(ctx, "list_clients")
+	defer end()
+
+	rows, err := s.db.DB.QueryContext(ctx, "SELECT "+clientColumns+`
+		FROM clients
+		WHERE tenant_id = ?
+		ORDER BY created_at
+	`, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar clientes: %w", err)
+	}
+	defer rows.Close()
+
+	clients := []models.Client{}
+	for rows.Next() {
+		client, err := scanClient(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao listar clientes: %w", err)
+		}
+		clients = append(clients, *client)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao listar clientes: %w", err)
+	}
+	return clients, nil
+}
+
+func (s *SQLStore) UpdateClient(ctx context.Context, client *models.Client) error {
+	ctx, end := s.observe(ctx, "update_client")
+	defer end()
+
+	result, err := s.db.DB.ExecContext(ctx, `
+		UPDATE clients SET name = ?, description = ?, secret = ?, active = ?, magic_link_enabled = ?, redirect_uris = ?, updated_at = ?
+		WHERE id = ?
+	`, client.Name, client.Description, client.Secret, client.Active, client.MagicLinkEnabled, strings.Join(client.RedirectURIs, " "),
+		client.UpdatedAt, client.ID)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar cliente: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar cliente: %w", err)
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func scanClient(row rowScanner) (*models.Client, error) {
+	var client models.Client
+	var description sql.NullString
+	var redirectURIs string
+	err := row.Scan(&client.ID, &client.TenantID, &client.Name, &description, &client.Secret, &client.Active, &client.MagicLinkEnabled,
+		&redirectURIs, &client.CreatedAt, &client.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	client.Description = description.String
+	// As URIs são guardadas separadas por espaço, como o parâmetro scope
+	client.RedirectURIs = strings.Fields(redirectURIs)
+	return &client, nil
+}
@@ -0,0 +1,152 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"auth-service/models"
+
+	"github.com/google/uuid"
+)
+
+const deviceCodeColumns = "id, device_code_hash, user_code, client_id, scope, status, user_id, interval_seconds, last_polled_at, auth_time, expires_at, created_at"
+
+func (s *SQLStore) CreateDeviceCode(ctx context.Context, code *models.DeviceCode) error {
+	ctx, end := s.observe(ctx, "create_device_code")
+	defer end()
+
+	_, err := s.db.DB.ExecContext(ctx, `
+		INSERT INTO device_codes (id, device_code_hash, user_code, client_id, scope, status, interval_seconds, expires_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, code.ID, code.DeviceCodeHash, code.UserCode, code.ClientID, code.Scope, code.Status, code.Interval, code.ExpiresAt, code.CreatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrDuplicate
+		}
+		return fmt.Errorf("erro ao salvar autorização de dispositivo: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLStore) GetDeviceCodeByHash(ctx context.Context, deviceCodeHash string) (*models.DeviceCode, error) {
+	ctx, end := s.observe(ctx, "get_device_code")
+	defer end()
+
+	row := s.db.DB.QueryRowContext(ctx, "SELECT "+deviceCodeColumns+" FROM device_codes WHERE device_code_hash = ?", deviceCodeHash)
+	return getDeviceCode(row)
+}
+
+func (s *SQLStore) GetDeviceCodeByUserCode(ctx context.Context, userCode string) (*models.DeviceCode, error) {
+	ctx, end := s.observe(ctx, "get_device_code_by_user_code")
+	defer end()
+
+	row := s.db.DB.QueryRowContext(ctx, "SELECT "+deviceCodeColumns+" FROM device_codes WHERE user_code = ?", userCode)
+	return getDeviceCode(row)
+}
+
+func (s *SQLStore) TouchDeviceCode(ctx context.Context, id uuid.UUID, polledAt time.Time, interval int) error {
+	ctx, end := s.observe(ctx, "touch_device_code")
+	defer end()
+
+	_, err := s.db.DB.ExecContext(ctx, `
+		UPDATE device_codes SET last_polled_at = ?, interval_seconds = ? WHERE id = ?
+	`, polledAt, interval, id)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar autorização de dispositivo: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLStore) DecideDeviceCode(ctx context.Context, id, userID uuid.UUID, status string, authTime *time.Time, now time.Time) error {
+	ctx, end := s.observe(ctx, "decide_device_code")
+	defer end()
+
+	// As condições no UPDATE impedem que duas decisões concorrentes se sobreponham
+	result, err := s.db.DB.ExecContext(ctx, `
+		UPDATE device_codes SET status = ?, user_id = ?, auth_time = ?
+		WHERE id = ? AND status = ? AND expires_at > ?
+	`, status, userID, authTime, id, models.DeviceCodePending, now)
+	if err != nil {
+		return fmt.Errorf("erro ao decidir autorização de dispositivo: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("erro ao decidir autorização de dispositivo: %w", err)
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *SQLStore) DeleteUserDeviceCodes(ctx context.Context, userID string) (int64, error) {
+	ctx, end := s.observe(ctx, "delete_user_device_codes")
+	defer end()
+
+	result, err := s.db.DB.ExecContext(ctx, "DELETE FROM device_codes WHERE user_id = ?", userID)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao remover autorizações de dispositivo: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+func (s *SQLStore) ConsumeDeviceCode(ctx context.Context, id uuid.UUID) error {
+	ctx, end := s.observe(ctx, "consume_device_code")
+	defer end()
+
+	result, err := s.db.DB.ExecContext(ctx, "DELETE FROM device_codes WHERE id = ? AND status = ?", id, models.DeviceCodeApproved)
+	if err != nil {
+		return fmt.Errorf("erro ao consumir autorização de dispositivo: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("erro ao consumir autorização de dispositivo: %w", err)
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *SQLStore) PurgeDeviceCodes(ctx context.Context, before time.Time, limit int) (int64, error) {
+	ctx, end := s.observe(ctx, "purge_device_codes")
+	defer end()
+
+	result, err := s.db.DB.ExecContext(ctx, `
+		DELETE FROM device_codes WHERE id IN (
+			SELECT id FROM (
+				SELECT id FROM device_codes WHERE expires_at < ? LIMIT ?
+			) AS batch
+		)
+	`, before, limit)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao remover autorizações de dispositivo: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+func getDeviceCode(row rowScanner) (*models.DeviceCode, error) {
+	var code models.DeviceCode
+	var userID sql.NullString
+	var lastPolledAt, authTime sql.NullTime
+	err := row.Scan(&code.ID, &code.DeviceCodeHash, &code.UserCode, &code.ClientID, &code.Scope, &code.Status, &userID,
+		&code.Interval, &lastPolledAt, &authTime, &code.ExpiresAt, &code.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar autorização de dispositivo: %w", err)
+	}
+	if userID.Valid {
+		if id, err := uuid.Parse(userID.String); err == nil {
+			code.UserID = &id
+		}
+	}
+	code.LastPolledAt = timePtr(lastPolledAt)
+	code.AuthTime = timePtr(authTime)
+	return &code, nil
+}
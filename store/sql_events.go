@@ -0,0 +1,115 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"auth-service/models"
+)
+
+func (s *SQLStore) CreateEvent(ctx context.Context, event *models.AuthEvent) error {
+	ctx, end := s.observe(ctx, "create_event")
+	defer end()
+
+	_, err := s.db.DB.ExecContext(ctx, `
+		INSERT INTO auth_events (id, event_type, user_id, client_id, ip, user_agent, outcome, reason, request_id, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, event.ID, event.Type, nullString(event.UserID), nullString(event.ClientID), event.IP, event.UserAgent,
+		event.Outcome, nullString(event.Reason), nullString(event.RequestID), event.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("erro ao registrar evento: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLStore) ListEvents(ctx context.Context, filter EventFilter) ([]models.AuthEvent, int64, error) {
+	ctx, end := s.observe(ctx, "list_events")
+	defer end()
+
+	var conditions []string
+	var args []interface{}
+
+	addCondition := func(condition string, value interface{}) {
+		conditions = append(conditions, condition)
+		args = append(args, value)
+	}
+
+	if filter.TenantID != "" {
+		conditions = append(conditions, "(user_id IN (SELECT id FROM users WHERE tenant_id = ?) OR client_id IN (SELECT id FROM clients WHERE tenant_id = ?))")
+		args = append(args, filter.TenantID, filter.TenantID)
+	}
+	if filter.UserID != "" {
+		addCondition("user_id = ?", filter.UserID)
+	}
+	if filter.ClientID != "" {
+		addCondition("client_id = ?", filter.ClientID)
+	}
+	if filter.EventType != "" {
+		addCondition("event_type = ?", filter.EventType)
+	}
+	if filter.Outcome != "" {
+		addCondition("outcome = ?", filter.Outcome)
+	}
+	if filter.IP != "" {
+		addCondition("ip = ?", filter.IP)
+	}
+	if filter.RequestID != "" {
+		addCondition("request_id = ?", filter.RequestID)
+	}
+	if !filter.From.IsZero() {
+		addCondition("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		addCondition("created_at < ?", filter.To)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int64
+	if err := s.db.DB.QueryRowContext(ctx, "SELECT COUNT(*) FROM auth_events"+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("erro ao contar eventos: %w", err)
+	}
+
+	rows, err := s.db.DB.QueryContext(ctx, `
+		SELECT id, event_type, user_id, client_id, ip, user_agent, outcome, reason, request_id, created_at
+		FROM auth_events`+where+`
+		ORDER BY created_at DESC
+		LIMIT ? OFFSET ?
+	`, append(args, filter.Limit, filter.Offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("erro ao listar eventos: %w", err)
+	}
+	defer rows.Close()
+
+	events := []models.AuthEvent{}
+	for rows.Next() {
+		var event models.AuthEvent
+		var userID, clientID, ip, userAgent, reason, requestID sql.NullString
+		err := rows.Scan(&event.ID, &event.Type, &userID, &clientID, &ip, &userAgent, &event.Outcome, &reason, &requestID, &event.CreatedAt)
+		if err != nil {
+			return nil, 0, fmt.Errorf("erro ao listar eventos: %w", err)
+		}
+		event.UserID = userID.String
+		event.ClientID = clientID.String
+		event.IP = ip.String
+		event.UserAgent = userAgent.String
+		event.Reason = reason.String
+		event.RequestID = requestID.String
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("erro ao listar eventos: %w", err)
+	}
+
+	return events, total, nil
+}
+
+// nullString grava NULL no lugar de strings vazias
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
@@ -0,0 +1,163 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"auth-service/models"
+
+	"github.com/google/uuid"
+)
+
+const invitationColumns = "id, tenant_id, code_hash, prefix, roles, max_uses, uses, expires_at, revoked_at, created_by, created_at"
+
+func (s *SQLStore) CreateInvitation(ctx context.Context, invitation *models.Invitation) error {
+	ctx, end := s.observe(ctx, "create_invitation")
+	defer end()
+
+	var createdBy sql.NullString
+	if invitation.CreatedBy != nil {
+		createdBy = sql.NullString{String: invitation.CreatedBy.String(), Valid: true}
+	}
+
+	_, err := s.db.DB.ExecContext(ctx, `
+		INSERT INTO invitations (id, tenant_id, code_hash, prefix, roles, max_uses, uses, expires_at, created_by, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, invitation.ID, invitation.TenantID, invitation.CodeHash, invitation.Prefix, strings.Join(invitation.Roles, " "), invitation.MaxUses,
+		invitation.Uses, invitation.ExpiresAt, createdBy, invitation.CreatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrDuplicate
+		}
+		return fmt.Errorf("erro ao salvar convite: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLStore) GetInvitationByHash(ctx context.Context, codeHash string) (*models.Invitation, error) {
+	ctx, end := s.observe(ctx, "get_invitation")
+	defer end()
+
+	row := s.db.DB.QueryRowContext(ctx, "SELECT "+invitationColumns+" FROM invitations WHERE code_hash = ?", codeHash)
+	invitation, err := scanInvitation(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar convite: %w", err)
+	}
+	return invitation, nil
+}
+
+func (s *SQLStore) ListInvitations(ctx context.Context, tenantID string) ([]models.Invitation, error) {
+	ctx, end := s.observe(ctx, "list_invitations")
+	defer end()
+
+	rows, err := s.db.DB.QueryContext(ctx, "SELECT "+invitationColumns+`
+		FROM invitations
+		WHERE tenant_id = ? AND revoked_at IS NULL
+		ORDER BY created_at DESC
+	`, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar convites: %w", err)
+	}
+	defer rows.Close()
+
+	invitations := []models.Invitation{}
+	for rows.Next() {
+		invitation, err := scanInvitation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao listar convites: %w", err)
+		}
+		invitations = append(invitations, *invitation)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao listar convites: %w", err)
+	}
+	return invitations, nil
+}
+
+// useInvitation consome um uso do convite na transação do cadastro. As condições no UPDATE
+// garantem que cadastros concorrentes não ultrapassem max_uses.
+func useInvitation(ctx context.Context, tx *sql.Tx, id uuid.UUID, now time.Time) error {
+	result, err := tx.ExecContext(ctx, `
+		UPDATE invitations SET uses = uses + 1
+		WHERE id = ? AND revoked_at IS NULL AND expires_at > ? AND uses < max_uses
+	`, id, now)
+	if err != nil {
+		return fmt.Errorf("erro ao usar convite: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("erro ao usar convite: %w", err)
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *SQLStore) RevokeInvitation(ctx context.Context, tenantID, id string) error {
+	ctx, end := s.observe(ctx, "revoke_invitation")
+	defer end()
+
+	result, err := s.db.DB.ExecContext(ctx, `
+		UPDATE invitations SET revoked_at = ?
+		WHERE id = ? AND tenant_id = ? AND revoked_at IS NULL
+	`, time.Now(), id, tenantID)
+	if err != nil {
+		return fmt.Errorf("erro ao revogar convite: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("erro ao revogar convite: %w", err)
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *SQLStore) PurgeInvitations(ctx context.Context, before time.Time, limit int) (int64, error) {
+	ctx, end := s.observe(ctx, "purge_invitations")
+	defer end()
+
+	result, err := s.db.DB.ExecContext(ctx, `
+		DELETE FROM invitations WHERE id IN (
+			SELECT id FROM (
+				SELECT id FROM invitations
+				WHERE expires_at < ? OR revoked_at < ?
+				LIMIT ?
+			) AS batch
+		)
+	`, before, before, limit)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao remover convites: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+func scanInvitation(row rowScanner) (*models.Invitation, error) {
+	var invitation models.Invitation
+	var roles string
+	var revokedAt sql.NullTime
+	var createdBy sql.NullString
+	err := row.Scan(&invitation.ID, &invitation.TenantID, &invitation.CodeHash, &invitation.Prefix, &roles, &invitation.MaxUses, &invitation.Uses,
+		&invitation.ExpiresAt, &revokedAt, &createdBy, &invitation.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	invitation.Roles = strings.Fields(roles)
+	invitation.RevokedAt = timePtr(revokedAt)
+	if createdBy.Valid {
+		if id, err := uuid.Parse(createdBy.String); err == nil {
+			invitation.CreatedBy = &id
+		}
+	}
+	return &invitation, nil
+}
@@ -0,0 +1,63 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"auth-service/models"
+)
+
+// TouchKnownDevice tenta primeiro a atualização, que é o caso comum. Se dois logins do mesmo
+// dispositivo novo chegarem juntos, o segundo INSERT viola a unicidade e o dispositivo é
+// tratado como conhecido, para que apenas um aviso seja enviado.
+func (s *SQLStore) TouchKnownDevice(ctx context.Context, device *models.KnownDevice) (bool, error) {
+	ctx, end := s.observe(ctx, "touch_known_device")
+	defer end()
+
+	result, err := s.db.DB.ExecContext(ctx, `
+		UPDATE known_devices SET last_seen_at = ? WHERE user_id = ? AND fingerprint = ?
+	`, device.LastSeenAt, device.UserID, device.Fingerprint)
+	if err != nil {
+		return false, fmt.Errorf("erro ao atualizar dispositivo: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected > 0 {
+		return false, nil
+	}
+
+	_, err = s.db.DB.ExecContext(ctx, `
+		INSERT INTO known_devices (id, user_id, fingerprint, user_agent, ip_prefix, first_seen_at, last_seen_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, device.ID, device.UserID, device.Fingerprint, device.UserAgent, device.IPPrefix, device.FirstSeenAt, device.LastSeenAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("erro ao registrar dispositivo: %w", err)
+	}
+	return true, nil
+}
+
+func (s *SQLStore) CountKnownDevices(ctx context.Context, userID string) (int, error) {
+	ctx, end := s.observe(ctx, "count_known_devices")
+	defer end()
+
+	var count int
+	if err := s.db.DB.QueryRowContext(ctx, "SELECT COUNT(*) FROM known_devices WHERE user_id = ?", userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("erro ao contar dispositivos: %w", err)
+	}
+	return count, nil
+}
+
+func (s *SQLStore) DeleteKnownDevice(ctx context.Context, userID, id string) error {
+	ctx, end := s.observe(ctx, "delete_known_device")
+	defer end()
+
+	result, err := s.db.DB.ExecContext(ctx, "DELETE FROM known_devices WHERE id = ? AND user_id = ?", id, userID)
+	if err != nil {
+		return fmt.Errorf("erro ao remover dispositivo: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+func (s *SQLStore) UseMagicLink(ctx context.Context, jti string, expiresAt time.Time) error {
+	ctx, end := s.observe(ctx, "use_magic_link")
+	defer end()
+
+	// A chave primária em jti garante que só uma das requisições concorrentes consome o link
+	_, err := s.db.DB.ExecContext(ctx, `
+		INSERT INTO used_magic_links (jti, expires_at, used_at)
+		VALUES (?, ?, ?)
+	`, jti, expiresAt, time.Now())
+	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrDuplicate
+		}
+		return fmt.Errorf("erro ao registrar uso do link de acesso: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLStore) PurgeMagicLinks(ctx context.Context, before time.Time, limit int) (int64, error) {
+	ctx, end := s.observe(ctx, "purge_magic_links")
+	defer end()
+
+	result, err := s.db.DB.ExecContext(ctx, `
+		DELETE FROM used_magic_links WHERE jti IN (
+			SELECT jti FROM (
+				SELECT jti FROM used_magic_links WHERE expires_at < ? LIMIT ?
+			) AS batch
+		)
+	`, before, limit)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao remover links de acesso usados: %w", err)
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,78 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"auth-service/models"
+)
+
+const organizationColumns = "id, slug, name, created_at, updated_at"
+
+func (s *SQLStore) CreateOrganization(ctx context.Context, organization *models.Organization) error {
+	ctx, end := s.observe(ctx, "create_organization")
+	defer end()
+
+	_, err := s.db.DB.ExecContext(ctx, `
+		INSERT INTO organizations (id, slug, name, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, organization.ID, organization.Slug, organization.Name, organization.CreatedAt, organization.UpdatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrDuplicate
+		}
+		return fmt.Errorf("erro ao criar organização: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLStore) GetOrganization(ctx context.Context, id string) (*models.Organization, error) {
+	ctx, end := s.observe(ctx, "get_organization")
+	defer end()
+
+	return scanOrganization(s.db.DB.QueryRowContext(ctx, "SELECT "+organizationColumns+" FROM organizations WHERE id = ?", id))
+}
+
+func (s *SQLStore) GetOrganizationBySlug(ctx context.Context, slug string) (*models.Organization, error) {
+	ctx, end := s.observe(ctx, "get_organization_by_slug")
+	defer end()
+
+	return scanOrganization(s.db.DB.QueryRowContext(ctx, "SELECT "+organizationColumns+" FROM organizations WHERE slug = ?", slug))
+}
+
+func (s *SQLStore) ListOrganizations(ctx context.Context) ([]models.Organization, error) {
+	ctx, end := s.observe(ctx, "list_organizations")
+	defer end()
+
+	rows, err := s.db.DB.QueryContext(ctx, "SELECT "+organizationColumns+" FROM organizations ORDER BY slug")
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar organizações: %w", err)
+	}
+	defer rows.Close()
+
+	organizations := []models.Organization{}
+	for rows.Next() {
+		var organization models.Organization
+		if err := rows.Scan(&organization.ID, &organization.Slug, &organization.Name, &organization.CreatedAt, &organization.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("erro ao listar organizações: %w", err)
+		}
+		organizations = append(organizations, organization)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao listar organizações: %w", err)
+	}
+	return organizations, nil
+}
+
+func scanOrganization(row *sql.Row) (*models.Organization, error) {
+	var organization models.Organization
+	err := row.Scan(&organization.ID, &organization.Slug, &organization.Name, &organization.CreatedAt, &organization.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar organização: %w", err)
+	}
+	return &organization, nil
+}
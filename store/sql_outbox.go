@@ -0,0 +1,347 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"auth-service/models"
+
+	"github.com/google/uuid"
+)
+
+const (
+	outboxColumns   = "id, tenant_id, event_type, aggregate_id, payload, dispatched_at, created_at"
+	deliveryColumns = "id, event_id, webhook_id, status, attempts, next_attempt_at, last_status_code, last_error, delivered_at, created_at, updated_at"
+)
+
+// withOutbox executa write e grava o evento no outbox na mesma transação. Sem evento, a
+// transação contém apenas a alteração.
+func (s *SQLStore) withOutbox(ctx context.Context, event *models.OutboxEvent, write func(tx *sql.Tx) error) error {
+	tx, err := s.db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := write(tx); err != nil {
+		return err
+	}
+
+	if event != nil {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO outbox_events (id, tenant_id, event_type, aggregate_id, payload, created_at)
+			VALUES (?, ?, ?, ?, ?, ?)
+		`, event.ID, event.TenantID, event.Type, event.AggregateID, string(event.Payload), event.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("erro ao gravar evento no outbox: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLStore) ListOutboxEvents(ctx context.Context, filter OutboxFilter) ([]models.OutboxEvent, int64, error) {
+	ctx, end := s.observe(ctx, "list_outbox_events")
+	defer end()
+
+	var conditions []string
+	var args []interface{}
+
+	addCondition := func(condition string, value interface{}) {
+		conditions = append(conditions, condition)
+		args = append(args, value)
+	}
+
+	if filter.TenantID != "" {
+		addCondition("tenant_id = ?", filter.TenantID)
+	}
+	if filter.EventType != "" {
+		addCondition("event_type = ?", filter.EventType)
+	}
+	if filter.AggregateID != "" {
+		addCondition("aggregate_id = ?", filter.AggregateID)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int64
+	if err := s.db.DB.QueryRowContext(ctx, "SELECT COUNT(*) FROM outbox_events"+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("erro ao contar eventos do outbox: %w", err)
+	}
+
+	rows, err := s.db.DB.QueryContext(ctx, "SELECT "+outboxColumns+`
+		FROM outbox_events`+where+`
+		ORDER BY created_at DESC
+		LIMIT ? OFFSET ?
+	`, append(args, filter.Limit, filter.Offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("erro ao listar eventos do outbox: %w", err)
+	}
+	defer rows.Close()
+
+	events, err := scanOutboxEvents(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return events, total, nil
+}
+
+func (s *SQLStore) GetOutboxEvent(ctx context.Context, id string) (*models.OutboxEvent, error) {
+	ctx, end := s.observe(ctx, "get_outbox_event")
+	defer end()
+
+	event, err := scanOutboxEvent(s.db.DB.QueryRowContext(ctx, "SELECT "+outboxColumns+" FROM outbox_events WHERE id = ?", id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar evento do outbox: %w", err)
+	}
+	return event, nil
+}
+
+func (s *SQLStore) ListUndispatchedEvents(ctx context.Context, limit int) ([]models.OutboxEvent, error) {
+	ctx, end := s.observe(ctx, "list_undispatched_events")
+	defer end()
+
+	rows, err := s.db.DB.QueryContext(ctx, "SELECT "+outboxColumns+`
+		FROM outbox_events
+		WHERE dispatched_at IS NULL
+		ORDER BY created_at
+		LIMIT ?
+	`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar eventos do outbox: %w", err)
+	}
+	defer rows.Close()
+
+	return scanOutboxEvents(rows)
+}
+
+func (s *SQLStore) DispatchOutboxEvent(ctx context.Context, eventID uuid.UUID, deliveries []models.WebhookDelivery, now time.Time) error {
+	ctx, end := s.observe(ctx, "dispatch_outbox_event")
+	defer end()
+
+	tx, err := s.db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE outbox_events SET dispatched_at = ? WHERE id = ? AND dispatched_at IS NULL
+	`, now, eventID)
+	if err != nil {
+		return fmt.Errorf("erro ao distribuir evento do outbox: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrNotFound
+	}
+
+	for _, delivery := range deliveries {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO webhook_deliveries (id, event_id, webhook_id, status, attempts, next_attempt_at, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		`, delivery.ID, delivery.EventID, delivery.WebhookID, delivery.Status, delivery.Attempts, delivery.NextAttemptAt, delivery.CreatedAt, delivery.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("erro ao criar entrega de webhook: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLStore) ResetOutboxEvent(ctx context.Context, id uuid.UUID) error {
+	ctx, end := s.observe(ctx, "reset_outbox_event")
+	defer end()
+
+	result, err := s.db.DB.ExecContext(ctx, "UPDATE outbox_events SET dispatched_at = NULL WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("erro ao reenfileirar evento do outbox: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *SQLStore) ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]models.WebhookDelivery, error) {
+	ctx, end := s.observe(ctx, "list_due_deliveries")
+	defer end()
+
+	rows, err := s.db.DB.QueryContext(ctx, "SELECT "+deliveryColumns+`
+		FROM webhook_deliveries
+		WHERE status = ? AND next_attempt_at <= ?
+		ORDER BY next_attempt_at
+		LIMIT ?
+	`, models.DeliveryPending, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar entregas de webhook: %w", err)
+	}
+	defer rows.Close()
+
+	return scanDeliveries(rows)
+}
+
+func (s *SQLStore) ClaimDelivery(ctx context.Context, id uuid.UUID, now, until time.Time) error {
+	ctx, end := s.observe(ctx, "claim_delivery")
+	defer end()
+
+	result, err := s.db.DB.ExecContext(ctx, `
+		UPDATE webhook_deliveries SET next_attempt_at = ?
+		WHERE id = ? AND status = ? AND next_attempt_at <= ?
+	`, until, id, models.DeliveryPending, now)
+	if err != nil {
+		return fmt.Errorf("erro ao reservar entrega de webhook: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *SQLStore) UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	ctx, end := s.observe(ctx, "update_delivery")
+	defer end()
+
+	_, err := s.db.DB.ExecContext(ctx, `
+		UPDATE webhook_deliveries
+		SET status = ?, attempts = ?, next_attempt_at = ?, last_status_code = ?, last_error = ?, delivered_at = ?, updated_at = ?
+		WHERE id = ?
+	`, delivery.Status, delivery.Attempts, delivery.NextAttemptAt, delivery.LastStatusCode, delivery.LastError,
+		delivery.DeliveredAt, delivery.UpdatedAt, delivery.ID)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar entrega de webhook: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLStore) ListDeliveries(ctx context.Context, eventID string) ([]models.WebhookDelivery, error) {
+	ctx, end := s.observe(ctx, "list_deliveries")
+	defer end()
+
+	rows, err := s.db.DB.QueryContext(ctx, "SELECT "+deliveryColumns+`
+		FROM webhook_deliveries
+		WHERE event_id = ?
+		ORDER BY created_at
+	`, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar entregas de webhook: %w", err)
+	}
+	defer rows.Close()
+
+	return scanDeliveries(rows)
+}
+
+// PurgeOutboxEvents seleciona o lote antes de remover porque o MySQL não aceita, no mesmo
+// DELETE, uma subconsulta em outra tabela que referencie a tabela alterada
+func (s *SQLStore) PurgeOutboxEvents(ctx context.Context, before time.Time, limit int) (int64, error) {
+	ctx, end := s.observe(ctx, "purge_outbox_events")
+	defer end()
+
+	rows, err := s.db.DB.QueryContext(ctx, `
+		SELECT id FROM outbox_events e
+		WHERE e.dispatched_at IS NOT NULL AND e.dispatched_at < ?
+		AND NOT EXISTS (SELECT 1 FROM webhook_deliveries d WHERE d.event_id = e.id AND d.status = ?)
+		LIMIT ?
+	`, before, models.DeliveryPending, limit)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao remover eventos do outbox: %w", err)
+	}
+
+	var ids []interface{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("erro ao remover eventos do outbox: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("erro ao remover eventos do outbox: %w", err)
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ")
+
+	tx, err := s.db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM webhook_deliveries WHERE event_id IN ("+placeholders+")", ids...); err != nil {
+		return 0, fmt.Errorf("erro ao remover entregas de webhook: %w", err)
+	}
+	result, err := tx.ExecContext(ctx, "DELETE FROM outbox_events WHERE id IN ("+placeholders+")", ids...)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao remover eventos do outbox: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+func scanOutboxEvent(row rowScanner) (*models.OutboxEvent, error) {
+	var event models.OutboxEvent
+	var payload string
+	var dispatchedAt sql.NullTime
+	err := row.Scan(&event.ID, &event.TenantID, &event.Type, &event.AggregateID, &payload, &dispatchedAt, &event.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	event.Payload = []byte(payload)
+	event.DispatchedAt = timePtr(dispatchedAt)
+	return &event, nil
+}
+
+func scanOutboxEvents(rows *sql.Rows) ([]models.OutboxEvent, error) {
+	events := []models.OutboxEvent{}
+	for rows.Next() {
+		event, err := scanOutboxEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao listar eventos do outbox: %w", err)
+		}
+		events = append(events, *event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao listar eventos do outbox: %w", err)
+	}
+	return events, nil
+}
+
+func scanDeliveries(rows *sql.Rows) ([]models.WebhookDelivery, error) {
+	deliveries := []models.WebhookDelivery{}
+	for rows.Next() {
+		var delivery models.WebhookDelivery
+		var deliveredAt sql.NullTime
+		err := rows.Scan(&delivery.ID, &delivery.EventID, &delivery.WebhookID, &delivery.Status, &delivery.Attempts, &delivery.NextAttemptAt,
+			&delivery.LastStatusCode, &delivery.LastError, &deliveredAt, &delivery.CreatedAt, &delivery.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao listar entregas de webhook: %w", err)
+		}
+		delivery.DeliveredAt = timePtr(deliveredAt)
+		deliveries = append(deliveries, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao listar entregas de webhook: %w", err)
+	}
+	return deliveries, nil
+}
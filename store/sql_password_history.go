@@ -0,0 +1,73 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"auth-service/models"
+)
+
+// addPasswordHistory grava o hash e poda o histórico na transação da troca de senha. A
+// poda é feita em Go porque o MySQL não aceita LIMIT em subconsultas com IN.
+func addPasswordHistory(ctx context.Context, tx *sql.Tx, entry *models.PasswordHistoryEntry, keep int) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO password_history (id, user_id, password_hash, created_at)
+		VALUES (?, ?, ?, ?)
+	`, entry.ID, entry.UserID, entry.PasswordHash, entry.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("erro ao registrar senha no histórico: %w", err)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id FROM password_history WHERE user_id = ? ORDER BY created_at DESC, id
+	`, entry.UserID)
+	if err != nil {
+		return fmt.Errorf("erro ao listar histórico de senhas: %w", err)
+	}
+	var expired []string
+	for position := 0; rows.Next(); position++ {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return fmt.Errorf("erro ao ler histórico de senhas: %w", err)
+		}
+		if position >= keep {
+			expired = append(expired, id)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("erro ao listar histórico de senhas: %w", err)
+	}
+
+	for _, id := range expired {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM password_history WHERE id = ?", id); err != nil {
+			return fmt.Errorf("erro ao podar histórico de senhas: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *SQLStore) ListPasswordHistory(ctx context.Context, userID string, limit int) ([]string, error) {
+	ctx, end := s.observe(ctx, "list_password_history")
+	defer end()
+
+	rows, err := s.db.DB.QueryContext(ctx, `
+		SELECT password_hash FROM password_history WHERE user_id = ? ORDER BY created_at DESC, id LIMIT ?
+	`, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar histórico de senhas: %w", err)
+	}
+	defer rows.Close()
+
+	var hashes []string
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			return nil, fmt.Errorf("erro ao ler histórico de senhas: %w", err)
+		}
+		hashes = append(hashes, hash)
+	}
+	return hashes, rows.Err()
+}
@@ -0,0 +1,173 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"auth-service/models"
+
+	"github.com/google/uuid"
+)
+
+const personalTokenColumns = "id, user_id, name, token_hash, prefix, scope, expires_at, last_used_at, revoked_at, created_at"
+
+func (s *SQLStore) CreatePersonalToken(ctx context.Context, token *models.PersonalAccessToken) error {
+	ctx, end := s.observe(ctx, "create_personal_token")
+	defer end()
+
+	_, err := s.db.DB.ExecContext(ctx, `
+		INSERT INTO personal_access_tokens (id, user_id, name, token_hash, prefix, scope, expires_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, token.ID, token.UserID, token.Name, token.TokenHash, token.Prefix, token.Scope, nullTime(token.ExpiresAt), token.CreatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrDuplicate
+		}
+		return fmt.Errorf("erro ao salvar token de acesso pessoal: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLStore) GetPersonalTokenByHash(ctx context.Context, tokenHash string) (*models.PersonalAccessToken, error) {
+	ctx, end := s.observe(ctx, "get_personal_token")
+	defer end()
+
+	row := s.db.DB.QueryRowContext(ctx, "SELECT "+personalTokenColumns+" FROM personal_access_tokens WHERE token_hash = ?", tokenHash)
+	token, err := scanPersonalToken(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar token de acesso pessoal: %w", err)
+	}
+	return token, nil
+}
+
+func (s *SQLStore) ListPersonalTokens(ctx context.Context, userID string) ([]models.PersonalAccessToken, error) {
+	ctx, end := s.observe(ctx, "list_personal_tokens")
+	defer end()
+
+	rows, err := s.db.DB.QueryContext(ctx, "SELECT "+personalTokenColumns+`
+		FROM personal_access_tokens
+		WHERE user_id = ? AND revoked_at IS NULL
+		ORDER BY created_at DESC
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar tokens de acesso pessoal: %w", err)
+	}
+	defer rows.Close()
+
+	tokens := []models.PersonalAccessToken{}
+	for rows.Next() {
+		token, err := scanPersonalToken(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao listar tokens de acesso pessoal: %w", err)
+		}
+		tokens = append(tokens, *token)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao listar tokens de acesso pessoal: %w", err)
+	}
+	return tokens, nil
+}
+
+func (s *SQLStore) RevokeUserPersonalTokens(ctx context.Context, userID string) (int64, error) {
+	ctx, end := s.observe(ctx, "revoke_user_personal_tokens")
+	defer end()
+
+	result, err := s.db.DB.ExecContext(ctx, `
+		UPDATE personal_access_tokens SET revoked_at = ? WHERE user_id = ? AND revoked_at IS NULL
+	`, time.Now(), userID)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao revogar tokens de acesso pessoal: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+func (s *SQLStore) RevokePersonalToken(ctx context.Context, id, userID string) error {
+	ctx, end := s.observe(ctx, "revoke_personal_token")
+	defer end()
+
+	result, err := s.db.DB.ExecContext(ctx, `
+		UPDATE personal_access_tokens SET revoked_at = ?
+		WHERE id = ? AND user_id = ? AND revoked_at IS NULL
+	`, time.Now(), id, userID)
+	if err != nil {
+		return fmt.Errorf("erro ao revogar token de acesso pessoal: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("erro ao revogar token de acesso pessoal: %w", err)
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *SQLStore) TouchPersonalToken(ctx context.Context, id uuid.UUID, usedAt time.Time) error {
+	ctx, end := s.observe(ctx, "touch_personal_token")
+	defer end()
+
+	_, err := s.db.DB.ExecContext(ctx, "UPDATE personal_access_tokens SET last_used_at = ? WHERE id = ?", usedAt, id)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar último uso do token de acesso pessoal: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLStore) PurgePersonalTokens(ctx context.Context, before time.Time, limit int) (int64, error) {
+	ctx, end := s.observe(ctx, "purge_personal_tokens")
+	defer end()
+
+	result, err := s.db.DB.ExecContext(ctx, `
+		DELETE FROM personal_access_tokens WHERE id IN (
+			SELECT id FROM (
+				SELECT id FROM personal_access_tokens
+				WHERE expires_at < ? OR revoked_at < ?
+				LIMIT ?
+			) AS batch
+		)
+	`, before, before, limit)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao remover tokens de acesso pessoal: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// rowScanner é satisfeito por *sql.Row e *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanPersonalToken(row rowScanner) (*models.PersonalAccessToken, error) {
+	var token models.PersonalAccessToken
+	var expiresAt, lastUsedAt, revokedAt sql.NullTime
+	err := row.Scan(&token.ID, &token.UserID, &token.Name, &token.TokenHash, &token.Prefix, &token.Scope,
+		&expiresAt, &lastUsedAt, &revokedAt, &token.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	token.ExpiresAt = timePtr(expiresAt)
+	token.LastUsedAt = timePtr(lastUsedAt)
+	token.RevokedAt = timePtr(revokedAt)
+	return &token, nil
+}
+
+// nullTime grava NULL no lugar de ponteiros nulos
+func nullTime(value *time.Time) sql.NullTime {
+	if value == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: *value, Valid: true}
+}
+
+func timePtr(value sql.NullTime) *time.Time {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Time
+}
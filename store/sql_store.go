@@ -0,0 +1,63 @@
+package store
+
+import (
+	"context"
+	"strings"
+
+	"auth-service/database"
+	"auth-service/metrics"
+	"auth-service/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// SQLStore implementa os stores sobre MySQL ou SQLite
+type SQLStore struct {
+	db *database.Database
+}
+
+func NewSQLStore(db *database.Database) *SQLStore {
+	return &SQLStore{db: db}
+}
+
+// NewSQLStores retorna todos os stores apoiados no banco informado
+func NewSQLStores(db *database.Database) *Stores {
+	sqlStore := NewSQLStore(db)
+	return &Stores{
+		Users:          sqlStore,
+		Clients:        sqlStore,
+		Tokens:         sqlStore,
+		Events:         sqlStore,
+		PersonalTokens: sqlStore,
+		MagicLinks:     sqlStore,
+		Invitations:    sqlStore,
+		Organizations:  sqlStore,
+		DeviceCodes:    sqlStore,
+		Outbox:         sqlStore,
+		Webhooks:       sqlStore,
+		KnownDevices:   sqlStore,
+		Passwords:      sqlStore,
+		Codes:          sqlStore,
+	}
+}
+
+// isUniqueViolation reconhece violações de unicidade do SQLite e do MySQL (erro 1062)
+func isUniqueViolation(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "UNIQUE constraint failed") || strings.Contains(msg, "Error 1062")
+}
+
+// observe mede a duração de uma operação no banco e a registra como span do trace atual.
+// Uso: ctx, end := s.observe(ctx, "get_user_by_id"); defer end()
+func (s *SQLStore) observe(ctx context.Context, operation string) (context.Context, func()) {
+	ctx, span := tracing.Start(ctx, "db."+operation,
+		attribute.String("db.system", s.db.DBType),
+		attribute.String("db.operation", operation),
+	)
+	done := metrics.Observe(metrics.DBQueryDuration, operation)
+
+	return ctx, func() {
+		done()
+		span.End()
+	}
+}
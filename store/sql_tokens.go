@@ -0,0 +1,93 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"auth-service/models"
+
+	"github.com/google/uuid"
+)
+
+func (s *SQLStore) CreateRefreshToken(ctx context.Context, token *models.RefreshToken) error {
+	ctx, end := s.observe(ctx, "create_refresh_token")
+	defer end()
+
+	_, err := s.db.DB.ExecContext(ctx, `
+		INSERT INTO refresh_tokens (id, user_id, client_id, token, scope, auth_time, expires_at, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, token.ID, token.UserID, token.ClientID, token.Token, token.Scope, token.AuthTime, token.ExpiresAt, token.CreatedAt, token.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("erro ao salvar refresh token: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLStore) GetRefreshToken(ctx context.Context, token, clientID string) (*models.RefreshToken, error) {
+	ctx, end := s.observe(ctx, "get_refresh_token")
+	defer end()
+
+	var refreshToken models.RefreshToken
+	var authTime sql.NullTime
+	err := s.db.DB.QueryRowContext(ctx, `
+		SELECT id, user_id, client_id, token, scope, auth_time, expires_at, revoked
+		FROM refresh_tokens
+		WHERE token = ? AND client_id = ?
+	`, token, clientID).Scan(
+		&refreshToken.ID, &refreshToken.UserID, &refreshToken.ClientID,
+		&refreshToken.Token, &refreshToken.Scope, &authTime, &refreshToken.ExpiresAt, &refreshToken.Revoked)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("erro ao verificar refresh token: %w", err)
+	}
+	refreshToken.AuthTime = timePtr(authTime)
+	return &refreshToken, nil
+}
+
+func (s *SQLStore) RevokeRefreshToken(ctx context.Context, id uuid.UUID) error {
+	ctx, end := s.observe(ctx, "revoke_refresh_token")
+	defer end()
+
+	_, err := s.db.DB.ExecContext(ctx, "UPDATE refresh_tokens SET revoked = true, updated_at = ? WHERE id = ?", time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("erro ao revogar refresh token: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLStore) RevokeUserRefreshTokens(ctx context.Context, userID string) (int64, error) {
+	ctx, end := s.observe(ctx, "revoke_user_refresh_tokens")
+	defer end()
+
+	result, err := s.db.DB.ExecContext(ctx, `
+		UPDATE refresh_tokens SET revoked = true, updated_at = ? WHERE user_id = ? AND revoked = false
+	`, time.Now(), userID)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao revogar refresh tokens: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+func (s *SQLStore) PurgeRefreshTokens(ctx context.Context, before time.Time, limit int) (int64, error) {
+	ctx, end := s.observe(ctx, "purge_refresh_tokens")
+	defer end()
+
+	// A subconsulta derivada permite LIMIT dentro de IN também no MySQL
+	result, err := s.db.DB.ExecContext(ctx, `
+		DELETE FROM refresh_tokens WHERE id IN (
+			SELECT id FROM (
+				SELECT id FROM refresh_tokens
+				WHERE expires_at < ? OR (revoked = true AND updated_at < ?)
+				LIMIT ?
+			) AS batch
+		)
+	`, before, before, limit)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao remover refresh tokens: %w", err)
+	}
+	return result.RowsAffected()
+}
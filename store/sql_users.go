@@ -0,0 +1,277 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"auth-service/models"
+
+	"github.com/google/uuid"
+)
+
+const userColumns = "id, tenant_id, email, password, name, active, created_at, updated_at"
+
+func scanUser(row rowScanner) (*models.User, error) {
+	var user models.User
+	err := row.Scan(&user.ID, &user.TenantID, &user.Email, &user.Password, &user.Name, &user.Active, &user.CreatedAt, &user.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar usuário: %w", err)
+	}
+	return &user, nil
+}
+
+func (s *SQLStore) CreateUser(ctx context.Context, user *models.User, roles []string, event *models.OutboxEvent) error {
+	ctx, end := s.observe(ctx, "create_user")
+	defer end()
+
+	return s.withOutbox(ctx, event, func(tx *sql.Tx) error {
+		if err := insertUser(ctx, tx, user); err != nil {
+			return err
+		}
+		return insertUserRoles(ctx, tx, user.ID, roles, user.CreatedAt)
+	})
+}
+
+func (s *SQLStore) CreateInvitedUser(ctx context.Context, user *models.User, invitationID uuid.UUID, roles []string, now time.Time, event *models.OutboxEvent) error {
+	ctx, end := s.observe(ctx, "create_invited_user")
+	defer end()
+
+	return s.withOutbox(ctx, event, func(tx *sql.Tx) error {
+		if err := useInvitation(ctx, tx, invitationID, now); err != nil {
+			return err
+		}
+		if err := insertUser(ctx, tx, user); err != nil {
+			return err
+		}
+		return insertUserRoles(ctx, tx, user.ID, roles, now)
+	})
+}
+
+func insertUser(ctx context.Context, tx *sql.Tx, user *models.User) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO users (id, tenant_id, email, password, name, active, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, user.ID, user.TenantID, user.Email, user.Password, user.Name, user.Active, user.CreatedAt, user.UpdatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrDuplicate
+		}
+		return fmt.Errorf("erro ao criar usuário: %w", err)
+	}
+	return nil
+}
+
+func insertUserRoles(ctx context.Context, tx *sql.Tx, userID uuid.UUID, roles []string, now time.Time) error {
+	for _, role := range roles {
+		_, err := tx.ExecContext(ctx, "INSERT INTO user_roles (user_id, role, created_at) VALUES (?, ?, ?)", userID, role, now)
+		if err != nil {
+			if isUniqueViolation(err) {
+				return ErrDuplicate
+			}
+			return fmt.Errorf("erro ao conceder papel: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *SQLStore) UpdateUser(ctx context.Context, user *models.User, roles []string, event *models.OutboxEvent) error {
+	ctx, end := s.observe(ctx, "update_user")
+	defer end()
+
+	return s.withOutbox(ctx, event, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, `
+			UPDATE users SET name = ?, active = ?, updated_at = ? WHERE id = ?
+		`, user.Name, user.Active, user.UpdatedAt, user.ID)
+		if err != nil {
+			return fmt.Errorf("erro ao atualizar usuário: %w", err)
+		}
+		if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+			return ErrNotFound
+		}
+		return insertUserRoles(ctx, tx, user.ID, roles, time.Now())
+	})
+}
+
+func (s *SQLStore) UpdateUserPassword(ctx context.Context, id uuid.UUID, password string, updatedAt time.Time, previous *models.PasswordHistoryEntry, keep int) error {
+	ctx, end := s.observe(ctx, "update_user_password")
+	defer end()
+
+	tx, err := s.db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, "UPDATE users SET password = ?, updated_at = ? WHERE id = ?", password, updatedAt, id)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar senha: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrNotFound
+	}
+
+	if previous != nil {
+		if err := addPasswordHistory(ctx, tx, previous, keep); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+	return nil
+}
+
+// DeleteUser remove explicitamente os registros dependentes: o SQLite não aplica o
+// ON DELETE CASCADE sem PRAGMA foreign_keys
+func (s *SQLStore) DeleteUser(ctx context.Context, id uuid.UUID, event *models.OutboxEvent) error {
+	ctx, end := s.observe(ctx, "delete_user")
+	defer end()
+
+	return s.withOutbox(ctx, event, func(tx *sql.Tx) error {
+		for _, query := range []string{
+			"DELETE FROM user_roles WHERE user_id = ?",
+			"DELETE FROM user_identities WHERE user_id = ?",
+			"DELETE FROM refresh_tokens WHERE user_id = ?",
+			"DELETE FROM personal_access_tokens WHERE user_id = ?",
+			"DELETE FROM device_codes WHERE user_id = ?",
+			"DELETE FROM known_devices WHERE user_id = ?",
+			"DELETE FROM password_history WHERE user_id = ?",
+			"UPDATE invitations SET created_by = NULL WHERE created_by = ?",
+		} {
+			if _, err := tx.ExecContext(ctx, query, id); err != nil {
+				return fmt.Errorf("erro ao remover dados do usuário: %w", err)
+			}
+		}
+
+		result, err := tx.ExecContext(ctx, "DELETE FROM users WHERE id = ?", id)
+		if err != nil {
+			return fmt.Errorf("erro ao remover usuário: %w", err)
+		}
+		if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+			return ErrNotFound
+		}
+		return nil
+	})
+}
+
+func (s *SQLStore) GetUserByID(ctx context.Context, id string) (*models.User, error) {
+	ctx, end := s.observe(ctx, "get_user_by_id")
+	defer end()
+
+	return scanUser(s.db.DB.QueryRowContext(ctx, "SELECT "+userColumns+" FROM users WHERE id = ?", id))
+}
+
+func (s *SQLStore) GetUserByEmail(ctx context.Context, tenantID, email string) (*models.User, error) {
+	ctx, end := s.observe(ctx, "get_user_by_email")
+	defer end()
+
+	return scanUser(s.db.DB.QueryRowContext(ctx, "SELECT "+userColumns+" FROM users WHERE tenant_id = ? AND email = ?", tenantID, email))
+}
+
+func (s *SQLStore) ListUsers(ctx context.Context, tenantID string) ([]models.User, error) {
+	ctx, end := s.observe(ctx, "list_users")
+	defer end()
+
+	rows, err := s.db.DB.QueryContext(ctx, "SELECT "+userColumns+" FROM users WHERE tenant_id = ? ORDER BY email", tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar usuários: %w", err)
+	}
+	defer rows.Close()
+
+	users := []models.User{}
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, *user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao listar usuários: %w", err)
+	}
+	return users, nil
+}
+
+func (s *SQLStore) GetUserByIdentity(ctx context.Context, tenantID, provider, subject string) (*models.User, error) {
+	ctx, end := s.observe(ctx, "get_user_by_identity")
+	defer end()
+
+	return scanUser(s.db.DB.QueryRowContext(ctx, `
+		SELECT u.id, u.tenant_id, u.email, u.password, u.name, u.active, u.created_at, u.updated_at
+		FROM user_identities i
+		JOIN users u ON u.id = i.user_id
+		WHERE u.tenant_id = ? AND i.provider = ? AND i.subject = ?
+	`, tenantID, provider, subject))
+}
+
+func (s *SQLStore) CreateIdentity(ctx context.Context, identity *models.UserIdentity) error {
+	ctx, end := s.observe(ctx, "create_identity")
+	defer end()
+
+	_, err := s.db.DB.ExecContext(ctx, `
+		INSERT INTO user_identities (id, user_id, provider, subject, email, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, identity.ID, identity.UserID, identity.Provider, identity.Subject, identity.Email, identity.CreatedAt, identity.UpdatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrDuplicate
+		}
+		return fmt.Errorf("erro ao vincular identidade: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLStore) GetUserRoles(ctx context.Context, userID string) ([]string, error) {
+	ctx, end := s.observe(ctx, "get_user_roles")
+	defer end()
+
+	rows, err := s.db.DB.QueryContext(ctx, "SELECT role FROM user_roles WHERE user_id = ? ORDER BY role", userID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar papéis do usuário: %w", err)
+	}
+	defer rows.Close()
+
+	var roles []string
+	for rows.Next() {
+		var role string
+		if err := rows.Scan(&role); err != nil {
+			return nil, fmt.Errorf("erro ao buscar papéis do usuário: %w", err)
+		}
+		roles = append(roles, role)
+	}
+	return roles, rows.Err()
+}
+
+func (s *SQLStore) AddUserRole(ctx context.Context, userID, role string) error {
+	ctx, end := s.observe(ctx, "add_user_role")
+	defer end()
+
+	_, err := s.db.DB.ExecContext(ctx, "INSERT INTO user_roles (user_id, role, created_at) VALUES (?, ?, ?)", userID, role, time.Now())
+	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrDuplicate
+		}
+		return fmt.Errorf("erro ao conceder papel: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLStore) RemoveUserRole(ctx context.Context, userID, role string) error {
+	ctx, end := s.observe(ctx, "remove_user_role")
+	defer end()
+
+	result, err := s.db.DB.ExecContext(ctx, "DELETE FROM user_roles WHERE user_id = ? AND role = ?", userID, role)
+	if err != nil {
+		return fmt.Errorf("erro ao remover papel: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"auth-service/models"
+)
+
+const webhookColumns = "id, tenant_id, url, secret, event_types, active, created_at, updated_at"
+
+func (s *SQLStore) CreateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	ctx, end := s.observe(ctx, "create_webhook")
+	defer end()
+
+	_, err := s.db.DB.ExecContext(ctx, `
+		INSERT INTO webhooks (id, tenant_id, url, secret, event_types, active, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, webhook.ID, webhook.TenantID, webhook.URL, webhook.Secret, strings.Join(webhook.EventTypes, " "), webhook.Active,
+		webhook.CreatedAt, webhook.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("erro ao criar webhook: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLStore) GetWebhook(ctx context.Context, id string) (*models.Webhook, error) {
+	ctx, end := s.observe(ctx, "get_webhook")
+	defer end()
+
+	webhook, err := scanWebhook(s.db.DB.QueryRowContext(ctx, "SELECT "+webhookColumns+" FROM webhooks WHERE id = ?", id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar webhook: %w", err)
+	}
+	return webhook, nil
+}
+
+func (s *SQLStore) ListWebhooks(ctx context.Context, tenantID string) ([]models.Webhook, error) {
+	ctx, end := s.observe(ctx, "list_webhooks")
+	defer end()
+
+	rows, err := s.db.DB.QueryContext(ctx, "SELECT "+webhookColumns+`
+		FROM webhooks
+		WHERE tenant_id = ?
+		ORDER BY created_at
+	`, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	webhooks := []models.Webhook{}
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao listar webhooks: %w", err)
+		}
+		webhooks = append(webhooks, *webhook)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao listar webhooks: %w", err)
+	}
+	return webhooks, nil
+}
+
+// DeleteWebhook remove também as entregas do webhook, pelo mesmo motivo do DeleteUser
+func (s *SQLStore) DeleteWebhook(ctx context.Context, tenantID, id string) error {
+	ctx, end := s.observe(ctx, "delete_webhook")
+	defer end()
+
+	tx, err := s.db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, "DELETE FROM webhooks WHERE id = ? AND tenant_id = ?", id, tenantID)
+	if err != nil {
+		return fmt.Errorf("erro ao remover webhook: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrNotFound
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM webhook_deliveries WHERE webhook_id = ?", id); err != nil {
+		return fmt.Errorf("erro ao remover entregas do webhook: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+	return nil
+}
+
+func scanWebhook(row rowScanner) (*models.Webhook, error) {
+	var webhook models.Webhook
+	var eventTypes string
+	err := row.Scan(&webhook.ID, &webhook.TenantID, &webhook.URL, &webhook.Secret, &eventTypes, &webhook.Active,
+		&webhook.CreatedAt, &webhook.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	webhook.EventTypes = strings.Fields(eventTypes)
+	return &webhook, nil
+}
@@ -0,0 +1,238 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"auth-service/models"
+
+	"github.com/google/uuid"
+)
+
+// ErrNotFound indica que o registro procurado não existe
+var ErrNotFound = errors.New("registro não encontrado")
+
+// ErrDuplicate indica violação de unicidade (ex: email já cadastrado)
+var ErrDuplicate = errors.New("registro duplicado")
+
+// UserStore persiste usuários e suas identidades em provedores externos. As alterações do
+// usuário recebem o evento do outbox, que é gravado na mesma transação quando informado.
+type UserStore interface {
+	// CreateUser cria o usuário e concede os papéis informados na mesma transação; retorna
+	// ErrDuplicate se o email já existir
+	CreateUser(ctx context.Context, user *models.User, roles []string, event *models.OutboxEvent) error
+	// CreateInvitedUser cria o usuário com os papéis do convite e consome um uso dele na
+	// mesma transação; retorna ErrNotFound se o convite estiver revogado, expirado em now ou
+	// sem usos restantes, e ErrDuplicate se o email já existir
+	CreateInvitedUser(ctx context.Context, user *models.User, invitationID uuid.UUID, roles []string, now time.Time, event *models.OutboxEvent) error
+	// UpdateUser altera o perfil do usuário e concede os papéis informados na mesma transação
+	UpdateUser(ctx context.Context, user *models.User, roles []string, event *models.OutboxEvent) error
+	// UpdateUserPassword troca o hash da senha; um hash vazio deixa o usuário sem senha local.
+	// Quando previous é informado, o hash substituído entra no histórico, podado aos keep
+	// mais recentes, na mesma transação.
+	UpdateUserPassword(ctx context.Context, id uuid.UUID, password string, updatedAt time.Time, previous *models.PasswordHistoryEntry, keep int) error
+	// DeleteUser remove o usuário com seus papéis, identidades e tokens; retorna ErrNotFound se ele não existir
+	DeleteUser(ctx context.Context, id uuid.UUID, event *models.OutboxEvent) error
+	GetUserByID(ctx context.Context, id string) (*models.User, error)
+	// GetUserByEmail busca o usuário pelo email dentro da organização; o email só é único por organização
+	GetUserByEmail(ctx context.Context, tenantID, email string) (*models.User, error)
+	// ListUsers retorna os usuários da organização ordenados pelo email
+	ListUsers(ctx context.Context, tenantID string) ([]models.User, error)
+	GetUserByIdentity(ctx context.Context, tenantID, provider, subject string) (*models.User, error)
+	CreateIdentity(ctx context.Context, identity *models.UserIdentity) error
+	GetUserRoles(ctx context.Context, userID string) ([]string, error)
+	AddUserRole(ctx context.Context, userID, role string) error
+	RemoveUserRole(ctx context.Context, userID, role string) error
+}
+
+// ClientStore persiste as aplicações clientes
+type ClientStore interface {
+	CreateClient(ctx context.Context, client *models.Client) error
+	GetClient(ctx context.Context, id string) (*models.Client, error)
+	// ListClients retorna os clients da organização, mais antigos primeiro
+	ListClients(ctx context.Context, tenantID string) ([]models.Client, error)
+	UpdateClient(ctx context.Context, client *models.Client) error
+}
+
+// TokenStore persiste refresh tokens
+type TokenStore interface {
+	CreateRefreshToken(ctx context.Context, token *models.RefreshToken) error
+	GetRefreshToken(ctx context.Context, token, clientID string) (*models.RefreshToken, error)
+	RevokeRefreshToken(ctx context.Context, id uuid.UUID) error
+	// RevokeUserRefreshTokens revoga todos os refresh tokens ativos do usuário e retorna quantos foram revogados
+	RevokeUserRefreshTokens(ctx context.Context, userID string) (int64, error)
+	// PurgeRefreshTokens remove até limit tokens expirados antes de before ou revogados
+	// há mais tempo que before, retornando quantos foram removidos
+	PurgeRefreshTokens(ctx context.Context, before time.Time, limit int) (int64, error)
+}
+
+// PersonalTokenStore persiste tokens de acesso pessoal
+type PersonalTokenStore interface {
+	CreatePersonalToken(ctx context.Context, token *models.PersonalAccessToken) error
+	GetPersonalTokenByHash(ctx context.Context, tokenHash string) (*models.PersonalAccessToken, error)
+	// ListPersonalTokens retorna os tokens não revogados do usuário, mais recentes primeiro
+	ListPersonalTokens(ctx context.Context, userID string) ([]models.PersonalAccessToken, error)
+	// RevokePersonalToken revoga o token se ele pertencer ao usuário; caso contrário retorna ErrNotFound
+	RevokePersonalToken(ctx context.Context, id, userID string) error
+	// RevokeUserPersonalTokens revoga todos os tokens ativos do usuário e retorna quantos foram revogados
+	RevokeUserPersonalTokens(ctx context.Context, userID string) (int64, error)
+	TouchPersonalToken(ctx context.Context, id uuid.UUID, usedAt time.Time) error
+	// PurgePersonalTokens remove até limit tokens expirados ou revogados antes de before
+	PurgePersonalTokens(ctx context.Context, before time.Time, limit int) (int64, error)
+}
+
+// MagicLinkStore registra os links de acesso já usados
+type MagicLinkStore interface {
+	// UseMagicLink marca o link como usado; retorna ErrDuplicate se ele já tiver sido usado
+	UseMagicLink(ctx context.Context, jti string, expiresAt time.Time) error
+	// PurgeMagicLinks remove até limit registros de links expirados antes de before
+	PurgeMagicLinks(ctx context.Context, before time.Time, limit int) (int64, error)
+}
+
+// DeviceCodeStore persiste as autorizações de dispositivo (RFC 8628)
+type DeviceCodeStore interface {
+	// CreateDeviceCode retorna ErrDuplicate se o user_code já estiver em uso
+	CreateDeviceCode(ctx context.Context, code *models.DeviceCode) error
+	GetDeviceCodeByHash(ctx context.Context, deviceCodeHash string) (*models.DeviceCode, error)
+	GetDeviceCodeByUserCode(ctx context.Context, userCode string) (*models.DeviceCode, error)
+	// TouchDeviceCode registra a consulta do dispositivo e o intervalo exigido a partir dela
+	TouchDeviceCode(ctx context.Context, id uuid.UUID, polledAt time.Time, interval int) error
+	// DecideDeviceCode aprova ou recusa o pedido de forma atômica; retorna ErrNotFound se ele
+	// já tiver sido decidido ou estiver expirado em now. authTime é o momento do login da
+	// sessão que decidiu, repassado ao ID token emitido para o dispositivo
+	DecideDeviceCode(ctx context.Context, id, userID uuid.UUID, status string, authTime *time.Time, now time.Time) error
+	// ConsumeDeviceCode remove o pedido aprovado; retorna ErrNotFound se ele já tiver sido consumido
+	ConsumeDeviceCode(ctx context.Context, id uuid.UUID) error
+	// DeleteUserDeviceCodes remove os pedidos já decididos pelo usuário, inclusive os aprovados
+	// que o dispositivo ainda não trocou por tokens
+	DeleteUserDeviceCodes(ctx context.Context, userID string) (int64, error)
+	// PurgeDeviceCodes remove até limit pedidos expirados antes de before
+	PurgeDeviceCodes(ctx context.Context, before time.Time, limit int) (int64, error)
+}
+
+// KnownDeviceStore persiste os dispositivos pelos quais cada usuário já entrou
+type KnownDeviceStore interface {
+	// TouchKnownDevice atualiza last_seen_at do dispositivo ou o registra; retorna true se ele era desconhecido
+	TouchKnownDevice(ctx context.Context, device *models.KnownDevice) (bool, error)
+	CountKnownDevices(ctx context.Context, userID string) (int, error)
+	// DeleteKnownDevice esquece o dispositivo, que volta a gerar aviso no próximo login
+	DeleteKnownDevice(ctx context.Context, userID, id string) error
+}
+
+// PasswordHistoryStore consulta os hashes das senhas substituídas de cada usuário, gravados
+// por UserStore.UpdateUserPassword
+type PasswordHistoryStore interface {
+	// ListPasswordHistory retorna até limit hashes, do mais recente para o mais antigo
+	ListPasswordHistory(ctx context.Context, userID string, limit int) ([]string, error)
+}
+
+// OrganizationStore persiste as organizações (tenants)
+type OrganizationStore interface {
+	CreateOrganization(ctx context.Context, organization *models.Organization) error
+	GetOrganization(ctx context.Context, id string) (*models.Organization, error)
+	GetOrganizationBySlug(ctx context.Context, slug string) (*models.Organization, error)
+	ListOrganizations(ctx context.Context) ([]models.Organization, error)
+}
+
+// InvitationStore persiste convites de cadastro
+type InvitationStore interface {
+	CreateInvitation(ctx context.Context, invitation *models.Invitation) error
+	GetInvitationByHash(ctx context.Context, codeHash string) (*models.Invitation, error)
+	// ListInvitations retorna os convites não revogados da organização, mais recentes primeiro
+	ListInvitations(ctx context.Context, tenantID string) ([]models.Invitation, error)
+	// RevokeInvitation revoga o convite se ele pertencer à organização; caso contrário retorna ErrNotFound
+	RevokeInvitation(ctx context.Context, tenantID, id string) error
+	// PurgeInvitations remove até limit convites expirados ou revogados antes de before
+	PurgeInvitations(ctx context.Context, before time.Time, limit int) (int64, error)
+}
+
+// OutboxFilter restringe a listagem do outbox. Campos vazios não filtram.
+type OutboxFilter struct {
+	TenantID    string
+	EventType   string
+	AggregateID string
+	Limit       int
+	Offset      int
+}
+
+// OutboxStore persiste os eventos do outbox e as entregas aos webhooks
+type OutboxStore interface {
+	// ListOutboxEvents retorna os eventos mais recentes primeiro e o total que atende ao filtro
+	ListOutboxEvents(ctx context.Context, filter OutboxFilter) ([]models.OutboxEvent, int64, error)
+	GetOutboxEvent(ctx context.Context, id string) (*models.OutboxEvent, error)
+	// ListUndispatchedEvents retorna até limit eventos ainda não distribuídos, mais antigos primeiro
+	ListUndispatchedEvents(ctx context.Context, limit int) ([]models.OutboxEvent, error)
+	// DispatchOutboxEvent marca o evento como distribuído e cria as entregas na mesma transação;
+	// retorna ErrNotFound se outro dispatcher já o tiver distribuído
+	DispatchOutboxEvent(ctx context.Context, eventID uuid.UUID, deliveries []models.WebhookDelivery, now time.Time) error
+	// ResetOutboxEvent volta o evento para a fila, para ser distribuído de novo
+	ResetOutboxEvent(ctx context.Context, id uuid.UUID) error
+	// ListDueDeliveries retorna até limit entregas pendentes com tentativa prevista até now
+	ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]models.WebhookDelivery, error)
+	// ClaimDelivery reserva a entrega pendente e vencida em now adiando a próxima tentativa
+	// para until; retorna ErrNotFound se outro dispatcher já a reservou
+	ClaimDelivery(ctx context.Context, id uuid.UUID, now, until time.Time) error
+	UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+	ListDeliveries(ctx context.Context, eventID string) ([]models.WebhookDelivery, error)
+	// PurgeOutboxEvents remove até limit eventos distribuídos antes de before, com suas
+	// entregas, desde que nenhuma esteja pendente
+	PurgeOutboxEvents(ctx context.Context, before time.Time, limit int) (int64, error)
+}
+
+// WebhookStore persiste os webhooks das organizações
+type WebhookStore interface {
+	CreateWebhook(ctx context.Context, webhook *models.Webhook) error
+	GetWebhook(ctx context.Context, id string) (*models.Webhook, error)
+	ListWebhooks(ctx context.Context, tenantID string) ([]models.Webhook, error)
+	// DeleteWebhook remove o webhook se ele pertencer à organização; caso contrário retorna ErrNotFound
+	DeleteWebhook(ctx context.Context, tenantID, id string) error
+}
+
+// EventFilter restringe a listagem de eventos. Campos vazios não filtram.
+type EventFilter struct {
+	TenantID  string // eventos de usuários ou clients da organização
+	UserID    string
+	ClientID  string
+	EventType string
+	Outcome   string
+	IP        string
+	RequestID string
+	From      time.Time
+	To        time.Time
+	Limit     int
+	Offset    int
+}
+
+// EventStore persiste a trilha de auditoria de autenticação
+type EventStore interface {
+	CreateEvent(ctx context.Context, event *models.AuthEvent) error
+	// ListEvents retorna os eventos mais recentes primeiro e o total que atende ao filtro
+	ListEvents(ctx context.Context, filter EventFilter) ([]models.AuthEvent, int64, error)
+}
+
+// AuthorizationCodeStore registra os códigos do /oauth/authorize já trocados por tokens
+type AuthorizationCodeStore interface {
+	// UseAuthorizationCode marca o código como usado; retorna ErrDuplicate se ele já tiver sido usado
+	UseAuthorizationCode(ctx context.Context, jti string, expiresAt time.Time) error
+	// PurgeAuthorizationCodes remove até limit registros de códigos expirados antes de before
+	PurgeAuthorizationCodes(ctx context.Context, before time.Time, limit int) (int64, error)
+}
+
+// Stores agrupa os stores usados pelos serviços
+type Stores struct {
+	Users          UserStore
+	Clients        ClientStore
+	Tokens         TokenStore
+	Events         EventStore
+	PersonalTokens PersonalTokenStore
+	MagicLinks     MagicLinkStore
+	Invitations    InvitationStore
+	Organizations  OrganizationStore
+	DeviceCodes    DeviceCodeStore
+	Outbox         OutboxStore
+	Webhooks       WebhookStore
+	KnownDevices   KnownDeviceStore
+	Passwords      PasswordHistoryStore
+	Codes          AuthorizationCodeStore
+}
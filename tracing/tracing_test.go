@@ -0,0 +1,108 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"auth-service/config"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// restoreGlobals devolve o provedor e o propagador globais ao fim do teste
+func restoreGlobals(t *testing.T) {
+	t.Helper()
+
+	provider, propagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	t.Cleanup(func() {
+		if otel.GetTracerProvider() != provider {
+			otel.SetTracerProvider(provider)
+		}
+		otel.SetTextMapPropagator(propagator)
+	})
+}
+
+func TestSetup(t *testing.T) {
+	tests := []struct {
+		name    string
+		tracing config.TracingConfig
+		wantErr bool
+	}{
+		{"desabilitado", config.TracingConfig{Exporter: "none"}, false},
+		{"sem exporter", config.TracingConfig{}, false},
+		{"stdout", config.TracingConfig{Exporter: "stdout", ServiceName: "auth-service", SampleRatio: 1}, false},
+		{"exporter desconhecido", config.TracingConfig{Exporter: "jaeger"}, true},
+		{"arquivo inacessível", config.TracingConfig{Exporter: "file", FilePath: filepath.Join(t.TempDir(), "nao-existe", "traces.json")}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restoreGlobals(t)
+
+			shutdown, err := Setup(&config.Config{Tracing: tt.tracing})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("erro %v, esperado erro: %v", err, tt.wantErr)
+			}
+			if err == nil {
+				if err := shutdown(context.Background()); err != nil {
+					t.Errorf("shutdown: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestFileExporterWritesChildSpans(t *testing.T) {
+	restoreGlobals(t)
+
+	path := filepath.Join(t.TempDir(), "traces.json")
+	shutdown, err := Setup(&config.Config{Tracing: config.TracingConfig{Exporter: "file", FilePath: path, ServiceName: "auth-service", SampleRatio: 1}})
+	if err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+
+	// O trace chega de outro serviço pelo cabeçalho traceparent
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	header := http.Header{}
+	header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(header))
+
+	_, span := Start(ctx, "bcrypt.compare")
+	if got := span.SpanContext().TraceID().String(); got != traceID {
+		t.Errorf("trace ID %s, esperado o do cabeçalho %s", got, traceID)
+	}
+	span.End()
+
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("arquivo de traces: %v", err)
+	}
+	if !strings.Contains(string(content), `"Name":"bcrypt.compare"`) || !strings.Contains(string(content), traceID) {
+		t.Errorf("span não exportado:\n%s", content)
+	}
+}
+
+func TestPropagationWithoutExporter(t *testing.T) {
+	restoreGlobals(t)
+
+	if _, err := Setup(&config.Config{}); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+
+	header := http.Header{}
+	header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(header))
+
+	out := http.Header{}
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(out))
+	if out.Get("traceparent") != header.Get("traceparent") {
+		t.Errorf("traceparent repassado = %q, esperado %q", out.Get("traceparent"), header.Get("traceparent"))
+	}
+}